@@ -3,20 +3,20 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
 	"github.com/kennyg/tome/internal/apropos"
+	"github.com/kennyg/tome/internal/artifact"
 	"github.com/kennyg/tome/internal/config"
 	"github.com/kennyg/tome/internal/ui"
 )
 
 var (
-	aproposJSON bool
+	aproposJSON  bool
+	aproposAgent string
 )
 
 var aproposCmd = &cobra.Command{
@@ -26,13 +26,18 @@ var aproposCmd = &cobra.Command{
 	Long: `Search installed skills by keyword or description.
 
 Like Unix apropos, this helps you discover which skill to use for a task.
-Searches skill names, descriptions, and extracted keywords.
+Searches skill names, descriptions, tags, and extracted keywords.
+Prefix a term with tag: to only match skills carrying that tag (from
+skill frontmatter or the collection's tome.yaml).
 
 Examples:
   tome apropos pdf          # Find skills related to PDF
   tome apropos "create chart"  # Find skills for creating charts
   tome apropos spreadsheet  # Find spreadsheet-related skills
-  tome apropos --json pdf   # Output as JSON (for AI agents)`,
+  tome apropos tag:pdf      # Only skills tagged "pdf"
+  tome apropos tag:docs export  # Tagged "docs" and matching "export"
+  tome apropos --json pdf   # Output as JSON (for AI agents)
+  tome apropos -a opencode pdf  # Search OpenCode's skills`,
 	Args: cobra.MinimumNArgs(1),
 	Run:  runApropos,
 }
@@ -53,29 +58,31 @@ var aproposListCmd = &cobra.Command{
 
 func init() {
 	aproposCmd.Flags().BoolVar(&aproposJSON, "json", false, "Output as JSON (for AI agents)")
+	aproposCmd.PersistentFlags().StringVarP(&aproposAgent, "agent", "a", "", "Agent whose skills to search (default: the detected agent)")
 	aproposCmd.AddCommand(aproposRebuildCmd)
 	aproposCmd.AddCommand(aproposListCmd)
 }
 
 // JSONResult is the structured output for AI agents
 type JSONResult struct {
-	Query   string       `json:"query"`
-	Count   int          `json:"count"`
-	Results []JSONSkill  `json:"results"`
+	Query   string      `json:"query"`
+	Count   int         `json:"count"`
+	Results []JSONSkill `json:"results"`
 }
 
 // JSONSkill is a skill in JSON output
 type JSONSkill struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Score       int    `json:"score"`
-	Invoke      string `json:"invoke"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	Score       int      `json:"score"`
+	Invoke      string   `json:"invoke"`
 }
 
 func runApropos(cmd *cobra.Command, args []string) {
 	query := strings.Join(args, " ")
 
-	paths, err := config.GetPaths()
+	paths, err := aproposPaths()
 	if err != nil {
 		if aproposJSON {
 			outputJSONError(err.Error())
@@ -84,17 +91,8 @@ func runApropos(cmd *cobra.Command, args []string) {
 		exitWithError("Failed to get paths: " + err.Error())
 	}
 
-	// Collect skills directories (user + project)
-	skillsDirs := []string{paths.SkillsDir}
-	if paths.HasProjectConfig() {
-		projectSkillsDir := filepath.Join(filepath.Dir(paths.ProjectConfigDir), ".claude", "skills")
-		if info, err := os.Stat(projectSkillsDir); err == nil && info.IsDir() {
-			skillsDirs = append(skillsDirs, projectSkillsDir)
-		}
-	}
-
 	// Load or build index (quiet mode for JSON)
-	index, err := getOrBuildIndexQuiet(skillsDirs, paths.SkillsDir, false, aproposJSON)
+	index, err := getOrBuildIndexQuiet(paths, false, aproposJSON)
 	if err != nil {
 		if aproposJSON {
 			outputJSONError(err.Error())
@@ -141,6 +139,23 @@ func runApropos(cmd *cobra.Command, args []string) {
 	fmt.Println(ui.PageFooter())
 }
 
+// aproposPaths returns the paths of the agent named by --agent, or the
+// detected one: the project's when attuned for it, so its skills are searched
+// along with global ones
+func aproposPaths() (*config.Paths, error) {
+	agent := config.DefaultAgent()
+	if aproposAgent != "" {
+		agent = config.Agent(strings.TrimSpace(aproposAgent))
+		if config.GetAgentConfig(agent) == nil {
+			return nil, fmt.Errorf("unknown agent: %s (try: claude, opencode, crush, cursor, windsurf)", aproposAgent)
+		}
+	}
+	if config.IsAttuned(agent) {
+		return config.GetLocalPaths(agent)
+	}
+	return config.GetPathsForAgent(agent)
+}
+
 func outputJSON(query string, results []apropos.SearchResult) {
 	out := JSONResult{
 		Query:   query,
@@ -152,6 +167,7 @@ func outputJSON(query string, results []apropos.SearchResult) {
 		out.Results[i] = JSONSkill{
 			Name:        r.Skill.Name,
 			Description: r.Skill.Description,
+			Tags:        r.Skill.Tags,
 			Score:       r.Score,
 			Invoke:      fmt.Sprintf("Skill: %s", r.Skill.Name),
 		}
@@ -182,20 +198,12 @@ func runAproposRebuild(cmd *cobra.Command, args []string) {
 	fmt.Println(ui.SectionHeader("Rebuilding Index", 56))
 	fmt.Println()
 
-	paths, err := config.GetPaths()
+	paths, err := aproposPaths()
 	if err != nil {
 		exitWithError("Failed to get paths: " + err.Error())
 	}
 
-	skillsDirs := []string{paths.SkillsDir}
-	if paths.HasProjectConfig() {
-		projectSkillsDir := filepath.Join(filepath.Dir(paths.ProjectConfigDir), ".claude", "skills")
-		if info, err := os.Stat(projectSkillsDir); err == nil && info.IsDir() {
-			skillsDirs = append(skillsDirs, projectSkillsDir)
-		}
-	}
-
-	index, err := getOrBuildIndex(skillsDirs, paths.SkillsDir, true)
+	index, err := getOrBuildIndex(paths, true)
 	if err != nil {
 		exitWithError("Failed to rebuild index: " + err.Error())
 	}
@@ -209,20 +217,12 @@ func runAproposList(cmd *cobra.Command, args []string) {
 	fmt.Println(ui.SectionHeader("Indexed Skills", 56))
 	fmt.Println()
 
-	paths, err := config.GetPaths()
+	paths, err := aproposPaths()
 	if err != nil {
 		exitWithError("Failed to get paths: " + err.Error())
 	}
 
-	skillsDirs := []string{paths.SkillsDir}
-	if paths.HasProjectConfig() {
-		projectSkillsDir := filepath.Join(filepath.Dir(paths.ProjectConfigDir), ".claude", "skills")
-		if info, err := os.Stat(projectSkillsDir); err == nil && info.IsDir() {
-			skillsDirs = append(skillsDirs, projectSkillsDir)
-		}
-	}
-
-	index, err := getOrBuildIndex(skillsDirs, paths.SkillsDir, false)
+	index, err := getOrBuildIndex(paths, false)
 	if err != nil {
 		exitWithError("Failed to load index: " + err.Error())
 	}
@@ -244,11 +244,12 @@ func runAproposList(cmd *cobra.Command, args []string) {
 	fmt.Println(ui.PageFooter())
 }
 
-func getOrBuildIndex(skillsDirs []string, primaryDir string, forceRebuild bool) (*apropos.Index, error) {
-	return getOrBuildIndexQuiet(skillsDirs, primaryDir, forceRebuild, false)
+func getOrBuildIndex(paths *config.Paths, forceRebuild bool) (*apropos.Index, error) {
+	return getOrBuildIndexQuiet(paths, forceRebuild, false)
 }
 
-func getOrBuildIndexQuiet(skillsDirs []string, primaryDir string, forceRebuild bool, quiet bool) (*apropos.Index, error) {
+func getOrBuildIndexQuiet(paths *config.Paths, forceRebuild bool, quiet bool) (*apropos.Index, error) {
+	skillsDirs, primaryDir := paths.SkillDirs, paths.SkillsDir
 	if !forceRebuild {
 		index, err := apropos.LoadIndex(primaryDir)
 		if err != nil {
@@ -256,7 +257,7 @@ func getOrBuildIndexQuiet(skillsDirs []string, primaryDir string, forceRebuild b
 		}
 
 		if index != nil {
-			stale, err := apropos.IsStale(primaryDir, index)
+			stale, err := apropos.IsStale(skillsDirs, index)
 			if err == nil && !stale {
 				return index, nil
 			}
@@ -274,7 +275,7 @@ func getOrBuildIndexQuiet(skillsDirs []string, primaryDir string, forceRebuild b
 		}
 	}
 
-	index, err := apropos.BuildIndex(skillsDirs)
+	index, err := apropos.BuildIndexWithTags(skillsDirs, installedSkillTags(paths))
 	if err != nil {
 		return nil, err
 	}
@@ -289,6 +290,21 @@ func getOrBuildIndexQuiet(skillsDirs []string, primaryDir string, forceRebuild b
 	return index, nil
 }
 
+// installedSkillTags returns the collection tags recorded for installed skills, by name
+func installedSkillTags(paths *config.Paths) map[string][]string {
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		return nil
+	}
+	tags := make(map[string][]string)
+	for _, a := range state.Installed {
+		if a.Type == artifact.TypeSkill && len(a.Tags) > 0 {
+			tags[a.Name] = a.Tags
+		}
+	}
+	return tags
+}
+
 func printSkillResult(skill apropos.Skill) {
 	name := lipgloss.NewStyle().Foreground(ui.White).Bold(true).Render(skill.Name)
 	fmt.Printf("  %s  %s\n", ui.SkillBadge(), name)
@@ -301,6 +317,9 @@ func printSkillResult(skill apropos.Skill) {
 	}
 	descStyled := lipgloss.NewStyle().Foreground(ui.Gray).Render(desc)
 	fmt.Printf("       %s\n", descStyled)
+	if len(skill.Tags) > 0 {
+		fmt.Printf("       %s\n", ui.Muted.Render("tags: "+strings.Join(skill.Tags, ", ")))
+	}
 
 	// Show invoke command
 	cmd := lipgloss.NewStyle().Foreground(ui.Cyan).Render("Skill: " + skill.Name)
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAproposPaths_Agent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	for _, dir := range []string{".git", ".claude/skills"} {
+		if err := os.MkdirAll(filepath.Join(project, filepath.FromSlash(dir)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".opencode", "skills"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(project)
+	t.Cleanup(func() { aproposAgent = "" })
+
+	// The project only has Claude's directories, so opencode's skills are global
+	aproposAgent = "opencode"
+	paths, err := aproposPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(home, ".opencode", "skills")}
+	if strings.Join(paths.SkillDirs, ",") != strings.Join(want, ",") {
+		t.Errorf("SkillDirs = %v, want %v", paths.SkillDirs, want)
+	}
+
+	// Attuned, the project's skills come first and are where the index lives
+	if err := os.MkdirAll(filepath.Join(project, ".opencode", "skills"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	paths, err = aproposPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{filepath.Join(project, ".opencode", "skills"), filepath.Join(home, ".opencode", "skills")}
+	if strings.Join(paths.SkillDirs, ",") != strings.Join(want, ",") {
+		t.Errorf("attuned SkillDirs = %v, want %v", paths.SkillDirs, want)
+	}
+
+	aproposAgent = "emacs"
+	if _, err := aproposPaths(); err == nil {
+		t.Error("aproposPaths() accepted an unknown agent")
+	}
+}
@@ -19,19 +19,73 @@ var attuneCmd = &cobra.Command{
 
 This command prepares your project by:
   - Creating .config/tome/ directory (following dot-config spec)
+  - Creating each agent's skills and commands directories in the project
   - Generating AGENTS.md with Tome usage instructions
-  - Setting up any necessary configuration
 
-Run this once in a new project to enable AI agents to use Tome effectively.`,
-	Run: runAttune,
+Once attuned, 'tome learn' installs into the project instead of your home
+directory (use --global to override). Run 'tome detach' to undo it.
+
+Examples:
+  tome attune                        # Attune for the default agent
+  tome attune --agent claude,cursor  # Attune for several agents`,
+	Args: cobra.NoArgs,
+	Run:  runAttune,
+}
+
+var detachCmd = &cobra.Command{
+	Use:   "detach",
+	Short: "Detach your project from the Tome",
+	Long: `Remove the project's .config/tome/ directory and its empty agent
+directories so 'tome learn' installs globally again.
+
+Artifacts already installed in the project's agent directories are left in
+place, and an agent whose directory still holds some keeps installing into
+the project. If the project's state still records artifacts, detach refuses
+unless --force is given, since tome would forget about them.`,
+	Args: cobra.NoArgs,
+	Run:  runDetach,
 }
 
 var (
-	attuneForce bool
+	attuneForce  bool
+	attuneAgents []string
+	detachForce  bool
 )
 
 func init() {
 	attuneCmd.Flags().BoolVarP(&attuneForce, "force", "f", false, "Overwrite existing AGENTS.md")
+	attuneCmd.Flags().StringSliceVarP(&attuneAgents, "agent", "a", nil, "Agent(s) to attune for, comma-separated or repeated (default: the detected agent)")
+	detachCmd.Flags().BoolVarP(&detachForce, "force", "f", false, "Detach even if the project's state records installed artifacts")
+}
+
+// attuneProject creates root's .config/tome directory and each agent's
+// project-local skills and commands directories, returning the directories
+// (relative to root) that didn't exist before
+func attuneProject(root string, agents []config.Agent) ([]string, error) {
+	dirs := []string{filepath.Join(".config", config.ConfigDir)}
+	for _, agent := range agents {
+		agentCfg := config.GetAgentConfig(agent)
+		if agentCfg.SkillsDir == "" {
+			return nil, fmt.Errorf("tome can't install artifacts for %s yet", agentCfg.DisplayName)
+		}
+		dirs = append(dirs, filepath.Join(agentCfg.ConfigDir, agentCfg.SkillsDir))
+		if agentCfg.CommandsDir != "" && agentCfg.CommandsDir != agentCfg.SkillsDir {
+			dirs = append(dirs, filepath.Join(agentCfg.ConfigDir, agentCfg.CommandsDir))
+		}
+	}
+
+	var created []string
+	for _, dir := range dirs {
+		path := filepath.Join(root, dir)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return created, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		created = append(created, dir)
+	}
+	return created, nil
 }
 
 func runAttune(cmd *cobra.Command, args []string) {
@@ -52,29 +106,18 @@ func runAttune(cmd *cobra.Command, args []string) {
 		fmt.Println()
 	}
 
-	// Create .config/tome/ directory
-	configDir := filepath.Join(cwd, ".config", "tome")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		exitWithError(fmt.Sprintf("failed to create config directory: %v", err))
+	// Create .config/tome/ and the project-local agent directories
+	created, err := attuneProject(cwd, resolveAgents(attuneAgents, false))
+	if err != nil {
+		exitWithError(err.Error())
 	}
-	fmt.Println(ui.Success.Render("  Created .config/tome/"))
-
-	// Determine which agent to set up for
-	agent := config.DefaultAgent()
-	agentCfg := config.GetAgentConfig(agent)
-
-	// Create project-local agent directories for artifact installation
-	agentDir := filepath.Join(cwd, agentCfg.ConfigDir)
-	skillsDir := filepath.Join(agentDir, agentCfg.SkillsDir)
-	commandsDir := filepath.Join(agentDir, agentCfg.CommandsDir)
-
-	if err := os.MkdirAll(skillsDir, 0755); err != nil {
-		exitWithError(fmt.Sprintf("failed to create skills directory: %v", err))
+	for _, dir := range created {
+		fmt.Println(ui.Success.Render(fmt.Sprintf("  Created %s/", filepath.ToSlash(dir))))
 	}
-	if err := os.MkdirAll(commandsDir, 0755); err != nil {
-		exitWithError(fmt.Sprintf("failed to create commands directory: %v", err))
+	if len(created) == 0 {
+		fmt.Println(ui.Info.Render("  Project directories already exist"))
 	}
-	fmt.Println(ui.Success.Render(fmt.Sprintf("  Created %s/skills/ and %s/commands/", agentCfg.ConfigDir, agentCfg.ConfigDir)))
+	configDir := filepath.Join(cwd, ".config", config.ConfigDir)
 
 	// Create .gitkeep in config dir
 	gitkeepPath := filepath.Join(configDir, ".gitkeep")
@@ -131,6 +174,60 @@ func runAttune(cmd *cobra.Command, args []string) {
 	fmt.Println()
 }
 
+// detachProject removes root's .config/tome directory and the agent
+// directories attune creates, when they're empty. Unless force is set, it
+// refuses when the project state still records installed artifacts.
+func detachProject(root string, force bool) error {
+	configDir := filepath.Join(root, ".config", config.ConfigDir)
+	if _, err := os.Stat(configDir); os.IsNotExist(err) {
+		return fmt.Errorf("%s isn't attuned", root)
+	}
+
+	if !force {
+		state, err := config.LoadState(filepath.Join(configDir, config.StateFile))
+		if err != nil {
+			return fmt.Errorf("failed to load project state: %w", err)
+		}
+		if n := len(state.Installed); n > 0 {
+			return fmt.Errorf("the project state records %d installed artifact(s); use --force to detach anyway", n)
+		}
+	}
+
+	if err := os.RemoveAll(configDir); err != nil {
+		return err
+	}
+	for _, agentCfg := range config.KnownAgents() {
+		if agentCfg.SkillsDir == "" {
+			continue
+		}
+		// Only empty directories go; commands first, as skills may hold them
+		for _, dir := range []string{agentCfg.CommandsDir, agentCfg.SkillsDir, ""} {
+			_ = os.Remove(filepath.Join(root, agentCfg.ConfigDir, dir))
+		}
+	}
+	return nil
+}
+
+func runDetach(cmd *cobra.Command, args []string) {
+	root := config.ProjectRoot()
+	if root == "" || config.ProjectConfigDir() == "" {
+		exitWithError("not attuned to this project")
+	}
+	if err := detachProject(root, detachForce); err != nil {
+		exitWithError(err.Error())
+	}
+
+	fmt.Println()
+	fmt.Println(ui.SuccessLine("Removed " + filepath.Join(root, ".config", config.ConfigDir)))
+	for _, agentCfg := range config.KnownAgents() {
+		if config.IsAttuned(agentCfg.Name) {
+			fmt.Println(ui.WarningLine(fmt.Sprintf("%s still holds artifacts, so 'tome learn' keeps installing there for %s",
+				filepath.Join(agentCfg.ConfigDir, agentCfg.SkillsDir), agentCfg.DisplayName)))
+		}
+	}
+	fmt.Println()
+}
+
 func createAgentsMd(path string) error {
 	content := getTomeAgentsContent()
 	return os.WriteFile(path, []byte(content), 0644)
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+)
+
+func TestAttuneAndDetach(t *testing.T) {
+	project := t.TempDir()
+	if err := os.Mkdir(filepath.Join(project, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(project)
+
+	agents := []config.Agent{config.AgentClaude, config.AgentCursor}
+	for _, agent := range agents {
+		if config.IsAttuned(agent) {
+			t.Errorf("%s attuned before attune", agent)
+		}
+	}
+
+	created, err := attuneProject(project, agents)
+	if err != nil {
+		t.Fatalf("attuneProject failed: %v", err)
+	}
+	want := []string{".config/tome", ".claude/skills", ".claude/commands", ".cursor/rules"}
+	var got []string
+	for _, dir := range created {
+		got = append(got, filepath.ToSlash(dir))
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("created %v, want %v", got, want)
+	}
+	for _, agent := range agents {
+		if !config.IsAttuned(agent) {
+			t.Errorf("%s not attuned after attune", agent)
+		}
+	}
+	if config.IsAttuned(config.AgentOpenCode) {
+		t.Error("opencode attuned without its directories")
+	}
+
+	// Attuning again creates nothing
+	if created, err := attuneProject(project, agents); err != nil || len(created) != 0 {
+		t.Errorf("second attune created %v (err %v), want nothing", created, err)
+	}
+
+	if err := detachProject(project, false); err != nil {
+		t.Fatalf("detachProject failed: %v", err)
+	}
+	for _, agent := range agents {
+		if config.IsAttuned(agent) {
+			t.Errorf("%s still attuned after detach", agent)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(project, ".claude")); !os.IsNotExist(err) {
+		t.Errorf("detach left the empty .claude directory: %v", err)
+	}
+	if err := detachProject(project, false); err == nil {
+		t.Error("expected detaching an unattuned project to fail")
+	}
+}
+
+func TestIsAttuned_AgentDirsWithoutConfig(t *testing.T) {
+	// Projects set up before .config/tome stay project-local
+	project := t.TempDir()
+	for _, dir := range []string{".git", filepath.Join(".claude", "skills", "pdf")} {
+		if err := os.MkdirAll(filepath.Join(project, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(project)
+
+	if !config.IsAttuned(config.AgentClaude) {
+		t.Error("project with .claude/skills isn't attuned")
+	}
+
+	// Detaching leaves agent directories that hold artifacts
+	if err := os.MkdirAll(filepath.Join(project, ".config", config.ConfigDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := detachProject(project, false); err != nil {
+		t.Fatalf("detachProject failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, ".claude", "skills", "pdf")); err != nil {
+		t.Errorf("detach removed an installed skill: %v", err)
+	}
+}
+
+func TestDetachProject_RefusesWithInstalledArtifacts(t *testing.T) {
+	project := t.TempDir()
+	if _, err := attuneProject(project, []config.Agent{config.AgentClaude}); err != nil {
+		t.Fatal(err)
+	}
+
+	state := &config.State{Version: "1"}
+	state.AddInstalled(artifact.InstalledArtifact{Artifact: artifact.Artifact{Name: "pdf", Type: artifact.TypeSkill}})
+	statePath := filepath.Join(project, ".config", config.ConfigDir, config.StateFile)
+	if err := config.SaveState(statePath, state); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := detachProject(project, false); err == nil {
+		t.Fatal("expected detach to refuse while artifacts are recorded")
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("refused detach removed state: %v", err)
+	}
+	if err := detachProject(project, true); err != nil {
+		t.Fatalf("forced detach failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(statePath)); !os.IsNotExist(err) {
+		t.Errorf("forced detach left %s", filepath.Dir(statePath))
+	}
+}
@@ -87,7 +87,7 @@ func runBuild(cmd *cobra.Command, args []string) {
 	if info, err := os.Stat(commandsDir); err == nil && info.IsDir() {
 		entries, _ := os.ReadDir(commandsDir)
 		for _, entry := range entries {
-			if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".md") {
+			if entry.IsDir() || !fetch.IsMarkdownFile(entry.Name()) {
 				continue
 			}
 
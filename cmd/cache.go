@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of fetched files",
+	Long: `Fetched files are cached under your user cache directory with their ETags,
+so learning or renewing an unchanged source revalidates each file instead of
+downloading it again. Pass --no-cache to any command to bypass the cache.
+
+Examples:
+  tome cache clear   # Delete every cached file`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete every cached file",
+	Args:  cobra.NoArgs,
+	Run:   runCacheClear,
+}
+
+// noCache bypasses the fetch cache for this run
+var noCache bool
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) {
+	dir, err := fetch.DefaultCacheDir()
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if err := fetch.NewCache(dir).Clear(); err != nil {
+		exitWithError(fmt.Sprintf("failed to clear cache: %v", err))
+	}
+	fmt.Println(ui.SuccessLine("Cleared " + dir))
+}
+
+// newFetchClient returns a fetch client that uses the on-disk cache unless
+// --no-cache was given or there's no user cache directory, and trusts the
+// CA bundle from --ca-bundle or $TOME_CA_BUNDLE. Its requests honor
+// --timeout and stop when the command is interrupted.
+func newFetchClient() *fetch.Client {
+	client := fetch.NewClient()
+	client.SetUserAgent("tome/" + Version)
+	client.SetContext(rootCmd.Context())
+	client.SetTimeout(fetchTimeout)
+	client.SetMaxFileSize(maxFileSize)
+	bundle := caBundle
+	if bundle == "" {
+		bundle = os.Getenv(fetch.CABundleEnv)
+	}
+	if bundle != "" {
+		if err := client.SetCABundle(bundle); err != nil {
+			exitWithError(err.Error())
+		}
+	}
+	if noCache {
+		return client
+	}
+	if dir, err := fetch.DefaultCacheDir(); err == nil {
+		client.SetCache(fetch.NewCache(dir))
+	}
+	return client
+}
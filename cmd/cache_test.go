@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/fetch"
+)
+
+func TestNewFetchClient_MaxFileSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Repeat("x", 100))
+	}))
+	defer srv.Close()
+	t.Cleanup(func() { maxFileSize, noCache = fetch.DefaultMaxFileSize, false })
+	noCache = true
+
+	maxFileSize = 64
+	if _, err := newFetchClient().FetchURL(srv.URL + "/SKILL.md"); !errors.Is(err, fetch.ErrFileTooLarge) {
+		t.Errorf("--max-file-size 64: error = %v, want ErrFileTooLarge", err)
+	}
+	maxFileSize = 0
+	if _, err := newFetchClient().FetchURL(srv.URL + "/SKILL.md"); err != nil {
+		t.Errorf("--max-file-size 0: error = %v, want no limit", err)
+	}
+}
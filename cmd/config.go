@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and set tome's defaults",
+	Long: `View and set defaults, saved in ~/.config/tome/config.json.
+
+Settings:
+  default-agent   Agent to install for when --agent isn't given
+  default-scope   Where learn installs in an attuned project: project or global
+  jobs            Artifacts learn fetches concurrently ($TOME_JOBS and --jobs win)
+
+Examples:
+  tome config list
+  tome config get default-agent
+  tome config set default-agent opencode
+  tome config set default-scope global   # learn --project still installs locally
+  tome config set jobs ""                # Back to the default`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:       "get <setting>",
+	Short:     "Print a setting's value",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: config.ConfigKeys,
+	Run:       runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:       "set <setting> <value>",
+	Short:     "Change a setting; an empty value unsets it",
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: config.ConfigKeys,
+	Run:       runConfigSet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print every setting",
+	Args:  cobra.NoArgs,
+	Run:   runConfigList,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) {
+	cfg, err := config.LoadUserConfig()
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	value, err := cfg.Get(args[0])
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if value != "" {
+		fmt.Fprintln(cmd.OutOrStdout(), value)
+	}
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) {
+	cfg, err := config.LoadUserConfig()
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	key, value := args[0], args[1]
+	if err := cfg.Set(key, value); err != nil {
+		exitWithError(err.Error())
+	}
+	if err := config.SaveUserConfig(cfg); err != nil {
+		exitWithError(fmt.Sprintf("failed to save config: %v", err))
+	}
+
+	if value == "" {
+		fmt.Fprintln(cmd.OutOrStdout(), ui.SuccessLine("Unset "+key))
+		return
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), ui.SuccessLine(fmt.Sprintf("Set %s to %s", key, value)))
+}
+
+func runConfigList(cmd *cobra.Command, args []string) {
+	cfg, err := config.LoadUserConfig()
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	for _, key := range config.ConfigKeys {
+		value, _ := cfg.Get(key)
+		if value == "" {
+			value = ui.Muted.Render("(not set)")
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s = %s\n", key, value)
+	}
+}
+
+// userConfig returns the defaults set with tome config. A file that can't be
+// read sets none; tome config reports why.
+func userConfig() *config.UserConfig {
+	cfg, err := config.LoadUserConfig()
+	if err != nil {
+		return &config.UserConfig{}
+	}
+	return cfg
+}
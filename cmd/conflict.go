@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/source"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+// conflictPolicy is what learn does when an artifact's name and type are
+// already taken by one learned from a different source
+type conflictPolicy string
+
+const (
+	conflictPrompt    conflictPolicy = "" // Ask on a terminal, otherwise skip
+	conflictRename    conflictPolicy = "rename"
+	conflictSkip      conflictPolicy = "skip"
+	conflictOverwrite conflictPolicy = "overwrite"
+)
+
+// stdinIsTerminal reports whether conflicts can be resolved by prompting
+func stdinIsTerminal() bool {
+	return term.IsTerminal(os.Stdin.Fd())
+}
+
+// parseConflictPolicy validates --on-conflict
+func parseConflictPolicy(value string) (conflictPolicy, error) {
+	switch p := conflictPolicy(strings.ToLower(value)); p {
+	case conflictPrompt, conflictRename, conflictSkip, conflictOverwrite:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid --on-conflict %q (want rename, skip or overwrite)", value)
+}
+
+// findConflict returns the installed artifact that art would replace in any
+// target's state if it comes from a different source, and the names already
+// taken by artifacts of art's type
+func findConflict(art *artifact.Artifact, targets []*config.Paths) (*artifact.InstalledArtifact, map[string]bool) {
+	var conflict *artifact.InstalledArtifact
+	taken := make(map[string]bool)
+	loaded := make(map[string]bool)
+
+	for _, target := range targets {
+		if loaded[target.StateFile] {
+			continue
+		}
+		loaded[target.StateFile] = true
+
+		state, err := config.LoadState(target.StateFile)
+		if err != nil {
+			continue // The install itself reports unreadable state
+		}
+		for i, existing := range state.Installed {
+			if existing.Type != art.Type {
+				continue
+			}
+			taken[existing.Name] = true
+			if conflict == nil && existing.Name == art.Name && installedForAny(existing, targets) && !sameSource(existing.Source, art.Source) {
+				conflict = &state.Installed[i]
+			}
+		}
+	}
+	return conflict, taken
+}
+
+// installedForAny reports whether an entry belongs to one of the target
+// agents; entries recorded before agents were tracked belong to all of them
+func installedForAny(a artifact.InstalledArtifact, targets []*config.Paths) bool {
+	if a.Agent == "" {
+		return true
+	}
+	for _, target := range targets {
+		if a.Agent == string(target.Agent) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSource reports whether two recorded sources are the same: identical,
+// or pointing into the same repository
+func sameSource(a, b string) bool {
+	if a == b {
+		return true
+	}
+	srcA, errA := source.Parse(a)
+	srcB, errB := source.Parse(b)
+	return errA == nil && errB == nil && srcA.SameRepo(srcB)
+}
+
+// conflictName suffixes an artifact's name with its source owner (or a
+// counter when there's no owner or that name is taken too)
+func conflictName(art *artifact.Artifact, taken map[string]bool) string {
+	base := art.Name
+	if owner := pathTemplateOwner(art); owner != "" {
+		base = art.Name + "-" + artifact.SanitizeFilename(owner)
+		if !taken[base] {
+			return base
+		}
+	}
+	for n := 2; ; n++ {
+		if name := fmt.Sprintf("%s-%d", base, n); !taken[name] {
+			return name
+		}
+	}
+}
+
+// renameArtifact installs art under name, rewriting the name its
+// frontmatter declares too so the agent doesn't see the old one
+func renameArtifact(art *artifact.Artifact, name string) {
+	art.Name = name
+	art.Content = fetch.RenameInFrontmatter(art.Content, name)
+}
+
+// resolveConflict applies the conflict policy when art collides with an
+// artifact from another source, renaming art in place if needed. Returns
+// false when art should be skipped.
+func resolveConflict(art *artifact.Artifact, targets []*config.Paths, policy conflictPolicy) bool {
+	existing, taken := findConflict(art, targets)
+	if existing == nil {
+		return true
+	}
+
+	if policy == conflictPrompt {
+		if !stdinIsTerminal() {
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipping %s: already inscribed from %s (use --on-conflict to rename or overwrite)", art.Name, existing.Source)))
+			return false
+		}
+		policy = promptConflict(art, existing)
+	}
+
+	switch policy {
+	case conflictRename:
+		newName := conflictName(art, taken)
+		fmt.Fprintln(learnOutput(), ui.Info.Render(fmt.Sprintf("  %s is already inscribed from %s; inscribing as %s", art.Name, existing.Source, newName)))
+		renameArtifact(art, newName)
+		return true
+	case conflictOverwrite:
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Overwriting %s from %s", art.Name, existing.Source)))
+		return true
+	default:
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipping %s: already inscribed from %s", art.Name, existing.Source)))
+		return false
+	}
+}
+
+// promptConflict asks how to resolve a conflict, defaulting to skip
+func promptConflict(art *artifact.Artifact, existing *artifact.InstalledArtifact) conflictPolicy {
+	fmt.Fprintf(learnOutput(), "  %s %s is already inscribed from %s. [r]ename, [s]kip or [o]verwrite? [s] ",
+		art.Type, ui.Highlight.Render(art.Name), existing.Source)
+	answer, _ := promptReader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "r", "rename":
+		return conflictRename
+	case "o", "overwrite":
+		return conflictOverwrite
+	default:
+		return conflictSkip
+	}
+}
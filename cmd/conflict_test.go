@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+)
+
+func TestDoInstallWithExtraReqs_ConflictPolicies(t *testing.T) {
+	tests := []struct {
+		policy     conflictPolicy
+		wantOK     bool
+		wantName   string
+		wantSource string // Source recorded for review-pr afterwards
+	}{
+		{conflictRename, true, "review-pr-other", "acme/tools"},
+		{conflictSkip, false, "review-pr", "acme/tools"},
+		{conflictOverwrite, true, "review-pr", "other/tools"},
+		{conflictPrompt, false, "review-pr", "acme/tools"}, // Not interactive: skip
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			paths, err := config.GetPaths()
+			if err != nil {
+				t.Fatal(err)
+			}
+			learnConflictPolicy = tt.policy
+			t.Cleanup(func() { learnConflictPolicy = conflictPrompt })
+
+			first := &artifact.Artifact{Name: "review-pr", Type: artifact.TypeCommand, Source: "acme/tools", Content: "# acme\n"}
+			if _, ok := doInstallWithExtraReqs(first, paths, nil, nil); !ok {
+				t.Fatal("first install skipped")
+			}
+
+			// Re-learning from the same repo is an update, not a conflict
+			again := &artifact.Artifact{Name: "review-pr", Type: artifact.TypeCommand, Source: "acme/tools:commands", Content: "# acme\n"}
+			if !resolveConflict(again, []*config.Paths{paths}, conflictSkip) {
+				t.Fatal("same-source install treated as a conflict")
+			}
+
+			second := &artifact.Artifact{Name: "review-pr", Type: artifact.TypeCommand, Source: "other/tools", Content: "# other\n"}
+			_, ok := doInstallWithExtraReqs(second, paths, nil, nil)
+			if ok != tt.wantOK {
+				t.Fatalf("installed = %v, want %v", ok, tt.wantOK)
+			}
+			if second.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", second.Name, tt.wantName)
+			}
+
+			state, err := config.LoadState(paths.StateFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a := state.FindInstalled("review-pr"); a == nil || a.Source != tt.wantSource {
+				t.Errorf("review-pr = %+v, want source %s", a, tt.wantSource)
+			}
+
+			content, err := os.ReadFile(filepath.Join(paths.CommandsDir, "review-pr.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantContent := "# acme\n"
+			if tt.policy == conflictOverwrite {
+				wantContent = "# other\n"
+			}
+			if string(content) != wantContent {
+				t.Errorf("review-pr.md = %q, want %q", content, wantContent)
+			}
+
+			if tt.policy == conflictRename {
+				renamed := state.FindInstalled("review-pr-other")
+				if renamed == nil || renamed.LocalPath != filepath.Join(paths.CommandsDir, "review-pr-other.md") {
+					t.Errorf("renamed entry = %+v", renamed)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveConflict_RenamesFrontmatter(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := &artifact.Artifact{Name: "pdf", Type: artifact.TypeSkill, Source: "acme/skills", Content: "---\nname: pdf\n---\n# acme\n"}
+	if _, ok := doInstallWithExtraReqs(first, paths, nil, nil); !ok {
+		t.Fatal("first install skipped")
+	}
+
+	second := &artifact.Artifact{Name: "pdf", Type: artifact.TypeSkill, Source: "other/skills", Content: "---\nname: pdf\n---\n# other\n"}
+	if !resolveConflict(second, []*config.Paths{paths}, conflictRename) {
+		t.Fatal("renaming conflict skipped")
+	}
+	if second.Name != "pdf-other" || second.Content != "---\nname: pdf-other\n---\n# other\n" {
+		t.Errorf("renamed to %q with content %q, want pdf-other in its frontmatter too", second.Name, second.Content)
+	}
+}
+
+func TestConflictName(t *testing.T) {
+	taken := map[string]bool{"commit": true, "commit-acme": true, "commit-acme-2": true}
+	if got := conflictName(&artifact.Artifact{Name: "commit", Source: "acme/tools"}, taken); got != "commit-acme-3" {
+		t.Errorf("conflictName with taken owner suffix = %q, want commit-acme-3", got)
+	}
+	if got := conflictName(&artifact.Artifact{Name: "commit", Source: "bob/tools"}, taken); got != "commit-bob" {
+		t.Errorf("conflictName = %q, want commit-bob", got)
+	}
+	if got := conflictName(&artifact.Artifact{Name: "commit", Source: "https://example.com/commit.md"}, taken); got != "commit-2" {
+		t.Errorf("conflictName without owner = %q, want commit-2", got)
+	}
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	for _, value := range []string{"", "rename", "SKIP", "overwrite"} {
+		if _, err := parseConflictPolicy(value); err != nil {
+			t.Errorf("parseConflictPolicy(%q): %v", value, err)
+		}
+	}
+	if _, err := parseConflictPolicy("merge"); err == nil {
+		t.Error("parseConflictPolicy(merge) succeeded, want error")
+	}
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var diffCmd = &cobra.Command{
+	Use:     "diff <name>",
+	Aliases: []string{"collate", "compare"},
+	Short:   "Compare an inscription against its source",
+	Long: `Show what renewing an artifact would change.
+
+Re-fetches the artifact from its source, builds what 'tome renew' would
+write, and prints a unified diff against the installed file. Lines marked
+- are only in your installed copy (local edits renew would overwrite);
+lines marked + are new in the source.
+
+Examples:
+  tome diff commit
+  tome collate my-skill`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDiff,
+}
+
+// diffContext is how many unchanged lines surround each hunk
+const diffContext = 3
+
+func runDiff(cmd *cobra.Command, args []string) {
+	paths, err := config.GetPaths()
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	a, err := state.LookupInstalled(args[0])
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	local, err := os.ReadFile(a.LocalPath)
+	if err != nil {
+		exitWithError(fmt.Sprintf("cannot read installed file: %v", err))
+	}
+
+	_, remote, err := fetchFromSource(newFetchClient(), a, paths)
+	if err != nil {
+		exitWithError(fmt.Sprintf("cannot compare %s: %v", a.Name, err))
+	}
+
+	fmt.Println()
+	fmt.Printf("  %s %s\n", getBadge(a.Type), ui.Highlight.Render(a.Name))
+	fmt.Println()
+
+	hunks := diffLines(splitLines(string(local)), splitLines(remote), diffContext)
+	if len(hunks) == 0 {
+		fmt.Println(ui.SuccessLine("Up to date with " + a.Source))
+		fmt.Println(ui.PageFooter())
+		return
+	}
+
+	fmt.Println(ui.Error.Render("--- installed  " + a.LocalPath))
+	fmt.Println(ui.Success.Render("+++ source     " + a.Source))
+	for _, h := range hunks {
+		fmt.Println(ui.Info.Render(h.header()))
+		for _, line := range h.lines {
+			switch line.op {
+			case diffDelete:
+				fmt.Println(ui.Error.Render("-" + line.text))
+			case diffInsert:
+				fmt.Println(ui.Success.Render("+" + line.text))
+			default:
+				fmt.Println(ui.Muted.Render(" " + line.text))
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Muted.Render(fmt.Sprintf("  Apply with: tome renew %s", a.Name)))
+	fmt.Println(ui.PageFooter())
+}
+
+// diffOp is the kind of change a diff line represents
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// diffHunk is a run of changes with surrounding context; starts are 1-based
+type diffHunk struct {
+	oldStart, oldLines int
+	newStart, newLines int
+	lines              []diffLine
+}
+
+func (h diffHunk) header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.oldStart, h.oldLines, h.newStart, h.newLines)
+}
+
+// splitLines splits text into lines, ignoring a trailing newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes a line diff of a and b from their longest common
+// subsequence and groups it into unified-diff hunks with the given context.
+// Returns nil when a and b are identical.
+func diffLines(a, b []string, context int) []diffHunk {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffLine{diffEqual, a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, diffLine{diffInsert, b[j]})
+			j++
+		default:
+			ops = append(ops, diffLine{diffDelete, a[i]})
+			i++
+		}
+	}
+
+	// Group changes into hunks, merging ones whose context would overlap
+	var hunks []diffHunk
+	oldLine, newLine := 1, 1
+	for k := 0; k < len(ops); {
+		if ops[k].op == diffEqual {
+			oldLine++
+			newLine++
+			k++
+			continue
+		}
+
+		start := max(k-context, 0)
+		h := diffHunk{oldStart: oldLine - (k - start), newStart: newLine - (k - start)}
+		end := k
+		for end < len(ops) {
+			if ops[end].op != diffEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].op == diffEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+			end = run
+		}
+
+		h.lines = ops[start:end]
+		for _, line := range h.lines {
+			if line.op != diffInsert {
+				h.oldLines++
+			}
+			if line.op != diffDelete {
+				h.newLines++
+			}
+		}
+		// An empty side starts at the line before, as in diff -u
+		if h.oldLines == 0 {
+			h.oldStart--
+		}
+		if h.newLines == 0 {
+			h.newStart--
+		}
+		hunks = append(hunks, h)
+
+		for _, line := range ops[k:end] {
+			if line.op != diffInsert {
+				oldLine++
+			}
+			if line.op != diffDelete {
+				newLine++
+			}
+		}
+		k = end
+	}
+
+	return hunks
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// renderHunks formats hunks as plain unified diff text
+func renderHunks(hunks []diffHunk) string {
+	var b strings.Builder
+	for _, h := range hunks {
+		b.WriteString(h.header() + "\n")
+		for _, line := range h.lines {
+			prefix := " "
+			switch line.op {
+			case diffDelete:
+				prefix = "-"
+			case diffInsert:
+				prefix = "+"
+			}
+			b.WriteString(prefix + line.text + "\n")
+		}
+	}
+	return b.String()
+}
+
+func TestDiffLines(t *testing.T) {
+	numbered := func(n int) []string {
+		lines := make([]string, n)
+		for i := range lines {
+			lines[i] = fmt.Sprintf("line %d", i+1)
+		}
+		return lines
+	}
+
+	tests := []struct {
+		name string
+		a, b []string
+		want string
+	}{
+		{
+			name: "identical",
+			a:    []string{"# Title", "body"},
+			b:    []string{"# Title", "body"},
+			want: "",
+		},
+		{
+			name: "changed line",
+			a:    []string{"# Title", "old", "end"},
+			b:    []string{"# Title", "new", "end"},
+			want: "@@ -1,3 +1,3 @@\n # Title\n-old\n+new\n end\n",
+		},
+		{
+			name: "from empty",
+			a:    nil,
+			b:    []string{"a", "b"},
+			want: "@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "separate hunks",
+			a:    numbered(20),
+			b: func() []string {
+				b := numbered(20)
+				b[1] = "changed 2"
+				b = append(b[:15], b[16:]...) // drop line 16
+				return b
+			}(),
+			want: "@@ -1,5 +1,5 @@\n line 1\n-line 2\n+changed 2\n line 3\n line 4\n line 5\n" +
+				"@@ -13,7 +13,6 @@\n line 13\n line 14\n line 15\n-line 16\n line 17\n line 18\n line 19\n",
+		},
+		{
+			name: "nearby changes merge",
+			a:    numbered(8),
+			b:    []string{"line 1", "x", "line 3", "line 4", "line 5", "line 6", "y", "line 8"},
+			want: "@@ -1,8 +1,8 @@\n line 1\n-line 2\n+x\n line 3\n line 4\n line 5\n line 6\n-line 7\n+y\n line 8\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderHunks(diffLines(tt.a, tt.b, diffContext)); got != tt.want {
+				t.Errorf("diffLines() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	if got := splitLines(""); got != nil {
+		t.Errorf("splitLines(\"\") = %q, want nil", got)
+	}
+	if got := splitLines("a\nb\n"); len(got) != 2 || got[1] != "b" {
+		t.Errorf("splitLines() = %q, want [a b]", got)
+	}
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/kennyg/tome/internal/artifact"
 	"github.com/kennyg/tome/internal/config"
 	"github.com/kennyg/tome/internal/detect"
 	"github.com/kennyg/tome/internal/ui"
@@ -13,19 +20,68 @@ import (
 var doctorCmd = &cobra.Command{
 	Use:   "doctor [name]",
 	Short: "Check setup requirements for artifacts",
-	Long: `Verify that detected setup requirements are satisfied.
+	Long: `Verify that detected setup requirements are satisfied and that
+skill include files are present on disk.
 
-If no artifact name is given, checks all artifacts with requirements.
+If no artifact name is given, checks all artifacts with requirements or includes.
 If a name is given, checks only that artifact.
 
+With --fix, unsatisfied packages (npm, pip, brew, cargo, editor extensions)
+are installed using the package manager from the skill's instructions.
+Each command is printed and confirmed before it runs; --yes skips the
+prompts. Requirements tome can't install (environment variables, runtimes,
+commands) are reported for you to handle. Exits non-zero if any fix fails.
+Add --dry-run to print the install commands without running them.
+
+With --json, prints each artifact's verified requirements and missing
+includes instead (a single object when a name is given, else a list) and
+exits non-zero if anything is unsatisfied.
+
 Examples:
   tome doctor                    # Check all artifacts
-  tome doctor open-orchestra     # Check specific artifact`,
+  tome doctor open-orchestra     # Check specific artifact
+  tome doctor --fix --dry-run    # Preview install commands
+  tome doctor commit --json      # Health report for agents and CI
+  tome doctor open-orchestra --fix --yes`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runDoctor,
 }
 
+var (
+	doctorFix    bool
+	doctorDryRun bool
+	doctorYes    bool
+	doctorJSON   bool
+)
+
+// doctorReport is the --json health report for one artifact
+type doctorReport struct {
+	Name            string                `json:"name"`
+	Satisfied       bool                  `json:"satisfied"`
+	Results         []detect.VerifyResult `json:"results"`
+	MissingIncludes []string              `json:"missing_includes,omitempty"`
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Install unsatisfied package requirements")
+	doctorCmd.Flags().BoolVar(&doctorDryRun, "dry-run", false, "With --fix, print install commands without running them")
+	doctorCmd.Flags().BoolVarP(&doctorYes, "yes", "y", false, "With --fix, run install commands without prompting")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output the health report as JSON")
+}
+
 func runDoctor(cmd *cobra.Command, args []string) {
+	if doctorDryRun && !doctorFix {
+		exitWithError("--dry-run requires --fix")
+	}
+	if doctorYes && !doctorFix {
+		exitWithError("--yes requires --fix")
+	}
+	if doctorJSON && doctorFix {
+		exitWithError("--json can't be combined with --fix")
+	}
+
+	var fixFailures int
+
 	paths, err := config.GetPaths()
 	if err != nil {
 		exitWithError(err.Error())
@@ -36,6 +92,17 @@ func runDoctor(cmd *cobra.Command, args []string) {
 		exitWithError(err.Error())
 	}
 
+	// Apply current .tomeignore patterns on top of what was recorded at install
+	ignores := ignoreFilePatterns(paths)
+	for i := range state.Installed {
+		state.Installed[i].Requirements = detect.ApplyIgnores(state.Installed[i].Requirements, ignores)
+	}
+
+	if doctorJSON {
+		printDoctorJSON(state, args)
+		return
+	}
+
 	fmt.Println()
 	fmt.Println(ui.SectionHeader("Diagnosing", 56))
 	fmt.Println()
@@ -43,26 +110,27 @@ func runDoctor(cmd *cobra.Command, args []string) {
 	if len(args) == 1 {
 		// Check specific artifact
 		name := args[0]
-		artifact := state.FindInstalled(name)
-		if artifact == nil {
-			exitWithError(fmt.Sprintf("artifact '%s' not found", name))
+		art, err := state.LookupInstalled(name)
+		if err != nil {
+			exitWithError(err.Error())
 		}
 
-		if len(artifact.Requirements) == 0 {
-			fmt.Printf("  %s %s\n", ui.Success.Render("✓"), artifact.Name)
+		if len(art.Requirements) == 0 && len(skillIncludes(art)) == 0 {
+			fmt.Printf("  %s %s\n", ui.Success.Render("✓"), art.Name)
 			fmt.Println(ui.Muted.Render("    No setup requirements detected"))
 			fmt.Println(ui.PageFooter())
 			return
 		}
 
-		checkArtifact(artifact.Name, artifact.Requirements, true)
+		fixFailures += checkArtifact(art, true)
 	} else {
-		// Check all artifacts with requirements
+		// Check all artifacts with requirements or includes
 		hasAny := false
-		for _, artifact := range state.Installed {
-			if len(artifact.Requirements) > 0 {
+		for i := range state.Installed {
+			art := &state.Installed[i]
+			if len(detect.Active(art.Requirements)) > 0 || len(skillIncludes(art)) > 0 {
 				hasAny = true
-				checkArtifact(artifact.Name, artifact.Requirements, false)
+				fixFailures += checkArtifact(art, false)
 				fmt.Println()
 			}
 		}
@@ -73,11 +141,86 @@ func runDoctor(cmd *cobra.Command, args []string) {
 	}
 
 	fmt.Println(ui.PageFooter())
+
+	if fixFailures > 0 {
+		fmt.Fprintln(os.Stderr, ui.Error.Render(fmt.Sprintf("Error: %d fix(es) failed", fixFailures)))
+		os.Exit(1)
+	}
+}
+
+// withProjectPackageManager returns a copy of reqs in which npm packages the
+// instructions didn't name a package manager for use the one the current
+// directory's lockfile belongs to, so fixes match the user's project
+func withProjectPackageManager(reqs []detect.Requirement) []detect.Requirement {
+	pm := detect.ProjectPackageManager(".")
+	if pm == "" {
+		return reqs
+	}
+	result := make([]detect.Requirement, len(reqs))
+	for i, req := range reqs {
+		if req.Type == detect.TypeNPM && req.PackageManager == "" {
+			req.PackageManager = pm
+		}
+		result[i] = req
+	}
+	return result
+}
+
+// newDoctorReport verifies an artifact's requirements and includes
+func newDoctorReport(art *artifact.InstalledArtifact) doctorReport {
+	results := detect.VerifyAll(withProjectPackageManager(art.Requirements))
+	missing := missingIncludes(art)
+	return doctorReport{
+		Name:            art.Name,
+		Satisfied:       !detect.HasUnsatisfied(results) && len(missing) == 0,
+		Results:         results,
+		MissingIncludes: missing,
+	}
 }
 
-func checkArtifact(name string, reqs []detect.Requirement, verbose bool) {
-	results := detect.VerifyAll(reqs)
-	allSatisfied := !detect.HasUnsatisfied(results)
+// printDoctorJSON writes the health report for the named artifact, or for
+// every artifact with requirements or includes, and exits non-zero if any
+// is unsatisfied
+func printDoctorJSON(state *config.State, args []string) {
+	var out any
+	reports := []doctorReport{}
+	if len(args) == 1 {
+		art, err := state.LookupInstalled(args[0])
+		if err != nil {
+			exitWithError(err.Error())
+		}
+		reports = append(reports, newDoctorReport(art))
+		out = reports[0]
+	} else {
+		for i := range state.Installed {
+			art := &state.Installed[i]
+			if len(detect.Active(art.Requirements)) > 0 || len(skillIncludes(art)) > 0 {
+				reports = append(reports, newDoctorReport(art))
+			}
+		}
+		out = reports
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		exitWithError(fmt.Sprintf("failed to encode report: %v", err))
+	}
+	fmt.Println(string(data))
+
+	for _, r := range reports {
+		if !r.Satisfied {
+			os.Exit(1)
+		}
+	}
+}
+
+// checkArtifact reports on an artifact's requirements and includes, applying
+// fixes with --fix. Returns the number of fixes that failed.
+func checkArtifact(art *artifact.InstalledArtifact, verbose bool) int {
+	name := art.Name
+	results := detect.VerifyAll(withProjectPackageManager(art.Requirements))
+	missing := missingIncludes(art)
+	allSatisfied := !detect.HasUnsatisfied(results) && len(missing) == 0
 
 	if allSatisfied {
 		fmt.Printf("  %s %s\n", ui.Success.Render("✓"), name)
@@ -94,17 +237,144 @@ func checkArtifact(name string, reqs []detect.Requirement, verbose bool) {
 				fmt.Printf("    %s %s: %s\n",
 					ui.Success.Render("✓"),
 					r.Requirement.Type,
-					r.Requirement.Value)
+					r.Requirement.Label())
 			}
 		} else {
 			fmt.Printf("    %s %s: %s\n",
 				ui.Error.Render("✗"),
 				r.Requirement.Type,
-				r.Requirement.Value)
+				r.Requirement.Label())
 			if r.Message != "" {
 				// Indent multi-line messages
 				fmt.Println(ui.Muted.Render("      " + r.Message))
 			}
 		}
 	}
+	if ignored := len(art.Requirements) - len(detect.Active(art.Requirements)); verbose && ignored > 0 {
+		fmt.Println(ui.Muted.Render(fmt.Sprintf("    %d requirement(s) ignored", ignored)))
+	}
+	if includes := skillIncludes(art); verbose && len(includes) > 0 && len(missing) == 0 {
+		fmt.Printf("    %s includes: %d files present\n",
+			ui.Success.Render("✓"),
+			len(includes))
+	}
+	for _, inc := range missing {
+		fmt.Printf("    %s include: %s\n",
+			ui.Error.Render("✗"),
+			inc)
+	}
+	if len(missing) > 0 {
+		fmt.Println(ui.Muted.Render(fmt.Sprintf("      Run: tome update %s", name)))
+	}
+
+	if doctorFix {
+		return fixRequirements(results, doctorDryRun, doctorYes)
+	}
+	return 0
+}
+
+// promptReader is shared by confirmation prompts so buffered input isn't lost between them
+var promptReader = bufio.NewReader(os.Stdin)
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N] ", prompt)
+	answer, _ := promptReader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// fixRequirements installs unsatisfied requirements, or prints the commands on a dry run.
+// Returns the number of install commands that failed.
+func fixRequirements(results []detect.VerifyResult, dryRun, yes bool) int {
+	failed := 0
+	for _, r := range results {
+		if r.Satisfied {
+			continue
+		}
+		argv := detect.InstallCommand(r.Requirement)
+		if argv == nil {
+			// Nothing tome can install (commands, runtimes, env vars)
+			fmt.Printf("    %s %s\n", ui.Warning.Render("manual:"), manualFixHint(r.Requirement))
+			continue
+		}
+		cmdLine := strings.Join(argv, " ")
+
+		if dryRun {
+			fmt.Printf("    %s %s\n", ui.Muted.Render("would run:"), cmdLine)
+			continue
+		}
+
+		if !yes && !confirm(fmt.Sprintf("    Run %s?", cmdLine)) {
+			fmt.Printf("    %s skipped %s\n", ui.Muted.Render("-"), cmdLine)
+			continue
+		}
+		fmt.Printf("    %s %s\n", ui.Info.Render("running:"), cmdLine)
+
+		c := exec.Command(argv[0], argv[1:]...)
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			fmt.Printf("    %s %s: %v\n", ui.Error.Render("✗"), cmdLine, err)
+			failed++
+			continue
+		}
+		fmt.Printf("    %s %s\n", ui.Success.Render("✓"), cmdLine)
+	}
+	return failed
+}
+
+// manualFixHint describes how to satisfy a requirement tome can't install itself
+func manualFixHint(req detect.Requirement) string {
+	switch req.Type {
+	case detect.TypeEnv:
+		return fmt.Sprintf("set $%s in your environment", req.Value)
+	case detect.TypeRuntime:
+		return fmt.Sprintf("install the %s runtime", req.Value)
+	default:
+		return fmt.Sprintf("install %s so it is on your PATH", req.Value)
+	}
+}
+
+// skillIncludes returns the files a skill expects next to its SKILL.md: the
+// includes recorded at install, and those its requirements were detected in
+// ("include:" sources). A flattened skill inlined its text includes, so only
+// the recorded ones were written.
+func skillIncludes(art *artifact.InstalledArtifact) []string {
+	includes := append([]string(nil), art.Includes...)
+	if art.Type != artifact.TypeSkill || art.Flattened {
+		return includes
+	}
+
+	seen := make(map[string]bool, len(includes))
+	for _, inc := range includes {
+		seen[inc] = true
+	}
+	for _, req := range art.Requirements {
+		inc, ok := strings.CutPrefix(req.Source, "include:")
+		if !ok || seen[inc] || !filepath.IsLocal(filepath.FromSlash(inc)) {
+			continue
+		}
+		seen[inc] = true
+		includes = append(includes, inc)
+	}
+	return includes
+}
+
+// missingIncludes returns a skill's include files that are absent or empty on disk
+func missingIncludes(art *artifact.InstalledArtifact) []string {
+	includes := skillIncludes(art)
+	if len(includes) == 0 {
+		return nil
+	}
+
+	skillDir := filepath.Dir(art.LocalPath)
+	var missing []string
+	for _, inc := range includes {
+		info, err := os.Stat(filepath.Join(skillDir, filepath.FromSlash(inc)))
+		if err != nil || info.IsDir() || info.Size() == 0 {
+			missing = append(missing, inc)
+		}
+	}
+	return missing
 }
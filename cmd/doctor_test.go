@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/detect"
+)
+
+func TestNewDoctorReport(t *testing.T) {
+	t.Setenv("TOME_TEST_SET", "1")
+
+	a := &artifact.InstalledArtifact{
+		Artifact: artifact.Artifact{Name: "deploy", Type: artifact.TypeCommand},
+		Requirements: []detect.Requirement{
+			{Type: detect.TypeEnv, Value: "TOME_TEST_SET"},
+			{Type: detect.TypeEnv, Value: "TOME_TEST_UNSET"},
+			{Type: detect.TypeEnv, Value: "TOME_TEST_IGNORED", Ignored: true},
+		},
+	}
+
+	data, err := json.Marshal(newDoctorReport(a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Name      string `json:"name"`
+		Satisfied bool   `json:"satisfied"`
+		Results   []struct {
+			Requirement struct {
+				Value string `json:"value"`
+			} `json:"requirement"`
+			Satisfied bool   `json:"satisfied"`
+			Message   string `json:"message"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Name != "deploy" || decoded.Satisfied {
+		t.Errorf("report = %s, want deploy unsatisfied", data)
+	}
+	want := map[string]bool{"TOME_TEST_SET": true, "TOME_TEST_UNSET": false}
+	if len(decoded.Results) != len(want) {
+		t.Fatalf("results = %s, want the two not ignored", data)
+	}
+	for _, r := range decoded.Results {
+		if r.Satisfied != want[r.Requirement.Value] {
+			t.Errorf("%s satisfied = %v, want %v", r.Requirement.Value, r.Satisfied, want[r.Requirement.Value])
+		}
+		if !r.Satisfied && r.Message == "" {
+			t.Errorf("%s has no message", r.Requirement.Value)
+		}
+	}
+
+	a.Requirements = a.Requirements[:1]
+	if report := newDoctorReport(a); !report.Satisfied {
+		t.Errorf("report = %+v, want satisfied", report)
+	}
+}
+
+func TestNewDoctorReport_MissingSkillIncludes(t *testing.T) {
+	skillDir := filepath.Join(t.TempDir(), "deploy")
+	for _, rel := range []string{"SKILL.md", "scripts/deploy.sh", "scripts/check.py"} {
+		path := filepath.Join(skillDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("# "+rel+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := &artifact.InstalledArtifact{
+		Artifact:  artifact.Artifact{Name: "deploy", Type: artifact.TypeSkill, Includes: []string{"scripts/deploy.sh"}},
+		LocalPath: filepath.Join(skillDir, "SKILL.md"),
+		Requirements: []detect.Requirement{
+			{Type: detect.TypeRuntime, Value: "go", Source: "include:scripts/check.py"},
+			{Type: detect.TypeRuntime, Value: "go", Source: "include:../outside.py"},
+		},
+	}
+	if missing := newDoctorReport(a).MissingIncludes; len(missing) != 0 {
+		t.Fatalf("missing = %v before anything was deleted", missing)
+	}
+
+	// A failed discovery leaves a requirement's include behind on disk
+	if err := os.Remove(filepath.Join(skillDir, "scripts", "check.py")); err != nil {
+		t.Fatal(err)
+	}
+	report := newDoctorReport(a)
+	if report.Satisfied {
+		t.Error("report satisfied with an include missing")
+	}
+	if strings.Join(report.MissingIncludes, ",") != "scripts/check.py" {
+		t.Errorf("missing = %v, want scripts/check.py", report.MissingIncludes)
+	}
+
+	// Flattening inlined the skill's text includes, so they're not on disk
+	a.Flattened = true
+	if missing := newDoctorReport(a).MissingIncludes; len(missing) != 0 {
+		t.Errorf("flattened skill missing = %v, want none", missing)
+	}
+}
+
+func TestMissingIncludes(t *testing.T) {
+	skillDir := filepath.Join(t.TempDir(), "pdf")
+	files := map[string]string{"SKILL.md": "# PDF\n", "scripts/fill.py": "print()\n", "empty.txt": ""}
+	for rel, content := range files {
+		path := filepath.Join(skillDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := &artifact.InstalledArtifact{
+		Artifact:  artifact.Artifact{Name: "pdf", Type: artifact.TypeSkill, Includes: []string{"scripts/fill.py", "empty.txt", "scripts", "gone.md"}},
+		LocalPath: filepath.Join(skillDir, "SKILL.md"),
+	}
+	// Empty files and directories don't count as present
+	if got := strings.Join(missingIncludes(a), ","); got != "empty.txt,scripts,gone.md" {
+		t.Errorf("missingIncludes() = %s, want empty.txt,scripts,gone.md", got)
+	}
+
+	a.Includes = nil
+	if got := missingIncludes(a); got != nil {
+		t.Errorf("missingIncludes() without includes = %v, want nil", got)
+	}
+}
+
+func TestCheckArtifact(t *testing.T) {
+	t.Cleanup(func() { doctorFix = false })
+	a := &artifact.InstalledArtifact{
+		Artifact:  artifact.Artifact{Name: "pdf", Type: artifact.TypeSkill, Includes: []string{"gone.md"}},
+		LocalPath: filepath.Join(t.TempDir(), "pdf", "SKILL.md"),
+	}
+	// A missing include is reported, but isn't a failed fix
+	if failed := checkArtifact(a, true); failed != 0 {
+		t.Errorf("checkArtifact() = %d failed fixes, want 0", failed)
+	}
+}
+
+func TestInstalledArtifact_IncludesRoundTrip(t *testing.T) {
+	a := artifact.InstalledArtifact{Artifact: artifact.Artifact{Name: "pdf", Includes: []string{"scripts/fill.py"}}}
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded artifact.InstalledArtifact
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(decoded.Includes, ",") != "scripts/fill.py" {
+		t.Errorf("includes after a round trip = %v (JSON %s)", decoded.Includes, data)
+	}
+}
+
+func TestWithProjectPackageManager(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"pnpm-lock.yaml": ""})
+	t.Chdir(dir)
+
+	reqs := []detect.Requirement{
+		{Type: detect.TypeNPM, Value: "prettier"},
+		{Type: detect.TypeNPM, Value: "vercel", PackageManager: detect.PMbun},
+		{Type: detect.TypePip, Value: "requests"},
+	}
+	got := withProjectPackageManager(reqs)
+	if got[0].PackageManager != detect.PMpnpm || got[1].PackageManager != detect.PMbun || got[2].PackageManager != "" {
+		t.Errorf("package managers = %q, %q, %q; want pnpm for the unrecorded npm package only",
+			got[0].PackageManager, got[1].PackageManager, got[2].PackageManager)
+	}
+	if reqs[0].PackageManager != "" {
+		t.Error("withProjectPackageManager changed the requirements it was given")
+	}
+	if cmd := detect.InstallCommand(got[0]); strings.Join(cmd, " ") != "pnpm add prettier" {
+		t.Errorf("InstallCommand() = %q, want pnpm add prettier", cmd)
+	}
+}
+
+func TestFixRequirements(t *testing.T) {
+	// Stand-in package managers that log their arguments; cargo always fails
+	bin := t.TempDir()
+	log := filepath.Join(bin, "log")
+	writeFiles(t, bin, map[string]string{
+		"npm":   "#!/bin/sh\necho \"npm $*\" >> " + log + "\n",
+		"cargo": "#!/bin/sh\nexit 1\n",
+	})
+	for _, name := range []string{"npm", "cargo"} {
+		if err := os.Chmod(filepath.Join(bin, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+	stdin := promptReader
+	t.Cleanup(func() { promptReader = stdin })
+
+	results := []detect.VerifyResult{
+		{Requirement: detect.Requirement{Type: detect.TypeNPM, Value: "prettier"}},
+		{Requirement: detect.Requirement{Type: detect.TypeNPM, Value: "vercel"}, Satisfied: true},
+		{Requirement: detect.Requirement{Type: detect.TypeEnv, Value: "API_KEY"}},
+	}
+	ran := func() string {
+		data, _ := os.ReadFile(log)
+		os.Remove(log)
+		return string(data)
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		var failed int
+		out := captureStdout(t, func() { failed = fixRequirements(results, true, false) })
+		if !strings.Contains(out, "would run: npm install prettier") || !strings.Contains(out, "set $API_KEY") {
+			t.Errorf("output = %q, want the npm command and a manual hint for API_KEY", out)
+		}
+		if got := ran(); got != "" || failed != 0 {
+			t.Errorf("dry run ran %q (failed %d), want nothing", got, failed)
+		}
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		promptReader = bufio.NewReader(strings.NewReader("n\n"))
+		out := captureStdout(t, func() { fixRequirements(results, false, false) })
+		if !strings.Contains(out, "Run npm install prettier? [y/N]") || !strings.Contains(out, "skipped npm install prettier") {
+			t.Errorf("output = %q, want the command in the prompt and a skip", out)
+		}
+		if strings.Contains(out, "running:") {
+			t.Errorf("output = %q, said running before being confirmed", out)
+		}
+		if got := ran(); got != "" {
+			t.Errorf("declined fix ran %q", got)
+		}
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		promptReader = bufio.NewReader(strings.NewReader("y\n"))
+		out := captureStdout(t, func() { fixRequirements(results, false, false) })
+		prompt, running := strings.Index(out, "Run npm install prettier?"), strings.Index(out, "running:")
+		if prompt < 0 || running < prompt {
+			t.Errorf("output = %q, want running: after the prompt", out)
+		}
+		if got := ran(); got != "npm install prettier\n" {
+			t.Errorf("ran %q, want npm install prettier", got)
+		}
+	})
+
+	t.Run("yes", func(t *testing.T) {
+		promptReader = bufio.NewReader(strings.NewReader(""))
+		failing := append(results, detect.VerifyResult{Requirement: detect.Requirement{Type: detect.TypeCargo, Value: "ripgrep"}})
+		var failed int
+		out := captureStdout(t, func() { failed = fixRequirements(failing, false, true) })
+		if strings.Contains(out, "[y/N]") {
+			t.Errorf("output = %q, prompted despite --yes", out)
+		}
+		if got := ran(); got != "npm install prettier\n" || failed != 1 {
+			t.Errorf("ran %q with %d failed, want npm install prettier and the cargo failure", got, failed)
+		}
+	})
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	fn()
+	os.Stdout = stdout
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
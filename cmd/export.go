@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	Aliases: []string{"transcribe"},
+	Short:   "Transcribe installed artifacts into a shareable collection",
+	Long: `Export your installed artifacts as a tome collection.
+
+Without --output, prints a tome.yaml manifest listing every installed
+artifact with its description and source. With --output, also copies the
+artifact files into that directory using the layout tome learn expects:
+  skills/<name>/SKILL.md   Skills (with their included files)
+  commands/<name>.md       Commands
+  agents/<name>.md         Agents
+  prompts/<name>.md        Prompts
+  hooks/<file>             Hooks
+
+The exported directory can be installed elsewhere with 'tome learn ./dir'
+or pushed to GitHub and shared.
+
+Examples:
+  tome export > tome.yaml
+  tome export --output my-skills --name my-skills --author "Your Name"`,
+	Args: cobra.NoArgs,
+	Run:  runExport,
+}
+
+var (
+	exportOutput      string
+	exportName        string
+	exportAuthor      string
+	exportDescription string
+)
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Directory to write tome.yaml and artifact files to")
+	exportCmd.Flags().StringVarP(&exportName, "name", "n", "", "Collection name (defaults to the output or current directory name)")
+	exportCmd.Flags().StringVarP(&exportAuthor, "author", "a", "", "Author name")
+	exportCmd.Flags().StringVarP(&exportDescription, "description", "d", "", "Collection description")
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	paths, err := config.GetPaths()
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if len(state.Installed) == 0 {
+		exitWithError("no artifacts installed; nothing to export")
+	}
+
+	manifest := artifact.Manifest{
+		Name:        exportName,
+		Description: exportDescription,
+		Author:      exportAuthor,
+	}
+	if manifest.Name == "" {
+		// Default to the output directory's name, like conjure does with the cwd
+		dir := exportOutput
+		if dir == "" {
+			dir = "."
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			manifest.Name = filepath.Base(abs)
+		}
+	}
+
+	// Manifest only: print it so it can be redirected
+	if exportOutput == "" {
+		for _, a := range state.Installed {
+			content, _ := os.ReadFile(a.LocalPath)
+			manifest.Artifacts = append(manifest.Artifacts, exportSummary(a, content))
+		}
+		data, err := marshalManifest(&manifest)
+		if err != nil {
+			exitWithError(err.Error())
+		}
+		fmt.Print(string(data))
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Transcribing Tome", 56))
+	fmt.Println()
+
+	var exported []string
+	var skipped []skippedArtifact
+	for _, a := range state.Installed {
+		content, err := exportArtifact(a, exportOutput)
+		if err != nil {
+			fmt.Println(ui.Warning.Render(fmt.Sprintf("  Skipping %s: %v", a.Name, err)))
+			skipped = append(skipped, skippedArtifact{a.Name, err.Error()})
+			continue
+		}
+		manifest.Artifacts = append(manifest.Artifacts, exportSummary(a, content))
+		exported = append(exported, a.Name)
+		fmt.Printf("  %s %s\n", getBadge(a.Type), ui.Highlight.Render(a.Name))
+	}
+
+	if len(exported) == 0 {
+		exitWithError("no artifacts could be exported")
+	}
+
+	data, err := marshalManifest(&manifest)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	manifestPath := filepath.Join(exportOutput, artifact.ManifestFilename)
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		exitWithError(fmt.Sprintf("failed to write %s: %v", manifestPath, err))
+	}
+
+	fmt.Println()
+	fmt.Println(ui.SuccessLine(fmt.Sprintf("Exported %d artifact(s) to %s", len(exported), exportOutput)))
+	if len(skipped) > 0 {
+		fmt.Println()
+		fmt.Println(ui.Warning.Render(fmt.Sprintf("  Skipped %d artifact(s):", len(skipped))))
+		for _, s := range skipped {
+			fmt.Println(ui.Muted.Render(fmt.Sprintf("    • %s: %s", s.name, s.reason)))
+		}
+	}
+	fmt.Println()
+	fmt.Println(ui.Muted.Render(fmt.Sprintf("  Share it with: tome learn %s", exportOutput)))
+	fmt.Println(ui.PageFooter())
+}
+
+// exportSummary builds the manifest entry for an installed artifact
+func exportSummary(a artifact.InstalledArtifact, content []byte) artifact.ArtifactSummary {
+	hash := ""
+	if len(content) > 0 {
+		h := sha256.Sum256(content)
+		hash = "sha256:" + hex.EncodeToString(h[:])
+	}
+	return artifact.ArtifactSummary{
+		Name:        a.Name,
+		Type:        a.Type,
+		Description: a.Description,
+		Source:      a.Source,
+		Hash:        hash,
+	}
+}
+
+// exportPath returns where an artifact goes in an exported collection, relative to its root
+func exportPath(a artifact.InstalledArtifact) (string, error) {
+	safeName := artifact.SanitizeFilename(a.Name)
+	switch a.Type {
+	case artifact.TypeSkill:
+		return filepath.Join(artifact.SkillsDirName, safeName, artifact.SkillFilename), nil
+	case artifact.TypeCommand:
+		return filepath.Join(artifact.CommandsDirName, safeName+".md"), nil
+	case artifact.TypeAgent:
+		return filepath.Join(artifact.AgentsDirName, safeName+".md"), nil
+	case artifact.TypePrompt:
+		return filepath.Join(artifact.PromptsDirName, safeName+".md"), nil
+	case artifact.TypeHook:
+		return filepath.Join(artifact.HooksDirName, filepath.Base(a.LocalPath)), nil
+	default:
+		return "", fmt.Errorf("unsupported artifact type: %s", a.Type)
+	}
+}
+
+// exportArtifact copies an installed artifact (and a skill's includes) into
+// outDir and returns the artifact's content
+func exportArtifact(a artifact.InstalledArtifact, outDir string) ([]byte, error) {
+	relPath, err := exportPath(a)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(a.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("read failed: %w", err)
+	}
+
+	dest := filepath.Join(outDir, relPath)
+	if err := writeExportFile(dest, content); err != nil {
+		return nil, err
+	}
+
+	if a.Type == artifact.TypeSkill {
+		srcDir, destDir := filepath.Dir(a.LocalPath), filepath.Dir(dest)
+		for _, inc := range a.Includes {
+			if err := fetch.ValidateIncludePath(inc); err != nil {
+				continue
+			}
+			incContent, err := os.ReadFile(filepath.Join(srcDir, inc))
+			if err != nil {
+				return nil, fmt.Errorf("read include %s: %w", inc, err)
+			}
+			if err := writeExportFile(filepath.Join(destDir, inc), incContent); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return content, nil
+}
+
+func writeExportFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// marshalManifest renders a manifest as tome.yaml content
+func marshalManifest(manifest *artifact.Manifest) ([]byte, error) {
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s: %w", artifact.ManifestFilename, err)
+	}
+	header := "# Tome Collection Manifest\n# https://github.com/kennyg/tome\n\n"
+	return append([]byte(header), data...), nil
+}
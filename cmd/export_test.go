@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/fetch"
+)
+
+func TestExportArtifact_RoundTripsThroughLocalDiscovery(t *testing.T) {
+	installDir := t.TempDir()
+	skillPath := filepath.Join(installDir, "skills", "pdf", artifact.SkillFilename)
+	commandPath := filepath.Join(installDir, "commands", "deploy.md")
+	files := map[string]string{
+		skillPath: "---\nname: pdf\ndescription: Work with PDFs\n---\n# PDF\n",
+		filepath.Join(installDir, "skills", "pdf", "ref", "forms.md"): "# Forms\n",
+		commandPath: "---\ndescription: Deploy the app\n---\n# Deploy\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	installed := []artifact.InstalledArtifact{
+		{
+			Artifact:  artifact.Artifact{Name: "pdf", Type: artifact.TypeSkill, Source: "owner/repo", Includes: []string{"ref/forms.md"}},
+			LocalPath: skillPath,
+		},
+		{
+			Artifact:  artifact.Artifact{Name: "deploy", Type: artifact.TypeCommand, Source: "owner/repo"},
+			LocalPath: commandPath,
+		},
+	}
+
+	outDir := t.TempDir()
+	for _, a := range installed {
+		content, err := exportArtifact(a, outDir)
+		if err != nil {
+			t.Fatalf("exportArtifact(%s) error = %v", a.Name, err)
+		}
+		if summary := exportSummary(a, content); summary.Source != "owner/repo" || summary.Hash == "" {
+			t.Errorf("exportSummary(%s) = %+v, want source and hash", a.Name, summary)
+		}
+	}
+
+	// The exported directory is laid out the way learn discovers artifacts
+	found, err := fetch.FindLocalArtifacts(outDir, nil)
+	if err != nil {
+		t.Fatalf("FindLocalArtifacts() error = %v", err)
+	}
+	var rel []string
+	for _, path := range found {
+		r, _ := filepath.Rel(outDir, path)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	sort.Strings(rel)
+	want := []string{"commands/deploy.md", "skills/pdf/SKILL.md"}
+	if len(rel) != len(want) || rel[0] != want[0] || rel[1] != want[1] {
+		t.Errorf("FindLocalArtifacts() = %v, want %v", rel, want)
+	}
+
+	includes, err := fetch.DiscoverLocalSkillFiles(filepath.Join(outDir, "skills", "pdf"))
+	if err != nil {
+		t.Fatalf("DiscoverLocalSkillFiles() error = %v", err)
+	}
+	if len(includes) != 1 || includes[0].Path != "ref/forms.md" || string(includes[0].Content) != "# Forms\n" {
+		t.Errorf("DiscoverLocalSkillFiles() = %+v, want ref/forms.md", includes)
+	}
+}
+
+func TestExportPath(t *testing.T) {
+	tests := []struct {
+		art  artifact.InstalledArtifact
+		want string
+	}{
+		{artifact.InstalledArtifact{Artifact: artifact.Artifact{Name: "My Skill", Type: artifact.TypeSkill}}, "skills/My-Skill/SKILL.md"},
+		{artifact.InstalledArtifact{Artifact: artifact.Artifact{Name: "review", Type: artifact.TypeAgent}}, "agents/review.md"},
+		{artifact.InstalledArtifact{Artifact: artifact.Artifact{Name: "ask", Type: artifact.TypePrompt}}, "prompts/ask.md"},
+		{artifact.InstalledArtifact{Artifact: artifact.Artifact{Name: "lint", Type: artifact.TypeHook}, LocalPath: "/x/hooks/lint.sh"}, "hooks/lint.sh"},
+	}
+	for _, tt := range tests {
+		got, err := exportPath(tt.art)
+		if err != nil {
+			t.Fatalf("exportPath(%s) error = %v", tt.art.Name, err)
+		}
+		if filepath.ToSlash(got) != tt.want {
+			t.Errorf("exportPath(%s) = %q, want %q", tt.art.Name, got, tt.want)
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
+	"github.com/kennyg/tome/internal/artifact"
 	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/detect"
 	"github.com/kennyg/tome/internal/ui"
 )
 
@@ -15,11 +20,37 @@ var infoCmd = &cobra.Command{
 	Short:   "Study an artifact in detail",
 	Long: `Examine the details of an inscribed artifact.
 
-Shows metadata, source, installation date, and contents.`,
+Shows metadata, source, install path and date, and each detected
+requirement with whether it's satisfied on this machine.
+
+Examples:
+  tome study commit
+  tome info pdf --json`,
 	Args: cobra.ExactArgs(1),
 	Run:  runInfo,
 }
 
+var infoJSON bool
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoJSON, "json", false, "Output as JSON, with verified requirement status")
+}
+
+// artifactInfo is the --json output: the state entry with each active
+// requirement's verified status in place of the recorded requirements
+type artifactInfo struct {
+	artifact.InstalledArtifact
+	Requirements []requirementStatus `json:"requirements"`
+}
+
+// newArtifactInfo verifies an artifact's requirements, honoring ignores
+func newArtifactInfo(a *artifact.InstalledArtifact, ignores []string) artifactInfo {
+	return artifactInfo{
+		InstalledArtifact: *a,
+		Requirements:      verifyRequirements(detect.ApplyIgnores(a.Requirements, ignores)),
+	}
+}
+
 func runInfo(cmd *cobra.Command, args []string) {
 	name := args[0]
 
@@ -33,13 +64,25 @@ func runInfo(cmd *cobra.Command, args []string) {
 		exitWithError(err.Error())
 	}
 
-	artifact := state.FindInstalled(name)
-	if artifact == nil {
-		exitWithError(fmt.Sprintf("artifact '%s' not found", name))
+	artifact, err := state.LookupInstalled(name)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	info := newArtifactInfo(artifact, ignoreFilePatterns(paths))
+
+	if infoJSON {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			exitWithError("Failed to encode JSON: " + err.Error())
+		}
+		fmt.Println(string(data))
+		return
 	}
 
 	badge := getBadge(artifact.Type)
 
+	fmt.Println()
 	fmt.Println(ui.Title.Render(artifact.Name))
 	fmt.Println()
 	fmt.Printf("%s %s\n", badge, ui.Muted.Render(string(artifact.Type)))
@@ -50,19 +93,49 @@ func runInfo(cmd *cobra.Command, args []string) {
 		fmt.Println()
 	}
 
-	fmt.Println(ui.Subtitle.Render("Details"))
-	fmt.Println(ui.Divider(40))
+	details := []string{ui.Subtitle.Render("Details")}
+	field := func(label, value string) {
+		if value != "" {
+			details = append(details, fmt.Sprintf("%s %s", ui.Muted.Render(fmt.Sprintf("%-10s", label+":")), value))
+		}
+	}
+	field("Author", artifact.Author)
+	field("Version", artifact.Version)
+	field("Source", artifact.Source)
+	field("Agent", artifact.Agent)
+	field("Path", artifact.LocalPath)
+	if !artifact.InstalledAt.IsZero() {
+		field("Installed", artifact.InstalledAt.Format("2006-01-02 15:04"))
+	}
+	if len(artifact.Includes) > 0 {
+		field("Includes", fmt.Sprintf("%d file(s)", len(artifact.Includes)))
+	}
+	fmt.Println(panel(ui.PanelHighlight, details))
 
-	if artifact.Author != "" {
-		fmt.Printf("  Author:    %s\n", artifact.Author)
+	reqs := []string{ui.Subtitle.Render("Requirements")}
+	if len(info.Requirements) == 0 {
+		reqs = append(reqs, ui.Muted.Render("None detected"))
 	}
-	if artifact.Version != "" {
-		fmt.Printf("  Version:   %s\n", artifact.Version)
+	for _, r := range info.Requirements {
+		if r.Satisfied {
+			reqs = append(reqs, fmt.Sprintf("%s %s: %s", ui.Success.Render("✓"), r.Type, r.Label()))
+			continue
+		}
+		reqs = append(reqs, fmt.Sprintf("%s %s: %s", ui.Error.Render("✗"), r.Type, r.Label()))
+		if r.Message != "" {
+			reqs = append(reqs, ui.Muted.Render("  "+r.Message))
+		}
 	}
-	fmt.Printf("  Source:    %s\n", artifact.Source)
-	fmt.Printf("  Path:      %s\n", artifact.LocalPath)
+	fmt.Println(panel(ui.Panel, reqs))
+	fmt.Println(ui.PageFooter())
+}
 
-	if !artifact.InstalledAt.IsZero() {
-		fmt.Printf("  Installed: %s\n", artifact.InstalledAt.Format("2006-01-02 15:04"))
+// panel renders lines in a bordered panel, or indented plain text when
+// stdout isn't a terminal
+func panel(style lipgloss.Style, lines []string) string {
+	body := strings.Join(lines, "\n")
+	if !ui.IsTTY {
+		return "  " + strings.ReplaceAll(body, "\n", "\n  ") + "\n"
 	}
+	return style.Render(body)
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/detect"
+)
+
+func TestNewArtifactInfo(t *testing.T) {
+	t.Setenv("TOME_TEST_SET", "1")
+
+	a := &artifact.InstalledArtifact{
+		Artifact:  artifact.Artifact{Name: "deploy", Type: artifact.TypeCommand, Source: "acme/tools"},
+		LocalPath: "/home/.claude/commands/deploy.md",
+		Requirements: []detect.Requirement{
+			{Type: detect.TypeEnv, Value: "TOME_TEST_SET"},
+			{Type: detect.TypeEnv, Value: "TOME_TEST_UNSET"},
+			{Type: detect.TypeEnv, Value: "TOME_TEST_IGNORED"},
+		},
+	}
+
+	info := newArtifactInfo(a, []string{"env:TOME_TEST_IGNORED"})
+	if len(info.Requirements) != 2 {
+		t.Fatalf("requirements = %+v, want the two not ignored", info.Requirements)
+	}
+	if !info.Requirements[0].Satisfied || info.Requirements[1].Satisfied {
+		t.Errorf("satisfied = %v, %v; want true, false", info.Requirements[0].Satisfied, info.Requirements[1].Satisfied)
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Name         string `json:"name"`
+		LocalPath    string `json:"local_path"`
+		Requirements []struct {
+			Value     string `json:"value"`
+			Satisfied bool   `json:"satisfied"`
+		} `json:"requirements"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Name != "deploy" || decoded.LocalPath != a.LocalPath {
+		t.Errorf("JSON = %s, want the state entry's fields", data)
+	}
+	if len(decoded.Requirements) != 2 || decoded.Requirements[1].Value != "TOME_TEST_UNSET" || decoded.Requirements[1].Satisfied {
+		t.Errorf("JSON requirements = %+v, want verified statuses", decoded.Requirements)
+	}
+}
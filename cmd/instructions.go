@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/schema"
+	"github.com/kennyg/tome/internal/source"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+// instructionDirs are where instruction files are looked for besides a
+// source's root
+var instructionDirs = []string{".github/instructions", ".cursor/rules"}
+
+// sourceInstructions is an instruction file found in a source
+type sourceInstructions struct {
+	path    string // Relative to the source, slash-separated
+	content []byte
+}
+
+// installSourceInstructions installs the instruction files in a local or GitHub
+// directory source for every target agent, converted to its format
+func installSourceInstructions(client *fetch.Client, src *source.Source) {
+	var found []sourceInstructions
+	var err error
+	switch {
+	case src.Type == source.TypeLocal:
+		found, err = findLocalInstructions(src.Path)
+	case src.Type == source.TypeRepo && src.IsGitHub():
+		found, err = findGitHubInstructions(client, src)
+	default:
+		fmt.Fprintln(learnOutput(), ui.WarningLine("--include-instructions needs a local directory or GitHub repo"))
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(learnOutput(), ui.WarningLine(fmt.Sprintf("Couldn't look for instructions: %v", err)))
+		return
+	}
+	if len(found) == 0 {
+		return
+	}
+
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Info.Render(fmt.Sprintf("  Instructions: %d file(s)", len(found))))
+
+	// Like artifacts, instructions come out again if a fatal error interrupts
+	tx := beginInstall()
+	defer tx.commit()
+	for _, f := range found {
+		inst, err := schema.ParseInstructionsAuto(f.content, f.path)
+		if err != nil {
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("    Skipping %s: %v", f.path, err)))
+			continue
+		}
+		for _, target := range installTargets(learnTargets[0]) {
+			dst, err := installInstructions(inst, src.String()+"/"+f.path, target, learnDryRun)
+			if err != nil {
+				fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("    Skipping %s for %s: %v", f.path, target.Agent, err)))
+				continue
+			}
+			verb := "Installed"
+			if learnDryRun {
+				verb = "Would install"
+			}
+			fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    %s %s to %s", verb, f.path, dst)))
+		}
+	}
+}
+
+// findLocalInstructions lists the instruction files in dir and its
+// instructionDirs
+func findLocalInstructions(dir string) ([]sourceInstructions, error) {
+	if info, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		if !schema.IsInstructionsFile(dir) {
+			return nil, nil
+		}
+		content, err := os.ReadFile(dir)
+		return []sourceInstructions{{filepath.Base(dir), content}}, err
+	}
+
+	var found []sourceInstructions
+	for _, sub := range append([]string{""}, instructionDirs...) {
+		entries, err := os.ReadDir(filepath.Join(dir, filepath.FromSlash(sub)))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			rel := path.Join(sub, entry.Name())
+			if entry.IsDir() || !schema.IsInstructionsFile(rel) {
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+			if err != nil {
+				return nil, err
+			}
+			found = append(found, sourceInstructions{rel, content})
+		}
+	}
+	return found, nil
+}
+
+// findGitHubInstructions lists and fetches the instruction files in a GitHub
+// source's directory and its instructionDirs
+func findGitHubInstructions(client *fetch.Client, src *source.Source) ([]sourceInstructions, error) {
+	var found []sourceInstructions
+	for _, sub := range append([]string{""}, instructionDirs...) {
+		dir := *src
+		dir.Path = path.Join(src.Path, sub)
+		items, err := client.ListGitHubContents(dir.GitHubAPIURL())
+		if err != nil {
+			if sub == "" {
+				return nil, err
+			}
+			continue // The directory doesn't exist
+		}
+		for _, item := range items {
+			rel := path.Join(sub, item.Name)
+			if item.Type != "file" || !schema.IsInstructionsFile(rel) {
+				continue
+			}
+			url := item.DownloadURL
+			if url == "" || src.FetchRef() != "" {
+				url = src.RepoRawURL(item.Path)
+			}
+			content, err := client.FetchURL(url)
+			if err != nil {
+				return nil, err
+			}
+			found = append(found, sourceInstructions{rel, content})
+		}
+	}
+	return found, nil
+}
+
+// installInstructions converts inst to the format of the agent paths are for
+// and writes it where that agent reads instructions, returning the path.
+// Files an agent reads all of its instructions from (CLAUDE.md, AGENTS.md,
+// .cursorrules) are shared, so inst is merged into a section of its own,
+// marked with key, that learning it again replaces.
+func installInstructions(inst schema.Skill, key string, paths *config.Paths, dryRun bool) (string, error) {
+	format := config.InstructionsFormat(paths.Agent)
+	if format == "" {
+		return "", fmt.Errorf("tome can't write %s instructions", paths.Agent)
+	}
+	dst, shared, err := instructionsPath(inst, format, paths)
+	if err != nil {
+		return "", err
+	}
+	content, err := schema.ConvertInstructions(inst, format)
+	if err != nil {
+		return "", err
+	}
+	if dryRun {
+		return dst, nil
+	}
+
+	if shared {
+		existing, err := os.ReadFile(dst)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		content = []byte(mergeInstructions(string(existing), key, string(content)))
+	}
+	if err := activeInstall.mkdirAll(filepath.Dir(dst)); err != nil {
+		return "", err
+	}
+	return dst, activeInstall.writeFile(dst, content, 0644)
+}
+
+// instructionsPath returns where an agent reads instructions in format from,
+// and whether that file is shared by all of them. Copilot's live in the agent
+// directory (.github/instructions) in either scope; Claude and OpenCode read
+// global instructions from theirs (~/.claude/CLAUDE.md). Other agents only
+// read instructions from a project.
+func instructionsPath(inst schema.Skill, format schema.Format, paths *config.Paths) (string, bool, error) {
+	name := schema.InstructionsOutputFilename(inst, format)
+	dir := schema.InstructionsOutputDirectory(inst, format)
+	root := filepath.Dir(paths.AgentDir)
+	project := root == config.ProjectRoot()
+
+	switch {
+	case format == schema.FormatCopilot:
+		return filepath.Join(paths.AgentDir, dir, name), false, nil
+	case project:
+		return filepath.Join(root, filepath.FromSlash(dir), name), dir == "", nil
+	case format == schema.FormatClaude || format == schema.FormatOpenCode:
+		return filepath.Join(paths.AgentDir, name), true, nil
+	default:
+		return "", false, fmt.Errorf("%s instructions only install into a project", format)
+	}
+}
+
+// mergeInstructions replaces the section marked with key in existing with
+// content, or appends it as a new section
+func mergeInstructions(existing, key, content string) string {
+	begin, end := "<!-- tome:begin "+key+" -->", "<!-- tome:end "+key+" -->"
+	section := begin + "\n" + strings.TrimSpace(content) + "\n" + end + "\n"
+
+	re := regexp.MustCompile(`(?s)` + regexp.QuoteMeta(begin) + `.*?` + regexp.QuoteMeta(end) + `\n?`)
+	if loc := re.FindStringIndex(existing); loc != nil {
+		return existing[:loc[0]] + section + existing[loc[1]:]
+	}
+	if existing == "" {
+		return section
+	}
+	if !strings.HasSuffix(existing, "\n") {
+		existing += "\n"
+	}
+	return existing + "\n" + section
+}
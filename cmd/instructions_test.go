@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/source"
+)
+
+func TestInstallSourceInstructions_CopilotToClaude(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { learnTargets = nil })
+	paths, err := config.GetPathsForAgent(config.AgentClaude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	learnTargets = []*config.Paths{paths}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".github/instructions/go.instructions.md": "---\napplyTo: \"**/*.go\"\n---\nRun gofmt before committing.\n",
+	})
+	src, err := source.Parse(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claudeMD := filepath.Join(home, ".claude", "CLAUDE.md")
+	if err := os.MkdirAll(filepath.Dir(claudeMD), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(claudeMD, []byte("# Mine\n\nKeep this.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Learning twice replaces the source's section rather than adding another
+	installSourceInstructions(fetch.NewClient(), src)
+	installSourceInstructions(fetch.NewClient(), src)
+
+	data, err := os.ReadFile(claudeMD)
+	if err != nil {
+		t.Fatalf("CLAUDE.md not written: %v", err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "# Mine\n\nKeep this.\n") {
+		t.Errorf("existing instructions not kept:\n%s", content)
+	}
+	if strings.Count(content, "Run gofmt before committing.") != 1 {
+		t.Errorf("want the instructions merged once:\n%s", content)
+	}
+	if strings.Contains(content, "applyTo") {
+		t.Errorf("copilot frontmatter kept in CLAUDE.md:\n%s", content)
+	}
+	if !strings.Contains(content, "<!-- tome:begin "+src.String()+"/.github/instructions/go.instructions.md -->") {
+		t.Errorf("section not marked with its source:\n%s", content)
+	}
+}
+
+func TestInstallSourceInstructions_ScopedSections(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { learnTargets = nil })
+	paths, err := config.GetPathsForAgent(config.AgentClaude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	learnTargets = []*config.Paths{paths}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".github/instructions/csharp.instructions.md": "---\napplyTo: \"**/*.cs\"\n---\nUse file-scoped namespaces.\n",
+		".github/instructions/web.instructions.md":    "---\napplyTo: \"**/*.ts,**/*.tsx\"\n---\nPrefer type over interface.\n",
+	})
+	src, err := source.Parse(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installSourceInstructions(fetch.NewClient(), src)
+
+	data, err := os.ReadFile(filepath.Join(home, ".claude", "CLAUDE.md"))
+	if err != nil {
+		t.Fatalf("CLAUDE.md not written: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"## Applies to `**/*.cs`\n\nUse file-scoped namespaces.\n<!-- tome:end",
+		"## Applies to `**/*.ts`, `**/*.tsx`\n\nPrefer type over interface.\n<!-- tome:end",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("CLAUDE.md missing section %q:\n%s", want, content)
+		}
+	}
+	if strings.Count(content, "<!-- tome:begin ") != 2 {
+		t.Errorf("want one delimited section per file:\n%s", content)
+	}
+}
+
+func TestMergeInstructions(t *testing.T) {
+	got := mergeInstructions("", "a", "one\n")
+	want := "<!-- tome:begin a -->\none\n<!-- tome:end a -->\n"
+	if got != want {
+		t.Errorf("merge into empty = %q, want %q", got, want)
+	}
+
+	got = mergeInstructions("before\n"+want+"after\n", "a", "two")
+	if got != "before\n<!-- tome:begin a -->\ntwo\n<!-- tome:end a -->\nafter\n" {
+		t.Errorf("replace section = %q", got)
+	}
+
+	got = mergeInstructions("mine", "b", "three")
+	if got != "mine\n\n<!-- tome:begin b -->\nthree\n<!-- tome:end b -->\n" {
+		t.Errorf("append section = %q", got)
+	}
+}
@@ -2,10 +2,16 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -20,7 +26,7 @@ import (
 )
 
 var learnCmd = &cobra.Command{
-	Use:     "learn <source>",
+	Use:     "learn [source]",
 	Aliases: []string{"inscribe", "add", "install"},
 	Short:   "Inscribe a new artifact into the tome",
 	Long: `Learn and inscribe artifacts from various sources.
@@ -29,118 +35,508 @@ Sources can be:
   owner/repo              GitHub repository (installs all artifacts)
   owner/repo:path         Specific path in a repo
   owner/repo@ref          Specific branch/tag/commit
+  owner/repo@^1.2         Highest tag matching a semver range (GitHub)
   https://...             Direct URL to a file
   ./local/path            Local file or directory
 
 Artifact types are auto-detected:
   SKILL.md files       → Skills (passive agent knowledge)
-  Other .md files      → Commands (invokable with /name)
+  Other .md/.mdx files → Commands (invokable with /name)
 
 Examples:
   tome learn kennyg/yegges-tips                    # All commands from repo
   tome inscribe steveyegge/beads:examples/claude-code-skill
   tome learn https://raw.githubusercontent.com/.../SKILL.md
-  tome learn ./my-local-skill`,
-	Args: cobra.ExactArgs(1),
+  tome learn ./my-local-skill
+  tome learn owner/repo --requirements-json        # Requirements as JSON on stdout
+  tome learn owner/repo --json                     # Summary as JSON (for AI agents)
+  tome learn owner/repo --dry-run                  # Preview files and requirements
+  tome learn owner/repo --strict                   # Fail CI if any artifact is skipped
+  tome learn ./my-skills --exclude 'drafts/*.md'   # Skip matching paths (repeatable)
+  tome learn owner/repo -i                         # Pick which artifacts to install
+  tome learn owner/marketplace#formatter           # One plugin from a marketplace
+  tome learn owner/marketplace --all-plugins       # Every plugin it lists
+  tome learn owner/repo --include-instructions     # Plus its CLAUDE.md/AGENTS.md
+  tome install                                     # Everything tome.lock pins
+
+Project-local installs are pinned in tome.lock at the project root: each
+artifact's source, the commit its ref resolved to (GitHub) and a checksum of
+its content. Commit it, and teammates run tome install with no source to get
+exactly those versions; an artifact whose content no longer matches fails.
+
+A repo with .claude-plugin/marketplace.json lists plugins; without #name or
+--all-plugins, learn asks which to install.
+
+Exclude patterns are globs relative to the source directory. A .tomeignore
+file there is honored too: its path:<glob> lines are excludes, and its other
+lines requirements to ignore. Patterns containing a slash match from the
+root; others match a file or directory name anywhere.
+
+A collection's tome.yaml and a skill's frontmatter can list other sources
+under requires; learn installs those as well, and what they require in turn,
+each once. Relative paths are relative to the local source requiring them.
+Use --no-deps to install only the source given.
+
+By default learn keeps going when an artifact can't be fetched or parsed,
+skipping it and reporting it in the summary. Use --fail-fast to stop at the
+first failure, or --strict to install what it can but still exit non-zero.
+Artifacts installed before a --fail-fast stop are kept, and pinned in
+tome.lock; other fatal errors roll back what the source installed.
+
+With --include-instructions, the instruction files at the source's root and
+in .github/instructions and .cursor/rules are converted to each agent's own
+(CLAUDE.md for Claude, AGENTS.md for OpenCode, .github/instructions for
+Copilot). CLAUDE.md, AGENTS.md and .cursorrules are shared with your own
+instructions, so each source's go in a marked section that learning it again
+replaces.
+
+Exit codes:
+  0  Artifacts installed (skipped ones are allowed without --strict)
+  1  Error: bad source, nothing installed, or stopped by --fail-fast
+  2  Some artifacts were skipped and --strict is set`,
+	Args: cobra.MaximumNArgs(1),
 	Run:  runLearn,
 }
 
 var (
-	learnGlobal bool
-	learnAgent  string
+	learnGlobal           bool
+	learnProject          bool
+	learnAgents           []string
+	learnAllAgents        bool
+	learnAllPlugins       bool
+	learnEnableHooks      bool
+	learnRequirementsJSON bool
+	learnJSON             bool
+	learnFollowSubmodules bool
+	learnFlattenSkill     bool
+	learnSingleFileSkills bool
+	learnJobs             int
+	learnStrict           bool
+	learnFailFast         bool
+	learnInstructions     bool
+	learnSHA256           string
+	learnRename           string
+	learnExclude          []string
+	learnArchive          bool
+	learnDryRun           bool
+	learnPathTemplate     string
+	learnOnConflict       string
+	learnOnly             string
+	learnToken            string
+	learnInteractive      bool
+
+	// learnConflictPolicy is the parsed --on-conflict
+	learnConflictPolicy conflictPolicy
+
+	// learnOnlyType is the parsed --only; empty installs every type
+	learnOnlyType artifact.Type
+
+	// learnExitStatus is the exit code runLearn finishes with once output is written
+	learnExitStatus int
+
+	// learnedReqs aggregates requirements across every artifact installed in this run
+	learnedReqs []detect.Requirement
+
+	// learnedArtifacts lists every artifact installed in this run, for --json
+	learnedArtifacts []learnedArtifact
+
+	// learnSkipped lists the artifacts this run skipped, for --json
+	learnSkipped []skippedArtifact
+
+	// learnWarnings collects what went wrong short of skipping an artifact:
+	// what converting for the target agent lost, and hooks not installed or
+	// enabled
+	learnWarnings []learnWarning
+
+	// learnLock is the project's tome.lock, updated with each artifact
+	// installed; nil when the run doesn't pin what it installs
+	learnLock *config.Lockfile
+
+	// learnLockPath is where learnLock is saved
+	learnLockPath string
+
+	// learnSource is the source being learned, and learnCommit the commit its
+	// ref resolved to (GitHub repos only), for tome.lock
+	learnSource *source.Source
+	learnCommit string
+
+	// learnFromLock is set while tome install reinstalls what tome.lock pins
+	learnFromLock bool
+
+	// learnRenameTo is --rename while the source given is installed; the
+	// sources it requires keep their names
+	learnRenameTo string
+
+	// learnTargets holds the paths of every agent this run installs for; the
+	// first is the one passed through the install pipeline
+	learnTargets []*config.Paths
 )
 
+const (
+	// defaultLearnJobs is how many artifacts are fetched concurrently by default
+	defaultLearnJobs = 8
+
+	// exitSkipped is the exit code when --strict is set and artifacts were skipped
+	exitSkipped = 2
+
+	// learnJobsEnvVar overrides the fetch concurrency when --jobs isn't set
+	learnJobsEnvVar = "TOME_JOBS"
+)
+
+// requirementStatus is the --requirements-json output for a single requirement
+type requirementStatus struct {
+	detect.Requirement
+	Satisfied bool   `json:"satisfied"`
+	Message   string `json:"message,omitempty"`
+}
+
+// learnJSONResult is the structured output of tome learn --json
+type learnJSONResult struct {
+	Source       string              `json:"source"`
+	DryRun       bool                `json:"dry_run"`
+	Installed    []learnedArtifact   `json:"installed"`
+	Skipped      []learnJSONSkipped  `json:"skipped"`
+	Warnings     []learnWarning      `json:"warnings"`
+	Requirements []requirementStatus `json:"requirements"`
+	Error        string              `json:"error,omitempty"`
+}
+
+// learnedArtifact is an artifact installed by learn; Path is where it was
+// written for the first target agent
+type learnedArtifact struct {
+	Name string        `json:"name"`
+	Type artifact.Type `json:"type"`
+	Path string        `json:"path"`
+}
+
+// learnJSONSkipped is a skipped artifact in tome learn --json output
+type learnJSONSkipped struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
 func init() {
 	learnCmd.Flags().BoolVarP(&learnGlobal, "global", "g", false, "Install globally to ~/.<agent>/ instead of project-local")
-	learnCmd.Flags().StringVarP(&learnAgent, "agent", "a", "", "Target agent (claude, opencode, crush, cursor, windsurf)")
+	learnCmd.Flags().BoolVarP(&learnProject, "project", "p", false, "Install to the attuned project even when tome config's default-scope is global")
+	learnCmd.MarkFlagsMutuallyExclusive("global", "project")
+	learnCmd.Flags().StringSliceVarP(&learnAgents, "agent", "a", nil, "Target agent(s), comma-separated or repeated (claude, opencode, crush, cursor, windsurf)")
+	learnCmd.Flags().BoolVar(&learnAllAgents, "all-agents", false, "Install for every agent detected on this machine")
+	learnCmd.Flags().BoolVar(&learnAllPlugins, "all-plugins", false, "Install every plugin a marketplace lists")
+	learnCmd.Flags().BoolVar(&learnEnableHooks, "enable-hooks", false, "Register plugin hooks in the agent's settings.json, not just copy them")
+	learnCmd.Flags().BoolVar(&learnFlattenSkill, "flatten-skill", false, "Inline text includes into SKILL.md instead of writing separate files (pair with --single-file to drop the skill directory too)")
+	learnCmd.Flags().BoolVar(&learnSingleFileSkills, "single-file", false, "Install skills without includes as skills/<name>.md instead of skills/<name>/SKILL.md (use --flatten-skill to inline includes first)")
+	learnCmd.Flags().BoolVar(&learnFollowSubmodules, "follow-submodules", false, "Scan repos referenced by git submodules for artifacts")
+	learnCmd.Flags().StringArrayVar(&learnExclude, "exclude", nil, "Skip artifacts whose path matches this glob (repeatable; adds to the source's .tomeignore)")
+	learnCmd.Flags().BoolVar(&learnDryRun, "dry-run", false, "Fetch and detect requirements, but only show what would be written")
+	learnCmd.Flags().BoolVar(&learnArchive, "archive", false, "Download GitHub repos as one tarball instead of listing and fetching each file")
+	learnCmd.Flags().IntVarP(&learnJobs, "jobs", "j", 0, fmt.Sprintf("Number of artifacts to fetch concurrently (default %d, or $%s, or tome config's jobs)", defaultLearnJobs, learnJobsEnvVar))
+	learnCmd.Flags().BoolVar(&learnRequirementsJSON, "requirements-json", false, "Print detected requirements (with verified status) as JSON to stdout; other output goes to stderr")
+	learnCmd.Flags().BoolVar(&learnJSON, "json", false, "Print only a JSON summary of installed and skipped artifacts and requirements (for AI agents)")
+	learnCmd.MarkFlagsMutuallyExclusive("json", "requirements-json")
+	learnCmd.Flags().BoolVar(&learnStrict, "strict", false, fmt.Sprintf("Exit with status %d if any artifact was skipped", exitSkipped))
+	learnCmd.Flags().StringVar(&learnSHA256, "sha256", "", "Refuse to install unless the artifact's content has this sha256 (single-artifact sources)")
+	learnCmd.Flags().StringVar(&learnRename, "rename", "", "Install the artifact under this name instead, rewriting its frontmatter name (single-artifact sources)")
+	learnCmd.Flags().StringVar(&learnPathTemplate, "path-template", "", fmt.Sprintf("Go template for install paths within each type's directory (default %q, or $%s)", defaultPathTemplate, pathTemplateEnvVar))
+	learnCmd.Flags().StringVar(&learnToken, "token", "", "GitHub token for private repos (overrides GITHUB_TOKEN, GH_TOKEN and gh CLI config)")
+	learnCmd.Flags().StringVar(&learnOnly, "only", "", "Install only artifacts of this type (skill or command)")
+	learnCmd.Flags().BoolVarP(&learnInteractive, "interactive", "i", false, "Pick which of a repo's discovered artifacts to install")
+	learnCmd.Flags().StringVar(&learnOnConflict, "on-conflict", "", "When a name is taken by an artifact from another source: rename, skip or overwrite (default: ask, or skip when not interactive)")
+	learnCmd.Flags().BoolVar(&learnNoDeps, "no-deps", false, "Don't install the sources a collection or skill requires")
+	learnCmd.Flags().BoolVar(&learnWithDeps, "with-deps", false, "Install the sources a collection or skill requires without asking (default: ask, or skip them when not interactive)")
+	learnCmd.MarkFlagsMutuallyExclusive("no-deps", "with-deps")
+	learnCmd.Flags().BoolVar(&learnInstructions, "include-instructions", false, "Also install the source's CLAUDE.md, AGENTS.md and *.instructions.md, converted for each agent")
+	learnCmd.Flags().BoolVar(&learnFailFast, "fail-fast", false, "Stop at the first artifact that can't be fetched or parsed, keeping those installed before it (default: keep going)")
 }
 
 func runLearn(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		root := config.ProjectRoot()
+		if root == "" {
+			exitWithError("no source given, and not in a project with a " + config.LockfileName)
+		}
+		installFromLockfile(filepath.Join(root, config.LockfileName))
+		return
+	}
+
 	src, err := source.Parse(args[0])
 	if err != nil {
 		exitWithError(err.Error())
 	}
+	learn(src)
+}
+
+// learn installs everything src selects for each target agent
+func learn(src *source.Source) {
+	var err error
+	installPathTemplate, err = resolvePathTemplate(learnPathTemplate)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	learnConflictPolicy, err = parseConflictPolicy(learnOnConflict)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	learnOnlyType, err = parseOnlyType(learnOnly)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if learnInteractive {
+		if learnJSON {
+			exitWithError("--interactive can't prompt when --json discards output")
+		}
+		if !stdinIsTerminal() {
+			exitWithError("--interactive needs a terminal; use --only <type> or a path (owner/repo:path) to pick artifacts instead")
+		}
+	}
+
+	// Exit only after deferred output (like --requirements-json) is written
+	defer func() {
+		if learnExitStatus != 0 {
+			os.Exit(learnExitStatus)
+		}
+	}()
+
+	learnedReqs, learnedArtifacts, learnSkipped, learnWarnings = nil, nil, nil, nil
+
+	// Nobody can answer a conflict prompt whose output is discarded
+	if learnJSON && learnConflictPolicy == conflictPrompt {
+		learnConflictPolicy = conflictSkip
+	}
+
+	// Keep stdout clean for the JSON contract: human output moves to stderr
+	// under --requirements-json and is discarded under --json. Errors still
+	// go to stderr, and exitWithError writes the JSON before exiting
+	switch {
+	case learnRequirementsJSON:
+		rootCmd.SetOut(os.Stderr)
+	case learnJSON:
+		rootCmd.SetOut(io.Discard)
+	}
+	defer func() {
+		rootCmd.SetOut(nil)
+		switch {
+		case learnRequirementsJSON:
+			printRequirementsJSON(learnedReqs)
+		case learnJSON:
+			printLearnJSON(src.String(), "")
+		}
+	}()
+
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.SectionHeader("Inscribing", 56))
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.InfoLine("Source: "+src.String()))
+	fmt.Fprintln(learnOutput())
+
+	// Determine which agents to install for, and where
+	learnTargets = nil
+	projectInstall := false
+	for i, agent := range resolveLearnAgents() {
+		paths, installLocation := learnPathsForAgent(agent)
+		learnTargets = append(learnTargets, paths)
+		if i == 0 {
+			projectInstall = installLocation == "project"
+		}
+
+		agentCfg := config.GetAgentConfig(agent)
+		locationInfo := fmt.Sprintf("  Target: %s (%s)", agentCfg.DisplayName, installLocation)
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(locationInfo))
+	}
+	fmt.Fprintln(learnOutput())
+	paths := learnTargets[0]
 
-	fmt.Println()
-	fmt.Println(ui.SectionHeader("Inscribing", 56))
-	fmt.Println()
-	fmt.Println(ui.InfoLine("Source: " + src.String()))
-	fmt.Println()
+	// Ensure directories exist
+	if learnDryRun {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render("  [dry-run] Nothing will be written"))
+		fmt.Fprintln(learnOutput())
+	} else {
+		for _, target := range learnTargets {
+			if err := target.EnsureDirs(); err != nil {
+				exitWithError(fmt.Sprintf("failed to create directories: %v", err))
+			}
+		}
+	}
+
+	client := newFetchClient()
+	if learnToken != "" {
+		client.SetToken(learnToken)
+	}
+	client.FollowSubmodules = learnFollowSubmodules
+	client.Exclude = learnExclude
+	resolveSourceRange(client, src)
 
-	// Determine which agent to use
-	agent := config.DefaultAgent()
-	if learnAgent != "" {
-		agent = config.Agent(learnAgent)
+	// Project installs are pinned in tome.lock, except when installing from it
+	learnLock, learnCommit, learnSource = nil, "", src
+	if projectInstall && !learnDryRun && !learnFromLock {
+		beginLockUpdate(client, src)
+	}
+
+	learnRequired = nil
+	learnRenameTo = learnRename
+	learnFrom(client, src, paths)
+	learnRenameTo = "" // Dependencies keep their own names
+	if learnInstructions {
+		installSourceInstructions(client, src)
+	}
+
+	// tome.lock already pins every dependency, so installing from it doesn't
+	// follow them. --sha256 is the given source's checksum, not theirs.
+	if !learnNoDeps && !learnFromLock {
+		sha256 := learnSHA256
+		learnSHA256 = ""
+		displayDependencyTree(learnDependencies(client, src, paths))
+		learnSHA256 = sha256
+	}
+
+	saveLockUpdate()
+}
+
+// learnFrom installs what src selects, by the kind of source it is
+func learnFrom(client *fetch.Client, src *source.Source, paths *config.Paths) {
+	switch src.Type {
+	case source.TypeRepo:
+		if src.IsGitHub() {
+			learnFromGitHub(client, src, paths)
+		} else {
+			learnFromRepo(client, src, paths)
+		}
+	case source.TypeURL:
+		learnFromURL(client, src, paths)
+	case source.TypeLocal:
+		learnFromLocal(src, paths)
+	}
+}
+
+// resolveSourceRange pins a repo source whose ref is a semver range
+// (owner/repo@^1.2) to the highest matching tag. Listing tags needs the
+// GitHub API, so other providers must name an exact ref.
+func resolveSourceRange(client *fetch.Client, src *source.Source) {
+	if src.Type != source.TypeRepo || !source.IsRefRange(src.Ref) {
+		return
+	}
+	if !src.IsGitHub() {
+		exitWithError(fmt.Sprintf("version range %s needs tag listing, which only GitHub repos support; use an exact tag", src.Ref))
+	}
+
+	tags, err := client.ListGitHubTags(src.GitHubAPIURL())
+	if err != nil {
+		exitWithError(fmt.Sprintf("failed to resolve %s: %v", src.Ref, err))
+	}
+	if err := src.ResolveRange(tags); err != nil {
+		exitWithError(fmt.Sprintf("%v in %s/%s", err, src.Owner, src.Repo))
+	}
+	fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("  Resolved %s to %s", src.Range, src.Ref)))
+	fmt.Fprintln(learnOutput())
+}
+
+// resolveLearnAgents returns the agents to install for: every detected agent
+// with --all-agents, those named by --agent, or the default agent
+func resolveLearnAgents() []config.Agent {
+	return resolveAgents(learnAgents, learnAllAgents)
+}
+
+// resolveAgents returns every detected agent when all is set, the agents
+// named (deduplicated, exiting on unknown ones), or the default agent
+func resolveAgents(names []string, all bool) []config.Agent {
+	if all {
+		var agents []config.Agent
+		for _, cfg := range config.DetectInstalledAgents() {
+			agents = append(agents, cfg.Name)
+		}
+		if len(agents) == 0 {
+			exitWithError("no agents detected; use --agent to choose one")
+		}
+		return agents
+	}
+
+	if len(names) == 0 {
+		return []config.Agent{config.DefaultAgent()}
+	}
+
+	var agents []config.Agent
+	seen := make(map[config.Agent]bool)
+	for _, name := range names {
+		agent := config.Agent(strings.TrimSpace(name))
 		if config.GetAgentConfig(agent) == nil {
-			exitWithError(fmt.Sprintf("unknown agent: %s (try: claude, opencode, crush, cursor, windsurf)", learnAgent))
+			exitWithError(fmt.Sprintf("unknown agent: %s (try: claude, opencode, crush, cursor, windsurf)", name))
+		}
+		if !seen[agent] {
+			seen[agent] = true
+			agents = append(agents, agent)
 		}
 	}
+	return agents
+}
 
-	// Determine paths: local by default if attuned, global with --global flag
+// learnPathsForAgent resolves where to install for an agent: project-local by
+// default if attuned, global with --global (or a default-scope of global
+// without --project). Returns the paths and a label.
+func learnPathsForAgent(agent config.Agent) (*config.Paths, string) {
 	var paths *config.Paths
-	var installLocation string
+	var err error
+	installLocation := "global"
 
-	if learnGlobal {
+	if learnProject && !config.IsAttuned(agent) {
+		exitWithError("not attuned to this project; run 'tome attune' first")
+	}
+	if learnGlobal || (!learnProject && userConfig().DefaultScope == config.ScopeGlobal) {
 		// Explicit global install
 		paths, err = config.GetPathsForAgent(agent)
-		if err != nil {
-			exitWithError(err.Error())
-		}
-		installLocation = "global"
 	} else if config.IsAttuned(agent) {
 		// Project-local install (default when attuned)
 		paths, err = config.GetLocalPaths(agent)
-		if err != nil {
-			exitWithError(err.Error())
-		}
 		installLocation = "project"
 	} else {
 		// Not attuned, fall back to global
 		paths, err = config.GetPathsForAgent(agent)
-		if err != nil {
-			exitWithError(err.Error())
-		}
-		installLocation = "global"
 	}
-
-	agentCfg := config.GetAgentConfig(agent)
-	locationInfo := fmt.Sprintf("  Target: %s (%s)", agentCfg.DisplayName, installLocation)
-	fmt.Println(ui.Muted.Render(locationInfo))
-	fmt.Println()
-
-	// Ensure directories exist
-	if err := paths.EnsureDirs(); err != nil {
-		exitWithError(fmt.Sprintf("failed to create directories: %v", err))
+	if err != nil {
+		exitWithError(err.Error())
 	}
+	return paths, installLocation
+}
 
-	client := fetch.NewClient()
-
-	switch src.Type {
-	case source.TypeGitHub:
-		learnFromGitHub(client, src, paths)
-	case source.TypeURL:
-		learnFromURL(client, src, paths)
-	case source.TypeLocal:
-		learnFromLocal(src, paths)
+// installTargets returns the paths of every agent an artifact is installed
+// for: those resolved by runLearn, or just paths outside a learn run
+func installTargets(paths *config.Paths) []*config.Paths {
+	if len(learnTargets) > 0 {
+		return learnTargets
 	}
+	return []*config.Paths{paths}
 }
 
 func learnFromGitHub(client *fetch.Client, src *source.Source, paths *config.Paths) {
+	if src.Name != "" && fetch.IsMarkdownFile(src.Path) {
+		exitWithError(fmt.Sprintf("#%s selects an artifact from a directory, but %s is a file", src.Name, src.Path))
+	}
+
 	// Handle single file case
-	if src.Path != "" && strings.HasSuffix(strings.ToLower(src.Path), ".md") {
+	if src.Path != "" && fetch.IsMarkdownFile(src.Path) {
 		displayGitHubSource(src)
 		url := src.GitHubRawURL("")
 		learnSingleFile(client, url, filepath.Base(src.Path), src.String(), paths, nil)
 		return
 	}
 
+	if !learnArchive || !useRepoArchive(client, src) {
+		useRepoTree(client, src)
+	}
+
 	// Fetch README.md for requirement detection
 	readmeReqs := fetchReadmeRequirements(client, src)
 
 	// Try to list directory contents
 	apiURL := src.GitHubAPIURL()
 
-	// Check if this is a plugin
+	// Check if this is a plugin marketplace or a plugin
+	marketplace := client.IsMarketplace(apiURL)
+	if (marketplace || client.IsPlugin(apiURL)) && learnRenameTo != "" {
+		exitWithError("--rename needs a single artifact, not a plugin")
+	}
+	if marketplace {
+		learnMarketplace(client, src, apiURL, paths)
+		return
+	}
 	if client.IsPlugin(apiURL) {
 		learnPlugin(client, src, apiURL, paths)
 		return
@@ -149,10 +545,17 @@ func learnFromGitHub(client *fetch.Client, src *source.Source, paths *config.Pat
 	// Display source/collection info
 	manifest, _ := client.FetchManifest(apiURL)
 	displaySourceInfo(manifest, src)
+	if manifest != nil {
+		requireSources(manifest.Requires)
+	}
 
 	// Find artifacts
-	fmt.Println(ui.Muted.Render("  Scanning for artifacts..."))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render("  Scanning for artifacts..."))
 	artifacts, err := client.FindArtifacts(apiURL)
+	displaySkippedSubmodules(client)
+	if client.DuplicateSkills > 0 {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("  Collapsed %d duplicate skill(s) mirrored in agent directories", client.DuplicateSkills)))
+	}
 
 	// Handle fallback cases
 	if err != nil || len(artifacts) == 0 {
@@ -162,26 +565,118 @@ func learnFromGitHub(client *fetch.Client, src *source.Source, paths *config.Pat
 	}
 
 	// Install found artifacts
-	result := installFoundArtifacts(client, src, paths, artifacts, readmeReqs)
+	artifacts, filtered := filterArtifactsByType(artifacts)
+	if src.Name != "" {
+		if artifacts, err = selectArtifactByName(client, src, artifacts); err != nil {
+			exitWithError(err.Error())
+		}
+	}
+	if learnSHA256 != "" && len(artifacts) != 1 {
+		exitWithError(fmt.Sprintf("--sha256 needs a source with a single artifact (found %d); declare checksums in tome.yaml instead", len(artifacts)))
+	}
+	if err := checkRename(len(artifacts)); err != nil {
+		exitWithError(err.Error())
+	}
+	result := installFoundArtifacts(client, src, paths, artifacts, readmeReqs, manifest)
+	result.filtered = filtered
 
 	// Display summary
 	displayInstallSummary(result, src)
 }
 
+// useRepoArchive downloads the repo as a single tarball and serves discovery
+// from it. Returns false on failure (e.g. a private repo), in which case
+// learn fetches files individually.
+func useRepoArchive(client *fetch.Client, src *source.Source) bool {
+	archive, err := client.FetchArchive(src.GitHubArchiveURL())
+	if err != nil {
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Couldn't download archive, fetching files individually: %v", err)))
+		return false
+	}
+
+	client.UseArchive(archive, repoRootAPIURL(src), src.RepoRawURL)
+	fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("  Downloaded archive (%d files)", archive.Len())))
+	return true
+}
+
+// useRepoTree lists the whole repo in one request so discovery doesn't make
+// one per directory. On failure, discovery lists directories individually.
+func useRepoTree(client *fetch.Client, src *source.Source) {
+	if err := client.UseTree(repoRootAPIURL(src), src.RepoRawURL); err != nil {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("  Listing directories individually: %v", err)))
+	}
+}
+
+// repoRootAPIURL returns the contents API URL of the root of src's repo
+func repoRootAPIURL(src *source.Source) string {
+	root := *src
+	root.Path = ""
+	return root.GitHubAPIURL()
+}
+
+// displaySkippedSubmodules warns about submodules that discovery did not scan
+func displaySkippedSubmodules(client *fetch.Client) {
+	for _, sub := range client.SkippedSubmodules {
+		msg := fmt.Sprintf("  Skipping submodule %s", sub.Path)
+		if sub.SubmoduleGitURL != "" {
+			msg += fmt.Sprintf(" (%s)", sub.SubmoduleGitURL)
+		}
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(msg))
+	}
+	if len(client.SkippedSubmodules) > 0 && !client.FollowSubmodules {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render("    Use --follow-submodules to scan submodule repos"))
+	}
+}
+
+// learnFromRepo installs from a GitLab or Bitbucket repo. Directory discovery
+// relies on the GitHub contents API, so these sources must point at a markdown
+// file or a directory containing SKILL.md.
+func learnFromRepo(client *fetch.Client, src *source.Source, paths *config.Paths) {
+	if src.Name != "" {
+		exitWithError(fmt.Sprintf("#%s needs artifact discovery, which only GitHub repos support; use %s:<path> instead", src.Name, src.Provider))
+	}
+	fmt.Fprintln(learnOutput(), ui.Info.Render(fmt.Sprintf("  Source: %s", src.Provider)))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    %s/%s", src.Owner, src.Repo)))
+	if src.Path != "" {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    Path: %s", src.Path)))
+	}
+	fmt.Fprintln(learnOutput())
+
+	if src.Path != "" && fetch.IsMarkdownFile(src.Path) {
+		learnSingleFile(client, src.RawURL(""), filepath.Base(src.Path), src.String(), paths, nil)
+		return
+	}
+
+	readmeReqs := fetchReadmeRequirements(client, src)
+
+	skillURL := src.RawURL(artifact.SkillFilename)
+	content, err := client.FetchURL(skillURL)
+	if err != nil {
+		exitWithError(fmt.Sprintf("no %s found in %s (only GitHub repos support artifact discovery)", artifact.SkillFilename, src.String()))
+	}
+
+	art, err := fetch.ParseSkill(content, skillURL)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	art.Source = src.String()
+	installArtifactWithExtraReqs(art, paths, readmeReqs)
+}
+
 // displayGitHubSource shows source info for a GitHub URL
 func displayGitHubSource(src *source.Source) {
-	fmt.Println(ui.Info.Render("  Source: GitHub"))
-	fmt.Println(ui.Muted.Render(fmt.Sprintf("    %s/%s", src.Owner, src.Repo)))
+	fmt.Fprintln(learnOutput(), ui.Info.Render("  Source: GitHub"))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    %s/%s", src.Owner, src.Repo)))
 	if src.Path != "" {
-		fmt.Println(ui.Muted.Render(fmt.Sprintf("    Path: %s", src.Path)))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    Path: %s", src.Path)))
 	}
-	fmt.Println()
+	fmt.Fprintln(learnOutput())
 }
 
 // fetchReadmeRequirements fetches README.md and extracts requirements
 func fetchReadmeRequirements(client *fetch.Client, src *source.Source) []detect.Requirement {
 	for _, readmeName := range []string{"README.md", "readme.md", "Readme.md"} {
-		readmeURL := src.GitHubRawURL(readmeName)
+		readmeURL := src.RawURL(readmeName)
 		if content, err := client.FetchURL(readmeURL); err == nil {
 			return detect.FromContent(string(content))
 		}
@@ -192,24 +687,24 @@ func fetchReadmeRequirements(client *fetch.Client, src *source.Source) []detect.
 // displaySourceInfo shows collection or source information
 func displaySourceInfo(manifest *artifact.Manifest, src *source.Source) {
 	if manifest != nil && manifest.Name != "" {
-		fmt.Println(ui.Info.Render("  Collection: " + manifest.Name))
+		fmt.Fprintln(learnOutput(), ui.Info.Render("  Collection: "+manifest.Name))
 		if manifest.Description != "" {
-			fmt.Println(ui.Muted.Render("    " + manifest.Description))
+			fmt.Fprintln(learnOutput(), ui.Muted.Render("    "+manifest.Description))
 		}
 		if manifest.Author != "" {
-			fmt.Println(ui.Muted.Render(fmt.Sprintf("    by %s", manifest.Author)))
+			fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    by %s", manifest.Author)))
 		}
 		if manifest.Source != "" {
-			fmt.Println(ui.Muted.Render(fmt.Sprintf("    %s", manifest.Source)))
+			fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    %s", manifest.Source)))
 		}
 		if len(manifest.Tags) > 0 {
-			fmt.Println(ui.Muted.Render(fmt.Sprintf("    tags: %s", strings.Join(manifest.Tags, ", "))))
+			fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    tags: %s", strings.Join(manifest.Tags, ", "))))
 		}
 	} else {
 		displayGitHubSource(src)
 		return // displayGitHubSource already prints newline
 	}
-	fmt.Println()
+	fmt.Fprintln(learnOutput())
 }
 
 // tryFallbackSkill attempts to find a SKILL.md when artifact scanning fails
@@ -229,6 +724,9 @@ func tryFallbackSkill(client *fetch.Client, src *source.Source, paths *config.Pa
 		}
 		exitWithError("no artifacts found in repository")
 	}
+	if err := verifyChecksum(content, learnSHA256); err != nil {
+		exitWithError(fmt.Sprintf("refusing to install %s: %v", artifact.SkillFilename, err))
+	}
 
 	art, parseErr := fetch.ParseSkill(content, skillURL)
 	if parseErr != nil {
@@ -245,6 +743,8 @@ type installResult struct {
 	skipped       []skippedArtifact
 	allReqs       []detect.Requirement
 	skillContents []skillContent
+	aborted       bool // stopped at the first skip (--fail-fast)
+	filtered      int  // artifacts left out by --only
 }
 
 type skippedArtifact struct {
@@ -252,105 +752,349 @@ type skippedArtifact struct {
 	reason string
 }
 
+// learnWarning is a problem an installed artifact had for the target agent's
+// format, like a field the format has no place for or a hook that couldn't
+// be enabled
+type learnWarning struct {
+	Name    string        `json:"name"`
+	Format  schema.Format `json:"format"`
+	Message string        `json:"message"`
+}
+
+// skipExitStatus returns the exit code for a run that skipped n artifacts
+func skipExitStatus(n int) int {
+	if learnStrict && n > 0 {
+		return exitSkipped
+	}
+	return 0
+}
+
+// parseOnlyType validates --only
+func parseOnlyType(value string) (artifact.Type, error) {
+	switch t := artifact.Type(strings.ToLower(value)); t {
+	case "", artifact.TypeSkill, artifact.TypeCommand:
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid --only %q (want skill or command)", value)
+}
+
+// wantType reports whether --only lets artifacts of type t through
+func wantType(t artifact.Type) bool {
+	return learnOnlyType == "" || t == learnOnlyType
+}
+
+// filterArtifactsByType drops discovered artifacts --only excludes, judging
+// each by its filename, and returns how many were dropped
+func filterArtifactsByType(items []fetch.GitHubContent) ([]fetch.GitHubContent, int) {
+	if learnOnlyType == "" {
+		return items, 0
+	}
+	var kept []fetch.GitHubContent
+	for _, item := range items {
+		if wantType(fetch.DetectArtifactType(item.Name)) {
+			kept = append(kept, item)
+		}
+	}
+	return kept, len(items) - len(kept)
+}
+
+// displayFiltered notes how many artifacts --only left out
+func displayFiltered(n int) {
+	if n > 0 {
+		fmt.Fprintln(learnOutput())
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("  Filtered out %d artifact(s) that aren't %ss (--only %s)", n, learnOnlyType, learnOnlyType)))
+	}
+}
+
+// displayLearnWarnings lists the warnings collected while installing
+func displayLearnWarnings() {
+	if len(learnWarnings) == 0 {
+		return
+	}
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Warnings (%d):", len(learnWarnings))))
+	for _, w := range learnWarnings {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    • %s (%s): %s", w.Name, w.Format, w.Message)))
+	}
+}
+
 type skillContent struct {
 	name    string
 	content string
 }
 
-// installFoundArtifacts installs all found artifacts and returns the results
-func installFoundArtifacts(client *fetch.Client, src *source.Source, paths *config.Paths, artifacts []fetch.GitHubContent, readmeReqs []detect.Requirement) installResult {
-	fmt.Println(ui.Success.Render(fmt.Sprintf("  Found %d artifact(s)", len(artifacts))))
-	fmt.Println()
+// learnWorkers resolves the fetch concurrency: --jobs, then $TOME_JOBS, then
+// tome config's jobs, then the default
+func learnWorkers() int {
+	if learnJobs > 0 {
+		return learnJobs
+	}
+	if n, err := strconv.Atoi(os.Getenv(learnJobsEnvVar)); err == nil && n > 0 {
+		return n
+	}
+	if n := userConfig().Jobs; n > 0 {
+		return n
+	}
+	return defaultLearnJobs
+}
+
+// fetchedArtifact is an artifact fetched and parsed ahead of installation
+type fetchedArtifact struct {
+	item       fetch.GitHubContent
+	url        string
+	content    []byte
+	art        *artifact.Artifact
+	includes   []fetch.IncludedFile
+	includeErr error  // include discovery failed; the artifact still installs
+	skipReason string // non-empty when the artifact can't be installed
+	err        error
+}
+
+// fetchArtifacts fetches, parses, and discovers includes for each artifact
+// using up to workers concurrent requests, stepping progress (which may be
+// nil) as each finishes. Results keep the input order. With failFast, no new
+// fetches start once one fails, and only the artifacts handed out by then are
+// returned.
+func fetchArtifacts(client *fetch.Client, src *source.Source, artifacts []fetch.GitHubContent, workers int, failFast bool, progress *ui.Progress) []fetchedArtifact {
+	results := make([]fetchedArtifact, len(artifacts))
+	if workers < 1 {
+		workers = 1
+	}
+
+	var failed atomic.Bool
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(artifacts); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = fetchArtifact(client, src, artifacts[i])
+				if failFast && results[i].skipReason != "" {
+					failed.Store(true)
+				}
+				progress.Step(artifacts[i].Path)
+			}
+		}()
+	}
+	// Artifacts go out in order, so the ones never fetched all come after
+	// the failure that stopped the pool
+	sent := 0
+	for sent < len(artifacts) && !failed.Load() {
+		indexes <- sent
+		sent++
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results[:sent]
+}
+
+// fetchArtifact fetches and parses a single artifact along with its includes
+func fetchArtifact(client *fetch.Client, src *source.Source, item fetch.GitHubContent) fetchedArtifact {
+	f := fetchArtifactContent(client, src, item)
+	if f.art != nil {
+		f.includes, f.includeErr = discoverSkillIncludes(client, src, item, f.art)
+	}
+	return f
+}
+
+// fetchArtifactContent fetches and parses a single artifact without its includes
+func fetchArtifactContent(client *fetch.Client, src *source.Source, item fetch.GitHubContent) fetchedArtifact {
+	f := fetchedArtifact{item: item, url: item.DownloadURL}
+	if f.url == "" {
+		f.url = src.GitHubRawURL(item.Path)
+	}
+
+	content, err := client.FetchURL(f.url)
+	if err != nil {
+		f.skipReason, f.err = "fetch failed", err
+		return f
+	}
+	f.content = content
+
+	art, err := parseArtifact(content, item.Name, f.url)
+	if err != nil {
+		f.skipReason, f.err = "parse failed", err
+		return f
+	}
+	f.art = art
+	return f
+}
+
+// installFoundArtifacts installs all found artifacts and returns the results.
+// Fetching runs concurrently; installs (and their state saves) run one at a
+// time in discovery order so output stays deterministic.
+func installFoundArtifacts(client *fetch.Client, src *source.Source, paths *config.Paths, artifacts []fetch.GitHubContent, readmeReqs []detect.Requirement, manifest *artifact.Manifest) installResult {
+	fmt.Fprintln(learnOutput(), ui.Success.Render(fmt.Sprintf("  Found %d artifact(s)", len(artifacts))))
+	fmt.Fprintln(learnOutput())
 
 	var result installResult
 
-	for _, item := range artifacts {
-		url := item.DownloadURL
-		if url == "" {
-			url = src.GitHubRawURL(item.Path)
-		}
+	progress := ui.NewProgress(learnOutput(), "Fetching", len(artifacts))
+	fetched := fetchArtifacts(client, src, artifacts, learnWorkers(), learnFailFast, progress)
+	progress.Done()
 
-		content, err := client.FetchURL(url)
-		if err != nil {
-			fmt.Println(ui.Warning.Render(fmt.Sprintf("  Skipping %s: %v", item.Name, err)))
-			result.skipped = append(result.skipped, skippedArtifact{item.Name, fmt.Sprintf("fetch failed: %v", err)})
-			continue
+	// Ctrl-C cancels the fetches; stop before installing what was half fetched
+	if ctx := rootCmd.Context(); ctx != nil && ctx.Err() != nil {
+		exitWithError("interrupted")
+	}
+
+	if learnInteractive {
+		fetched = promptArtifactSelection(fetched)
+		if len(fetched) == 0 {
+			exitWithError("no artifacts selected")
 		}
+	}
 
-		art, err := parseArtifact(content, item.Name, url)
-		if err != nil {
-			fmt.Println(ui.Warning.Render(fmt.Sprintf("  Skipping %s: %v", item.Name, err)))
-			result.skipped = append(result.skipped, skippedArtifact{item.Name, fmt.Sprintf("parse failed: %v", err)})
+	// A fatal error partway through removes what this run installed
+	tx := beginInstall()
+	for _, f := range fetched {
+		if f.skipReason == "" {
+			if err := verifyChecksum(f.content, expectedChecksum(manifest, f.item)); err != nil {
+				f.skipReason, f.err = "checksum mismatch", err
+			}
+		}
+		if f.skipReason != "" {
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipping %s: %v", f.item.Name, f.err)))
+			result.skipped = append(result.skipped, skippedArtifact{f.item.Name, fmt.Sprintf("%s: %v", f.skipReason, f.err)})
+			if learnFailFast {
+				result.aborted = true
+				break
+			}
 			continue
 		}
+		if f.includeErr != nil {
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Warning: couldn't fetch skill files for %s: %v", f.item.Name, f.includeErr)))
+		}
 
-		// Discover skill includes if applicable
-		includes := discoverSkillIncludes(client, src, item, art)
-
+		art := f.art
 		art.Source = src.String()
-		reqs := installArtifactQuietWithExtras(art, paths, includes, readmeReqs)
+		if manifest != nil {
+			art.Tags = artifact.MergeTags(art.Tags, manifest.Tags)
+		}
+		reqs, ok := installArtifactQuietWithExtras(art, paths, f.includes, readmeReqs)
+		if !ok {
+			result.skipped = append(result.skipped, skippedArtifact{f.item.Name, "name conflict"})
+			continue
+		}
 		result.installed = append(result.installed, art.Name)
 		result.allReqs = detect.Merge(result.allReqs, reqs)
 
 		if art.Type == artifact.TypeSkill {
-			result.skillContents = append(result.skillContents, skillContent{art.Name, string(content)})
+			result.skillContents = append(result.skillContents, skillContent{art.Name, string(f.content)})
 		}
 	}
+	saveLockUpdate()
+	tx.commit()
 
 	return result
 }
 
-// discoverSkillIncludes finds additional files to include with a skill
-func discoverSkillIncludes(client *fetch.Client, src *source.Source, item fetch.GitHubContent, art *artifact.Artifact) []fetch.IncludedFile {
-	if art.Type != artifact.TypeSkill {
+// expectedChecksum returns the sha256 an artifact must match: --sha256 when
+// given, otherwise whatever the collection's tome.yaml declares
+func expectedChecksum(manifest *artifact.Manifest, item fetch.GitHubContent) string {
+	if learnSHA256 != "" {
+		return learnSHA256
+	}
+	return manifest.Checksum(item.Path, fetch.TrimMarkdownExt(item.Name))
+}
+
+// verifyChecksum checks content against an expected sha256 (hex, optionally
+// prefixed with "sha256:"); an empty expectation always passes
+func verifyChecksum(content []byte, expected string) error {
+	expected = strings.ToLower(strings.TrimPrefix(expected, "sha256:"))
+	if expected == "" {
 		return nil
 	}
+	if actual := hashContent(content); actual != expected {
+		return fmt.Errorf("expected sha256 %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// discoverSkillIncludes finds additional files to include with a skill. When
+// the skill lists includes, exactly those are fetched; otherwise every allowed
+// file in the skill's directory is discovered.
+func discoverSkillIncludes(client *fetch.Client, src *source.Source, item fetch.GitHubContent, art *artifact.Artifact) ([]fetch.IncludedFile, error) {
+	if art.Type != artifact.TypeSkill {
+		return nil, nil
+	}
 
 	skillDir := item.SkillDir
 	if skillDir == "" && src.Path != "" {
 		skillDir = src.Path
 	}
+
+	if len(art.Includes) > 0 {
+		return client.FetchSkillIncludes(skillRawBaseURL(src, item, skillDir, art.SourceURL), "", art.Includes)
+	}
+
 	if skillDir == "" {
-		return nil
+		return nil, nil
 	}
 
 	// Build base API URL for discovery
 	var baseAPIURL string
-	if src.Host == "github.com" || src.Host == "" {
+	if item.RepoAPIURL != "" {
+		// Artifact came from a followed submodule; includes live in that repo
+		baseAPIURL = item.RepoAPIURL
+	} else if src.Host == "github.com" || src.Host == "" {
 		baseAPIURL = fmt.Sprintf("https://api.github.com/repos/%s/%s/contents", src.Owner, src.Repo)
 	} else {
 		baseAPIURL = fmt.Sprintf("https://%s/api/v3/repos/%s/%s/contents", src.Host, src.Owner, src.Repo)
 	}
-	if src.Ref != "" {
-		baseAPIURL += "?ref=" + src.Ref
+	// Fetch includes from the pinned ref rather than the listing's download
+	// URLs; submodule repos keep their own refs, so use theirs
+	var rawURL fetch.RawURLFunc
+	if src.FetchRef() != "" && item.RepoAPIURL == "" {
+		baseAPIURL += "?ref=" + src.FetchRef()
+		rawURL = src.RepoRawURL
 	}
 
-	includes, err := client.DiscoverSkillFiles(baseAPIURL, skillDir)
-	if err != nil {
-		fmt.Println(ui.Warning.Render(fmt.Sprintf("  Warning: couldn't fetch skill files for %s: %v", item.Name, err)))
+	return client.DiscoverSkillFiles(baseAPIURL, skillDir, rawURL)
+}
+
+// skillRawBaseURL returns the raw URL of the directory holding a skill's
+// SKILL.md, pinned to the source's ref unless the skill came from a submodule
+func skillRawBaseURL(src *source.Source, item fetch.GitHubContent, skillDir, skillURL string) string {
+	if src.FetchRef() != "" && item.RepoAPIURL == "" {
+		if skillDir != "" {
+			return src.RepoRawURL(skillDir)
+		}
+		return strings.TrimSuffix(src.RepoRawURL(""), "/")
 	}
-	return includes
+	return skillURL[:strings.LastIndex(skillURL, "/")]
 }
 
 // displayInstallSummary shows the final installation summary
 func displayInstallSummary(result installResult, src *source.Source) {
-	fmt.Println()
+	learnSkipped = result.skipped
+	fmt.Fprintln(learnOutput())
 	if len(result.installed) > 0 {
-		fmt.Println(ui.SuccessLine(fmt.Sprintf("Inscribed %d artifact(s)", len(result.installed))))
+		fmt.Fprintln(learnOutput(), ui.SuccessLine(fmt.Sprintf("%s %d artifact(s)", inscribedVerb(), len(result.installed))))
 		for _, name := range result.installed {
-			fmt.Println(ui.Muted.Render("    • " + name))
+			fmt.Fprintln(learnOutput(), ui.Muted.Render("    • "+name))
 		}
 	}
 
 	if len(result.skipped) > 0 {
-		fmt.Println()
-		fmt.Println(ui.Warning.Render(fmt.Sprintf("  Skipped %d artifact(s):", len(result.skipped))))
+		fmt.Fprintln(learnOutput())
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipped %d artifact(s):", len(result.skipped))))
 		for _, s := range result.skipped {
-			fmt.Println(ui.Muted.Render(fmt.Sprintf("    • %s: %s", s.name, s.reason)))
+			fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    • %s: %s", s.name, s.reason)))
 		}
 	}
+	displayFiltered(result.filtered)
+	displayLearnWarnings()
 
+	if result.aborted {
+		exitWithError(fmt.Sprintf("stopped after %s failed (--fail-fast)", result.skipped[len(result.skipped)-1].name))
+	}
 	if len(result.installed) == 0 {
+		if len(result.skipped) == 0 && result.filtered > 0 {
+			exitWithError(fmt.Sprintf("no %ss found (--only %s)", learnOnlyType, learnOnlyType))
+		}
 		exitWithError("no artifacts were installed successfully")
 	}
 
@@ -358,32 +1102,36 @@ func displayInstallSummary(result installResult, src *source.Source) {
 		displayDetectedRequirements(src.String(), result.allReqs)
 	}
 
-	fmt.Println()
-	fmt.Println(ui.Dim.Render("  Your tome grows stronger."))
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Dim.Render("  Your tome grows stronger."))
 
 	// Show usage info for installed skills
 	for _, skill := range result.skillContents {
 		usage := extractUsageSection(skill.content)
 		if usage != "" {
-			fmt.Println()
-			fmt.Println(ui.Subtitle.Render(fmt.Sprintf("  Quick Start: %s", skill.name)))
-			fmt.Println(ui.Divider(50))
+			fmt.Fprintln(learnOutput())
+			fmt.Fprintln(learnOutput(), ui.Subtitle.Render(fmt.Sprintf("  Quick Start: %s", skill.name)))
+			fmt.Fprintln(learnOutput(), ui.Divider(50))
 			for _, line := range strings.Split(usage, "\n") {
-				fmt.Println("  " + line)
+				fmt.Fprintln(learnOutput(), "  "+line)
 			}
 		}
 	}
 
-	fmt.Println(ui.PageFooter())
+	fmt.Fprintln(learnOutput(), ui.PageFooter())
+	learnExitStatus = skipExitStatus(len(result.skipped))
 }
 
 func learnSingleFile(client *fetch.Client, url, filename, source string, paths *config.Paths, extraReqs []detect.Requirement) {
-	fmt.Println(ui.Muted.Render("  Fetching " + filename))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render("  Fetching "+filename))
 
 	content, err := client.FetchURL(url)
 	if err != nil {
 		exitWithError(err.Error())
 	}
+	if err := verifyChecksum(content, learnSHA256); err != nil {
+		exitWithError(fmt.Sprintf("refusing to install %s: %v", filename, err))
+	}
 
 	art, err := parseArtifact(content, filename, url)
 	if err != nil {
@@ -395,16 +1143,20 @@ func learnSingleFile(client *fetch.Client, url, filename, source string, paths *
 }
 
 func parseArtifact(content []byte, filename, sourceURL string) (*artifact.Artifact, error) {
-	artType := fetch.DetectArtifactType(filename)
+	artType := fetch.DetectArtifactTypeFromContent(filename, content)
 
 	switch artType {
 	case artifact.TypeSkill:
 		return fetch.ParseSkill(content, sourceURL)
 	case artifact.TypeCommand:
+		if path.Ext(filename) == "" {
+			// Guessed from content; install it as markdown
+			filename += ".md"
+		}
 		return fetch.ParseCommand(content, filename, sourceURL)
 	default:
-		// Default to command for unknown .md files
-		if strings.HasSuffix(strings.ToLower(filename), ".md") {
+		// Default to command for unknown markdown files
+		if fetch.IsMarkdownFile(filename) {
 			return fetch.ParseCommand(content, filename, sourceURL)
 		}
 		return nil, fmt.Errorf("unknown artifact type for %s", filename)
@@ -412,18 +1164,18 @@ func parseArtifact(content []byte, filename, sourceURL string) (*artifact.Artifa
 }
 
 func learnFromURL(client *fetch.Client, src *source.Source, paths *config.Paths) {
-	fmt.Println(ui.Info.Render("  Source: URL"))
-	fmt.Println(ui.Muted.Render("    " + src.URL))
-	fmt.Println()
+	fmt.Fprintln(learnOutput(), ui.Info.Render("  Source: URL"))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render("    "+src.URL))
+	fmt.Fprintln(learnOutput())
 
 	filename := filepath.Base(src.URL)
 	learnSingleFile(client, src.URL, filename, src.Original, paths, nil)
 }
 
 func learnFromLocal(src *source.Source, paths *config.Paths) {
-	fmt.Println(ui.Info.Render("  Source: Local"))
-	fmt.Println(ui.Muted.Render("    " + src.Path))
-	fmt.Println()
+	fmt.Fprintln(learnOutput(), ui.Info.Render("  Source: Local"))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render("    "+src.Path))
+	fmt.Fprintln(learnOutput())
 
 	// Check if it's a file or directory
 	info, err := os.Stat(src.Path)
@@ -438,6 +1190,10 @@ func learnFromLocal(src *source.Source, paths *config.Paths) {
 			exitWithError(fmt.Sprintf("cannot read %s: %v", src.Path, err))
 		}
 
+		if err := verifyChecksum(content, learnSHA256); err != nil {
+			exitWithError(fmt.Sprintf("refusing to install %s: %v", src.Path, err))
+		}
+
 		filename := filepath.Base(src.Path)
 		art, err := parseArtifact(content, filename, src.Path)
 		if err != nil {
@@ -449,87 +1205,154 @@ func learnFromLocal(src *source.Source, paths *config.Paths) {
 		return
 	}
 
+	manifest, err := fetch.ReadLocalManifest(src.Path)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if manifest != nil {
+		requireSources(manifest.Requires)
+	}
+
 	// Directory - scan for artifacts
-	fmt.Println(ui.Muted.Render("  Scanning for artifacts..."))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render("  Scanning for artifacts..."))
 
-	entries, err := os.ReadDir(src.Path)
+	files, err := fetch.FindLocalArtifacts(src.Path, learnExclude)
 	if err != nil {
 		exitWithError(fmt.Sprintf("cannot read directory: %v", err))
 	}
+	if learnRenameTo != "" || learnSHA256 != "" {
+		wanted := 0
+		for _, filePath := range files {
+			if wantType(fetch.DetectArtifactType(filepath.Base(filePath))) {
+				wanted++
+			}
+		}
+		if learnSHA256 != "" && wanted != 1 {
+			exitWithError(fmt.Sprintf("--sha256 needs a source with a single artifact (found %d); declare checksums in tome.yaml instead", wanted))
+		}
+		if err := checkRename(wanted); err != nil {
+			exitWithError(err.Error())
+		}
+	}
 
 	var installed []string
-	var skipped []struct {
-		name   string
-		reason string
-	}
-	for _, entry := range entries {
-		if entry.IsDir() {
+	var skipped []skippedArtifact
+	filtered := 0
+	aborted := false // stopped at the first skip (--fail-fast)
+	tx := beginInstall()
+	for _, filePath := range files {
+		name, _ := filepath.Rel(src.Path, filePath)
+		if !wantType(fetch.DetectArtifactType(filepath.Base(filePath))) {
+			filtered++
+			continue
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipping %s: %v", name, err)))
+			skipped = append(skipped, skippedArtifact{name, fmt.Sprintf("read failed: %v", err)})
+			if learnFailFast {
+				aborted = true
+				break
+			}
 			continue
 		}
 
-		if !fetch.IsArtifactFile(entry.Name()) {
+		item := fetch.GitHubContent{Name: filepath.Base(filePath), Path: filepath.ToSlash(name)}
+		if err := verifyChecksum(content, expectedChecksum(manifest, item)); err != nil {
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipping %s: %v", name, err)))
+			skipped = append(skipped, skippedArtifact{name, fmt.Sprintf("checksum mismatch: %v", err)})
+			if learnFailFast {
+				aborted = true
+				break
+			}
 			continue
 		}
 
-		filePath := filepath.Join(src.Path, entry.Name())
-		content, err := os.ReadFile(filePath)
+		art, err := parseArtifact(content, filepath.Base(filePath), filePath)
 		if err != nil {
-			fmt.Println(ui.Warning.Render(fmt.Sprintf("  Skipping %s: %v", entry.Name(), err)))
-			skipped = append(skipped, struct {
-				name   string
-				reason string
-			}{entry.Name(), fmt.Sprintf("read failed: %v", err)})
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipping %s: %v", name, err)))
+			skipped = append(skipped, skippedArtifact{name, fmt.Sprintf("parse failed: %v", err)})
+			if learnFailFast {
+				aborted = true
+				break
+			}
 			continue
 		}
 
-		art, err := parseArtifact(content, entry.Name(), filePath)
+		// Skills bring the includes they list; without a list, skills in
+		// subdirectories carry the other files in their directory along (a
+		// root SKILL.md shares its directory with the rest of the collection)
+		var includes []fetch.IncludedFile
+		skillDir := filepath.Dir(filePath)
+		switch {
+		case art.Type != artifact.TypeSkill:
+		case len(art.Includes) > 0:
+			includes, err = fetch.ReadLocalSkillIncludes(skillDir, art.Includes)
+		case skillDir != filepath.Clean(src.Path):
+			includes, err = fetch.DiscoverLocalSkillFiles(skillDir)
+		}
 		if err != nil {
-			fmt.Println(ui.Warning.Render(fmt.Sprintf("  Skipping %s: %v", entry.Name(), err)))
-			skipped = append(skipped, struct {
-				name   string
-				reason string
-			}{entry.Name(), fmt.Sprintf("parse failed: %v", err)})
-			continue
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Warning: couldn't read skill files for %s: %v", name, err)))
 		}
 
 		art.Source = src.Original
-		installArtifactQuiet(art, paths)
+		if _, ok := installArtifactQuietWithExtras(art, paths, includes, nil); !ok {
+			skipped = append(skipped, skippedArtifact{name, "name conflict"})
+			continue
+		}
 		installed = append(installed, art.Name)
 	}
+	saveLockUpdate()
+	tx.commit()
 
+	learnSkipped = skipped
 	if len(installed) == 0 && len(skipped) == 0 {
+		if filtered > 0 {
+			exitWithError(fmt.Sprintf("no %ss found in directory (--only %s)", learnOnlyType, learnOnlyType))
+		}
 		exitWithError("no artifacts found in directory")
 	}
 
 	if len(installed) == 0 && len(skipped) > 0 {
 		// Found artifacts but all failed
-		fmt.Println()
-		fmt.Println(ui.Warning.Render(fmt.Sprintf("  Skipped %d artifact(s):", len(skipped))))
+		fmt.Fprintln(learnOutput())
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipped %d artifact(s):", len(skipped))))
 		for _, s := range skipped {
-			fmt.Println(ui.Muted.Render(fmt.Sprintf("    • %s: %s", s.name, s.reason)))
+			fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    • %s: %s", s.name, s.reason)))
+		}
+		if aborted {
+			exitWithError(fmt.Sprintf("stopped after %s failed (--fail-fast)", skipped[len(skipped)-1].name))
 		}
 		exitWithError("no artifacts were installed successfully")
 	}
 
 	// Summary
-	fmt.Println()
-	fmt.Println(ui.SuccessLine(fmt.Sprintf("Inscribed %d artifact(s)", len(installed))))
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.SuccessLine(fmt.Sprintf("%s %d artifact(s)", inscribedVerb(), len(installed))))
 	for _, name := range installed {
-		fmt.Println(ui.Muted.Render("    • " + name))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render("    • "+name))
 	}
 
 	// Report any skipped artifacts
 	if len(skipped) > 0 {
-		fmt.Println()
-		fmt.Println(ui.Warning.Render(fmt.Sprintf("  Skipped %d artifact(s):", len(skipped))))
+		fmt.Fprintln(learnOutput())
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipped %d artifact(s):", len(skipped))))
 		for _, s := range skipped {
-			fmt.Println(ui.Muted.Render(fmt.Sprintf("    • %s: %s", s.name, s.reason)))
+			fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    • %s: %s", s.name, s.reason)))
 		}
 	}
+	displayFiltered(filtered)
+	displayLearnWarnings()
+
+	if aborted {
+		exitWithError(fmt.Sprintf("stopped after %s failed (--fail-fast)", skipped[len(skipped)-1].name))
+	}
 
-	fmt.Println()
-	fmt.Println(ui.Dim.Render("  Your tome grows stronger."))
-	fmt.Println(ui.PageFooter())
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Dim.Render("  Your tome grows stronger."))
+	fmt.Fprintln(learnOutput(), ui.PageFooter())
+	learnExitStatus = skipExitStatus(len(skipped))
 }
 
 func installArtifact(art *artifact.Artifact, paths *config.Paths) {
@@ -537,69 +1360,164 @@ func installArtifact(art *artifact.Artifact, paths *config.Paths) {
 }
 
 func installArtifactWithExtraReqs(art *artifact.Artifact, paths *config.Paths, extraReqs []detect.Requirement) {
-	reqs := doInstallWithExtraReqs(art, paths, nil, extraReqs)
+	reqs, ok := doInstallWithExtraReqs(art, paths, nil, extraReqs)
+	if !ok {
+		learnSkipped = append(learnSkipped, skippedArtifact{art.Name, "name conflict"})
+		learnExitStatus = skipExitStatus(1)
+		fmt.Fprintln(learnOutput(), ui.PageFooter())
+		return
+	}
 
 	// Success output
 	badge := getBadge(art.Type)
-	fmt.Printf("\n  %s %s\n", badge, ui.Highlight.Render(art.Name))
+	fmt.Fprintf(learnOutput(), "\n  %s %s\n", badge, ui.Highlight.Render(art.Name))
 	if art.Description != "" {
 		desc := ui.Truncate(art.Description, 55)
-		fmt.Println(ui.Muted.Render("  " + desc))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render("  "+desc))
+	}
+	fmt.Fprintln(learnOutput())
+	if learnDryRun {
+		fmt.Fprintln(learnOutput(), ui.SuccessLine("Dry run complete"))
+	} else {
+		fmt.Fprintln(learnOutput(), ui.SuccessLine("Inscribed successfully"))
+	}
+	for _, target := range installTargets(paths) {
+		if installPath, err := getInstallPath(art, target, singleFileSkill(art, nil)); err == nil {
+			fmt.Fprintln(learnOutput(), ui.Dim.Render("  "+installPath))
+		}
 	}
-	fmt.Println()
-	fmt.Println(ui.SuccessLine("Inscribed successfully"))
-	fmt.Println(ui.Dim.Render("  " + getInstallPath(art, paths)))
 
 	// Display detected requirements
 	displayDetectedRequirements(art.Name, reqs)
+	displayLearnWarnings()
 
-	fmt.Println()
-	fmt.Println(ui.Dim.Render("  Your tome grows stronger."))
-	fmt.Println(ui.PageFooter())
-}
-
-func installArtifactQuiet(art *artifact.Artifact, paths *config.Paths) {
-	installArtifactQuietWithExtras(art, paths, nil, nil)
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Dim.Render("  Your tome grows stronger."))
+	fmt.Fprintln(learnOutput(), ui.PageFooter())
 }
 
-func installArtifactQuietWithExtras(art *artifact.Artifact, paths *config.Paths, includes []fetch.IncludedFile, extraReqs []detect.Requirement) []detect.Requirement {
-	reqs := doInstallWithExtraReqs(art, paths, includes, extraReqs)
+// installArtifactQuietWithExtras installs an artifact with a one-line report
+// and returns its requirements, or false if a name conflict skipped it
+func installArtifactQuietWithExtras(art *artifact.Artifact, paths *config.Paths, includes []fetch.IncludedFile, extraReqs []detect.Requirement) ([]detect.Requirement, bool) {
+	reqs, ok := doInstallWithExtraReqs(art, paths, includes, extraReqs)
+	if !ok {
+		return nil, false
+	}
 
 	badge := getBadge(art.Type)
 	name := art.Name
 	if len(includes) > 0 {
 		name = fmt.Sprintf("%s (+%d files)", art.Name, len(includes))
 	}
-	fmt.Printf("  %s %s\n", badge, ui.Highlight.Render(name))
-	return reqs
-}
-
-func doInstallWithExtraReqs(art *artifact.Artifact, paths *config.Paths, includes []fetch.IncludedFile, extraReqs []detect.Requirement) []detect.Requirement {
-	reqs := doInstallWithIncludes(art, paths, includes)
-	// Merge extra requirements (e.g., from README)
-	if len(extraReqs) > 0 {
-		reqs = detect.Merge(reqs, extraReqs)
-		// Update the state with merged requirements
-		state, err := config.LoadState(paths.StateFile)
-		if err == nil {
-			if installed := state.FindInstalled(art.Name); installed != nil {
-				installed.Requirements = reqs
-				config.SaveState(paths.StateFile, state)
+	fmt.Fprintf(learnOutput(), "  %s %s\n", badge, ui.Highlight.Render(name))
+	return reqs, true
+}
+
+// doInstallWithExtraReqs installs an artifact for every target agent and
+// returns its requirements, which are the same for each target. It returns
+// false, installing nothing, when the artifact's name is taken by one from
+// another source and the conflict policy skips it; renaming updates art.Name.
+func doInstallWithExtraReqs(art *artifact.Artifact, paths *config.Paths, includes []fetch.IncludedFile, extraReqs []detect.Requirement) ([]detect.Requirement, bool) {
+	fetched := *art // As the source has it, which tome.lock pins
+	if learnRenameTo != "" {
+		renameArtifact(art, artifact.SanitizeFilename(learnRenameTo))
+	}
+	art.SourceURL = unpinnedURL(art.SourceURL)
+	targets := installTargets(paths)
+	if !resolveConflict(art, targets, learnConflictPolicy) {
+		return nil, false
+	}
+
+	var reqs []detect.Requirement
+	for i, target := range targets {
+		// Each target gets its own copy; flattening and conversion rewrite it
+		targetArt := *art
+		targetReqs, installPath := doInstallWithIncludes(&targetArt, target, includes, extraReqs, learnDryRun)
+		if i == 0 {
+			reqs = targetReqs
+			learnedArtifacts = append(learnedArtifacts, learnedArtifact{targetArt.Name, targetArt.Type, installPath})
+		}
+	}
+	if learnLock != nil {
+		if entry, ok := lockEntryFor(&fetched, learnSource, learnCommit); ok {
+			if art.Name != fetched.Name {
+				entry.Name, entry.Selector = art.Name, fetched.Name
 			}
+			learnLock.Set(entry)
 		}
 	}
-	return reqs
+	learnedReqs = detect.Merge(learnedReqs, reqs)
+	requireSources(art.Requires)
+	return reqs, true
+}
+
+// inscribedVerb describes installed artifacts in summaries, which only
+// preview the install under --dry-run
+func inscribedVerb() string {
+	if learnDryRun {
+		return "Would inscribe"
+	}
+	return "Inscribed"
 }
 
-func doInstallWithIncludes(art *artifact.Artifact, paths *config.Paths, includes []fetch.IncludedFile) []detect.Requirement {
+// doInstallWithIncludes writes an artifact and its includes and records it
+// in state, merging extraReqs (e.g. from the README) into the detected
+// requirements. With dryRun, it does everything up to writing (conversion and
+// requirement detection included) and prints the paths it would write. It
+// returns the requirements and the path the artifact is written to.
+func doInstallWithIncludes(art *artifact.Artifact, paths *config.Paths, includes []fetch.IncludedFile, extraReqs []detect.Requirement, dryRun bool) ([]detect.Requirement, string) {
+	// Requirement detection looks at every include, even ones inlined below
+	var includePaths []string
+	for _, inc := range includes {
+		includePaths = append(includePaths, inc.Path)
+	}
+
+	// Record the fetched content's checksum before flattening rewrites it
+	contentHash := hashContent([]byte(art.Content))
+
+	// Inline text includes into the skill body when flattening
+	flattened := false
+	if learnFlattenSkill && art.Type == artifact.TypeSkill && len(includes) > 0 {
+		art.Content, includes = fetch.FlattenSkill(art.Content, includes)
+		flattened = true
+		for _, inc := range includes {
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Note: %s can't be inlined; writing it as a separate file", inc.Path)))
+		}
+	}
+
+	// A skill's includes go next to it, so it keeps its directory
+	singleFile := singleFileSkill(art, includes)
+	if learnSingleFileSkills && art.Type == artifact.TypeSkill && !singleFile {
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Note: %s has %d include file(s), which need a skill directory; installing it as %s (--flatten-skill inlines text includes)", art.Name, len(includes), artifact.SkillFilename)))
+	}
+
 	// Convert artifact to target format if needed
 	convertedContent, wasConverted := convertArtifactIfNeeded(art, paths)
 
-	installPath := getInstallPath(art, paths)
+	installPath, err := getInstallPath(art, paths, singleFile)
+	if err != nil {
+		exitWithError(err.Error())
+	}
 	installDir := filepath.Dir(installPath)
 
+	// Detect requirements from content and includes
+	contentReqs := detect.FromContent(art.Content)
+	includeReqs := detect.FromIncludes(includePaths)
+	allReqs := detect.Merge(detect.Merge(contentReqs, includeReqs), extraReqs)
+	allReqs = detect.ApplyIgnores(allReqs, requirementIgnores(art.Content, paths))
+
+	if dryRun {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render("    [dry-run] Would write: "+installPath))
+		if art.Type == artifact.TypeSkill {
+			for _, inc := range includes {
+				fmt.Fprintln(learnOutput(), ui.Muted.Render("    [dry-run] Would write: "+filepath.Join(installDir, inc.Path)))
+			}
+		}
+		return allReqs, installPath
+	}
+
 	// Create directory if needed
-	if err := os.MkdirAll(installDir, 0755); err != nil {
+	if err := activeInstall.mkdirAll(installDir); err != nil {
 		exitWithError(fmt.Sprintf("failed to create directory: %v", err))
 	}
 
@@ -608,28 +1526,26 @@ func doInstallWithIncludes(art *artifact.Artifact, paths *config.Paths, includes
 	if wasConverted {
 		contentToWrite = convertedContent
 	}
-	if err := os.WriteFile(installPath, []byte(contentToWrite), 0644); err != nil {
+	if err := activeInstall.writeFile(installPath, []byte(contentToWrite), 0644); err != nil {
 		exitWithError(fmt.Sprintf("failed to write file: %v", err))
 	}
 
-	// Collect include paths for requirement detection
-	var includePaths []string
-
 	// Write included files (for skills)
+	var writtenIncludes []string
 	if art.Type == artifact.TypeSkill && len(includes) > 0 {
 		skillDir := filepath.Dir(installPath)
 		for _, inc := range includes {
 			incPath := filepath.Join(skillDir, inc.Path)
 			incDir := filepath.Dir(incPath)
-			includePaths = append(includePaths, inc.Path)
+			writtenIncludes = append(writtenIncludes, inc.Path)
 
 			// Create subdirectory if needed
-			if err := os.MkdirAll(incDir, 0755); err != nil {
+			if err := activeInstall.mkdirAll(incDir); err != nil {
 				exitWithError(fmt.Sprintf("failed to create directory for %s: %v", inc.Path, err))
 			}
 
 			// Write the included file
-			if err := os.WriteFile(incPath, inc.Content, 0644); err != nil {
+			if err := activeInstall.writeFile(incPath, inc.Content, 0644); err != nil {
 				exitWithError(fmt.Sprintf("failed to write %s: %v", inc.Path, err))
 			}
 
@@ -640,11 +1556,6 @@ func doInstallWithIncludes(art *artifact.Artifact, paths *config.Paths, includes
 		}
 	}
 
-	// Detect requirements from content and includes
-	contentReqs := detect.FromContent(art.Content)
-	includeReqs := detect.FromIncludes(includePaths)
-	allReqs := detect.Merge(contentReqs, includeReqs)
-
 	// Update state
 	state, err := config.LoadState(paths.StateFile)
 	if err != nil {
@@ -654,131 +1565,189 @@ func doInstallWithIncludes(art *artifact.Artifact, paths *config.Paths, includes
 	installed := artifact.InstalledArtifact{
 		Artifact:     *art,
 		LocalPath:    installPath,
+		Agent:        string(paths.Agent),
+		Flattened:    flattened,
+		SingleFile:   singleFile,
+		Hash:         contentHash,
 		Requirements: allReqs,
 	}
+	installed.Includes = writtenIncludes
 	installed.InstalledAt = time.Now()
 
 	state.AddInstalled(installed)
 
 	// Ensure state directory exists
 	stateDir := filepath.Dir(paths.StateFile)
-	if err := os.MkdirAll(stateDir, 0755); err != nil {
+	if err := activeInstall.mkdirAll(stateDir); err != nil {
 		exitWithError(fmt.Sprintf("failed to create state directory: %v", err))
 	}
+	if err := activeInstall.track(paths.StateFile); err != nil {
+		exitWithError(fmt.Sprintf("failed to read state: %v", err))
+	}
 
 	if err := config.SaveState(paths.StateFile, state); err != nil {
 		exitWithError(fmt.Sprintf("failed to save state: %v", err))
 	}
 
-	return allReqs
+	return allReqs, installPath
 }
 
 // convertArtifactIfNeeded converts artifact content to the target agent's format
 // Returns the converted content and whether conversion was performed
 func convertArtifactIfNeeded(art *artifact.Artifact, paths *config.Paths) (string, bool) {
-	// Determine source format from the artifact's source URL/filename
-	sourceFilename := art.Filename
-	if art.Source != "" {
-		// Extract filename from source URL
-		if idx := strings.LastIndex(art.Source, "/"); idx >= 0 {
-			sourceFilename = art.Source[idx+1:]
-		}
+	result, ok := convertArtifact(art, paths)
+	if !ok {
+		return "", false
 	}
 
-	sourceFormat := schema.DetectFormat(sourceFilename, []byte(art.Content))
+	// Log conversion; warnings wait for the summary
+	fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    Converting: %s → %s", result.SourceFormat, result.TargetFormat)))
+	for _, w := range result.Warnings {
+		learnWarnings = append(learnWarnings, learnWarning{art.Name, result.TargetFormat, w})
+	}
+
+	return string(result.Content), true
+}
+
+// convertArtifact converts artifact content to the target agent's format without output
+// Returns the conversion result and whether conversion was performed
+func convertArtifact(art *artifact.Artifact, paths *config.Paths) (*schema.ConversionResult, bool) {
+	sourceFormat := schema.DetectFormat(artifactSourceFile(art), []byte(art.Content))
 	targetFormat := config.AgentToFormat(paths.Agent)
+	if art.Type == artifact.TypeCommand {
+		targetFormat = config.CommandFormat(paths.Agent)
+	}
 
 	// No conversion needed if formats match
 	if sourceFormat == targetFormat {
-		return "", false
+		return nil, false
 	}
 
 	// Parse and convert based on artifact type
-	var converted []byte
-	var err error
 	var result *schema.ConversionResult
+	var err error
 
 	switch art.Type {
 	case artifact.TypeSkill:
 		skill, parseErr := schema.Parse([]byte(art.Content), sourceFormat)
 		if parseErr != nil {
 			// Can't parse, skip conversion
-			return "", false
+			return nil, false
 		}
 		result, err = schema.ConvertWithInfo(skill, targetFormat)
-		if err == nil {
-			converted = result.Content
-		}
 
 	case artifact.TypeCommand:
 		cmd, parseErr := schema.ParseCommand([]byte(art.Content), sourceFormat)
 		if parseErr != nil {
-			return "", false
+			return nil, false
 		}
 		result, err = schema.ConvertCommandWithInfo(cmd, targetFormat)
-		if err == nil {
-			converted = result.Content
-		}
 
 	default:
 		// Unknown type, skip conversion
-		return "", false
+		return nil, false
 	}
 
 	if err != nil {
 		// Conversion failed, use original
-		return "", false
+		return nil, false
 	}
 
-	// Log conversion
-	fmt.Println(ui.Muted.Render(fmt.Sprintf("    Converting: %s → %s", sourceFormat, targetFormat)))
+	return result, true
+}
 
-	// Show warnings if any
-	if result != nil && len(result.Warnings) > 0 {
-		for _, w := range result.Warnings {
-			fmt.Println(ui.Warning.Render("      ⚠ " + w))
-		}
+// artifactSourceFile returns the name of the file an artifact was read from,
+// which format detection keys on (a .agent.md or .mdc suffix): the last
+// segment of its source URL, else its filename, else the last segment of
+// its source
+func artifactSourceFile(art *artifact.Artifact) string {
+	if art.SourceURL != "" {
+		u, _, _ := strings.Cut(art.SourceURL, "?")
+		return path.Base(u)
 	}
+	if art.Filename != "" {
+		return art.Filename
+	}
+	if idx := strings.LastIndex(art.Source, "/"); idx >= 0 {
+		return art.Source[idx+1:]
+	}
+	return art.Source
+}
+
+// checkRename fails when --rename is set for a source with found artifacts
+// other than one, since they can't all take the name
+func checkRename(found int) error {
+	if learnRenameTo != "" && found != 1 {
+		return fmt.Errorf("--rename needs a source with a single artifact (found %d); use owner/repo#name or a path to pick one", found)
+	}
+	return nil
+}
 
-	return string(converted), true
+// singleFileSkill reports whether --single-file installs a skill as
+// <name>.md: only one without includes can leave its directory
+func singleFileSkill(art *artifact.Artifact, includes []fetch.IncludedFile) bool {
+	return learnSingleFileSkills && art.Type == artifact.TypeSkill && len(includes) == 0
 }
 
-func getInstallPath(art *artifact.Artifact, paths *config.Paths) string {
+// getInstallPath returns where an artifact is written for the target agent:
+// the install path template rendered inside the type's directory. A
+// single-file skill is written as <name>.md even for agents that nest skills.
+func getInstallPath(art *artifact.Artifact, paths *config.Paths, singleFile bool) (string, error) {
 	targetFormat := config.AgentToFormat(paths.Agent)
-	safeName := fetch.SanitizeFilename(art.Name)
+	safeName := artifact.SanitizeFilename(art.Name)
+
+	data := installPathData{
+		Name:   safeName,
+		Type:   string(art.Type),
+		Owner:  pathTemplateOwner(art),
+		Source: art.Source,
+	}
+	var baseDir string
 
 	switch art.Type {
 	case artifact.TypeSkill:
-		// Get the appropriate filename for the target format
-		filename := getSkillFilename(safeName, targetFormat)
-		if targetFormat == schema.FormatCopilot {
-			// Copilot: agents/<name>.agent.md (flat structure)
-			return filepath.Join(paths.SkillsDir, filename)
-		} else if targetFormat == schema.FormatCursor {
-			// Cursor: .cursor/rules/<name>.md (flat structure)
-			return filepath.Join(paths.SkillsDir, filename)
-		}
 		// Claude/OpenCode: skills/<name>/SKILL.md (directory structure)
-		return filepath.Join(paths.SkillsDir, safeName, filename)
+		// Copilot: agents/<name>.agent.md, Cursor: .cursor/rules/<name>.md (flat structure)
+		baseDir = paths.SkillsDir
+		data.Filename = getSkillFilename(safeName, targetFormat)
+		data.Nested = targetFormat != schema.FormatCopilot && targetFormat != schema.FormatCursor
+		if singleFile && data.Nested {
+			data.Filename = safeName + ".md"
+			data.Nested = false
+		}
 
 	case artifact.TypeCommand:
-		// Get the appropriate filename for the target format
-		filename := getCommandFilename(safeName, targetFormat)
-		return filepath.Join(paths.CommandsDir, filename)
+		// Each agent's commands directory and format come from its config:
+		// OpenCode command/, Copilot prompts/*.prompt.md, Windsurf workflows/
+		baseDir = paths.CommandsDir
+		data.Filename = getCommandFilename(safeName, config.CommandFormat(paths.Agent))
 
 	case artifact.TypeAgent:
 		// Agents are .md files in agents/
+		baseDir = paths.CommandsDir // Fallback to commands if agents not supported
 		agentCfg := config.GetAgentConfig(paths.Agent)
 		if agentCfg != nil && agentCfg.AgentsDir != "" {
-			agentsDir := filepath.Join(paths.AgentDir, agentCfg.AgentsDir)
-			return filepath.Join(agentsDir, safeName+".md")
+			baseDir = filepath.Join(paths.AgentDir, agentCfg.AgentsDir)
 		}
-		// Fallback to commands if agents not supported
-		return filepath.Join(paths.CommandsDir, safeName+".md")
+		data.Filename = safeName + ".md"
 
 	default:
-		return filepath.Join(paths.CommandsDir, art.Filename)
+		baseDir = paths.CommandsDir
+		data.Filename = art.Filename
 	}
+
+	tmpl := installPathTemplate
+	if tmpl == nil {
+		var err error
+		if tmpl, err = parsePathTemplate(""); err != nil {
+			return "", err
+		}
+	}
+	rel, err := renderPathTemplate(tmpl, data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", art.Name, err)
+	}
+	return filepath.Join(baseDir, rel), nil
 }
 
 // getSkillFilename returns the appropriate skill filename for the target format
@@ -798,16 +1767,16 @@ func getCommandFilename(name string, format schema.Format) string {
 	switch format {
 	case schema.FormatCopilot:
 		return name + ".prompt.md"
-	default: // Claude, OpenCode, Cursor
+	default: // Claude, OpenCode, Cursor, Windsurf
 		return name + ".md"
 	}
 }
 
 // learnPlugin handles installing a plugin and all its artifacts
 func learnPlugin(client *fetch.Client, src *source.Source, apiURL string, paths *config.Paths) {
-	fmt.Println(ui.PluginBadge() + "  " + ui.Info.Render("Plugin detected"))
-	fmt.Println(ui.Muted.Render(fmt.Sprintf("    %s/%s", src.Owner, src.Repo)))
-	fmt.Println()
+	fmt.Fprintln(learnOutput(), ui.PluginBadge()+"  "+ui.Info.Render("Plugin detected"))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    %s/%s", src.Owner, src.Repo)))
+	fmt.Fprintln(learnOutput())
 
 	// Fetch the complete plugin
 	plugin, err := client.FetchPlugin(apiURL, src.String())
@@ -815,93 +1784,291 @@ func learnPlugin(client *fetch.Client, src *source.Source, apiURL string, paths
 		exitWithError(fmt.Sprintf("failed to fetch plugin: %v", err))
 	}
 
+	result, ok := installPlugin(plugin, src, paths)
+	if !ok {
+		return
+	}
+	displayPluginSummary(src, "plugin", result)
+	learnExitStatus = skipExitStatus(len(learnSkipped))
+}
+
+// installPlugin installs a fetched plugin's artifacts, returning their names,
+// those skipped, the setup requirements detected in them and how many --only
+// left out. It
+// reports false, after saying why, when the plugin has nothing to install.
+func installPlugin(plugin *artifact.Plugin, src *source.Source, paths *config.Paths) (installResult, bool) {
+	var result installResult
+
 	// Display plugin info
-	fmt.Println(ui.Highlight.Render("  " + plugin.Manifest.Name))
+	fmt.Fprintln(learnOutput(), ui.Highlight.Render("  "+plugin.Manifest.Name))
 	if plugin.Manifest.Description != "" {
-		fmt.Println(ui.Muted.Render("    " + plugin.Manifest.Description))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render("    "+plugin.Manifest.Description))
 	}
 	if plugin.Manifest.Author.Name != "" {
-		fmt.Println(ui.Muted.Render(fmt.Sprintf("    by %s", plugin.Manifest.Author.Name)))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    by %s", plugin.Manifest.Author.Name)))
 	}
 	if plugin.Manifest.Version != "" {
-		fmt.Println(ui.Muted.Render(fmt.Sprintf("    v%s", plugin.Manifest.Version)))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    v%s", plugin.Manifest.Version)))
+	}
+	fmt.Fprintln(learnOutput())
+
+	// Leave out the types --only excludes
+	filtered := 0
+	groups := map[artifact.Type]*[]artifact.Artifact{
+		artifact.TypeSkill:   &plugin.Skills,
+		artifact.TypeCommand: &plugin.Commands,
+		artifact.TypeAgent:   &plugin.Agents,
+		artifact.TypeHook:    &plugin.Hooks,
+	}
+	for t, group := range groups {
+		if !wantType(t) {
+			filtered += len(*group)
+			*group = nil
+		}
+	}
+	result.filtered = filtered
+
+	// A name selects one artifact, as tome.lock does to verify each
+	if src.Name != "" {
+		if err := selectPluginArtifact(plugin, src.Name); err != nil {
+			exitWithError(fmt.Sprintf("%v in %s", err, src.String()))
+		}
 	}
-	fmt.Println()
 
 	// Count artifacts
 	totalArtifacts := len(plugin.Skills) + len(plugin.Commands) + len(plugin.Agents) + len(plugin.Hooks)
+	if learnSHA256 != "" {
+		if totalArtifacts != 1 {
+			exitWithError(fmt.Sprintf("--sha256 needs a single artifact (the plugin has %d); select one with #name", totalArtifacts))
+		}
+		for _, group := range groups {
+			for _, art := range *group {
+				if err := verifyChecksum([]byte(art.Content), learnSHA256); err != nil {
+					exitWithError(fmt.Sprintf("refusing to install %s: %v", art.Name, err))
+				}
+			}
+		}
+	}
 	if totalArtifacts == 0 {
-		fmt.Println(ui.Warning.Render("  No artifacts found in plugin"))
-		return
+		if filtered > 0 {
+			fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  No %ss found in plugin (--only %s)", learnOnlyType, learnOnlyType)))
+			return result, false
+		}
+		fmt.Fprintln(learnOutput(), ui.Warning.Render("  No artifacts found in plugin"))
+		return result, false
 	}
 
-	fmt.Println(ui.Muted.Render(fmt.Sprintf("  Found %d artifact(s):", totalArtifacts)))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("  Found %d artifact(s):", totalArtifacts)))
 	if len(plugin.Skills) > 0 {
-		fmt.Println(ui.Muted.Render(fmt.Sprintf("    • %d skill(s)", len(plugin.Skills))))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    • %d skill(s)", len(plugin.Skills))))
 	}
 	if len(plugin.Commands) > 0 {
-		fmt.Println(ui.Muted.Render(fmt.Sprintf("    • %d command(s)", len(plugin.Commands))))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    • %d command(s)", len(plugin.Commands))))
 	}
 	if len(plugin.Agents) > 0 {
-		fmt.Println(ui.Muted.Render(fmt.Sprintf("    • %d agent(s)", len(plugin.Agents))))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    • %d agent(s)", len(plugin.Agents))))
+	}
+	if len(plugin.Hooks) > 0 {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    • %d hook(s)", len(plugin.Hooks))))
+	}
+	fmt.Fprintln(learnOutput())
+
+	// Install all artifacts, removing them again if a fatal error interrupts
+	tx := beginInstall()
+
+	for _, group := range [][]artifact.Artifact{plugin.Skills, plugin.Commands, plugin.Agents} {
+		for _, art := range group {
+			art.Source = src.String()
+			reqs, ok := installArtifactQuietWithExtras(&art, paths, nil, nil)
+			if !ok {
+				result.skipped = append(result.skipped, skippedArtifact{art.Name, "name conflict"})
+				learnSkipped = append(learnSkipped, skippedArtifact{art.Name, "name conflict"})
+				continue
+			}
+			result.installed = append(result.installed, art.Name)
+			result.allReqs = detect.Merge(result.allReqs, reqs)
+		}
 	}
+
+	// Install hooks to each agent's hooks directory, listing each hook once
 	if len(plugin.Hooks) > 0 {
-		fmt.Println(ui.Muted.Render(fmt.Sprintf("    • %d hook(s)", len(plugin.Hooks))))
+		seenHooks := make(map[string]bool)
+		for _, target := range installTargets(paths) {
+			for _, name := range installPluginHooks(plugin.Hooks, target) {
+				if !seenHooks[name] {
+					seenHooks[name] = true
+					result.installed = append(result.installed, name)
+				}
+			}
+		}
+		for _, hook := range plugin.Hooks {
+			hook.Source = src.String()
+			if learnLock == nil || !seenHooks[hook.Name] {
+				continue
+			}
+			if entry, ok := lockEntryFor(&hook, learnSource, learnCommit); ok {
+				learnLock.Set(entry)
+			}
+		}
+	}
+
+	saveLockUpdate()
+	tx.commit()
+	return result, true
+}
+
+// selectPluginArtifact narrows a plugin to its artifact called name
+func selectPluginArtifact(plugin *artifact.Plugin, name string) error {
+	found := 0
+	for _, group := range []*[]artifact.Artifact{&plugin.Skills, &plugin.Commands, &plugin.Agents, &plugin.Hooks} {
+		var kept []artifact.Artifact
+		for _, art := range *group {
+			if art.Name == name {
+				kept = append(kept, art)
+			}
+		}
+		*group = kept
+		found += len(kept)
+	}
+	switch found {
+	case 0:
+		return fmt.Errorf("no artifact named %q", name)
+	case 1:
+		return nil
+	}
+	return fmt.Errorf("%d artifacts are named %q", found, name)
+}
+
+// displayPluginSummary lists what was installed and skipped from a plugin or
+// marketplace at src, and the setup requirements detected in it
+func displayPluginSummary(src *source.Source, from string, result installResult) {
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.SuccessLine(fmt.Sprintf("%s %d artifact(s) from %s", inscribedVerb(), len(result.installed), from)))
+	for _, name := range result.installed {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render("    • "+name))
+	}
+	if len(result.skipped) > 0 {
+		fmt.Fprintln(learnOutput())
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipped %d artifact(s):", len(result.skipped))))
+		for _, s := range result.skipped {
+			fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    • %s: %s", s.name, s.reason)))
+		}
+	}
+	displayFiltered(result.filtered)
+	displayLearnWarnings()
+	displayDetectedRequirements(src.String(), result.allReqs)
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Dim.Render("  Your tome grows stronger."))
+	fmt.Fprintln(learnOutput(), ui.PageFooter())
+}
+
+// installPluginHooks writes hooks to an agent's hooks directory and returns
+// the names of those installed
+func installPluginHooks(hooks []artifact.Artifact, paths *config.Paths) []string {
+	agentCfg := config.GetAgentConfig(paths.Agent)
+	if agentCfg == nil || agentCfg.HooksDir == "" {
+		fmt.Fprintln(learnOutput(), ui.Warning.Render("  Note: Hooks not supported for this agent"))
+		return nil
 	}
-	fmt.Println()
 
-	// Install all artifacts
 	var installed []string
+	hooksDir := filepath.Join(paths.AgentDir, agentCfg.HooksDir)
+	if learnDryRun {
+		for _, hook := range hooks {
+			fmt.Fprintf(learnOutput(), "  %s %s\n", ui.HookBadge(), ui.Highlight.Render(hook.Name))
+			fmt.Fprintln(learnOutput(), ui.Muted.Render("    [dry-run] Would write: "+filepath.Join(hooksDir, hook.Filename)))
+			installed = append(installed, hook.Name)
+		}
+		if learnEnableHooks {
+			fmt.Fprintln(learnOutput(), ui.Muted.Render("    [dry-run] Would enable them in: "+filepath.Join(paths.AgentDir, config.SettingsFilename)))
+		}
+		return installed
+	}
 
-	for _, skill := range plugin.Skills {
-		skill.Source = src.String()
-		installArtifactQuiet(&skill, paths)
-		installed = append(installed, skill.Name)
+	format := config.AgentToFormat(paths.Agent)
+	warn := func(name, message string) {
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Warning: %s: %s", name, message)))
+		learnWarnings = append(learnWarnings, learnWarning{name, format, message})
 	}
 
-	for _, cmd := range plugin.Commands {
-		cmd.Source = src.String()
-		installArtifactQuiet(&cmd, paths)
-		installed = append(installed, cmd.Name)
+	if err := activeInstall.mkdirAll(hooksDir); err != nil {
+		for _, hook := range hooks {
+			warn(hook.Name, fmt.Sprintf("not installed: %v", err))
+		}
+		return nil
+	}
+	for _, hook := range hooks {
+		hookPath := filepath.Join(hooksDir, hook.Filename)
+		if err := activeInstall.writeFile(hookPath, []byte(hook.Content), 0755); err != nil {
+			warn(hook.Name, fmt.Sprintf("not installed: %v", err))
+			continue
+		}
+		fmt.Fprintf(learnOutput(), "  %s %s\n", ui.HookBadge(), ui.Highlight.Render(hook.Name))
+		installed = append(installed, hook.Name)
+	}
+	fmt.Fprintln(learnOutput())
+	if !learnEnableHooks {
+		fmt.Fprintln(learnOutput(), ui.Warning.Render("  Note: Add hooks to settings.json to enable them (or pass --enable-hooks)"))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    Installed to: %s", hooksDir)))
+		return installed
 	}
 
-	for _, agent := range plugin.Agents {
-		agent.Source = src.String()
-		installArtifactQuiet(&agent, paths)
-		installed = append(installed, agent.Name)
+	// Hooks run from wherever the agent starts, so point them at an absolute path
+	if abs, err := filepath.Abs(hooksDir); err == nil {
+		hooksDir = abs
+	}
+	settingsPath := filepath.Join(paths.AgentDir, config.SettingsFilename)
+	settings, err := pluginHookSettings(hooks, hooksDir)
+	if err == nil && len(settings) == 0 {
+		err = errors.New("the plugin defines no hook handlers")
+	}
+	if err == nil {
+		err = activeInstall.track(settingsPath)
+	}
+	var added int
+	if err == nil {
+		added, err = config.EnableHooks(settingsPath, settings)
+	}
+	if err != nil {
+		warn(config.SettingsFilename, fmt.Sprintf("hooks not enabled: %v", err))
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    Installed to: %s", hooksDir)))
+		return installed
 	}
+	fmt.Fprintln(learnOutput(), ui.SuccessLine(fmt.Sprintf("Enabled %d hook handler(s) in %s", added, settingsPath)))
+	return installed
+}
 
-	// Install hooks to hooks directory
-	if len(plugin.Hooks) > 0 {
-		agentCfg := config.GetAgentConfig(paths.Agent)
-		if agentCfg != nil && agentCfg.HooksDir != "" {
-			hooksDir := filepath.Join(paths.AgentDir, agentCfg.HooksDir)
-			if err := os.MkdirAll(hooksDir, 0755); err == nil {
-				for _, hook := range plugin.Hooks {
-					hookPath := filepath.Join(hooksDir, hook.Filename)
-					if err := os.WriteFile(hookPath, []byte(hook.Content), 0755); err == nil {
-						fmt.Printf("  %s %s\n", ui.HookBadge(), ui.Highlight.Render(hook.Name))
-						installed = append(installed, hook.Name)
-					}
-				}
-				fmt.Println()
-				fmt.Println(ui.Warning.Render("  Note: Add hooks to settings.json to enable them"))
-				fmt.Println(ui.Muted.Render(fmt.Sprintf("    Installed to: %s", hooksDir)))
+// pluginHookSettings returns the settings.json handlers for a plugin's
+// hooks. A hooks.json defines them itself, with ${CLAUDE_PLUGIN_ROOT}/hooks
+// pointed at where the hooks were copied; otherwise each script runs for
+// the event its name gives.
+func pluginHookSettings(hooks []artifact.Artifact, hooksDir string) ([]config.Hook, error) {
+	for _, hook := range hooks {
+		if hook.Filename != "hooks.json" {
+			continue
+		}
+		parsed, err := config.ParseHooks([]byte(hook.Content))
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range parsed {
+			if command, ok := h.Entry["command"].(string); ok {
+				h.Entry["command"] = strings.ReplaceAll(command, "${CLAUDE_PLUGIN_ROOT}/hooks", hooksDir)
 			}
-		} else {
-			fmt.Println(ui.Warning.Render("  Note: Hooks not supported for this agent"))
 		}
+		return parsed, nil
 	}
 
-	// Summary
-	fmt.Println()
-	fmt.Println(ui.SuccessLine(fmt.Sprintf("Inscribed %d artifact(s) from plugin", len(installed))))
-	for _, name := range installed {
-		fmt.Println(ui.Muted.Render("    • " + name))
+	var settings []config.Hook
+	for _, hook := range hooks {
+		if strings.HasSuffix(hook.Filename, ".sh") {
+			settings = append(settings, config.Hook{
+				Event: hook.Event,
+				Entry: map[string]any{"type": "command", "command": filepath.Join(hooksDir, hook.Filename)},
+			})
+		}
 	}
-	fmt.Println()
-	fmt.Println(ui.Dim.Render("  Your tome grows stronger."))
-	fmt.Println(ui.PageFooter())
+	return settings, nil
 }
 
 // extractUsageSection extracts a "Quick Start", "Usage", or "Examples" section from markdown content.
@@ -975,14 +2142,113 @@ func isScript(path string, content []byte) bool {
 	return false
 }
 
+// requirementIgnores collects ignore patterns from the artifact's frontmatter
+// and from .tomeignore files in the project root and user config dir
+func requirementIgnores(content string, paths *config.Paths) []string {
+	patterns := detect.IgnoresFromContent(content)
+	patterns = append(patterns, ignoreFilePatterns(paths)...)
+	return patterns
+}
+
+// ignoreFilePatterns loads patterns from project and user .tomeignore files
+func ignoreFilePatterns(paths *config.Paths) []string {
+	var patterns []string
+	for _, path := range []string{
+		detect.IgnoreFileName,
+		filepath.Join(paths.UserConfigDir, detect.IgnoreFileName),
+	} {
+		if p, err := detect.LoadIgnoreFile(path); err == nil {
+			patterns = append(patterns, p...)
+		}
+	}
+	return patterns
+}
+
+// verifyRequirements checks active requirements and returns their status
+func verifyRequirements(reqs []detect.Requirement) []requirementStatus {
+	statuses := make([]requirementStatus, 0, len(reqs))
+	for _, r := range detect.VerifyAll(withProjectPackageManager(reqs)) {
+		statuses = append(statuses, requirementStatus{
+			Requirement: r.Requirement,
+			Satisfied:   r.Satisfied,
+			Message:     r.Message,
+		})
+	}
+	return statuses
+}
+
+// printRequirementsJSON writes requirements and their verified status to stdout
+func printRequirementsJSON(reqs []detect.Requirement) {
+	data, err := json.MarshalIndent(verifyRequirements(reqs), "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.Error.Render(fmt.Sprintf("Error: failed to encode requirements: %v", err)))
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// learnOutput is where learn writes human-readable output: stdout, unless
+// a JSON flag has claimed it (learn redirects the root command's output, as
+// learn also runs for tome install)
+func learnOutput() io.Writer {
+	return rootCmd.OutOrStdout()
+}
+
+// printLearnJSON writes the run's installed and skipped artifacts and its
+// requirements, with verified status, to stdout, along with the error that
+// ended it, if any
+func printLearnJSON(src, errMsg string) {
+	out := learnJSONResult{
+		Source:       src,
+		Error:        errMsg,
+		DryRun:       learnDryRun,
+		Installed:    learnedArtifacts,
+		Skipped:      make([]learnJSONSkipped, len(learnSkipped)),
+		Warnings:     learnWarnings,
+		Requirements: verifyRequirements(learnedReqs),
+	}
+	if out.Installed == nil {
+		out.Installed = []learnedArtifact{}
+	}
+	if out.Warnings == nil {
+		out.Warnings = []learnWarning{}
+	}
+	for i, s := range learnSkipped {
+		out.Skipped[i] = learnJSONSkipped{Name: s.name, Reason: s.reason}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.Error.Render(fmt.Sprintf("Error: failed to encode summary: %v", err)))
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// printLearnFailure writes the JSON a failed learn still owes stdout; it's
+// a no-op unless --json or --requirements-json is set
+func printLearnFailure(errMsg string) {
+	switch {
+	case learnRequirementsJSON:
+		printRequirementsJSON(learnedReqs)
+	case learnJSON:
+		src := ""
+		if learnSource != nil {
+			src = learnSource.String()
+		}
+		printLearnJSON(src, errMsg)
+	}
+}
+
 // displayDetectedRequirements shows any detected setup requirements after install
 func displayDetectedRequirements(name string, reqs []detect.Requirement) {
+	reqs = detect.Active(reqs)
 	if len(reqs) == 0 {
 		return
 	}
 
-	fmt.Println()
-	fmt.Println(ui.Warning.Render("  ⚠ Detected setup requirements:"))
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Warning.Render("  ⚠ Detected setup requirements:"))
 
 	for _, req := range reqs {
 		var icon, label string
@@ -993,20 +2259,26 @@ func displayDetectedRequirements(name string, reqs []detect.Requirement) {
 			if pm == "" {
 				pm = "npm"
 			}
-			label = fmt.Sprintf("%s: %s", pm, req.Value)
+			label = fmt.Sprintf("%s: %s", pm, req.Label())
 		case detect.TypePip:
 			icon = "🐍"
 			pm := req.PackageManager
 			if pm == "" {
 				pm = "pip"
 			}
-			label = fmt.Sprintf("%s: %s", pm, req.Value)
+			label = fmt.Sprintf("%s: %s", pm, req.Label())
 		case detect.TypeBrew:
 			icon = "🍺"
 			label = fmt.Sprintf("brew: %s", req.Value)
 		case detect.TypeCargo:
 			icon = "🦀"
 			label = fmt.Sprintf("cargo: %s", req.Value)
+		case detect.TypeGo:
+			icon = "🐹"
+			label = fmt.Sprintf("go: %s", req.Value)
+		case detect.TypeGem:
+			icon = "💎"
+			label = fmt.Sprintf("gem: %s", req.Value)
 		case detect.TypeEnv:
 			icon = "🔑"
 			label = fmt.Sprintf("env: %s", req.Value)
@@ -1015,7 +2287,13 @@ func displayDetectedRequirements(name string, reqs []detect.Requirement) {
 			label = fmt.Sprintf("runtime: %s", req.Value)
 		case detect.TypeCommand:
 			icon = "💻"
+			if req.Value == "docker" || req.Value == "docker-compose" {
+				icon = "🐳"
+			}
 			label = fmt.Sprintf("command: %s", req.Value)
+		case detect.TypeExtension:
+			icon = "🧩"
+			label = fmt.Sprintf("extension: %s", req.Value)
 		default:
 			icon = "•"
 			label = req.Value
@@ -1029,11 +2307,11 @@ func displayDetectedRequirements(name string, reqs []detect.Requirement) {
 			source = ui.Dim.Render(fmt.Sprintf(" (line %d)", req.Line))
 		}
 
-		fmt.Printf("    %s %s%s\n", icon, label, source)
+		fmt.Fprintf(learnOutput(), "    %s %s%s\n", icon, label, source)
 	}
 
-	fmt.Println()
-	fmt.Printf("  Run: %s\n", ui.Highlight.Render(fmt.Sprintf("tome doctor %s", name)))
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintf(learnOutput(), "  Run: %s\n", ui.Highlight.Render(fmt.Sprintf("tome doctor %s", name)))
 }
 
 // showNpmPackageGuidance displays helpful info when a repo is an npm package, not a tome collection
@@ -1048,22 +2326,22 @@ func showNpmPackageGuidance(pkgContent []byte, src *source.Source) {
 		pkg.Name = src.Repo
 	}
 
-	fmt.Println()
-	fmt.Println(ui.Warning.Render("  This repository is an npm package, not a tome collection."))
-	fmt.Println()
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Warning.Render("  This repository is an npm package, not a tome collection."))
+	fmt.Fprintln(learnOutput())
 
 	if pkg.Description != "" {
-		fmt.Println(ui.Muted.Render("  " + ui.Truncate(pkg.Description, 60)))
-		fmt.Println()
-	}
-
-	fmt.Println(ui.Info.Render("  To install this package:"))
-	fmt.Println()
-	fmt.Printf("    %s\n", ui.Highlight.Render(fmt.Sprintf("bun add %s", pkg.Name)))
-	fmt.Println(ui.Muted.Render("    or"))
-	fmt.Printf("    %s\n", ui.Highlight.Render(fmt.Sprintf("npm install %s", pkg.Name)))
-	fmt.Println()
-	fmt.Println(ui.Dim.Render("  Tome is for skills, commands, and prompts (markdown artifacts)."))
-	fmt.Println(ui.Dim.Render("  Use your package manager for npm/bun packages."))
-	fmt.Println(ui.PageFooter())
+		fmt.Fprintln(learnOutput(), ui.Muted.Render("  "+ui.Truncate(pkg.Description, 60)))
+		fmt.Fprintln(learnOutput())
+	}
+
+	fmt.Fprintln(learnOutput(), ui.Info.Render("  To install this package:"))
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintf(learnOutput(), "    %s\n", ui.Highlight.Render(fmt.Sprintf("bun add %s", pkg.Name)))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render("    or"))
+	fmt.Fprintf(learnOutput(), "    %s\n", ui.Highlight.Render(fmt.Sprintf("npm install %s", pkg.Name)))
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Dim.Render("  Tome is for skills, commands, and prompts (markdown artifacts)."))
+	fmt.Fprintln(learnOutput(), ui.Dim.Render("  Use your package manager for npm/bun packages."))
+	fmt.Fprintln(learnOutput(), ui.PageFooter())
 }
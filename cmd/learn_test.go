@@ -0,0 +1,1231 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/detect"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/schema"
+	"github.com/kennyg/tome/internal/source"
+)
+
+// slowCommandServer serves command files after a fixed delay, standing in for network latency
+func slowCommandServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		if strings.HasSuffix(r.URL.Path, "missing.md") {
+			http.NotFound(w, r)
+			return
+		}
+		name := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".md")
+		fmt.Fprintf(w, "---\ndescription: %s command\n---\n# %s\n", name, name)
+	}))
+}
+
+func commandListing(baseURL string, n int) []fetch.GitHubContent {
+	items := make([]fetch.GitHubContent, n)
+	for i := range items {
+		name := fmt.Sprintf("cmd-%02d.md", i)
+		items[i] = fetch.GitHubContent{
+			Name:        name,
+			Path:        "commands/" + name,
+			Type:        "file",
+			DownloadURL: baseURL + "/commands/" + name,
+		}
+	}
+	return items
+}
+
+func TestFetchArtifacts_PreservesOrder(t *testing.T) {
+	srv := slowCommandServer(0)
+	defer srv.Close()
+
+	items := commandListing(srv.URL, 20)
+	items[5].Name, items[5].DownloadURL = "missing.md", srv.URL+"/commands/missing.md"
+
+	results := fetchArtifacts(fetch.NewClient(), &source.Source{}, items, 8, false, nil)
+	if len(results) != len(items) {
+		t.Fatalf("expected %d results, got %d", len(items), len(results))
+	}
+	for i, r := range results {
+		if r.item.Name != items[i].Name {
+			t.Errorf("result %d is %s, want %s", i, r.item.Name, items[i].Name)
+		}
+		if i == 5 {
+			if r.skipReason != "fetch failed" {
+				t.Errorf("missing file skipReason = %q, want fetch failed", r.skipReason)
+			}
+			continue
+		}
+		if r.art == nil || r.art.Name != strings.TrimSuffix(items[i].Name, ".md") {
+			t.Errorf("result %d parsed as %+v", i, r.art)
+		}
+	}
+}
+
+func TestFetchArtifacts_FailFastStopsPool(t *testing.T) {
+	srv := slowCommandServer(time.Millisecond)
+	defer srv.Close()
+
+	items := commandListing(srv.URL, 40)
+	items[3].Name, items[3].DownloadURL = "missing.md", srv.URL+"/commands/missing.md"
+
+	const workers = 2
+	results := fetchArtifacts(fetch.NewClient(), &source.Source{}, items, workers, true, nil)
+	if len(results) < 4 || len(results) > 4+workers {
+		t.Fatalf("fetched %d of %d artifacts, want the pool stopped just after the failure at 3", len(results), len(items))
+	}
+	for i, r := range results[:3] {
+		if r.art == nil {
+			t.Errorf("result %d before the failure wasn't fetched: %+v", i, r)
+		}
+	}
+	if results[3].skipReason != "fetch failed" {
+		t.Errorf("missing file skipReason = %q, want fetch failed", results[3].skipReason)
+	}
+}
+
+func TestLearnWorkers(t *testing.T) {
+	t.Cleanup(func() { learnJobs = 0 })
+
+	t.Setenv(learnJobsEnvVar, "")
+	if got := learnWorkers(); got != defaultLearnJobs {
+		t.Errorf("default learnWorkers() = %d, want %d", got, defaultLearnJobs)
+	}
+
+	t.Setenv(learnJobsEnvVar, "3")
+	if got := learnWorkers(); got != 3 {
+		t.Errorf("learnWorkers() with %s=3 = %d, want 3", learnJobsEnvVar, got)
+	}
+
+	t.Setenv(learnJobsEnvVar, "bogus")
+	if got := learnWorkers(); got != defaultLearnJobs {
+		t.Errorf("learnWorkers() with invalid env = %d, want %d", got, defaultLearnJobs)
+	}
+
+	learnJobs = 2
+	t.Setenv(learnJobsEnvVar, "3")
+	if got := learnWorkers(); got != 2 {
+		t.Errorf("learnWorkers() with --jobs 2 = %d, want 2", got)
+	}
+}
+
+// BenchmarkFetchArtifacts compares serial and pooled fetching of 24 commands
+// from a server with 10ms latency per file.
+func BenchmarkFetchArtifacts(b *testing.B) {
+	srv := slowCommandServer(10 * time.Millisecond)
+	defer srv.Close()
+
+	items := commandListing(srv.URL, 24)
+	for _, workers := range []int{1, defaultLearnJobs} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			client := fetch.NewClient()
+			for i := 0; i < b.N; i++ {
+				fetchArtifacts(client, &source.Source{}, items, workers, false, nil)
+			}
+		})
+	}
+}
+
+func TestSkipExitStatus(t *testing.T) {
+	t.Cleanup(func() { learnStrict = false })
+
+	tests := []struct {
+		strict  bool
+		skipped int
+		want    int
+	}{
+		{false, 0, 0},
+		{false, 3, 0},
+		{true, 0, 0},
+		{true, 1, exitSkipped},
+	}
+	for _, tt := range tests {
+		learnStrict = tt.strict
+		if got := skipExitStatus(tt.skipped); got != tt.want {
+			t.Errorf("skipExitStatus(%d) with strict=%v = %d, want %d", tt.skipped, tt.strict, got, tt.want)
+		}
+	}
+}
+
+func TestInstallFoundArtifacts_FailFast(t *testing.T) {
+	srv := slowCommandServer(0)
+	defer srv.Close()
+	t.Cleanup(func() { learnFailFast = false })
+	learnFailFast = true
+
+	items := commandListing(srv.URL, 3)
+	items[0].Name, items[0].DownloadURL = "missing.md", srv.URL+"/commands/missing.md"
+
+	// Nothing after the failure is installed, so no install paths are needed
+	result := installFoundArtifacts(fetch.NewClient(), &source.Source{}, nil, items, nil, nil)
+
+	if !result.aborted {
+		t.Error("expected the run to be aborted")
+	}
+	if len(result.installed) != 0 {
+		t.Errorf("expected nothing installed after the failure, got %v", result.installed)
+	}
+	if len(result.skipped) != 1 || result.skipped[0].name != "missing.md" {
+		t.Errorf("skipped = %+v, want only missing.md", result.skipped)
+	}
+	if !strings.HasPrefix(result.skipped[0].reason, "fetch failed:") {
+		t.Errorf("skip reason = %q, want a fetch failure", result.skipped[0].reason)
+	}
+}
+
+func TestDiscoverSkillIncludes_HonorsIncludesList(t *testing.T) {
+	var fetched []string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contents/skills/demo":
+			fmt.Fprintf(w, `[
+				{"name": "SKILL.md", "path": "skills/demo/SKILL.md", "type": "file", "download_url": "%[1]s/raw/skills/demo/SKILL.md"},
+				{"name": "listed.md", "path": "skills/demo/listed.md", "type": "file", "download_url": "%[1]s/raw/skills/demo/listed.md"},
+				{"name": "stray.md", "path": "skills/demo/stray.md", "type": "file", "download_url": "%[1]s/raw/skills/demo/stray.md"}
+			]`, srv.URL)
+		case "/raw/skills/demo/listed.md", "/raw/skills/demo/stray.md":
+			fetched = append(fetched, r.URL.Path)
+			w.Write([]byte("content"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// A followed submodule keeps its own URLs, which lets the test serve them
+	item := fetch.GitHubContent{
+		Name:       "SKILL.md",
+		Path:       "skills/demo/SKILL.md",
+		SkillDir:   "skills/demo",
+		RepoAPIURL: srv.URL + "/contents",
+	}
+	src := &source.Source{Type: source.TypeRepo, Owner: "owner", Repo: "repo", Ref: "main"}
+	skillURL := srv.URL + "/raw/skills/demo/SKILL.md"
+
+	listed, err := fetch.ParseSkill([]byte("---\nname: demo\nincludes: [listed.md]\n---\n# Demo\n"), skillURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	includes, err := discoverSkillIncludes(fetch.NewClient(), src, item, listed)
+	if err != nil {
+		t.Fatalf("discoverSkillIncludes() error = %v", err)
+	}
+	if len(includes) != 1 || includes[0].Path != "listed.md" {
+		t.Errorf("includes = %+v, want only listed.md", includes)
+	}
+	if len(fetched) != 1 || fetched[0] != "/raw/skills/demo/listed.md" {
+		t.Errorf("fetched %v, want only the listed include", fetched)
+	}
+
+	// Without an includes list, every allowed file is discovered
+	fetched = nil
+	unlisted, err := fetch.ParseSkill([]byte("---\nname: demo\n---\n# Demo\n"), skillURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	includes, err = discoverSkillIncludes(fetch.NewClient(), src, item, unlisted)
+	if err != nil {
+		t.Fatalf("discoverSkillIncludes() error = %v", err)
+	}
+	if len(includes) != 2 {
+		t.Errorf("includes = %+v, want listed.md and stray.md", includes)
+	}
+}
+
+func TestInstallFoundArtifacts_VerifiesChecksums(t *testing.T) {
+	srv := slowCommandServer(0)
+	defer srv.Close()
+	t.Setenv("HOME", t.TempDir())
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := commandListing(srv.URL, 2)
+	good := hashContent([]byte("---\ndescription: cmd-00 command\n---\n# cmd-00\n"))
+	manifest := &artifact.Manifest{Checksums: map[string]string{
+		"commands/cmd-00.md": "sha256:" + good,
+		"cmd-01":             strings.Repeat("0", 64),
+	}}
+
+	result := installFoundArtifacts(fetch.NewClient(), &source.Source{}, paths, items, nil, manifest)
+
+	if len(result.installed) != 1 || result.installed[0] != "cmd-00" {
+		t.Errorf("installed = %v, want only cmd-00", result.installed)
+	}
+	if len(result.skipped) != 1 || !strings.HasPrefix(result.skipped[0].reason, "checksum mismatch:") {
+		t.Errorf("skipped = %+v, want cmd-01 with a checksum mismatch", result.skipped)
+	}
+
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := state.FindInstalled("cmd-00"); a == nil || a.Hash != good {
+		t.Errorf("installed state = %+v, want hash %s", a, good)
+	}
+}
+
+func TestInstallFoundArtifacts_IgnoresBindHashes(t *testing.T) {
+	srv := slowCommandServer(0)
+	defer srv.Close()
+	t.Setenv("HOME", t.TempDir())
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tome bind --write recorded a hash, then the command was edited
+	manifest := &artifact.Manifest{Artifacts: []artifact.ArtifactSummary{
+		{Name: "cmd-00", Type: artifact.TypeCommand, Hash: "sha256:" + strings.Repeat("0", 64)},
+	}}
+
+	result := installFoundArtifacts(fetch.NewClient(), &source.Source{}, paths, commandListing(srv.URL, 1), nil, manifest)
+
+	if len(result.installed) != 1 || len(result.skipped) != 0 {
+		t.Errorf("installed = %v, skipped = %+v; want a stale bind hash not to block install", result.installed, result.skipped)
+	}
+}
+
+func TestInstallFoundArtifacts_MergesTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "---\ndescription: Review a PR\ntags: [git, Review]\n---\n# Review\n")
+	}))
+	defer srv.Close()
+	t.Setenv("HOME", t.TempDir())
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := &artifact.Manifest{Tags: []string{"review", "team"}}
+	items := []fetch.GitHubContent{{Name: "review.md", Path: "commands/review.md", Type: "file", DownloadURL: srv.URL + "/commands/review.md"}}
+	if result := installFoundArtifacts(fetch.NewClient(), &source.Source{}, paths, items, nil, manifest); len(result.installed) != 1 {
+		t.Fatalf("installed = %v, want review", result.installed)
+	}
+
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Installed[0].Tags; strings.Join(got, ",") != "git,Review,team" {
+		t.Errorf("tags = %q, want the frontmatter's and then the collection's, deduped", got)
+	}
+}
+
+func TestLearnFromLocal_VerifiesChecksums(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { learnSHA256, learnSkipped = "", nil })
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deploy := "---\ndescription: Deploy\n---\nDeploy the app.\n"
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"tome.yaml": "name: ops\nchecksums:\n  commands/deploy.md: sha256:" + hashContent([]byte(deploy)) +
+			"\n  review: " + strings.Repeat("0", 64) + "\n",
+		"commands/deploy.md": deploy,
+		"commands/review.md": "---\ndescription: Review\n---\nReview the diff.\n",
+	})
+
+	learnFromLocal(&source.Source{Type: source.TypeLocal, Path: dir, Original: dir}, paths)
+
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FindInstalled("deploy") == nil {
+		t.Error("deploy matches its checksum but wasn't installed")
+	}
+	if state.FindInstalled("review") != nil {
+		t.Error("review doesn't match its checksum but was installed")
+	}
+	if len(learnSkipped) != 1 || !strings.HasPrefix(learnSkipped[0].reason, "checksum mismatch:") {
+		t.Errorf("skipped = %+v, want review with a checksum mismatch", learnSkipped)
+	}
+
+	// --sha256 applies to a directory holding a single artifact
+	single := t.TempDir()
+	writeFiles(t, single, map[string]string{"commands/review.md": "---\ndescription: Review\n---\nReview the diff.\n"})
+	learnSHA256 = hashContent([]byte("---\ndescription: Review\n---\nReview the diff.\n"))
+	learnFromLocal(&source.Source{Type: source.TypeLocal, Path: single, Original: single}, paths)
+	if state, _ := config.LoadState(paths.StateFile); state.FindInstalled("review") == nil {
+		t.Error("review matches --sha256 but wasn't installed")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	content := []byte("# hello\n")
+	sum := hashContent(content)
+
+	for _, expected := range []string{"", sum, "sha256:" + sum, strings.ToUpper(sum)} {
+		if err := verifyChecksum(content, expected); err != nil {
+			t.Errorf("verifyChecksum(%q) error = %v", expected, err)
+		}
+	}
+	if err := verifyChecksum(content, hashContent([]byte("tampered"))); err == nil {
+		t.Error("expected a mismatched checksum to fail")
+	}
+}
+
+func TestLearnFromLocal_NestedSkillDirectory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"README.md":                         "# My skills\n",
+		"tools/pdf/SKILL.md":                "---\nname: pdf\ndescription: Work with PDFs\n---\n# PDF\n",
+		"tools/pdf/reference.md":            "# Reference\n",
+		"tools/pdf/scripts/extract.py":      "print('extract')\n",
+		"skills/writing/style/SKILL.md":     "---\nname: style\ndescription: House style\n---\n# Style\n",
+		"skills/writing/style/examples.md":  "# Examples\n",
+		"skills/writing/.drafts/x/SKILL.md": "---\nname: hidden\n---\n# Hidden\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	learnFromLocal(&source.Source{Type: source.TypeLocal, Path: dir, Original: dir}, paths)
+
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Installed) != 2 {
+		t.Fatalf("installed %+v, want pdf and style", state.Installed)
+	}
+
+	wantIncludes := map[string][]string{
+		"pdf":   {"reference.md", "scripts/extract.py"},
+		"style": {"examples.md"},
+	}
+	for name, want := range wantIncludes {
+		a := state.FindInstalled(name)
+		if a == nil {
+			t.Fatalf("%s not installed", name)
+		}
+		sort.Strings(a.Includes)
+		if strings.Join(a.Includes, ",") != strings.Join(want, ",") {
+			t.Errorf("%s includes = %v, want %v", name, a.Includes, want)
+		}
+		for _, inc := range want {
+			if _, err := os.Stat(filepath.Join(filepath.Dir(a.LocalPath), inc)); err != nil {
+				t.Errorf("%s include %s not written: %v", name, inc, err)
+			}
+		}
+	}
+}
+
+func TestLearnFromLocal_SingleFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { learnSingleFileSkills = false })
+	learnSingleFileSkills = true
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"skills/style/SKILL.md":   "---\nname: style\ndescription: House style\n---\n# Style\n",
+		"skills/pdf/SKILL.md":     "---\nname: pdf\ndescription: Work with PDFs\n---\n# PDF\n",
+		"skills/pdf/extract.py":   "print('extract')\n",
+		"skills/pdf/reference.md": "# Reference\n",
+		"commands/deploy.md":      "---\ndescription: Deploy\n---\nDeploy the app.\n",
+	})
+
+	learnFromLocal(&source.Source{Type: source.TypeLocal, Path: dir, Original: dir}, paths)
+
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	style := state.FindInstalled("style")
+	if style == nil {
+		t.Fatal("style not installed")
+	}
+	if want := filepath.Join(paths.SkillsDir, "style.md"); style.LocalPath != want || !style.SingleFile {
+		t.Errorf("style at %s (single file %v), want a single file at %s", style.LocalPath, style.SingleFile, want)
+	}
+	if _, err := os.Stat(style.LocalPath); err != nil {
+		t.Errorf("single-file skill not written: %v", err)
+	}
+
+	// Includes need a directory, so pdf keeps one
+	pdf := state.FindInstalled("pdf")
+	if pdf == nil {
+		t.Fatal("pdf not installed")
+	}
+	if want := filepath.Join(paths.SkillsDir, "pdf", artifact.SkillFilename); pdf.LocalPath != want || pdf.SingleFile {
+		t.Errorf("pdf at %s (single file %v), want it kept at %s", pdf.LocalPath, pdf.SingleFile, want)
+	}
+	if _, err := os.Stat(filepath.Join(paths.SkillsDir, "pdf", "extract.py")); err != nil {
+		t.Errorf("pdf include not written next to it: %v", err)
+	}
+
+	if deploy := state.FindInstalled("deploy"); deploy == nil || deploy.SingleFile {
+		t.Errorf("deploy = %+v, want the command installed as usual", deploy)
+	}
+}
+
+func TestLearnFromLocal_Rename(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { learnRenameTo = "" })
+	learnRenameTo = "Team Deploy!"
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"commands/deploy.md":  "---\ndescription: Deploy\n---\nDeploy the app.\n",
+		"skills/pdf/SKILL.md": "---\nname: pdf\ndescription: Work with PDFs\n---\n# PDF\n",
+	})
+	file := filepath.Join(dir, "commands", "deploy.md")
+	learnFromLocal(&source.Source{Type: source.TypeLocal, Path: file, Original: file}, paths)
+
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.FindInstalled("deploy") != nil {
+		t.Error("deploy recorded under its original name")
+	}
+	renamed := state.FindInstalled("Team-Deploy")
+	if renamed == nil {
+		t.Fatalf("installed = %+v, want the command as Team-Deploy", state.Installed)
+	}
+	if want := filepath.Join(paths.CommandsDir, "Team-Deploy.md"); renamed.LocalPath != want {
+		t.Errorf("path = %s, want %s", renamed.LocalPath, want)
+	}
+	if _, err := os.Stat(renamed.LocalPath); err != nil {
+		t.Errorf("renamed command not written: %v", err)
+	}
+
+	// A directory with two artifacts can't give them both the name
+	if err := checkRename(2); err == nil || !strings.Contains(err.Error(), "single artifact") {
+		t.Errorf("checkRename(2) = %v, want a single-artifact error", err)
+	}
+	if err := checkRename(1); err != nil {
+		t.Errorf("checkRename(1) = %v", err)
+	}
+	learnRenameTo = ""
+	if err := checkRename(2); err != nil {
+		t.Errorf("checkRename(2) without --rename = %v", err)
+	}
+}
+
+func TestLearnFromLocal_DryRunWritesNothing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { learnDryRun = false })
+	learnDryRun = true
+
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"commands/deploy.md":      "---\ndescription: Deploy\n---\nRun `npm install -g vercel` first.\n",
+		"skills/pdf/SKILL.md":     "---\nname: pdf\ndescription: Work with PDFs\n---\n# PDF\n",
+		"skills/pdf/extract.py":   "print('extract')\n",
+		"skills/pdf/reference.md": "# Reference\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	learnedReqs = nil
+	learnFromLocal(&source.Source{Type: source.TypeLocal, Path: dir, Original: dir}, paths)
+
+	filepath.WalkDir(home, func(path string, d os.DirEntry, err error) error {
+		if path != home {
+			t.Errorf("dry run created %s", path)
+		}
+		return nil
+	})
+
+	// Requirement detection still runs
+	var found bool
+	for _, req := range learnedReqs {
+		found = found || req.Value == "vercel"
+	}
+	if !found {
+		t.Errorf("learnedReqs = %+v, want the vercel npm requirement", learnedReqs)
+	}
+}
+
+func TestInstallFoundArtifacts_MultipleAgents(t *testing.T) {
+	srv := slowCommandServer(0)
+	defer srv.Close()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { learnTargets = nil })
+
+	learnTargets = nil
+	for _, agent := range []config.Agent{config.AgentClaude, config.AgentOpenCode} {
+		paths, err := config.GetPathsForAgent(agent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		learnTargets = append(learnTargets, paths)
+	}
+
+	result := installFoundArtifacts(fetch.NewClient(), &source.Source{}, learnTargets[0], commandListing(srv.URL, 1), nil, nil)
+	if len(result.installed) != 1 {
+		t.Fatalf("installed = %v, want cmd-00 listed once", result.installed)
+	}
+
+	for _, target := range learnTargets {
+		path := filepath.Join(target.CommandsDir, "cmd-00.md")
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s did not receive the command: %v", target.Agent, err)
+		}
+	}
+
+	state, err := config.LoadState(learnTargets[0].StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agents := map[string]bool{}
+	for _, a := range state.Installed {
+		agents[a.Agent] = true
+	}
+	if len(state.Installed) != 2 || !agents["claude"] || !agents["opencode"] {
+		t.Errorf("state = %+v, want one entry per agent", state.Installed)
+	}
+}
+
+func TestLearnFromLocal_OnlyType(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"commands/deploy.md":  "---\ndescription: Deploy\n---\n# Deploy\n",
+		"commands/review.md":  "---\ndescription: Review\n---\n# Review\n",
+		"skills/pdf/SKILL.md": "---\nname: pdf\ndescription: Work with PDFs\n---\n# PDF\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { learnOnlyType = "" })
+
+	for _, only := range []artifact.Type{artifact.TypeSkill, artifact.TypeCommand} {
+		t.Run(string(only), func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			paths, err := config.GetPaths()
+			if err != nil {
+				t.Fatal(err)
+			}
+			learnOnlyType = only
+
+			learnFromLocal(&source.Source{Type: source.TypeLocal, Path: dir, Original: dir}, paths)
+
+			state, err := config.LoadState(paths.StateFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[artifact.Type]int{artifact.TypeSkill: 1, artifact.TypeCommand: 2}[only]
+			if len(state.Installed) != want {
+				t.Fatalf("installed %+v, want %d %ss", state.Installed, want, only)
+			}
+			for _, a := range state.Installed {
+				if a.Type != only {
+					t.Errorf("installed %s %s with --only %s", a.Type, a.Name, only)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterArtifactsByType(t *testing.T) {
+	items := []fetch.GitHubContent{
+		{Name: "SKILL.md", Path: "skills/pdf/SKILL.md"},
+		{Name: "deploy.md", Path: "commands/deploy.md"},
+		{Name: "pre-commit.sh", Path: "hooks/pre-commit.sh"},
+	}
+	t.Cleanup(func() { learnOnlyType = "" })
+
+	learnOnlyType = ""
+	if kept, filtered := filterArtifactsByType(items); len(kept) != 3 || filtered != 0 {
+		t.Errorf("without --only: kept %d, filtered %d; want 3, 0", len(kept), filtered)
+	}
+
+	learnOnlyType = artifact.TypeSkill
+	kept, filtered := filterArtifactsByType(items)
+	if len(kept) != 1 || kept[0].Name != "SKILL.md" || filtered != 2 {
+		t.Errorf("--only skill: kept %+v, filtered %d; want SKILL.md, 2", kept, filtered)
+	}
+
+	if _, err := parseOnlyType("hook"); err == nil {
+		t.Error("parseOnlyType(hook) succeeded, want error")
+	}
+}
+
+func TestRunLearn_JSON(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() {
+		learnJSON, learnGlobal = false, false
+		learnTargets, learnedReqs, learnedArtifacts, learnSkipped = nil, nil, nil, nil
+	})
+	learnJSON, learnGlobal = true, true
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"commands/deploy.md":  "---\ndescription: Deploy\n---\nRun `npm install -g vercel` first.\n",
+		"commands/broken.md":  "---\ndescription: [unclosed\n---\n# Broken\n",
+		"skills/pdf/SKILL.md": "---\nname: pdf\ndescription: Work with PDFs\n---\n# PDF\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	runLearn(learnCmd, []string{dir})
+	os.Stdout = stdout
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result learnJSONResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatalf("stdout isn't only JSON: %v\n%s", err, out)
+	}
+
+	installed := map[string]learnedArtifact{}
+	for _, a := range result.Installed {
+		installed[a.Name] = a
+	}
+	if len(installed) != 2 || installed["deploy"].Type != artifact.TypeCommand || installed["pdf"].Type != artifact.TypeSkill {
+		t.Errorf("installed = %+v, want the deploy command and pdf skill", result.Installed)
+	}
+	for _, a := range result.Installed {
+		if _, err := os.Stat(a.Path); err != nil {
+			t.Errorf("%s path %s: %v", a.Name, a.Path, err)
+		}
+	}
+	if len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0].Name, "broken") || result.Skipped[0].Reason == "" {
+		t.Errorf("skipped = %+v, want broken.md with a reason", result.Skipped)
+	}
+
+	var found bool
+	for _, req := range result.Requirements {
+		found = found || req.Value == "vercel"
+	}
+	if !found {
+		t.Errorf("requirements = %+v, want the vercel npm requirement", result.Requirements)
+	}
+}
+
+func TestPrintLearnFailure_JSON(t *testing.T) {
+	t.Cleanup(func() {
+		learnJSON, learnSource, learnedArtifacts = false, nil, nil
+	})
+	learnJSON = true
+	learnSource = &source.Source{Type: source.TypeLocal, Path: "./skills"}
+	learnedArtifacts = []learnedArtifact{{Name: "deploy", Type: artifact.TypeCommand}}
+
+	// What a failed install rolls back drops out of the summary too
+	tx := beginInstall()
+	learnedArtifacts = append(learnedArtifacts, learnedArtifact{Name: "pdf", Type: artifact.TypeSkill})
+	tx.rollback()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	printLearnFailure("fetch failed")
+	os.Stdout = stdout
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result learnJSONResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatalf("stdout isn't only JSON: %v\n%s", err, out)
+	}
+	if result.Error != "fetch failed" || result.Source != "./skills" {
+		t.Errorf("error, source = %q, %q; want fetch failed, ./skills", result.Error, result.Source)
+	}
+	if len(result.Installed) != 1 || result.Installed[0].Name != "deploy" {
+		t.Errorf("installed = %+v, want only deploy", result.Installed)
+	}
+}
+
+func TestRunLearn_ConvertsForAgent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() {
+		learnAgents, learnGlobal = nil, false
+		learnTargets, learnedReqs, learnedArtifacts, learnSkipped, learnWarnings = nil, nil, nil, nil, nil
+	})
+	learnAgents, learnGlobal = []string{"copilot"}, true
+
+	dir := t.TempDir()
+	skill := "---\nname: pdf\ndescription: Work with PDFs\nallowed-tools: [Read, Bash]\nglobs: [\"**/*.pdf\"]\n---\n# PDF\n\nUse pdftotext.\n"
+	if err := os.MkdirAll(filepath.Join(dir, "skills", "pdf"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "skills", "pdf", "SKILL.md"), []byte(skill), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runLearn(learnCmd, []string{dir})
+
+	path := filepath.Join(home, ".github", "agents", "pdf.agent.md")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("copilot agent not written: %v", err)
+	}
+	if got := schema.DetectFormat(path, content); got != schema.FormatCopilot {
+		t.Errorf("installed format = %s, want %s", got, schema.FormatCopilot)
+	}
+	if !strings.Contains(string(content), "description: Work with PDFs") {
+		t.Errorf("description lost in conversion:\n%s", content)
+	}
+	if strings.Contains(string(content), "allowed-tools") {
+		t.Errorf("claude-only field kept in copilot agent:\n%s", content)
+	}
+
+	if len(learnWarnings) == 0 {
+		t.Fatal("no conversion warnings recorded")
+	}
+	for _, w := range learnWarnings {
+		if w.Name != "pdf" || w.Format != schema.FormatCopilot {
+			t.Errorf("warning = %+v, want one for pdf converting to copilot", w)
+		}
+	}
+}
+
+func TestPluginHookSettings(t *testing.T) {
+	hooksJSON := `{"hooks": {"PostToolUse": [{"matcher": "Write", "hooks": [{"type": "command", "command": "${CLAUDE_PLUGIN_ROOT}/hooks/fmt.sh"}]}]}}`
+	hooks := []artifact.Artifact{
+		{Name: "PostToolUse-Write-1-1", Type: artifact.TypeHook, Filename: "hooks.json", Content: hooksJSON},
+		{Name: "fmt", Type: artifact.TypeHook, Filename: "fmt.sh", Event: "Fmt"},
+	}
+
+	got, err := pluginHookSettings(hooks, "/home/me/.claude/hooks")
+	if err != nil || len(got) != 1 || got[0].Event != "PostToolUse" || got[0].Matcher != "Write" {
+		t.Fatalf("settings = %+v, want hooks.json's handler only", got)
+	}
+	if cmd := got[0].Entry["command"]; cmd != "/home/me/.claude/hooks/fmt.sh" {
+		t.Errorf("command = %v, want the plugin root rewritten to the hooks dir", cmd)
+	}
+
+	got, err = pluginHookSettings(hooks[1:], "/home/me/.claude/hooks")
+	if err != nil || len(got) != 1 || got[0].Event != "Fmt" || got[0].Entry["command"] != "/home/me/.claude/hooks/fmt.sh" {
+		t.Errorf("settings = %+v, %v; want the script registered for its event", got, err)
+	}
+
+	broken := []artifact.Artifact{{Name: "hooks", Type: artifact.TypeHook, Filename: "hooks.json", Content: "{"}}
+	if got, err := pluginHookSettings(broken, "/home/me/.claude/hooks"); err == nil {
+		t.Errorf("settings = %+v, want an error for a hooks.json that doesn't parse", got)
+	}
+}
+
+func TestInstallPluginHooks_WarnsWhenNotEnabled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { learnEnableHooks, learnWarnings = false, nil })
+	learnEnableHooks = true
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A hooks.json that doesn't parse enables nothing
+	hooks := []artifact.Artifact{{Name: "hooks", Type: artifact.TypeHook, Filename: "hooks.json", Content: "{"}}
+	out := captureStdout(t, func() { installPluginHooks(hooks, paths) })
+	if strings.Contains(out, "hook handler(s)") {
+		t.Errorf("output = %q, reported hooks enabled", out)
+	}
+	if len(learnWarnings) != 1 || !strings.Contains(learnWarnings[0].Message, "hooks not enabled") {
+		t.Errorf("warnings = %+v, want hooks not enabled", learnWarnings)
+	}
+	if _, err := os.Stat(filepath.Join(paths.AgentDir, config.SettingsFilename)); err == nil {
+		t.Error("settings.json written with nothing to enable")
+	}
+
+	// Nor does a hooks directory that can't be created
+	learnWarnings = nil
+	if err := os.RemoveAll(filepath.Join(paths.AgentDir, "hooks")); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, paths.AgentDir, map[string]string{"hooks": "not a directory"})
+	hooks = []artifact.Artifact{{Name: "fmt", Type: artifact.TypeHook, Filename: "fmt.sh", Event: "PostToolUse"}}
+	if installed := installPluginHooks(hooks, paths); len(installed) != 0 {
+		t.Errorf("installed = %v, want nothing", installed)
+	}
+	if len(learnWarnings) != 1 || learnWarnings[0].Name != "fmt" || !strings.Contains(learnWarnings[0].Message, "not installed") {
+		t.Errorf("warnings = %+v, want fmt not installed", learnWarnings)
+	}
+}
+
+func TestInstallPlugin_DetectsRequirements(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { learnTargets, learnedReqs, learnedArtifacts = nil, nil, nil })
+
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin := &artifact.Plugin{
+		Manifest: artifact.PluginManifest{Name: "tools"},
+		Skills: []artifact.Artifact{{
+			Name:     "builder",
+			Type:     artifact.TypeSkill,
+			Filename: "SKILL.md",
+			Content:  "---\nname: builder\ndescription: Builds things\n---\nRun `npm install foo` first.\n",
+		}},
+	}
+
+	result, ok := installPlugin(plugin, &source.Source{Type: source.TypeRepo, Owner: "acme", Repo: "tools"}, paths)
+	if !ok || len(result.installed) != 1 {
+		t.Fatalf("installPlugin() = %+v, %v; want the builder skill installed", result, ok)
+	}
+
+	var found bool
+	for _, req := range result.allReqs {
+		found = found || (req.Type == detect.TypeNPM && req.Value == "foo")
+	}
+	if !found {
+		t.Errorf("requirements = %+v, want the foo npm requirement", result.allReqs)
+	}
+}
+
+func TestLearnPlugin_StrictFailsOnConflicts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() {
+		learnStrict, learnConflictPolicy, learnExitStatus = false, conflictPrompt, 0
+		learnTargets, learnedReqs, learnedArtifacts, learnSkipped = nil, nil, nil, nil
+	})
+	learnStrict, learnConflictPolicy = true, conflictSkip
+
+	var srv *httptest.Server
+	entry := func(name, typ, path string) string {
+		return fmt.Sprintf(`{"name": %q, "type": %q, "path": %q, "download_url": "%s/raw/%s"}`, name, typ, path, srv.URL, path)
+	}
+	listings := map[string][]string{}
+	files := map[string]string{
+		".claude-plugin/plugin.json": `{"name": "ops"}`,
+		"commands/deploy.md":         "---\ndescription: Deploy\n---\nDeploy the app.\n",
+		"commands/review.md":         "---\ndescription: Review\n---\nReview the diff.\n",
+	}
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if content, ok := files[strings.TrimPrefix(r.URL.Path, "/raw/")]; ok {
+			w.Write([]byte(content))
+			return
+		}
+		if listing, ok := listings[r.URL.Path]; ok {
+			w.Write([]byte("[" + strings.Join(listing, ",") + "]"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	listings["/repo"] = []string{entry(".claude-plugin", "dir", ".claude-plugin"), entry("commands", "dir", "commands")}
+	listings["/repo/.claude-plugin"] = []string{entry("plugin.json", "file", ".claude-plugin/plugin.json")}
+	listings["/repo/commands"] = []string{entry("deploy.md", "file", "commands/deploy.md"), entry("review.md", "file", "commands/review.md")}
+
+	// deploy is already inscribed from another source
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &config.State{Installed: []artifact.InstalledArtifact{{
+		Artifact:  artifact.Artifact{Name: "deploy", Type: artifact.TypeCommand, Source: "other/repo"},
+		LocalPath: filepath.Join(paths.CommandsDir, "deploy.md"),
+	}}}
+	if err := config.SaveState(paths.StateFile, state); err != nil {
+		t.Fatal(err)
+	}
+	learnTargets = []*config.Paths{paths}
+
+	out := captureStdout(t, func() {
+		learnPlugin(fetch.NewClient(), &source.Source{Type: source.TypeRepo, Owner: "acme", Repo: "ops"}, srv.URL+"/repo", paths)
+	})
+	if !strings.Contains(out, "Skipped 1 artifact(s)") || !strings.Contains(out, "deploy: name conflict") {
+		t.Errorf("summary doesn't list the skipped deploy:\n%s", out)
+	}
+	if learnExitStatus != exitSkipped {
+		t.Errorf("exit status = %d, want %d under --strict", learnExitStatus, exitSkipped)
+	}
+}
+
+// writeFiles writes files, keyed by slash-separated paths relative to dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// captureLearn runs learn on source with stdout captured
+func captureLearn(t *testing.T, src string) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	runLearn(learnCmd, []string{src})
+	os.Stdout = stdout
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestLearn_DependencyChain(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() {
+		learnGlobal, learnWithDeps = false, false
+		learnTargets, learnedReqs, learnedArtifacts, learnSkipped = nil, nil, nil, nil
+	})
+	learnGlobal, learnWithDeps = true, true
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"a/tome.yaml":                 "name: a\nrequires:\n  - ../b\n",
+		"a/commands/deploy.md":        "---\ndescription: Deploy\n---\n# Deploy\n",
+		"b/skills/builder/SKILL.md":   "---\nname: builder\ndescription: Builds\nrequires:\n  - " + filepath.Join(root, "c") + "\n---\n# Builder\n",
+		"c/commands/lint.md":          "---\ndescription: Lint\n---\n# Lint\n",
+		"c/skills/linter/SKILL.md":    "---\nname: linter\ndescription: Lints\n---\n# Linter\n",
+		"unrelated/commands/other.md": "---\ndescription: Other\n---\n# Other\n",
+	})
+
+	out := captureLearn(t, filepath.Join(root, "a"))
+
+	var names []string
+	for _, a := range learnedArtifacts {
+		names = append(names, a.Name)
+	}
+	sort.Strings(names)
+	if want := "builder,deploy,lint,linter"; strings.Join(names, ",") != want {
+		t.Errorf("installed %v, want %s", names, want)
+	}
+	if !strings.Contains(out, "Dependencies of") || !strings.Contains(out, "../b") || !strings.Contains(out, filepath.Join(root, "c")) {
+		t.Errorf("output doesn't show the dependency tree:\n%s", out)
+	}
+}
+
+func TestLearn_DependencyCycle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() {
+		learnGlobal, learnWithDeps = false, false
+		learnTargets, learnedReqs, learnedArtifacts, learnSkipped = nil, nil, nil, nil
+	})
+	learnGlobal, learnWithDeps = true, true
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"x/tome.yaml":        "name: x\nrequires:\n  - ../y\n",
+		"x/commands/ping.md": "---\ndescription: Ping\n---\n# Ping\n",
+		"y/tome.yaml":        "name: y\nrequires:\n  - ../x\n",
+		"y/commands/pong.md": "---\ndescription: Pong\n---\n# Pong\n",
+	})
+
+	done := make(chan string)
+	go func() { done <- captureLearn(t, filepath.Join(root, "x")) }()
+	var out string
+	select {
+	case out = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("learn didn't finish; the dependency cycle wasn't broken")
+	}
+
+	if len(learnedArtifacts) != 2 {
+		t.Errorf("installed %+v, want ping and pong once each", learnedArtifacts)
+	}
+	if !strings.Contains(out, "(cycle)") {
+		t.Errorf("output doesn't mark the cycle:\n%s", out)
+	}
+}
+
+func TestLearn_DependenciesNeedConsent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() {
+		learnGlobal = false
+		learnTargets, learnedReqs, learnedArtifacts, learnSkipped = nil, nil, nil, nil
+	})
+	learnGlobal = true
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"a/tome.yaml":          "name: a\nrequires:\n  - ../b\n",
+		"a/commands/deploy.md": "---\ndescription: Deploy\n---\n# Deploy\n",
+		"b/commands/lint.md":   "---\ndescription: Lint\n---\n# Lint\n",
+	})
+
+	// Tests have no terminal to ask on, so without --with-deps nothing is
+	// learned but the source given
+	out := captureLearn(t, filepath.Join(root, "a"))
+	if len(learnedArtifacts) != 1 || learnedArtifacts[0].Name != "deploy" {
+		t.Errorf("installed %+v, want only deploy", learnedArtifacts)
+	}
+	if !strings.Contains(out, "--with-deps") {
+		t.Errorf("output doesn't say how to install dependencies:\n%s", out)
+	}
+}
+
+func TestLearn_DependenciesIgnoreSHA256(t *testing.T) {
+	const b = "---\nname: b\ndescription: B\n---\n# B\n"
+	var a string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a/SKILL.md":
+			w.Write([]byte(a))
+		case "/b/SKILL.md":
+			w.Write([]byte(b))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	a = "---\nname: a\ndescription: A\nrequires:\n  - " + srv.URL + "/b/SKILL.md\n---\n# A\n"
+
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() {
+		learnGlobal, learnWithDeps, learnSHA256 = false, false, ""
+		learnTargets, learnedReqs, learnedArtifacts, learnSkipped = nil, nil, nil, nil
+	})
+	learnGlobal, learnWithDeps = true, true
+	learnSHA256 = hashContent([]byte(a))
+
+	captureLearn(t, srv.URL+"/a/SKILL.md")
+
+	if len(learnedArtifacts) != 2 {
+		t.Errorf("installed %+v, want a and the b it requires", learnedArtifacts)
+	}
+	if learnSHA256 != hashContent([]byte(a)) {
+		t.Error("learning dependencies cleared --sha256")
+	}
+}
+
+func TestParseArtifact_ExtensionlessURL(t *testing.T) {
+	skill, err := parseArtifact([]byte("# Go Testing\n\nWrite table-driven tests.\n"), "index", "https://example.com/index")
+	if err != nil {
+		t.Fatalf("parseArtifact skill: %v", err)
+	}
+	if skill.Type != artifact.TypeSkill || skill.Name != "Go Testing" {
+		t.Errorf("got %s %q, want skill \"Go Testing\"", skill.Type, skill.Name)
+	}
+
+	cmd, err := parseArtifact([]byte("---\ndescription: Commit staged changes\n---\nWrite a commit.\n"), "commit", "https://example.com/commit")
+	if err != nil {
+		t.Fatalf("parseArtifact command: %v", err)
+	}
+	if cmd.Type != artifact.TypeCommand || cmd.Name != "commit" || cmd.Filename != "commit.md" {
+		t.Errorf("got %s %q (%s), want command \"commit\" (commit.md)", cmd.Type, cmd.Name, cmd.Filename)
+	}
+
+	if _, err := parseArtifact([]byte("just some text\n"), "index", "https://example.com/index"); err == nil {
+		t.Error("parseArtifact of plain text: want an error")
+	}
+}
+
+func TestSelectPluginArtifact(t *testing.T) {
+	plugin := &artifact.Plugin{
+		Skills:   []artifact.Artifact{{Name: "pdf"}, {Name: "docx"}},
+		Commands: []artifact.Artifact{{Name: "deploy"}},
+		Hooks:    []artifact.Artifact{{Name: "pre-commit"}},
+	}
+	if err := selectPluginArtifact(plugin, "deploy"); err != nil {
+		t.Fatalf("selectPluginArtifact() error = %v", err)
+	}
+	if len(plugin.Skills) != 0 || len(plugin.Commands) != 1 || len(plugin.Hooks) != 0 {
+		t.Errorf("plugin = %+v, want only the deploy command", plugin)
+	}
+	if err := selectPluginArtifact(plugin, "missing"); err == nil {
+		t.Error("selectPluginArtifact() of a missing name: want an error")
+	}
+}
+
+func TestDoInstall_FrontmatterScopesRequirements(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	art := &artifact.Artifact{Name: "fmt", Type: artifact.TypeCommand, Source: "acme/tools", Content: "---\n" +
+		"description: Format\n" +
+		"detect-requirements: code-blocks\n" +
+		"---\n" +
+		"Unlike npm install left-pad, this needs:\n\n" +
+		"```sh\nnpm install prettier\n```\n"}
+	reqs, ok := doInstallWithExtraReqs(art, paths, nil, nil)
+	if !ok {
+		t.Fatal("install skipped")
+	}
+	if len(reqs) != 1 || reqs[0].Value != "prettier" {
+		t.Errorf("requirements = %+v, want only prettier from the code block", reqs)
+	}
+}
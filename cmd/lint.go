@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/source"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var lintCmd = &cobra.Command{
+	Use:     "lint <path-or-source>",
+	Aliases: []string{"proofread"},
+	Short:   "Check skill and command frontmatter before publishing",
+	Long: `Lint the skills and commands in a file, directory, or remote source.
+
+Errors (exit status 1):
+  - Frontmatter that is malformed or never closed
+  - Includes that tome refuses to install (absolute, .., or disallowed types)
+  - Globs that don't compile
+
+Warnings:
+  - Missing name (skills) or description
+  - Descriptions longer than 1024 characters
+
+Examples:
+  tome lint ./skills/my-skill/SKILL.md
+  tome lint .                       # Everything tome learn would install
+  tome lint owner/repo --json       # Structured results for CI`,
+	Args: cobra.ExactArgs(1),
+	Run:  runLint,
+}
+
+var lintJSON bool
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintJSON, "json", false, "Output results as JSON")
+}
+
+// lintTarget is a file to lint; err is set when it couldn't be read
+type lintTarget struct {
+	path    string
+	content []byte
+	err     error
+}
+
+// lintFileResult is the lint result for one file
+type lintFileResult struct {
+	Path   string            `json:"path"`
+	Name   string            `json:"name,omitempty"`
+	Type   artifact.Type     `json:"type,omitempty"`
+	Issues []fetch.LintIssue `json:"issues"`
+}
+
+// lintReport is the --json output
+type lintReport struct {
+	Source   string           `json:"source"`
+	Errors   int              `json:"errors"`
+	Warnings int              `json:"warnings"`
+	Files    []lintFileResult `json:"files"`
+}
+
+func runLint(cmd *cobra.Command, args []string) {
+	src, err := source.Parse(args[0])
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	targets, err := collectLintTargets(newFetchClient(), src)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	report := lintReport{Source: src.String(), Files: []lintFileResult{}}
+	for _, t := range targets {
+		result := lintFile(t)
+		for _, issue := range result.Issues {
+			if issue.Severity == fetch.LintError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+		}
+		report.Files = append(report.Files, result)
+	}
+
+	if lintJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			exitWithError(fmt.Sprintf("failed to marshal results: %v", err))
+		}
+		fmt.Println(string(data))
+	} else {
+		printLintReport(report)
+	}
+
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+// lintFile lints one target, parsing it to report the name tome would install it under
+func lintFile(t lintTarget) lintFileResult {
+	result := lintFileResult{Path: t.path, Issues: []fetch.LintIssue{}}
+	if t.err != nil {
+		result.Issues = append(result.Issues, fetch.LintIssue{Severity: fetch.LintError, Message: t.err.Error()})
+		return result
+	}
+
+	result.Issues = append(result.Issues, fetch.LintArtifact(t.content, filepath.Base(t.path))...)
+	if fetch.HasLintErrors(result.Issues) {
+		return result
+	}
+
+	if art, err := parseArtifact(t.content, filepath.Base(t.path), t.path); err == nil {
+		result.Name, result.Type = art.Name, art.Type
+	} else {
+		result.Issues = append(result.Issues, fetch.LintIssue{Severity: fetch.LintError, Message: err.Error()})
+	}
+	return result
+}
+
+// collectLintTargets gathers the artifact files tome learn would install from src
+func collectLintTargets(client *fetch.Client, src *source.Source) ([]lintTarget, error) {
+	switch src.Type {
+	case source.TypeLocal:
+		info, err := os.Stat(src.Path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot access %s: %v", src.Path, err)
+		}
+		if !info.IsDir() {
+			content, err := os.ReadFile(src.Path)
+			return []lintTarget{{src.Path, content, err}}, nil
+		}
+		files, err := fetch.FindLocalArtifacts(src.Path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read directory: %v", err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no artifacts found in %s", src.Path)
+		}
+		var targets []lintTarget
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			targets = append(targets, lintTarget{file, content, err})
+		}
+		return targets, nil
+
+	case source.TypeURL:
+		content, err := client.FetchURL(src.URL)
+		return []lintTarget{{src.URL, content, err}}, nil
+	}
+
+	// Repo sources: a single markdown file, or everything discovery finds
+	if src.Path != "" && fetch.IsMarkdownFile(src.Path) {
+		content, err := client.FetchURL(src.RawURL(""))
+		return []lintTarget{{src.Path, content, err}}, nil
+	}
+
+	var items []fetch.GitHubContent
+	if src.IsGitHub() {
+		var err error
+		if items, err = client.FindArtifacts(src.GitHubAPIURL()); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", src.String(), err)
+		}
+	}
+	if len(items) == 0 {
+		skillPath := artifact.SkillFilename
+		if src.Path != "" {
+			skillPath = src.Path + "/" + skillPath
+		}
+		content, err := client.FetchURL(src.RawURL(artifact.SkillFilename))
+		if err != nil {
+			return nil, fmt.Errorf("no artifacts found at %s", src.String())
+		}
+		return []lintTarget{{skillPath, content, nil}}, nil
+	}
+
+	var targets []lintTarget
+	for _, item := range items {
+		url := item.DownloadURL
+		if url == "" {
+			url = src.RepoRawURL(item.Path)
+		}
+		content, err := client.FetchURL(url)
+		targets = append(targets, lintTarget{item.Path, content, err})
+	}
+	return targets, nil
+}
+
+func printLintReport(report lintReport) {
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Linting", 56))
+	fmt.Println()
+	fmt.Println(ui.InfoLine("Source: " + report.Source))
+	fmt.Println()
+
+	for _, file := range report.Files {
+		status := ui.Success.Render("✓")
+		if fetch.HasLintErrors(file.Issues) {
+			status = ui.Error.Render("✗")
+		} else if len(file.Issues) > 0 {
+			status = ui.Warning.Render("!")
+		}
+		fmt.Printf("  %s %s\n", status, ui.Highlight.Render(file.Path))
+
+		for _, issue := range file.Issues {
+			msg := issue.Message
+			if issue.Field != "" {
+				msg = issue.Field + ": " + msg
+			}
+			if issue.Severity == fetch.LintError {
+				fmt.Println(ui.Error.Render("      error   " + msg))
+			} else {
+				fmt.Println(ui.Warning.Render("      warning " + msg))
+			}
+		}
+	}
+
+	fmt.Println()
+	summary := fmt.Sprintf("%d file(s), %d error(s), %d warning(s)", len(report.Files), report.Errors, report.Warnings)
+	switch {
+	case report.Errors > 0:
+		fmt.Println(ui.ErrorLine(summary))
+	case report.Warnings > 0:
+		fmt.Println(ui.WarningLine(summary))
+	default:
+		fmt.Println(ui.SuccessLine(summary))
+	}
+	fmt.Println(ui.PageFooter())
+}
@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -16,8 +20,16 @@ var listCmd = &cobra.Command{
 	Use:     "index",
 	Aliases: []string{"contents", "list", "ls"},
 	Short:   "View the tome's index",
-	Long:    `Display all inscribed skills, commands, prompts, and hooks.`,
-	Run:     runList,
+	Long: `Display all inscribed skills, commands, prompts, agents, and hooks.
+
+By default both the project and global tomes are shown. Use --project or
+--global to read just one state file, --table for a compact inventory, or
+--json to emit the installed artifacts for scripting.
+
+An artifact is shown as shadowed when the agent loads another copy first:
+one from the project tome, or a skill in any directory the agent searches
+ahead of it, even if tome didn't install it.`,
+	Run: runList,
 }
 
 var (
@@ -26,6 +38,11 @@ var (
 	listPrompts  bool
 	listHooks    bool
 	listShort    bool
+	listType     string
+	listGlobal   bool
+	listProject  bool
+	listTable    bool
+	listJSON     bool
 )
 
 func init() {
@@ -34,8 +51,18 @@ func init() {
 	listCmd.Flags().BoolVar(&listPrompts, "prompts", false, "Show only prompts")
 	listCmd.Flags().BoolVar(&listHooks, "hooks", false, "Show only hooks")
 	listCmd.Flags().BoolVar(&listShort, "short", false, "Truncate descriptions to one line")
+	listCmd.Flags().StringVarP(&listType, "type", "t", "", "Show only one type (skill, command, prompt, agent, hook)")
+	listCmd.Flags().BoolVarP(&listGlobal, "global", "g", false, "Show only the global tome")
+	listCmd.Flags().BoolVarP(&listProject, "project", "p", false, "Show only the project tome")
+	listCmd.Flags().BoolVar(&listTable, "table", false, "Show a compact table of name, type, source, and install date")
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output installed artifacts as JSON")
+	listCmd.MarkFlagsMutuallyExclusive("global", "project")
+	listCmd.MarkFlagsMutuallyExclusive("table", "json")
 }
 
+// listTypes is the display order of artifact types
+var listTypes = []artifact.Type{artifact.TypeSkill, artifact.TypeCommand, artifact.TypePrompt, artifact.TypeAgent, artifact.TypeHook}
+
 // artifactWithLocation tracks an artifact and where it's from
 type artifactWithLocation struct {
 	artifact.InstalledArtifact
@@ -51,9 +78,16 @@ func runList(cmd *cobra.Command, args []string) {
 	seenNames := make(map[string]bool) // track which names we've seen (for in-effect logic)
 
 	// First, load project-local artifacts (they take precedence)
-	if config.IsAttuned(agent) {
+	var localStateFile string
+	var skillDirs []string // the agent's skill search order, for shadowing
+	if listProject && !config.IsAttuned(agent) {
+		exitWithError("not attuned to this project; run 'tome attune' first")
+	}
+	if !listGlobal && config.IsAttuned(agent) {
 		localPaths, err := config.GetLocalPaths(agent)
 		if err == nil {
+			localStateFile = localPaths.StateFile
+			skillDirs = localPaths.SkillDirs
 			localState, err := config.LoadState(localPaths.StateFile)
 			if err == nil {
 				for _, a := range localState.Installed {
@@ -70,17 +104,29 @@ func runList(cmd *cobra.Command, args []string) {
 	}
 
 	// Then load global artifacts
-	globalPaths, err := config.GetPathsForAgent(agent)
-	if err != nil {
-		exitWithError(err.Error())
-	}
+	var globalInstalled []artifact.InstalledArtifact
+	if !listProject {
+		globalPaths, err := config.GetPathsForAgent(agent)
+		if err != nil {
+			exitWithError(err.Error())
+		}
+		if skillDirs == nil {
+			skillDirs = globalPaths.SkillDirs
+		}
+
+		globalState, err := config.LoadState(globalPaths.StateFile)
+		if err != nil {
+			exitWithError(err.Error())
+		}
 
-	globalState, err := config.LoadState(globalPaths.StateFile)
-	if err != nil {
-		exitWithError(err.Error())
+		// With a --state/$TOME_STATE override both scopes share one file;
+		// skip the global pass so entries aren't listed twice.
+		if globalPaths.StateFile != localStateFile {
+			globalInstalled = globalState.Installed
+		}
 	}
 
-	for _, a := range globalState.Installed {
+	for _, a := range globalInstalled {
 		key := fmt.Sprintf("%s:%s", a.Type, a.Name)
 		inEffect := !seenNames[key] // only in effect if not shadowed by local
 		allArtifacts = append(allArtifacts, artifactWithLocation{
@@ -90,9 +136,27 @@ func runList(cmd *cobra.Command, args []string) {
 		})
 	}
 
+	// A skill tome didn't install can still shadow one it did, if it sits in
+	// a directory the agent searches first
+	for i, a := range allArtifacts {
+		if a.Type == artifact.TypeSkill && a.InEffect && skillShadowed(skillDirs, a.Name, a.LocalPath) {
+			allArtifacts[i].InEffect = false
+		}
+	}
+
 	// Determine which types to show
-	showAll := !listSkills && !listCommands && !listPrompts && !listHooks
+	showAll := !listSkills && !listCommands && !listPrompts && !listHooks && listType == ""
 	typeFilter := make(map[artifact.Type]bool)
+	if listType != "" {
+		t, err := parseListType(listType)
+		if err != nil {
+			exitWithError(err.Error())
+		}
+		typeFilter[t] = true
+	}
+	if showAll {
+		typeFilter[artifact.TypeAgent] = true
+	}
 	if showAll || listSkills {
 		typeFilter[artifact.TypeSkill] = true
 	}
@@ -114,11 +178,29 @@ func runList(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if listJSON {
+		installed := make([]artifact.InstalledArtifact, 0, len(filtered))
+		for _, a := range filtered {
+			installed = append(installed, a.InstalledArtifact)
+		}
+		data, err := json.MarshalIndent(installed, "", "  ")
+		if err != nil {
+			exitWithError("Failed to encode JSON: " + err.Error())
+		}
+		fmt.Println(string(data))
+		return
+	}
+
 	if len(filtered) == 0 {
 		fmt.Print(ui.EmptyTome())
 		return
 	}
 
+	if listTable {
+		printListTable(filtered)
+		return
+	}
+
 	// Header
 	fmt.Println()
 	fmt.Println(ui.SectionHeader("Your Tome", 56))
@@ -131,7 +213,7 @@ func runList(cmd *cobra.Command, args []string) {
 	}
 
 	// Display each type
-	for _, t := range []artifact.Type{artifact.TypeSkill, artifact.TypeCommand, artifact.TypePrompt, artifact.TypeHook} {
+	for _, t := range listTypes {
 		artifacts := byType[t]
 		if len(artifacts) == 0 {
 			continue
@@ -221,6 +303,79 @@ func runList(cmd *cobra.Command, args []string) {
 	fmt.Println(ui.PageFooter())
 }
 
+// skillShadowed reports whether a skill directory searched before the one
+// holding path has its own skill called name
+func skillShadowed(dirs []string, name, path string) bool {
+	for _, dir := range dirs {
+		if rel, err := filepath.Rel(dir, path); err == nil && filepath.IsLocal(rel) {
+			return false
+		}
+		for _, candidate := range []string{
+			filepath.Join(dir, name, artifact.SkillFilename),
+			filepath.Join(dir, name+".md"),
+		} {
+			if _, err := os.Stat(candidate); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseListType validates a --type value
+func parseListType(value string) (artifact.Type, error) {
+	t := artifact.Type(strings.ToLower(strings.TrimSpace(value)))
+	for _, known := range listTypes {
+		if t == known {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown type %q (expected skill, command, prompt, agent, or hook)", value)
+}
+
+// printListTable renders artifacts as a name/type/source/installed table
+func printListTable(artifacts []artifactWithLocation) {
+	nameWidth, sourceWidth, badgeWidth := len("NAME"), len("SOURCE"), len("TYPE")
+	for _, a := range artifacts {
+		nameWidth = max(nameWidth, lipgloss.Width(a.Name))
+		sourceWidth = max(sourceWidth, lipgloss.Width(a.Source))
+		badgeWidth = max(badgeWidth, lipgloss.Width(getBadge(a.Type)))
+	}
+
+	fmt.Println()
+	fmt.Println("  " + ui.TableHeader(
+		padRight("NAME", nameWidth),
+		padRight("TYPE", badgeWidth),
+		padRight("SOURCE", sourceWidth),
+		"INSTALLED",
+	))
+	for _, a := range artifacts {
+		installed := "-"
+		if !a.InstalledAt.IsZero() {
+			installed = a.InstalledAt.Local().Format("2006-01-02")
+		}
+		source := a.Source
+		if source == "" {
+			source = "-"
+		}
+		fmt.Println("  " + ui.TableRow(
+			padRight(a.Name, nameWidth),
+			padRight(getBadge(a.Type), badgeWidth),
+			padRight(source, sourceWidth),
+			installed,
+		))
+	}
+	fmt.Println(ui.PageFooter())
+}
+
+// padRight pads styled text with spaces to the given display width
+func padRight(text string, width int) string {
+	if w := lipgloss.Width(text); w < width {
+		return text + strings.Repeat(" ", width-w)
+	}
+	return text
+}
+
 func getBadge(t artifact.Type) string {
 	switch t {
 	case artifact.TypeSkill:
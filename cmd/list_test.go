@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+)
+
+// listFixture records a review skill and deploy command in the global tome
+// and another deploy command in an attuned project's tome
+func listFixture(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	writeFiles(t, project, map[string]string{
+		".git/HEAD":            "ref: refs/heads/main",
+		".claude/skills/.keep": "",
+	})
+	t.Chdir(project)
+	t.Cleanup(func() {
+		listSkills, listCommands, listPrompts, listHooks, listShort = false, false, false, false, false
+		listType, listGlobal, listProject, listTable, listJSON = "", false, false, false, false
+	})
+
+	installedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	installed := func(name string, typ artifact.Type, src string) artifact.InstalledArtifact {
+		return artifact.InstalledArtifact{
+			Artifact: artifact.Artifact{Name: name, Type: typ, Source: src, InstalledAt: installedAt},
+		}
+	}
+	globalPaths, err := config.GetPathsForAgent(config.AgentClaude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	localPaths, err := config.GetLocalPaths(config.AgentClaude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, state := range map[string]*config.State{
+		globalPaths.StateFile: {Installed: []artifact.InstalledArtifact{
+			installed("review", artifact.TypeSkill, "global/repo"),
+			installed("deploy", artifact.TypeCommand, "global/repo"),
+		}},
+		localPaths.StateFile: {Installed: []artifact.InstalledArtifact{
+			installed("deploy", artifact.TypeCommand, "project/repo"),
+		}},
+	} {
+		if err := config.SaveState(path, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// listJSONOutput runs list --json and returns its entries as name@source
+func listJSONOutput(t *testing.T) []string {
+	t.Helper()
+	listJSON = true
+	out := captureStdout(t, func() { runList(listCmd, nil) })
+	var installed []artifact.InstalledArtifact
+	if err := json.Unmarshal([]byte(out), &installed); err != nil {
+		t.Fatalf("list --json output isn't JSON: %v\n%s", err, out)
+	}
+	var entries []string
+	for _, a := range installed {
+		entries = append(entries, a.Name+"@"+a.Source)
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+func TestRunList_Flags(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func()
+		want string
+	}{
+		{"both tomes", func() {}, "deploy@global/repo,deploy@project/repo,review@global/repo"},
+		{"global", func() { listGlobal = true }, "deploy@global/repo,review@global/repo"},
+		{"project", func() { listProject = true }, "deploy@project/repo"},
+		{"type", func() { listType = "Skill" }, "review@global/repo"},
+		{"commands", func() { listCommands = true }, "deploy@global/repo,deploy@project/repo"},
+		{"type and scope", func() { listType, listProject = "skill", true }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listFixture(t)
+			tt.set()
+			if got := strings.Join(listJSONOutput(t), ","); got != tt.want {
+				t.Errorf("list --json = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunList_Table(t *testing.T) {
+	listFixture(t)
+	listTable, listGlobal = true, true
+
+	out := captureStdout(t, func() { runList(listCmd, nil) })
+	for _, want := range []string{"NAME", "SOURCE", "INSTALLED", "review", "global/repo", "2026-03-01"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "project/repo") {
+		t.Errorf("--global table lists the project tome:\n%s", out)
+	}
+}
+
+func TestRunList_ShadowedAndEmpty(t *testing.T) {
+	listFixture(t)
+
+	// The project's deploy shadows the global one
+	out := captureStdout(t, func() { runList(listCmd, nil) })
+	if !strings.Contains(out, "2 in effect (1 project, 1 global, 1 shadowed)") {
+		t.Errorf("footer missing the shadowed deploy:\n%s", out)
+	}
+
+	listType = "agent"
+	if out := captureStdout(t, func() { runList(listCmd, nil) }); !strings.Contains(out, "awaits its first inscription") {
+		t.Errorf("no agents = %q, want the empty tome", out)
+	}
+}
+
+func TestSkillShadowed_FollowsSkillDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	globalSkill := filepath.Join(home, ".claude", "skills", "review", "SKILL.md")
+	writeFiles(t, home, map[string]string{
+		".claude/skills/review/SKILL.md": "# Review",
+		".claude/skills/lint/SKILL.md":   "# Lint",
+	})
+	// A hand-written project skill, not installed by tome
+	writeFiles(t, project, map[string]string{
+		".git/HEAD":                      "ref: refs/heads/main",
+		".claude/skills/review/SKILL.md": "# Project review",
+	})
+	t.Chdir(project)
+
+	paths, err := config.GetLocalPaths(config.AgentClaude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !skillShadowed(paths.SkillDirs, "review", globalSkill) {
+		t.Error("global review not shadowed by the project's copy")
+	}
+	if skillShadowed(paths.SkillDirs, "lint", filepath.Join(home, ".claude", "skills", "lint", "SKILL.md")) {
+		t.Error("global lint shadowed without a project copy")
+	}
+	if skillShadowed(paths.SkillDirs, "review", filepath.Join(project, ".claude", "skills", "review", "SKILL.md")) {
+		t.Error("project review shadowed by itself")
+	}
+
+	// Searched first, the global dir wins
+	globalPaths, err := config.GetPathsForAgent(config.AgentClaude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skillShadowed(globalPaths.SkillDirs, "review", globalSkill) {
+		t.Error("global review shadowed though its dir is searched first")
+	}
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/source"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+// beginLockUpdate loads the project's tome.lock so this run's installs are
+// pinned in it, and resolves a GitHub source's ref to the commit learned
+// from. The source is then fetched at that commit, so what's installed is
+// what the lock pins even if the branch moves meanwhile.
+func beginLockUpdate(client *fetch.Client, src *source.Source) {
+	learnLockPath = filepath.Join(config.ProjectRoot(), config.LockfileName)
+	lock, err := config.LoadLockfile(learnLockPath)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	learnLock = lock
+
+	if src.Type != source.TypeRepo || !src.IsGitHub() {
+		return
+	}
+	commit, err := client.ResolveGitHubCommit(src.GitHubAPIURL(), src.Ref)
+	if err != nil {
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Couldn't resolve %s to a commit; %s will pin its checksum only: %v", src.Ref, config.LockfileName, err)))
+		return
+	}
+	learnCommit, src.Commit = commit, commit
+}
+
+// unpinnedURL returns a URL fetched at learnCommit as it reads at the ref
+// the source named, so renew follows the branch rather than the commit
+func unpinnedURL(rawURL string) string {
+	if learnCommit == "" || learnSource == nil || learnSource.Ref == "" {
+		return rawURL
+	}
+	return strings.Replace(rawURL, "/"+learnCommit+"/", "/"+learnSource.Ref+"/", 1)
+}
+
+// saveLockUpdate writes back the lockfile beginLockUpdate loaded, as part of
+// the install in progress, so rolling that back restores tome.lock too
+func saveLockUpdate() {
+	if learnLock == nil {
+		return
+	}
+	err := activeInstall.track(learnLockPath)
+	if err == nil {
+		err = config.SaveLockfile(learnLockPath, learnLock)
+	}
+	if err != nil {
+		fmt.Fprintln(learnOutput(), ui.WarningLine(fmt.Sprintf("Failed to update %s: %v", config.LockfileName, err)))
+	}
+}
+
+// inProject reports whether an installed file is in the current project,
+// whose tome.lock pins it
+func inProject(localPath string) bool {
+	root := config.ProjectRoot()
+	if root == "" {
+		return false
+	}
+	rel, err := filepath.Rel(root, localPath)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// updateProjectLock applies update to the current project's tome.lock, if it
+// has one, saving it when update reports a change
+func updateProjectLock(update func(*config.Lockfile) bool) {
+	root := config.ProjectRoot()
+	if root == "" {
+		return
+	}
+	path := filepath.Join(root, config.LockfileName)
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	lock, err := config.LoadLockfile(path)
+	if err == nil && update(lock) {
+		err = config.SaveLockfile(path, lock)
+	}
+	if err != nil {
+		fmt.Println(ui.WarningLine(fmt.Sprintf("Failed to update %s: %v", config.LockfileName, err)))
+	}
+}
+
+// unpinArtifact drops a forgotten artifact from the project's tome.lock, so
+// tome install doesn't bring it back
+func unpinArtifact(name string, t artifact.Type) {
+	updateProjectLock(func(lock *config.Lockfile) bool {
+		return lock.Remove(name, t)
+	})
+}
+
+// repinArtifact records a renewed artifact's new content in the project's
+// tome.lock, along with the commit its ref now resolves to
+func repinArtifact(client *fetch.Client, a *artifact.InstalledArtifact, content []byte) {
+	updateProjectLock(func(lock *config.Lockfile) bool {
+		entry, ok := lock.Get(a.Name, a.Type)
+		if !ok {
+			return false
+		}
+		entry.SHA256 = hashContent(content)
+		if entry.Commit != "" {
+			entry.Commit = ""
+			if src, err := source.Parse(entry.Source); err == nil && src.IsGitHub() {
+				entry.Commit, _ = client.ResolveGitHubCommit(src.GitHubAPIURL(), src.Ref)
+			}
+		}
+		lock.Set(entry)
+		return true
+	})
+}
+
+// lockEntryFor pins an artifact installed while learning runSrc, whose ref
+// resolved to commit. Artifacts from local paths aren't pinned, since the
+// paths only mean something on this machine.
+func lockEntryFor(art *artifact.Artifact, runSrc *source.Source, commit string) (config.LockEntry, bool) {
+	src, err := source.Parse(art.Source)
+	if err != nil || src.Type == source.TypeLocal {
+		return config.LockEntry{}, false
+	}
+
+	entry := config.LockEntry{
+		Name:   art.Name,
+		Type:   art.Type,
+		Source: art.Source,
+		SHA256: hashContent([]byte(art.Content)),
+	}
+	if src.Type == source.TypeRepo {
+		entry.Ref = src.Ref
+		// A marketplace plugin may come from another repo than the one resolved
+		if runSrc != nil && src.SameRepo(runSrc) {
+			entry.Commit = commit
+		}
+	}
+	return entry, true
+}
+
+// lockedSource returns the source that reinstalls what entry pins: its repo
+// at the locked commit, narrowed to the one artifact when discovery allows
+func lockedSource(entry config.LockEntry) (*source.Source, error) {
+	src, err := source.Parse(entry.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	switch src.Type {
+	case source.TypeLocal:
+		return nil, fmt.Errorf("%s pins local path %s, which can't be installed elsewhere", config.LockfileName, entry.Source)
+	case source.TypeRepo:
+		if entry.Commit != "" {
+			src.Ref, src.Range = entry.Commit, ""
+		}
+		if src.Name == "" && src.IsGitHub() && !fetch.IsMarkdownFile(src.Path) {
+			src.Name = entry.Name
+			if entry.Selector != "" {
+				src.Name = entry.Selector
+			}
+		}
+	}
+	return src, nil
+}
+
+// installFromLockfile reinstalls every artifact a lockfile pins, each from
+// its locked commit and only if its content still has the locked checksum.
+// The lockfile itself is left as it is.
+func installFromLockfile(path string) {
+	if learnJSON || learnRequirementsJSON {
+		exitWithError("--json and --requirements-json need a source")
+	}
+	if learnSHA256 != "" {
+		exitWithError(fmt.Sprintf("--sha256 needs a source; %s pins each artifact's checksum", config.LockfileName))
+	}
+	if learnRename != "" {
+		exitWithError("--rename needs a source with a single artifact")
+	}
+
+	lock, err := config.LoadLockfile(path)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if len(lock.Artifacts) == 0 {
+		exitWithError(fmt.Sprintf("no source given, and %s pins no artifacts", path))
+	}
+
+	// A checksum mismatch skips the artifact, which must fail the install
+	strict := learnStrict
+	learnFromLock, learnStrict = true, true
+	defer func() {
+		learnFromLock, learnStrict, learnSHA256, learnRename = false, strict, "", ""
+	}()
+
+	// Learning a plugin installs all its artifacts, so later entries for
+	// them are already done
+	type lockKey struct {
+		source, name string
+		typ          artifact.Type
+	}
+	done := map[lockKey]bool{}
+	for _, entry := range lock.Artifacts {
+		if done[lockKey{entry.Source, entry.Name, entry.Type}] {
+			continue
+		}
+		src, err := lockedSource(entry)
+		if err != nil {
+			exitWithError(err.Error())
+		}
+
+		// An artifact installed under another name is renamed again
+		learnSHA256, learnRename = entry.SHA256, ""
+		if entry.Selector != "" {
+			learnRename = entry.Name
+		}
+		learn(src)
+		for _, a := range learnedArtifacts {
+			done[lockKey{entry.Source, a.Name, a.Type}] = true
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/source"
+)
+
+func TestLockEntryFor(t *testing.T) {
+	runSrc, err := source.Parse("acme/skills@v1.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		name   string
+		source string
+		want   config.LockEntry
+		ok     bool
+	}{
+		{
+			name:   "same repo pins the commit",
+			source: "acme/skills@v1.2.0",
+			want:   config.LockEntry{Name: "pdf", Type: artifact.TypeSkill, Source: "acme/skills@v1.2.0", Ref: "v1.2.0", Commit: commit, SHA256: hashContent([]byte("# PDF\n"))},
+			ok:     true,
+		},
+		{
+			name:   "other repo pins only the ref",
+			source: "other/plugin",
+			want:   config.LockEntry{Name: "pdf", Type: artifact.TypeSkill, Source: "other/plugin", Ref: "main", SHA256: hashContent([]byte("# PDF\n"))},
+			ok:     true,
+		},
+		{
+			name:   "url",
+			source: "https://example.com/pdf/SKILL.md",
+			want:   config.LockEntry{Name: "pdf", Type: artifact.TypeSkill, Source: "https://example.com/pdf/SKILL.md", SHA256: hashContent([]byte("# PDF\n"))},
+			ok:     true,
+		},
+		{
+			name:   "local path isn't pinned",
+			source: t.TempDir(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			art := &artifact.Artifact{Name: "pdf", Type: artifact.TypeSkill, Source: tt.source, Content: "# PDF\n"}
+			got, ok := lockEntryFor(art, runSrc, commit)
+			if ok != tt.ok {
+				t.Fatalf("lockEntryFor() ok = %v, want %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("lockEntryFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnpinnedURL(t *testing.T) {
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	t.Cleanup(func() { learnSource, learnCommit = nil, "" })
+	learnSource, learnCommit = &source.Source{Type: source.TypeRepo, Owner: "acme", Repo: "skills", Ref: "main", Commit: commit}, commit
+
+	got := unpinnedURL("https://raw.githubusercontent.com/acme/skills/" + commit + "/pdf/SKILL.md")
+	if want := "https://raw.githubusercontent.com/acme/skills/main/pdf/SKILL.md"; got != want {
+		t.Errorf("unpinnedURL() = %q, want %q", got, want)
+	}
+
+	learnCommit = ""
+	if got := unpinnedURL("https://example.com/SKILL.md"); got != "https://example.com/SKILL.md" {
+		t.Errorf("unpinnedURL() without a commit = %q, want it unchanged", got)
+	}
+}
+
+func TestLockedSource(t *testing.T) {
+	src, err := lockedSource(config.LockEntry{Name: "pdf", Source: "acme/skills@^1.2", Ref: "^1.2", Commit: "abc123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.Ref != "abc123" || src.Name != "pdf" || src.String() != "acme/skills@abc123#pdf" {
+		t.Errorf("lockedSource() = %s (ref %s), want the pdf artifact at the locked commit", src.String(), src.Ref)
+	}
+
+	src, err = lockedSource(config.LockEntry{Name: "pdf", Source: "acme/skills:pdf/SKILL.md", Commit: "abc123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.Name != "" || src.Path != "pdf/SKILL.md" {
+		t.Errorf("lockedSource() = %s, want the file itself without a #name", src.String())
+	}
+
+	src, err = lockedSource(config.LockEntry{Name: "pdf-acme", Selector: "pdf", Source: "acme/skills"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.Name != "pdf" {
+		t.Errorf("lockedSource() of a renamed artifact = %s, want it selected by its name in the source", src.String())
+	}
+
+	if _, err := lockedSource(config.LockEntry{Name: "pdf", Source: t.TempDir()}); err == nil {
+		t.Error("lockedSource() for a local path succeeded, want error")
+	}
+}
+
+func TestInstallFromLockfile(t *testing.T) {
+	const content = "---\ndescription: Greet someone\n---\nSay hello.\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/commands/greet.md" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() {
+		learnGlobal = false
+		learnTargets, learnedReqs, learnedArtifacts, learnSkipped, learnWarnings = nil, nil, nil, nil, nil
+	})
+	learnGlobal = true
+
+	lockPath := filepath.Join(t.TempDir(), config.LockfileName)
+	lock := &config.Lockfile{Artifacts: []config.LockEntry{{
+		Name:   "greet",
+		Type:   artifact.TypeCommand,
+		Source: srv.URL + "/commands/greet.md",
+		SHA256: hashContent([]byte(content)),
+	}}}
+	if err := config.SaveLockfile(lockPath, lock); err != nil {
+		t.Fatal(err)
+	}
+	locked, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installed := filepath.Join(home, ".claude", "commands", "greet.md")
+	for i := 0; i < 2; i++ {
+		installFromLockfile(lockPath)
+
+		got, err := os.ReadFile(installed)
+		if err != nil {
+			t.Fatalf("install %d: command not written: %v", i+1, err)
+		}
+		if string(got) != content {
+			t.Errorf("install %d wrote %q, want the locked content", i+1, got)
+		}
+	}
+
+	if after, _ := os.ReadFile(lockPath); string(after) != string(locked) {
+		t.Errorf("installing changed the lockfile:\n%s", after)
+	}
+	if learnFromLock || learnSHA256 != "" || learnStrict {
+		t.Error("lockfile install left learn flags set")
+	}
+}
+
+func TestProjectLock_ForgetAndRenew(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	if err := os.Mkdir(filepath.Join(project, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(project)
+	paths, err := config.GetLocalPaths(config.AgentClaude)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockPath := filepath.Join(project, config.LockfileName)
+	lock := &config.Lockfile{Artifacts: []config.LockEntry{
+		{Name: "greet", Type: artifact.TypeCommand, Source: "https://example.com/greet.md", SHA256: hashContent([]byte("old"))},
+		{Name: "wave", Type: artifact.TypeCommand, Source: "https://example.com/wave.md", SHA256: hashContent([]byte("old"))},
+	}}
+	if err := config.SaveLockfile(lockPath, lock); err != nil {
+		t.Fatal(err)
+	}
+
+	state := &config.State{}
+	for _, name := range []string{"greet", "wave"} {
+		state.AddInstalled(artifact.InstalledArtifact{
+			Artifact:  artifact.Artifact{Name: name, Type: artifact.TypeCommand},
+			LocalPath: filepath.Join(paths.CommandsDir, name+".md"),
+		})
+	}
+
+	// Renewing records the new checksum
+	repinArtifact(nil, state.FindInstalled("wave"), []byte("new"))
+	// Forgetting drops the entry, so tome install doesn't bring it back
+	removeArtifact(state, paths, "greet", artifact.TypeCommand)
+
+	got, err := config.LoadLockfile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Artifacts) != 1 || got.Artifacts[0].Name != "wave" {
+		t.Fatalf("lockfile = %+v, want only wave", got.Artifacts)
+	}
+	if got.Artifacts[0].SHA256 != hashContent([]byte("new")) {
+		t.Errorf("wave's checksum = %s, want the renewed content's", got.Artifacts[0].SHA256)
+	}
+}
+
+func TestInstallFromLockfile_Renamed(t *testing.T) {
+	const content = "---\nname: greet\ndescription: Greet someone\n---\nSay hello.\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() {
+		learnGlobal = false
+		learnTargets, learnedReqs, learnedArtifacts, learnSkipped, learnWarnings = nil, nil, nil, nil, nil
+	})
+	learnGlobal = true
+
+	lockPath := filepath.Join(t.TempDir(), config.LockfileName)
+	lock := &config.Lockfile{Artifacts: []config.LockEntry{{
+		Name:     "hello",
+		Selector: "greet",
+		Type:     artifact.TypeCommand,
+		Source:   srv.URL + "/commands/greet.md",
+		SHA256:   hashContent([]byte(content)),
+	}}}
+	if err := config.SaveLockfile(lockPath, lock); err != nil {
+		t.Fatal(err)
+	}
+
+	installFromLockfile(lockPath)
+
+	got, err := os.ReadFile(filepath.Join(home, ".claude", "commands", "hello.md"))
+	if err != nil {
+		t.Fatalf("renamed command not written: %v", err)
+	}
+	if !strings.Contains(string(got), "name: hello\n") {
+		t.Errorf("installed frontmatter keeps the old name:\n%s", got)
+	}
+	if learnRename != "" {
+		t.Error("lockfile install left --rename set")
+	}
+}
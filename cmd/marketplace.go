@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/detect"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/source"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+// learnMarketplace installs plugins listed in a marketplace: the one src's
+// #name selector picks, all of them with --all-plugins, or the ones chosen at
+// a prompt
+func learnMarketplace(client *fetch.Client, src *source.Source, apiURL string, paths *config.Paths) {
+	marketplace, err := client.FetchMarketplace(apiURL)
+	if err != nil {
+		exitWithError(fmt.Sprintf("failed to fetch marketplace: %v", err))
+	}
+
+	fmt.Fprintln(learnOutput(), ui.PluginBadge()+"  "+ui.Info.Render("Marketplace detected"))
+	fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    %s/%s", src.Owner, src.Repo)))
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Highlight.Render("  "+marketplace.Name))
+	if marketplace.Metadata.Description != "" {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render("    "+marketplace.Metadata.Description))
+	}
+	if marketplace.Owner.Name != "" {
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("    by %s", marketplace.Owner.Name)))
+	}
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Muted.Render(fmt.Sprintf("  Lists %d plugin(s):", len(marketplace.Plugins))))
+	for i, p := range marketplace.Plugins {
+		line := fmt.Sprintf("    %d. %s", i+1, p.Name)
+		if p.Description != "" {
+			line += " - " + ui.Truncate(p.Description, 50)
+		}
+		fmt.Fprintln(learnOutput(), ui.Muted.Render(line))
+	}
+	fmt.Fprintln(learnOutput())
+
+	selected, err := selectMarketplacePlugins(marketplace, src.Name, learnAllPlugins)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if selected == nil {
+		if learnJSON || !stdinIsTerminal() {
+			picked := *src
+			picked.Name = "<name>"
+			exitWithError(fmt.Sprintf("%s is a marketplace; pick a plugin with tome learn %s, or pass --all-plugins", src.String(), picked.String()))
+		}
+		selected = promptMarketplacePlugins(marketplace)
+		if len(selected) == 0 {
+			exitWithError("no plugin selected")
+		}
+	}
+
+	var result installResult
+	for _, entry := range selected {
+		pluginSrc, err := marketplacePluginSource(src, marketplace, entry)
+		if err == nil {
+			var plugin *artifact.Plugin
+			plugin, err = client.FetchMarketplacePlugin(pluginSrc.GitHubAPIURL(), pluginSrc.String(), entry)
+			if err == nil {
+				r, _ := installPlugin(plugin, pluginSrc, paths)
+				result.installed = append(result.installed, r.installed...)
+				result.skipped = append(result.skipped, r.skipped...)
+				result.allReqs = detect.Merge(result.allReqs, r.allReqs)
+				result.filtered += r.filtered
+				continue
+			}
+		}
+		fmt.Fprintln(learnOutput(), ui.Warning.Render(fmt.Sprintf("  Skipping plugin %s: %v", entry.Name, err)))
+		result.skipped = append(result.skipped, skippedArtifact{entry.Name, err.Error()})
+		learnSkipped = append(learnSkipped, skippedArtifact{entry.Name, err.Error()})
+	}
+
+	displayPluginSummary(src, fmt.Sprintf("%d plugin(s)", len(selected)), result)
+	learnExitStatus = skipExitStatus(len(learnSkipped))
+}
+
+// selectMarketplacePlugins returns the plugin called name, or every plugin
+// with all. It returns nil when neither picks anything, so the caller asks.
+func selectMarketplacePlugins(m *artifact.Marketplace, name string, all bool) ([]artifact.MarketplacePlugin, error) {
+	switch {
+	case name != "":
+		var names []string
+		for _, p := range m.Plugins {
+			if strings.EqualFold(p.Name, name) {
+				return []artifact.MarketplacePlugin{p}, nil
+			}
+			names = append(names, p.Name)
+		}
+		return nil, fmt.Errorf("no plugin named %q in marketplace %s (it lists %s)", name, m.Name, strings.Join(names, ", "))
+	case all:
+		return m.Plugins, nil
+	}
+	return nil, nil
+}
+
+// promptMarketplacePlugins asks which listed plugin to install, by number,
+// or "a" for all of them
+func promptMarketplacePlugins(m *artifact.Marketplace) []artifact.MarketplacePlugin {
+	fmt.Fprintf(learnOutput(), "  Install which plugin? [1-%d, a for all] ", len(m.Plugins))
+	answer, _ := promptReader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "a" || answer == "all" {
+		return m.Plugins
+	}
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(m.Plugins) {
+		return m.Plugins[n-1 : n]
+	}
+	return nil
+}
+
+// marketplacePluginSource returns the source a marketplace plugin installs
+// from: a path within the marketplace's repo, or another GitHub repo
+func marketplacePluginSource(src *source.Source, m *artifact.Marketplace, entry artifact.MarketplacePlugin) (*source.Source, error) {
+	if rel := m.PluginPath(entry); rel != "" {
+		if rel == ".." || strings.HasPrefix(rel, "../") || path.IsAbs(rel) {
+			return nil, fmt.Errorf("source %q is outside the marketplace", entry.Source.Path)
+		}
+		pluginSrc := *src
+		pluginSrc.Name = ""
+		pluginSrc.Path = path.Join(src.Path, rel)
+		if pluginSrc.Path == "." {
+			pluginSrc.Path = ""
+		}
+		return &pluginSrc, nil
+	}
+
+	ref := entry.Source.Repo
+	if ref == "" {
+		ref = entry.Source.URL
+	}
+	if ref == "" {
+		return nil, fmt.Errorf("no source")
+	}
+	pluginSrc, err := source.Parse(ref)
+	if err != nil || !pluginSrc.IsGitHub() {
+		return nil, fmt.Errorf("unsupported source %s (only GitHub repos)", ref)
+	}
+	return pluginSrc, nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/source"
+)
+
+func TestSelectMarketplacePlugins(t *testing.T) {
+	m := &artifact.Marketplace{Name: "acme", Plugins: []artifact.MarketplacePlugin{{Name: "formatter"}, {Name: "linter"}}}
+
+	if got, err := selectMarketplacePlugins(m, "Linter", false); err != nil || len(got) != 1 || got[0].Name != "linter" {
+		t.Errorf("by name = %+v, %v; want linter", got, err)
+	}
+	if _, err := selectMarketplacePlugins(m, "missing", false); err == nil {
+		t.Error("unknown name: want an error")
+	}
+	if got, _ := selectMarketplacePlugins(m, "", true); len(got) != 2 {
+		t.Errorf("--all-plugins = %+v, want both plugins", got)
+	}
+	if got, err := selectMarketplacePlugins(m, "", false); got != nil || err != nil {
+		t.Errorf("no selection = %+v, %v; want nil so the caller asks", got, err)
+	}
+}
+
+func TestMarketplacePluginSource(t *testing.T) {
+	src, err := source.Parse("acme/tools:market#formatter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &artifact.Marketplace{Metadata: artifact.MarketplaceMetadata{PluginRoot: "./plugins"}}
+
+	tests := []struct {
+		name    string
+		source  artifact.MarketplaceSource
+		want    string
+		wantErr bool
+	}{
+		{"relative", artifact.MarketplaceSource{Path: "formatter"}, "acme/tools:market/plugins/formatter", false},
+		{"github", artifact.MarketplaceSource{Repo: "other/plugin"}, "other/plugin", false},
+		{"outside", artifact.MarketplaceSource{Path: "../../.."}, "", true},
+		{"git url", artifact.MarketplaceSource{URL: "https://gitlab.example.com/x/y.git"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marketplacePluginSource(src, m, artifact.MarketplacePlugin{Name: "p", Source: tt.source})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("source = %s, want %s", got.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/schema"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Inspect the MCP servers an agent is configured with",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var mcpListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the effective MCP servers across global and project configs",
+	Long: `List the MCP servers an agent will start, layering its configs the way the
+agent does: servers from the user-level config, overridden and extended by
+those in the project's config.
+
+Examples:
+  tome mcp list                  # Servers for the default agent
+  tome mcp list --agent cursor   # ~/.cursor/mcp.json and .cursor/mcp.json`,
+	Args: cobra.NoArgs,
+	Run:  runMCPList,
+}
+
+var mcpAgent string
+
+func init() {
+	mcpListCmd.Flags().StringVarP(&mcpAgent, "agent", "a", "", "Agent whose configs to read (claude, opencode, cursor, windsurf, copilot)")
+	mcpCmd.AddCommand(mcpListCmd)
+}
+
+// Scopes an MCP config layer can come from, lowest precedence first
+const (
+	mcpScopeGlobal  = "global"
+	mcpScopeProject = "project"
+)
+
+// mcpLayer is one MCP config file in the stack an agent reads
+type mcpLayer struct {
+	scope string
+	path  string
+	found bool
+}
+
+// mcpLayers returns agent's MCP config files, global before project
+func mcpLayers(agent config.Agent) []mcpLayer {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	global, project := config.MCPConfigFiles(agent, home, config.ProjectRoot())
+
+	var layers []mcpLayer
+	if global != "" {
+		layers = append(layers, mcpLayer{scope: mcpScopeGlobal, path: global})
+	}
+	if project != "" {
+		layers = append(layers, mcpLayer{scope: mcpScopeProject, path: project})
+	}
+	return layers
+}
+
+// loadMCPLayers parses each layer that exists and merges them, later layers
+// overriding earlier ones. It marks which layers were found and returns the
+// scope each effective server came from.
+func loadMCPLayers(layers []mcpLayer) (*schema.MCPConfig, map[string]string, error) {
+	var configs []*schema.MCPConfig
+	origins := make(map[string]string)
+	for i := range layers {
+		content, err := os.ReadFile(layers[i].path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read %s: %w", layers[i].path, err)
+		}
+		layers[i].found = true
+
+		cfg, err := schema.ParseMCPAuto(content, layers[i].path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", layers[i].path, err)
+		}
+		for name := range cfg.Servers {
+			origins[name] = layers[i].scope
+		}
+		configs = append(configs, cfg)
+	}
+	return schema.MergeMCPConfigs(configs...), origins, nil
+}
+
+func runMCPList(cmd *cobra.Command, args []string) {
+	agent := config.DefaultAgent()
+	if mcpAgent != "" {
+		agent = config.Agent(strings.TrimSpace(mcpAgent))
+	}
+	agentCfg := config.GetAgentConfig(agent)
+	if agentCfg == nil {
+		exitWithError(fmt.Sprintf("unknown agent: %s (try: claude, opencode, cursor, windsurf, copilot)", mcpAgent))
+	}
+
+	layers := mcpLayers(agent)
+	if len(layers) == 0 {
+		exitWithError(fmt.Sprintf("tome doesn't know where %s keeps MCP servers", agentCfg.DisplayName))
+	}
+	merged, origins, err := loadMCPLayers(layers)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("MCP Servers: "+agentCfg.DisplayName, 56))
+	fmt.Println()
+	for _, layer := range layers {
+		status := ui.Muted.Render("(not found)")
+		if layer.found {
+			status = ui.Success.Render("✓")
+		}
+		fmt.Printf("  %s %s %s\n", ui.Muted.Render(padRight(layer.scope, len(mcpScopeProject))), layer.path, status)
+	}
+	fmt.Println()
+
+	if len(merged.Servers) == 0 {
+		fmt.Println(ui.Muted.Render("  No MCP servers configured"))
+		fmt.Println(ui.PageFooter())
+		return
+	}
+	printMCPTable(merged, origins)
+	fmt.Println(ui.PageFooter())
+}
+
+// printMCPTable renders servers as a name/scope/transport/target table
+func printMCPTable(cfg *schema.MCPConfig, origins map[string]string) {
+	names := cfg.ServerNames()
+	nameWidth, transportWidth := len("NAME"), len("TRANSPORT")
+	for _, name := range names {
+		nameWidth = max(nameWidth, lipgloss.Width(name))
+		transportWidth = max(transportWidth, len(cfg.Servers[name].EffectiveTransport()))
+	}
+
+	fmt.Println("  " + ui.TableHeader(
+		padRight("NAME", nameWidth),
+		padRight("SCOPE", len(mcpScopeProject)),
+		padRight("TRANSPORT", transportWidth),
+		"TARGET",
+	))
+	for _, name := range names {
+		server := cfg.Servers[name]
+		fmt.Println("  " + ui.TableRow(
+			padRight(name, nameWidth),
+			padRight(origins[name], len(mcpScopeProject)),
+			padRight(server.EffectiveTransport(), transportWidth),
+			ui.Truncate(mcpServerTarget(server), 50),
+		))
+	}
+}
+
+// mcpServerTarget describes what a server runs or connects to
+func mcpServerTarget(server *schema.MCPServer) string {
+	if server.IsRemote() {
+		return server.URL
+	}
+	return strings.TrimSpace(server.Command + " " + strings.Join(server.Args, " "))
+}
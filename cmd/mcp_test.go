@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kennyg/tome/internal/config"
+)
+
+func TestLoadMCPLayers_ProjectOverridesGlobal(t *testing.T) {
+	home, project := t.TempDir(), t.TempDir()
+	global, local := config.MCPConfigFiles(config.AgentClaude, home, project)
+
+	files := map[string]string{
+		global: `{"mcpServers": {
+  "filesystem": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-filesystem", "/"]},
+  "github": {"command": "github-mcp"}
+}}`,
+		local: `{"mcpServers": {
+  "filesystem": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-filesystem", "."]},
+  "docs": {"type": "http", "url": "https://docs.example.com/mcp"}
+}}`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	layers := []mcpLayer{
+		{scope: mcpScopeGlobal, path: global},
+		{scope: mcpScopeProject, path: local},
+		{scope: mcpScopeProject, path: filepath.Join(project, "missing.json")},
+	}
+	merged, origins, err := loadMCPLayers(layers)
+	if err != nil {
+		t.Fatalf("loadMCPLayers failed: %v", err)
+	}
+	if !layers[0].found || !layers[1].found || layers[2].found {
+		t.Errorf("found = %v %v %v, want true true false", layers[0].found, layers[1].found, layers[2].found)
+	}
+
+	if len(merged.Servers) != 3 {
+		t.Fatalf("servers = %v, want filesystem, github and docs", merged.ServerNames())
+	}
+	fs := merged.Servers["filesystem"]
+	if fs == nil || fs.Args[len(fs.Args)-1] != "." || origins["filesystem"] != mcpScopeProject {
+		t.Errorf("filesystem = %+v from %s, want the project's definition", fs, origins["filesystem"])
+	}
+	if origins["github"] != mcpScopeGlobal {
+		t.Errorf("github from %q, want global", origins["github"])
+	}
+	if docs := merged.Servers["docs"]; docs == nil || !docs.IsRemote() || origins["docs"] != mcpScopeProject {
+		t.Errorf("docs = %+v from %s, want the project's remote server", docs, origins["docs"])
+	}
+	if got := mcpServerTarget(merged.Servers["github"]); got != "github-mcp" {
+		t.Errorf("github target = %q, want github-mcp", got)
+	}
+}
+
+func TestLoadMCPLayers_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".mcp.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := loadMCPLayers([]mcpLayer{{scope: mcpScopeProject, path: path}}); err == nil {
+		t.Error("expected an error for an unparseable config")
+	}
+}
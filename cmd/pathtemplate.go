@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/source"
+)
+
+const (
+	// defaultPathTemplate reproduces tome's standard layout: skills get their
+	// own directory where the agent expects one, everything else is a file
+	defaultPathTemplate = "{{if .Nested}}{{.Name}}/{{end}}{{.Filename}}"
+
+	// pathTemplateEnvVar sets the install path template when --path-template isn't set
+	pathTemplateEnvVar = "TOME_PATH_TEMPLATE"
+)
+
+// installPathTemplate is the parsed template getInstallPath renders; nil
+// means defaultPathTemplate
+var installPathTemplate *template.Template
+
+// installPathData is what a path template can reference
+type installPathData struct {
+	Name     string // Artifact name, sanitized for use in a path
+	Type     string // skill, command, agent, ...
+	Owner    string // Repo owner for repo sources, otherwise empty
+	Source   string // Source the artifact was learned from
+	Filename string // File name the target agent expects (SKILL.md, <name>.md, ...)
+	Nested   bool   // The agent expects the skill in its own directory
+}
+
+// parsePathTemplate parses an install path template and checks that it
+// renders a safe path for a sample command and skill, and different paths
+// for differently named ones. An empty text selects the default.
+func parsePathTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = defaultPathTemplate
+	}
+	tmpl, err := template.New("path").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path template: %w", err)
+	}
+
+	for _, sample := range []installPathData{
+		{Type: string(artifact.TypeCommand), Filename: ".md"},
+		{Type: string(artifact.TypeSkill), Filename: "SKILL.md", Nested: true},
+		{Type: string(artifact.TypeSkill), Filename: ".md"},
+	} {
+		rendered := map[string]string{}
+		for _, name := range []string{"example", "other"} {
+			data := sample
+			data.Name, data.Owner, data.Source = name, "owner", "owner/repo"
+			if data.Filename == ".md" {
+				data.Filename = name + ".md"
+			}
+			path, err := renderPathTemplate(tmpl, data)
+			if err != nil {
+				return nil, err
+			}
+			if prev, ok := rendered[path]; ok {
+				return nil, fmt.Errorf("path template gives %s %q and %q the same path, %q; use {{.Name}} or {{.Filename}}", sample.Type, prev, name, path)
+			}
+			rendered[path] = name
+		}
+	}
+	return tmpl, nil
+}
+
+// resolvePathTemplate parses --path-template, then $TOME_PATH_TEMPLATE, then the default
+func resolvePathTemplate(flag string) (*template.Template, error) {
+	if flag == "" {
+		flag = os.Getenv(pathTemplateEnvVar)
+	}
+	return parsePathTemplate(flag)
+}
+
+// renderPathTemplate executes tmpl and validates the result: it must be a
+// relative path that stays inside the directory it's joined to
+func renderPathTemplate(tmpl *template.Template, data installPathData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid path template: %w", err)
+	}
+
+	rendered := strings.TrimSpace(buf.String())
+	if rendered == "" {
+		return "", fmt.Errorf("path template rendered an empty path")
+	}
+	if filepath.IsAbs(rendered) || strings.HasPrefix(rendered, "/") {
+		return "", fmt.Errorf("path template must produce a relative path, got %q", rendered)
+	}
+	cleaned := filepath.Clean(filepath.FromSlash(rendered))
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path template must stay inside the install directory, got %q", rendered)
+	}
+	if strings.HasSuffix(rendered, "/") {
+		return "", fmt.Errorf("path template must name a file, got %q", rendered)
+	}
+	return cleaned, nil
+}
+
+// pathTemplateOwner returns the repo owner of an artifact's source, if any
+func pathTemplateOwner(art *artifact.Artifact) string {
+	src, err := source.Parse(art.Source)
+	if err != nil || src.Type != source.TypeRepo {
+		return ""
+	}
+	return src.Owner
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+)
+
+func TestGetInstallPath_Templates(t *testing.T) {
+	claude := &config.Paths{Agent: config.AgentClaude, SkillsDir: "/home/.claude/skills", CommandsDir: "/home/.claude/commands"}
+	cursor := &config.Paths{Agent: config.AgentCursor, SkillsDir: "/proj/.cursor/rules", CommandsDir: "/proj/.cursor/rules"}
+	skill := &artifact.Artifact{Name: "pdf", Type: artifact.TypeSkill, Source: "acme/skills"}
+	command := &artifact.Artifact{Name: "commit", Type: artifact.TypeCommand, Source: "acme/tools:commands"}
+
+	tests := []struct {
+		name     string
+		template string
+		art      *artifact.Artifact
+		paths    *config.Paths
+		want     string
+	}{
+		{"default skill", "", skill, claude, "/home/.claude/skills/pdf/SKILL.md"},
+		{"default flat skill", "", skill, cursor, "/proj/.cursor/rules/pdf.md"},
+		{"default command", "", command, claude, "/home/.claude/commands/commit.md"},
+		{"owner prefix", "{{.Owner}}-{{if .Nested}}{{.Name}}/{{end}}{{.Filename}}", command, claude, "/home/.claude/commands/acme-commit.md"},
+		{"owner prefix skill", "{{.Owner}}-{{if .Nested}}{{.Name}}/{{end}}{{.Filename}}", skill, claude, "/home/.claude/skills/acme-pdf/SKILL.md"},
+		{"owner directory", "{{.Owner}}/{{if .Nested}}{{.Name}}/{{end}}{{.Filename}}", skill, claude, "/home/.claude/skills/acme/pdf/SKILL.md"},
+		{"type suffix", "{{.Name}}.{{.Type}}.md", command, claude, "/home/.claude/commands/commit.command.md"},
+	}
+
+	t.Cleanup(func() { installPathTemplate = nil })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parsePathTemplate(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			installPathTemplate = tmpl
+
+			got, err := getInstallPath(tt.art, tt.paths, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("getInstallPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePathTemplate_RejectsUnsafePaths(t *testing.T) {
+	for _, text := range []string{
+		"/etc/{{.Filename}}",
+		"../{{.Filename}}",
+		"{{.Name}}/../../{{.Filename}}",
+		"{{.Name}}/",
+		"{{if false}}x{{end}}",
+		"{{.Missing}}",
+		"{{.Name",
+		`{{if eq .Type "skill"}}../{{end}}{{.Filename}}`, // Unsafe for skills only
+		"{{.Type}}/{{.Filename}}",                        // Every nested skill is SKILL.md
+		"tools.md",                                       // Every artifact gets one path
+	} {
+		if _, err := parsePathTemplate(text); err == nil {
+			t.Errorf("parsePathTemplate(%q) succeeded, want error", text)
+		}
+	}
+
+	// A source can still steer an otherwise valid template out of bounds
+	tmpl, err := parsePathTemplate("{{.Source}}/{{.Name}}.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { installPathTemplate = nil })
+	installPathTemplate = tmpl
+	paths := &config.Paths{Agent: config.AgentClaude, CommandsDir: "/home/.claude/commands"}
+	if _, err := getInstallPath(&artifact.Artifact{Name: "x", Type: artifact.TypeCommand, Source: "../../x"}, paths, false); err == nil {
+		t.Error("getInstallPath with an escaping source succeeded, want error")
+	}
+}
+
+func TestGetInstallPath_CommandPerAgent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { installPathTemplate = nil })
+	installPathTemplate = nil
+
+	command := &artifact.Artifact{
+		Name:    "commit",
+		Type:    artifact.TypeCommand,
+		Content: "---\ndescription: Write a commit message\n---\nSummarize the staged diff.\n",
+	}
+
+	tests := []struct {
+		agent     config.Agent
+		want      string
+		converted bool
+	}{
+		{config.AgentClaude, ".claude/commands/commit.md", false},
+		{config.AgentOpenCode, ".opencode/command/commit.md", true},
+		{config.AgentCopilot, ".github/prompts/commit.prompt.md", true},
+		{config.AgentWindsurf, ".windsurf/workflows/commit.md", true},
+	}
+	seen := map[string]bool{}
+	for _, tt := range tests {
+		t.Run(string(tt.agent), func(t *testing.T) {
+			paths, err := config.GetPathsForAgent(tt.agent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := getInstallPath(command, paths, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(home, filepath.FromSlash(tt.want)); got != want {
+				t.Errorf("getInstallPath() = %q, want %q", got, want)
+			}
+			if seen[got] {
+				t.Errorf("%s shares install path %s with another agent", tt.agent, got)
+			}
+			seen[got] = true
+
+			result, ok := convertArtifact(command, paths)
+			if ok != tt.converted {
+				t.Fatalf("convertArtifact() converted = %v, want %v", ok, tt.converted)
+			}
+			if ok && result.TargetFormat != config.CommandFormat(tt.agent) {
+				t.Errorf("converted to %s, want %s", result.TargetFormat, config.CommandFormat(tt.agent))
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -49,10 +48,15 @@ func runPeek(cmd *cobra.Command, args []string) {
 	fmt.Println(ui.InfoLine("Source: " + src.String()))
 	fmt.Println()
 
-	client := fetch.NewClient()
+	client := newFetchClient()
+	resolveSourceRange(client, src)
 
 	switch src.Type {
-	case source.TypeGitHub:
+	case source.TypeRepo:
+		if !src.IsGitHub() {
+			peekRepo(client, src)
+			return
+		}
 		peekGitHub(client, src)
 	case source.TypeURL:
 		peekURL(client, src)
@@ -61,9 +65,19 @@ func runPeek(cmd *cobra.Command, args []string) {
 	}
 }
 
+// peekRepo previews a GitLab or Bitbucket source, which must point at a
+// markdown file or a directory containing SKILL.md
+func peekRepo(client *fetch.Client, src *source.Source) {
+	if src.Path != "" && fetch.IsMarkdownFile(src.Path) {
+		peekSingleFile(client, src.RawURL(""), filepath.Base(src.Path), src.String())
+		return
+	}
+	peekSingleFile(client, src.RawURL(artifact.SkillFilename), artifact.SkillFilename, src.String())
+}
+
 func peekGitHub(client *fetch.Client, src *source.Source) {
 	// Check if path points to a specific file
-	if src.Path != "" && strings.HasSuffix(strings.ToLower(src.Path), ".md") {
+	if src.Path != "" && fetch.IsMarkdownFile(src.Path) {
 		url := src.GitHubRawURL("")
 		peekSingleFile(client, url, filepath.Base(src.Path), src.String())
 		return
@@ -142,17 +156,22 @@ func peekGitHub(client *fetch.Client, src *source.Source) {
 
 		// Count included files for skills
 		includesInfo := ""
-		if art.Type == artifact.TypeSkill && item.SkillDir != "" {
+		if art.Type == artifact.TypeSkill && len(art.Includes) > 0 {
+			// Only the listed includes are installed
+			includesInfo = fmt.Sprintf(" (+%d files)", len(art.Includes))
+		} else if art.Type == artifact.TypeSkill && item.SkillDir != "" {
 			var baseAPIURL string
 			if src.Host == "github.com" || src.Host == "" {
 				baseAPIURL = fmt.Sprintf("https://api.github.com/repos/%s/%s/contents", src.Owner, src.Repo)
 			} else {
 				baseAPIURL = fmt.Sprintf("https://%s/api/v3/repos/%s/%s/contents", src.Host, src.Owner, src.Repo)
 			}
+			var rawURL fetch.RawURLFunc
 			if src.Ref != "" {
 				baseAPIURL += "?ref=" + src.Ref
+				rawURL = src.RepoRawURL
 			}
-			includes, _ := client.DiscoverSkillFiles(baseAPIURL, item.SkillDir)
+			includes, _ := client.DiscoverSkillFiles(baseAPIURL, item.SkillDir, rawURL)
 			if len(includes) > 0 {
 				includesInfo = fmt.Sprintf(" (+%d files)", len(includes))
 			}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:     "reconcile",
+	Aliases: []string{"tidy"},
+	Short:   "Reconcile the tome's records with the files on disk",
+	Long: `Bring the state file back in line with the filesystem.
+
+Entries whose files were deleted by hand are dropped. With --import, skills
+and commands found in the agent's directories that tome has no record of are
+parsed and added, so list and apropos see them too. Imported artifacts are
+recorded with their own path as the source.
+
+('tome sync' is an alias of 'tome renew', which re-fetches from sources.)
+
+Examples:
+  tome reconcile                 # Forget artifacts whose files are gone
+  tome reconcile --import        # Also adopt untracked skills and commands
+  tome tidy --import --dry-run   # Show what would change`,
+	Args: cobra.NoArgs,
+	Run:  runReconcile,
+}
+
+var (
+	reconcileImport bool
+	reconcileDryRun bool
+)
+
+func init() {
+	reconcileCmd.Flags().BoolVar(&reconcileImport, "import", false, "Add untracked skills and commands found on disk")
+	reconcileCmd.Flags().BoolVar(&reconcileDryRun, "dry-run", false, "Show what would change without saving")
+}
+
+// reconcileResult is the outcome of reconciling state with the filesystem
+type reconcileResult struct {
+	removed   []artifact.InstalledArtifact
+	imported  []artifact.InstalledArtifact
+	unchanged int
+}
+
+func runReconcile(cmd *cobra.Command, args []string) {
+	paths, err := config.GetPaths()
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Reconciling", 56))
+	fmt.Println()
+
+	result := reconcileState(state, paths, reconcileImport)
+
+	for _, a := range result.removed {
+		fmt.Printf("  %s %s %s\n", getBadge(a.Type), ui.Highlight.Render(a.Name), ui.Warning.Render("✗ file missing, removed"))
+	}
+	for _, a := range result.imported {
+		fmt.Printf("  %s %s %s\n", getBadge(a.Type), ui.Highlight.Render(a.Name), ui.Success.Render("+ imported"))
+	}
+
+	changed := len(result.removed) > 0 || len(result.imported) > 0
+	if changed && !reconcileDryRun {
+		if err := config.SaveState(paths.StateFile, state); err != nil {
+			exitWithError(fmt.Sprintf("failed to save state: %v", err))
+		}
+	}
+
+	if changed {
+		fmt.Println()
+	}
+	summary := fmt.Sprintf("%d removed, %d imported, %d unchanged", len(result.removed), len(result.imported), result.unchanged)
+	if reconcileDryRun {
+		fmt.Println(ui.InfoLine("Dry run: " + summary))
+	} else {
+		fmt.Println(ui.SuccessLine(summary))
+	}
+	fmt.Println(ui.PageFooter())
+}
+
+// reconcileState drops state entries whose files no longer exist and, with
+// importOrphans, adds skills and commands found in paths' directories that
+// state doesn't track. state is modified in place.
+func reconcileState(state *config.State, paths *config.Paths, importOrphans bool) reconcileResult {
+	var result reconcileResult
+
+	kept := make([]artifact.InstalledArtifact, 0, len(state.Installed))
+	tracked := make(map[string]bool)
+	for _, a := range state.Installed {
+		if _, err := os.Stat(a.LocalPath); os.IsNotExist(err) {
+			result.removed = append(result.removed, a)
+			continue
+		}
+		kept = append(kept, a)
+		tracked[filepath.Clean(a.LocalPath)] = true
+		result.unchanged++
+	}
+	state.Installed = kept
+
+	if !importOrphans {
+		return result
+	}
+
+	for _, path := range untrackedArtifactFiles(paths, tracked) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		art, err := parseArtifact(content, filepath.Base(path), path)
+		if err != nil {
+			continue
+		}
+		art.Source = path
+
+		imported := artifact.InstalledArtifact{
+			Artifact:  *art,
+			LocalPath: path,
+			Agent:     string(paths.Agent),
+		}
+		imported.Includes = nil // Those on disk, not those the frontmatter lists
+		if art.Type == artifact.TypeSkill {
+			files, _ := fetch.DiscoverLocalSkillFiles(filepath.Dir(path))
+			for _, f := range files {
+				imported.Includes = append(imported.Includes, f.Path)
+			}
+		}
+		if info, err := os.Stat(path); err == nil {
+			imported.InstalledAt = info.ModTime()
+		}
+
+		state.AddInstalled(imported)
+		result.imported = append(result.imported, imported)
+	}
+
+	return result
+}
+
+// untrackedArtifactFiles lists skill (<skills>/<name>/SKILL.md) and command
+// (<commands>/<name>.md) files on disk whose paths aren't in tracked
+func untrackedArtifactFiles(paths *config.Paths, tracked map[string]bool) []string {
+	var files []string
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !tracked[path] {
+			tracked[path] = true // Agents sharing one directory for both types list it once
+			files = append(files, path)
+		}
+	}
+
+	if entries, err := os.ReadDir(paths.SkillsDir); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			skillPath := filepath.Join(paths.SkillsDir, entry.Name(), artifact.SkillFilename)
+			if _, err := os.Stat(skillPath); err == nil {
+				add(skillPath)
+			}
+		}
+	}
+
+	if entries, err := os.ReadDir(paths.CommandsDir); err == nil {
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasSuffix(strings.ToLower(name), ".md") || fetch.IsExcludedFile(name) {
+				continue
+			}
+			add(filepath.Join(paths.CommandsDir, name))
+		}
+	}
+
+	return files
+}
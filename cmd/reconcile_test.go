@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+)
+
+func TestReconcileState(t *testing.T) {
+	home := t.TempDir()
+	paths := &config.Paths{
+		Agent:       config.AgentClaude,
+		SkillsDir:   filepath.Join(home, ".claude", "skills"),
+		CommandsDir: filepath.Join(home, ".claude", "commands"),
+	}
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	kept := filepath.Join(paths.CommandsDir, "deploy.md")
+	write(kept, "---\ndescription: Deploy\n---\n# Deploy\n")
+	orphanSkill := filepath.Join(paths.SkillsDir, "pdf", artifact.SkillFilename)
+	write(orphanSkill, "---\nname: pdf\ndescription: Work with PDFs\n---\n# PDF\n")
+	write(filepath.Join(paths.SkillsDir, "pdf", "reference.md"), "# Reference\n")
+	orphanCommand := filepath.Join(paths.CommandsDir, "review.md")
+	write(orphanCommand, "---\ndescription: Review a PR\n---\n# Review\n")
+	write(filepath.Join(paths.CommandsDir, "README.md"), "# Not a command\n")
+
+	state := &config.State{Installed: []artifact.InstalledArtifact{
+		{Artifact: artifact.Artifact{Name: "deploy", Type: artifact.TypeCommand}, LocalPath: kept},
+		{Artifact: artifact.Artifact{Name: "gone", Type: artifact.TypeCommand}, LocalPath: filepath.Join(paths.CommandsDir, "gone.md")},
+	}}
+
+	// Without --import, only the deleted file's entry changes
+	result := reconcileState(state, paths, false)
+	if len(result.removed) != 1 || result.removed[0].Name != "gone" {
+		t.Errorf("removed = %+v, want gone", result.removed)
+	}
+	if len(result.imported) != 0 || result.unchanged != 1 || len(state.Installed) != 1 {
+		t.Errorf("result = %+v, state = %+v; want deploy kept and nothing imported", result, state.Installed)
+	}
+
+	result = reconcileState(state, paths, true)
+	if len(result.removed) != 0 || result.unchanged != 1 {
+		t.Errorf("second pass result = %+v, want nothing removed", result)
+	}
+	if len(result.imported) != 2 {
+		t.Fatalf("imported = %+v, want the pdf skill and review command", result.imported)
+	}
+
+	skill := state.FindInstalled("pdf")
+	if skill == nil || skill.Type != artifact.TypeSkill || skill.LocalPath != orphanSkill {
+		t.Fatalf("pdf skill = %+v", skill)
+	}
+	if len(skill.Includes) != 1 || skill.Includes[0] != "reference.md" {
+		t.Errorf("pdf includes = %v, want reference.md", skill.Includes)
+	}
+	if review := state.FindInstalled("review"); review == nil || review.Description != "Review a PR" {
+		t.Errorf("review command = %+v", review)
+	}
+
+	// Imported artifacts are tracked from then on
+	if result := reconcileState(state, paths, true); len(result.imported) != 0 || result.unchanged != 3 {
+		t.Errorf("third pass result = %+v, want everything unchanged", result)
+	}
+}
@@ -4,30 +4,52 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	artifactPkg "github.com/kennyg/tome/internal/artifact"
 	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/source"
 	"github.com/kennyg/tome/internal/ui"
 )
 
 var removeCmd = &cobra.Command{
 	Use:     "forget <name>",
-	Aliases: []string{"erase", "unlearn", "remove", "rm"},
+	Aliases: []string{"erase", "unlearn", "remove", "rm", "uninstall"},
 	Short:   "Erase an inscription from the tome",
 	Long: `Forget an artifact, erasing it from your tome.
 
+With --source, forget every artifact learned from a source instead. A repo
+(owner/repo) covers everything learned from any path in it; a path
+(owner/repo:skills) covers what was learned from there. tome asks before
+erasing them unless --force is given.
+
 Examples:
   tome forget my-skill
-  tome erase deploy-command`,
-	Args: cobra.ExactArgs(1),
-	Run:  runRemove,
+  tome erase deploy-command
+  tome uninstall --source owner/repo
+  tome forget --source owner/repo:skills --force`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if removeSource != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: runRemove,
 }
 
-func runRemove(cmd *cobra.Command, args []string) {
-	name := args[0]
+var (
+	removeSource string
+	removeForce  bool
+)
 
+func init() {
+	removeCmd.Flags().StringVar(&removeSource, "source", "", "Forget every artifact learned from this source")
+	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Don't ask before forgetting a source's artifacts")
+}
+
+func runRemove(cmd *cobra.Command, args []string) {
 	paths, err := config.GetPaths()
 	if err != nil {
 		exitWithError(err.Error())
@@ -38,42 +60,144 @@ func runRemove(cmd *cobra.Command, args []string) {
 		exitWithError(err.Error())
 	}
 
-	artifact := state.FindInstalled(name)
-	if artifact == nil {
-		exitWithError(fmt.Sprintf("artifact '%s' not found", name))
+	if removeSource != "" {
+		removeFromSource(state, paths, removeSource, removeForce)
+		return
+	}
+
+	artifact, err := state.LookupInstalled(args[0])
+	if err != nil {
+		exitWithError(err.Error())
 	}
 
 	fmt.Println()
-	fmt.Println(ui.Title.Render("  Removing " + name))
+	fmt.Println(ui.Title.Render("  Removing " + artifact.Name))
 	fmt.Println()
 
-	badge := getBadge(artifact.Type)
-	fmt.Printf("  %s %s\n", badge, ui.Highlight.Render(artifact.Name))
-	fmt.Println(ui.Muted.Render(fmt.Sprintf("    Path: %s", artifact.LocalPath)))
+	removeArtifact(state, paths, artifact.Name, artifact.Type)
 	fmt.Println()
 
-	// Remove the file from disk
-	if err := os.Remove(artifact.LocalPath); err != nil && !os.IsNotExist(err) {
-		exitWithError(fmt.Sprintf("failed to remove file: %v", err))
+	if err := config.SaveState(paths.StateFile, state); err != nil {
+		exitWithError(fmt.Sprintf("failed to update state: %v", err))
 	}
 
-	// For skills, also try to remove the parent directory if empty
-	if artifact.Type == artifactPkg.TypeSkill {
-		parentDir := filepath.Dir(artifact.LocalPath)
-		// Only remove if it's a skill-specific directory (not the main skills dir)
-		if parentDir != paths.SkillsDir {
-			_ = os.Remove(parentDir) // Ignore error - dir may not be empty
+	fmt.Println(ui.Success.Render("  Removed successfully."))
+	fmt.Println()
+	fmt.Println(ui.Muted.Render("  Your tome has been lightened."))
+	fmt.Println()
+}
+
+// removeFromSource forgets every artifact learned from src, asking first
+// unless force is set
+func removeFromSource(state *config.State, paths *config.Paths, src string, force bool) {
+	type key struct {
+		name string
+		typ  artifactPkg.Type
+	}
+	var matched []key
+	seen := map[key]bool{}
+	for _, a := range state.Installed {
+		k := key{a.Name, a.Type}
+		if !seen[k] && fromSource(a.Source, src) {
+			seen[k] = true
+			matched = append(matched, k)
+		}
+	}
+	if len(matched) == 0 {
+		exitWithError(fmt.Sprintf("nothing in your tome was learned from %s", src))
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Title.Render("  Removing everything from " + src))
+	fmt.Println()
+
+	if !force {
+		if !stdinIsTerminal() {
+			exitWithError(fmt.Sprintf("%d artifact(s) would be forgotten; use --force to confirm without a terminal", len(matched)))
 		}
+		if !confirm(fmt.Sprintf("  Forget %d artifact(s) learned from %s?", len(matched), src)) {
+			fmt.Println(ui.Muted.Render("  Nothing was forgotten."))
+			fmt.Println()
+			return
+		}
+		fmt.Println()
 	}
 
-	// Update state
-	state.RemoveInstalled(artifact.Name, artifact.Type)
+	for _, k := range matched {
+		removeArtifact(state, paths, k.name, k.typ)
+	}
+	fmt.Println()
+
 	if err := config.SaveState(paths.StateFile, state); err != nil {
 		exitWithError(fmt.Sprintf("failed to update state: %v", err))
 	}
 
-	fmt.Println(ui.Success.Render("  Removed successfully."))
+	fmt.Println(ui.Success.Render(fmt.Sprintf("  Removed %d artifact(s).", len(matched))))
 	fmt.Println()
 	fmt.Println(ui.Muted.Render("  Your tome has been lightened."))
 	fmt.Println()
 }
+
+// removeArtifact deletes an artifact's files, for every agent it was learned
+// for, and drops it from state
+func removeArtifact(state *config.State, paths *config.Paths, name string, t artifactPkg.Type) {
+	badge := getBadge(t)
+	fmt.Printf("  %s %s\n", badge, ui.Highlight.Render(name))
+
+	// An artifact learned for several agents has a copy in each agent's directories
+	pinned := false
+	for _, installed := range state.Installed {
+		if installed.Name != name || installed.Type != t {
+			continue
+		}
+		pinned = pinned || inProject(installed.LocalPath)
+		fmt.Println(ui.Muted.Render(fmt.Sprintf("    Path: %s", installed.LocalPath)))
+
+		// Remove the file from disk
+		if err := os.Remove(installed.LocalPath); err != nil && !os.IsNotExist(err) {
+			exitWithError(fmt.Sprintf("failed to remove file: %v", err))
+		}
+
+		// For skills in their own directory, also try to remove it if empty
+		if installed.Type == artifactPkg.TypeSkill && !installed.SingleFile {
+			parentDir := filepath.Dir(installed.LocalPath)
+			// Only remove if it's a skill-specific directory (not the main skills dir)
+			if !paths.IsSkillsRoot(parentDir) {
+				_ = os.Remove(parentDir) // Ignore error - dir may not be empty
+			}
+		}
+	}
+
+	state.RemoveInstalled(name, t)
+	if pinned {
+		unpinArtifact(name, t)
+	}
+}
+
+// fromSource reports whether an artifact learned from artSource came from
+// want. A repo matches everything learned from it, at any path or ref; a
+// path in a repo matches what was learned from that path or below it.
+func fromSource(artSource, want string) bool {
+	if artSource == want {
+		return true
+	}
+	a, errA := source.Parse(artSource)
+	w, errW := source.Parse(want)
+	if errA != nil || errW != nil || a.Type != w.Type {
+		return false
+	}
+
+	switch a.Type {
+	case source.TypeRepo:
+		if !a.SameRepo(w) {
+			return false
+		}
+		wantPath := strings.Trim(w.Path, "/")
+		artPath := strings.Trim(a.Path, "/")
+		return wantPath == "" || artPath == wantPath || strings.HasPrefix(artPath, wantPath+"/")
+	case source.TypeLocal:
+		rel, err := filepath.Rel(w.Path, a.Path)
+		return err == nil && filepath.IsLocal(rel)
+	}
+	return false
+}
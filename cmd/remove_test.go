@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+)
+
+func TestRemoveFromSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &config.State{}
+	install := func(name string, typ artifact.Type, src, rel string) string {
+		path := filepath.Join(paths.AgentDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("# "+name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		state.AddInstalled(artifact.InstalledArtifact{
+			Artifact:  artifact.Artifact{Name: name, Type: typ, Source: src},
+			LocalPath: path,
+		})
+		return path
+	}
+	pdf := install("pdf", artifact.TypeSkill, "owner/repo:skills/pdf", "skills/pdf/SKILL.md")
+	deploy := install("deploy", artifact.TypeCommand, "owner/repo", "commands/deploy.md")
+	lint := install("lint", artifact.TypeCommand, "other/tools", "commands/lint.md")
+	fork := install("fork", artifact.TypeCommand, "owner/repo-fork", "commands/fork.md")
+
+	removeFromSource(state, paths, "owner/repo", true)
+
+	for _, path := range []string{pdf, deploy, filepath.Dir(pdf)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", path)
+		}
+	}
+	for _, path := range []string{lint, fork} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s from another source was removed: %v", path, err)
+		}
+	}
+
+	saved, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, a := range saved.Installed {
+		names = append(names, a.Name)
+	}
+	if len(names) != 2 || saved.FindInstalled("lint") == nil || saved.FindInstalled("fork") == nil {
+		t.Errorf("state = %v, want only lint and fork left", names)
+	}
+}
+
+func TestFromSource(t *testing.T) {
+	tests := []struct {
+		artSource, want string
+		match           bool
+	}{
+		{"owner/repo", "owner/repo", true},
+		{"owner/repo:skills/pdf", "owner/repo", true},
+		{"owner/repo@v1.2.0", "owner/repo", true},
+		{"Owner/Repo:commands", "owner/repo", true},
+		{"owner/repo:skills/pdf", "owner/repo:skills", true},
+		{"owner/repo:commands/deploy.md", "owner/repo:skills", false},
+		{"owner/repo:skillset", "owner/repo:skills", false},
+		{"owner/repo-fork", "owner/repo", false},
+		{"gitlab:owner/repo", "owner/repo", false},
+		{"https://example.com/deploy.md", "owner/repo", false},
+	}
+
+	for _, tt := range tests {
+		if got := fromSource(tt.artSource, tt.want); got != tt.match {
+			t.Errorf("fromSource(%q, %q) = %v, want %v", tt.artSource, tt.want, got, tt.match)
+		}
+	}
+}
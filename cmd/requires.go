@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/source"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var (
+	// learnNoDeps is --no-deps: install only the source given, not what it requires
+	learnNoDeps bool
+
+	// learnWithDeps is --with-deps: install what the source requires without asking
+	learnWithDeps bool
+
+	// learnRequired collects the sources declared by what's been learned
+	// (tome.yaml and skill requires) until learnDependencies queues them
+	learnRequired []string
+)
+
+// learnDep is a source in the dependency tree of a learn run
+type learnDep struct {
+	source     string // As declared
+	key        string // The resolved source, to tell sources apart
+	src        *source.Source
+	requiredBy *learnDep // nil for the source learn was given
+	requires   []*learnDep
+	status     string // Why it wasn't learned; empty when it was
+}
+
+// requireSources records sources the collection or artifact being learned
+// depends on
+func requireSources(sources []string) {
+	learnRequired = append(learnRequired, sources...)
+}
+
+// takeRequired turns the sources collected while learning dep into its
+// children in the dependency tree
+func takeRequired(dep *learnDep) []*learnDep {
+	seen := make(map[string]bool)
+	for _, s := range learnRequired {
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		dep.requires = append(dep.requires, &learnDep{source: s, requiredBy: dep})
+	}
+	learnRequired = nil
+	return dep.requires
+}
+
+// learnDependencies installs what root's artifacts require, then what those
+// require, breadth first. Each source is learned once, so a cycle stops when
+// it reaches a source already learned. It returns root's dependency tree.
+func learnDependencies(client *fetch.Client, root *source.Source, paths *config.Paths) *learnDep {
+	top := &learnDep{source: root.String(), key: root.String(), src: root}
+	visited := map[string]bool{top.key: true}
+	queue := approveDependencies(top, takeRequired(top))
+
+	for len(queue) > 0 {
+		dep := queue[0]
+		queue = queue[1:]
+
+		src, err := resolveDependency(dep)
+		if err != nil {
+			dep.status = err.Error()
+			fmt.Fprintln(learnOutput(), ui.WarningLine(fmt.Sprintf("Skipping dependency %s: %v", dep.source, err)))
+			continue
+		}
+		dep.src, dep.key = src, src.String()
+		if visited[dep.key] {
+			dep.status = "already inscribed"
+			if dep.requiredBy.dependsOn(dep.key) {
+				dep.status = "cycle"
+			}
+			continue
+		}
+		visited[dep.key] = true
+
+		fmt.Fprintln(learnOutput())
+		fmt.Fprintln(learnOutput(), ui.InfoLine(fmt.Sprintf("Dependency: %s (required by %s)", dep.source, dep.requiredBy.source)))
+		fmt.Fprintln(learnOutput())
+
+		// Each source's summary reports its own skips; the run reports them all
+		skipped, status := learnSkipped, learnExitStatus
+		learnSkipped = nil
+		resolveSourceRange(client, src)
+		learnFrom(client, src, paths)
+		learnSkipped = append(skipped, learnSkipped...)
+		learnExitStatus = max(status, learnExitStatus)
+
+		queue = append(queue, approveDependencies(dep, takeRequired(dep))...)
+	}
+	return top
+}
+
+// approveDependencies returns the sources dep requires that may be learned.
+// A source can require any other, so unless --with-deps is given they're
+// listed and learned only if the user agrees; without a terminal to ask on,
+// none are.
+func approveDependencies(dep *learnDep, deps []*learnDep) []*learnDep {
+	if len(deps) == 0 || learnWithDeps || learnDryRun {
+		return deps
+	}
+
+	status := "not confirmed; pass --with-deps"
+	if !learnJSON && stdinIsTerminal() {
+		fmt.Fprintln(learnOutput())
+		fmt.Fprintln(learnOutput(), ui.Info.Render(fmt.Sprintf("  %s requires:", dep.source)))
+		for _, d := range deps {
+			fmt.Fprintln(learnOutput(), ui.Muted.Render("    • "+d.source))
+		}
+		if confirm(fmt.Sprintf("  Install %d required source(s)?", len(deps))) {
+			return deps
+		}
+		status = "declined"
+	} else {
+		fmt.Fprintln(learnOutput(), ui.WarningLine(fmt.Sprintf("Not installing %d source(s) %s requires; pass --with-deps to install them", len(deps), dep.source)))
+	}
+	for _, d := range deps {
+		d.status = status
+	}
+	return nil
+}
+
+// dependsOn reports whether dep is, or is required by, the source with key
+func (dep *learnDep) dependsOn(key string) bool {
+	for d := dep; d != nil; d = d.requiredBy {
+		if d.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveDependency parses a required source. A relative path is relative to
+// the local source that requires it.
+func resolveDependency(dep *learnDep) (*source.Source, error) {
+	if !isRelativePath(dep.source) {
+		return source.Parse(dep.source)
+	}
+	parent := dep.requiredBy.src
+	if parent == nil || parent.Type != source.TypeLocal {
+		return nil, fmt.Errorf("relative path needs a local source to be relative to")
+	}
+	base := parent.Path
+	if info, err := os.Stat(base); err == nil && !info.IsDir() {
+		base = filepath.Dir(base)
+	}
+	path := filepath.Join(base, dep.source)
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("%s doesn't exist", path)
+	}
+	return source.Parse(path)
+}
+
+// isRelativePath reports whether a source is a ./ or ../ path
+func isRelativePath(s string) bool {
+	s = filepath.ToSlash(s)
+	return s == "." || s == ".." || strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../")
+}
+
+// displayDependencyTree shows the sources learned because root required them
+func displayDependencyTree(root *learnDep) {
+	if len(root.requires) == 0 {
+		return
+	}
+	fmt.Fprintln(learnOutput())
+	fmt.Fprintln(learnOutput(), ui.Info.Render("  Dependencies of "+root.source))
+	displayDependencies(root, "    ")
+	fmt.Fprintln(learnOutput(), ui.PageFooter())
+}
+
+// displayDependencies prints dep's requirements as a tree, each under what
+// requires it
+func displayDependencies(dep *learnDep, indent string) {
+	for i, child := range dep.requires {
+		branch, next := "├─", "│  "
+		if i == len(dep.requires)-1 {
+			branch, next = "└─", "   "
+		}
+		line := indent + ui.Muted.Render(branch) + " " + ui.Highlight.Render(child.source)
+		if child.status != "" {
+			line += ui.Muted.Render(" (" + child.status + ")")
+		}
+		fmt.Fprintln(learnOutput(), line)
+		displayDependencies(child, indent+next)
+	}
+}
@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/fetch"
 	"github.com/kennyg/tome/internal/ui"
 )
 
@@ -15,6 +20,18 @@ var (
 
 	// plainOutput forces plain text output without colors/decorations
 	plainOutput bool
+
+	// statePath overrides the state file location (see config.StateOverride)
+	statePath string
+
+	// caBundle is a PEM file of extra CA certificates to trust (see fetch.CABundleEnv)
+	caBundle string
+
+	// fetchTimeout bounds each network request (see fetch.DefaultTimeout)
+	fetchTimeout time.Duration
+
+	// maxFileSize caps each fetched file, in bytes (see fetch.DefaultMaxFileSize)
+	maxFileSize int64
 )
 
 var rootCmd = &cobra.Command{
@@ -29,24 +46,45 @@ var rootCmd = &cobra.Command{
 		if plainOutput {
 			ui.IsTTY = false
 		}
+		if statePath != "" {
+			config.SetStateOverride(statePath)
+		}
+		if override := config.StateOverride(); override != "" {
+			if err := config.ValidateStatePath(override); err != nil {
+				exitWithError(err.Error())
+			}
+		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
 }
 
-// Execute runs the root command
+// Execute runs the root command. Ctrl-C cancels in-flight fetches so the
+// command can clean up; a second Ctrl-C exits immediately.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return rootCmd.ExecuteContext(ctx)
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolVar(&plainOutput, "plain", false, "Force plain text output (no colors/decorations)")
+	rootCmd.PersistentFlags().StringVar(&statePath, "state", "", "Path to state file (overrides $TOME_STATE and default locations)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Download every file instead of revalidating cached copies")
+	rootCmd.PersistentFlags().StringVar(&caBundle, "ca-bundle", "", "PEM file of extra CA certificates to trust (overrides $TOME_CA_BUNDLE)")
+	rootCmd.PersistentFlags().DurationVar(&fetchTimeout, "timeout", fetch.DefaultTimeout, "How long each network request may take (0 for no limit)")
+	rootCmd.PersistentFlags().Int64Var(&maxFileSize, "max-file-size", fetch.DefaultMaxFileSize, "Largest file to fetch, in bytes (0 for no limit)")
 
 	// Subcommands
 	rootCmd.AddCommand(aproposCmd)
 	rootCmd.AddCommand(attuneCmd)
+	rootCmd.AddCommand(detachCmd)
 	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(learnCmd)
 	rootCmd.AddCommand(listCmd)
@@ -57,6 +95,16 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(buildCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(reconcileCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(treeCmd)
+	rootCmd.AddCommand(whichCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(verifySourceCmd)
 }
 
 var versionCmd = &cobra.Command{
@@ -68,8 +116,11 @@ var versionCmd = &cobra.Command{
 	},
 }
 
-// exitWithError prints an error and exits
+// exitWithError prints an error, rolls back any install in progress, writes
+// any JSON learn owes stdout and exits
 func exitWithError(msg string) {
 	fmt.Fprintln(os.Stderr, ui.Error.Render("Error: "+msg))
+	activeInstall.rollback()
+	printLearnFailure(msg)
 	os.Exit(1)
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -18,106 +19,143 @@ var searchCmd = &cobra.Command{
 	Short:   "Seek artifacts in the archives",
 	Long: `Seek skills, commands, and prompts in the archives.
 
-Searches GitHub for repositories containing artifacts.
+Searches GitHub for repositories with a tome.yaml or SKILL.md matching the
+query. Code search needs a GitHub token (GITHUB_TOKEN, GH_TOKEN or gh auth);
+without one, tome falls back to searching repository names and descriptions.
 
 Examples:
   tome seek memory
   tome seek "code review"
-  tome scry deploy`,
+  tome search pdf --limit 5
+  tome scry deploy --json`,
 	Args: cobra.MinimumNArgs(1),
 	Run:  runSearch,
 }
 
-var searchLimit int
+var (
+	searchLimit int
+	searchJSON  bool
+)
 
 func init() {
 	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 10, "Maximum results to show")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Output results as JSON (for AI agents)")
 }
 
-func runSearch(cmd *cobra.Command, args []string) {
-	query := strings.Join(args, " ")
-
-	fmt.Println()
-	fmt.Println(ui.SectionHeader("Seeking: "+query, 56))
-	fmt.Println()
-	fmt.Println(ui.InfoLine("Searching the archives..."))
-	fmt.Println()
+// searchJSONResult is the structured output of tome seek --json
+type searchJSONResult struct {
+	Query   string           `json:"query"`
+	Count   int              `json:"count"`
+	Results []searchJSONRepo `json:"results"`
+}
 
-	gh := ghclient.New()
-	ctx := context.Background()
+// searchJSONRepo is a repository in tome seek --json output
+type searchJSONRepo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Stars       int    `json:"stars"`
+	Learn       string `json:"learn"`
+}
 
-	// Search for repositories with SKILL.md files
-	searchQuery := fmt.Sprintf("%s filename:SKILL.md", query)
+func runSearch(cmd *cobra.Command, args []string) {
+	query := strings.Join(args, " ")
+	if searchLimit < 1 {
+		exitWithError("--limit must be at least 1")
+	}
 
-	codeResults, err := gh.SearchCode(ctx, searchQuery, searchLimit*2)
-	if err != nil || len(codeResults) == 0 {
-		searchRepos(gh, ctx, query)
-		return
+	if !searchJSON {
+		fmt.Println()
+		fmt.Println(ui.SectionHeader("Seeking: "+query, 56))
+		fmt.Println()
+		fmt.Println(ui.InfoLine("Searching the archives..."))
+		fmt.Println()
 	}
 
-	// Deduplicate repos
-	seen := make(map[string]bool)
-	var repos []string
-	for _, r := range codeResults {
-		if !seen[r.Repository] {
-			seen[r.Repository] = true
-			repos = append(repos, r.Repository)
-			if len(repos) >= searchLimit {
-				break
-			}
+	repos, err := searchArchives(ghclient.New(), query)
+	if searchJSON {
+		if err != nil {
+			outputJSONError(err.Error())
+			return
 		}
+		printSearchJSON(query, repos)
+		return
 	}
 
-	fmt.Println(ui.SuccessLine(fmt.Sprintf("Found %d grimoires with artifacts", len(repos))))
-	fmt.Println()
-
-	for _, repo := range repos {
-		name := lipgloss.NewStyle().Foreground(ui.White).Bold(true).Render(repo)
-		cmdText := lipgloss.NewStyle().Foreground(ui.Cyan).Render("tome learn " + repo)
-		fmt.Printf("  %s  %s\n", ui.SkillBadge(), name)
-		fmt.Printf("       %s\n", cmdText)
-		fmt.Println()
+	if err != nil || len(repos) == 0 {
+		fmt.Print(ui.NoResults(query))
+		fmt.Println(ui.PageFooter())
+		return
 	}
 
+	fmt.Println(ui.SuccessLine(fmt.Sprintf("Found %d grimoires", len(repos))))
+	printSearchTable(repos)
+	fmt.Println()
+	hint := lipgloss.NewStyle().Foreground(ui.Cyan).Render("tome learn <name>")
+	fmt.Printf("  Inscribe one with %s\n", hint)
 	fmt.Println(ui.PageFooter())
 }
 
-func searchRepos(gh *ghclient.Client, ctx context.Context, query string) {
-	// Fallback: search for repos mentioning claude-code or skills
-	searchQuery := fmt.Sprintf("%s claude-code OR SKILL.md in:readme,name,description", query)
+// searchArchives finds repositories holding artifacts that match query,
+// falling back to a repository search when code search fails or finds nothing
+func searchArchives(gh *ghclient.Client, query string) ([]ghclient.SearchRepoResult, error) {
+	ctx := context.Background()
 
-	repos, err := gh.SearchRepos(ctx, searchQuery, searchLimit)
-	if err != nil {
-		fmt.Print(ui.NoResults(query))
-		return
+	repos, err := gh.SearchCollections(ctx, query, searchLimit)
+	if err == nil && len(repos) > 0 {
+		return repos, nil
 	}
 
-	if len(repos) == 0 {
-		fmt.Print(ui.NoResults(query))
-		return
+	fallback := fmt.Sprintf("%s claude-code OR SKILL.md in:readme,name,description", query)
+	return gh.SearchRepos(ctx, fallback, searchLimit)
+}
+
+// printSearchTable renders repositories as a name/stars/description table
+func printSearchTable(repos []ghclient.SearchRepoResult) {
+	nameWidth, starsWidth := len("NAME"), len("STARS")
+	for _, r := range repos {
+		nameWidth = max(nameWidth, lipgloss.Width(r.FullName))
+		starsWidth = max(starsWidth, len(fmt.Sprint(r.Stars)))
 	}
 
-	fmt.Println(ui.SuccessLine(fmt.Sprintf("Found %d repositories", len(repos))))
 	fmt.Println()
-
-	for _, repo := range repos {
-		name := lipgloss.NewStyle().Foreground(ui.White).Bold(true).Render(repo.FullName)
-		stars := ""
-		if repo.Stars > 0 {
-			stars = lipgloss.NewStyle().Foreground(ui.Gold).Render(fmt.Sprintf(" ★ %d", repo.Stars))
+	fmt.Println("  " + ui.TableHeader(
+		padRight("NAME", nameWidth),
+		padRight("STARS", starsWidth),
+		"DESCRIPTION",
+	))
+	for _, r := range repos {
+		desc := r.Description
+		if desc == "" {
+			desc = "-"
 		}
-		fmt.Printf("  %s%s\n", name, stars)
+		fmt.Println("  " + ui.TableRow(
+			padRight(r.FullName, nameWidth),
+			padRight(fmt.Sprint(r.Stars), starsWidth),
+			ui.Truncate(desc, 55),
+		))
+	}
+}
 
-		if repo.Description != "" {
-			desc := ui.Truncate(repo.Description, 55)
-			descStyled := lipgloss.NewStyle().Foreground(ui.Gray).Render(desc)
-			fmt.Printf("  %s\n", descStyled)
+// printSearchJSON writes search results for agents
+func printSearchJSON(query string, repos []ghclient.SearchRepoResult) {
+	out := searchJSONResult{
+		Query:   query,
+		Count:   len(repos),
+		Results: make([]searchJSONRepo, len(repos)),
+	}
+	for i, r := range repos {
+		out.Results[i] = searchJSONRepo{
+			Name:        r.FullName,
+			Description: r.Description,
+			Stars:       r.Stars,
+			Learn:       "tome learn " + r.FullName,
 		}
-
-		cmdText := lipgloss.NewStyle().Foreground(ui.Cyan).Render("tome learn " + repo.FullName)
-		fmt.Printf("  %s\n", cmdText)
-		fmt.Println()
 	}
 
-	fmt.Println(ui.PageFooter())
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		outputJSONError(fmt.Sprintf("failed to marshal results: %v", err))
+		return
+	}
+	fmt.Println(string(data))
 }
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/source"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+// discoveredName returns the name an artifact goes by before it's fetched:
+// its skill directory, or its filename without the extension
+func discoveredName(item fetch.GitHubContent) string {
+	if item.SkillDir != "" {
+		return path.Base(item.SkillDir)
+	}
+	return fetch.CommandNameFromFile(item.Name)
+}
+
+// matchArtifactsByName returns the discovered artifacts called name, first by
+// directory or filename and, when none match, by the name parsed from each
+// artifact's content. parsedName fetches and parses an artifact, returning ""
+// if it can't.
+func matchArtifactsByName(items []fetch.GitHubContent, name string, parsedName func(fetch.GitHubContent) string) []fetch.GitHubContent {
+	var matches []fetch.GitHubContent
+	for _, item := range items {
+		if strings.EqualFold(discoveredName(item), name) {
+			matches = append(matches, item)
+		}
+	}
+	if len(matches) > 0 {
+		return matches
+	}
+
+	for _, item := range items {
+		if strings.EqualFold(parsedName(item), name) {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
+// selectArtifactByName narrows discovered artifacts to the one src's #name
+// selector picks. It's an error when none match or several do; the latter
+// lists each candidate as a source that selects it by path.
+func selectArtifactByName(client *fetch.Client, src *source.Source, items []fetch.GitHubContent) ([]fetch.GitHubContent, error) {
+	matches := matchArtifactsByName(items, src.Name, func(item fetch.GitHubContent) string {
+		f := fetchArtifactContent(client, src, item)
+		if f.art == nil {
+			return ""
+		}
+		return f.art.Name
+	})
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no artifact named %q in %s", src.Name, src.String())
+	case 1:
+		return matches, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d artifacts are named %q; pick one by path:", len(matches), src.Name)
+	for _, item := range matches {
+		selected := *src
+		selected.Name = ""
+		selected.Path = item.Path
+		if strings.EqualFold(item.Name, artifact.SkillFilename) {
+			selected.Path = strings.TrimPrefix(path.Dir(item.Path), ".")
+		}
+		fmt.Fprintf(&b, "\n  tome learn %s", selected.String())
+	}
+	return nil, fmt.Errorf("%s", b.String())
+}
+
+// parseSelection parses a pick from a numbered list of n choices: numbers
+// and ranges separated by commas or spaces ("1,3-5"), or "all". An empty
+// answer picks everything. Returns zero-based indexes in list order.
+func parseSelection(answer string, n int) ([]int, error) {
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	if answer == "" || answer == "all" {
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+
+	picked := make([]bool, n)
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		lo, hi, isRange := strings.Cut(field, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("%q isn't a number or range", field)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, fmt.Errorf("%q isn't a number or range", field)
+			}
+		}
+		if first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("%q is outside 1-%d", field, n)
+		}
+		for i := first; i <= last; i++ {
+			picked[i-1] = true
+		}
+	}
+
+	var indexes []int
+	for i, ok := range picked {
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// selectFetched returns the fetched artifacts at indexes, in list order
+func selectFetched(fetched []fetchedArtifact, indexes []int) []fetchedArtifact {
+	selected := make([]fetchedArtifact, 0, len(indexes))
+	for _, i := range indexes {
+		selected = append(selected, fetched[i])
+	}
+	return selected
+}
+
+// promptArtifactSelection lists fetched artifacts with their badges and
+// descriptions and asks which to install (--interactive), asking again
+// until the answer parses
+func promptArtifactSelection(fetched []fetchedArtifact) []fetchedArtifact {
+	fmt.Fprintln(learnOutput(), ui.Info.Render("  Choose artifacts to install:"))
+	for i, f := range fetched {
+		name, badge, detail := discoveredName(f.item), "", ""
+		switch {
+		case f.art != nil:
+			name, badge, detail = f.art.Name, getBadge(f.art.Type), ui.Truncate(f.art.Description, 50)
+		case f.err != nil:
+			detail = ui.Warning.Render(fmt.Sprintf("%s: %v", f.skipReason, f.err))
+		}
+		fmt.Fprintf(learnOutput(), "  %3d. %s %s  %s\n", i+1, badge, ui.Highlight.Render(name), ui.Muted.Render(detail))
+	}
+	fmt.Fprintln(learnOutput())
+
+	for {
+		fmt.Fprint(learnOutput(), "  Install which? (e.g. 1,3-5; Enter for all) ")
+		answer, err := promptReader.ReadString('\n')
+		if err != nil && answer == "" {
+			fmt.Fprintln(learnOutput())
+			return nil
+		}
+		indexes, parseErr := parseSelection(answer, len(fetched))
+		if parseErr == nil {
+			fmt.Fprintln(learnOutput())
+			return selectFetched(fetched, indexes)
+		}
+		fmt.Fprintln(learnOutput(), ui.Warning.Render("  "+parseErr.Error()))
+	}
+}
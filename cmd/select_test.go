@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/source"
+)
+
+func TestMatchArtifactsByName(t *testing.T) {
+	items := []fetch.GitHubContent{
+		{Name: "SKILL.md", Path: "skills/pdf/SKILL.md", SkillDir: "skills/pdf"},
+		{Name: "SKILL.md", Path: "skills/docx/SKILL.md", SkillDir: "skills/docx"},
+		{Name: "review.md", Path: "commands/review.md"},
+	}
+	parsed := map[string]string{
+		"skills/pdf/SKILL.md":  "pdf-tools",
+		"skills/docx/SKILL.md": "word",
+		"commands/review.md":   "review",
+	}
+	var fetched int
+	parsedName := func(item fetch.GitHubContent) string {
+		fetched++
+		return parsed[item.Path]
+	}
+
+	tests := []struct {
+		name        string
+		want        []string
+		wantFetched bool
+	}{
+		{"pdf", []string{"skills/pdf/SKILL.md"}, false},
+		{"Review", []string{"commands/review.md"}, false},
+		{"word", []string{"skills/docx/SKILL.md"}, true},
+		{"missing", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched = 0
+			got := matchArtifactsByName(items, tt.name, parsedName)
+			if len(got) != len(tt.want) {
+				t.Fatalf("matchArtifactsByName(%q) = %+v, want %v", tt.name, got, tt.want)
+			}
+			for i := range got {
+				if got[i].Path != tt.want[i] {
+					t.Errorf("match %d = %s, want %s", i, got[i].Path, tt.want[i])
+				}
+			}
+			if (fetched > 0) != tt.wantFetched {
+				t.Errorf("fetched %d artifacts, want fetching = %v", fetched, tt.wantFetched)
+			}
+		})
+	}
+}
+
+func TestSelectArtifactByName_Ambiguous(t *testing.T) {
+	src, err := source.Parse("owner/repo#pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []fetch.GitHubContent{
+		{Name: "SKILL.md", Path: "skills/pdf/SKILL.md", SkillDir: "skills/pdf"},
+		{Name: "pdf.md", Path: "commands/pdf.md"},
+	}
+
+	_, err = selectArtifactByName(fetch.NewClient(), src, items)
+	if err == nil {
+		t.Fatal("selectArtifactByName() with two matches succeeded, want error")
+	}
+	for _, want := range []string{"2 artifacts", "tome learn owner/repo:skills/pdf\n", "tome learn owner/repo:commands/pdf.md"} {
+		if !strings.Contains(err.Error()+"\n", want) {
+			t.Errorf("error %q doesn't contain %q", err, want)
+		}
+	}
+
+	got, err := selectArtifactByName(fetch.NewClient(), src, items[:1])
+	if err != nil || len(got) != 1 {
+		t.Errorf("selectArtifactByName() = %v, %v; want the single match", got, err)
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		answer  string
+		want    []int
+		wantErr bool
+	}{
+		{"", []int{0, 1, 2, 3, 4}, false},
+		{"all", []int{0, 1, 2, 3, 4}, false},
+		{" 2 \n", []int{1}, false},
+		{"1,3-5", []int{0, 2, 3, 4}, false},
+		{"4 2", []int{1, 3}, false},
+		{"2,2,1-2", []int{0, 1}, false},
+		{"0", nil, true},
+		{"6", nil, true},
+		{"3-1", nil, true},
+		{"2-x", nil, true},
+		{"pdf", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSelection(tt.answer, 5)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSelection(%q) error = %v, wantErr %v", tt.answer, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("parseSelection(%q) = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestSelectFetched(t *testing.T) {
+	fetched := []fetchedArtifact{
+		{item: fetch.GitHubContent{Name: "a.md"}},
+		{item: fetch.GitHubContent{Name: "b.md"}},
+		{item: fetch.GitHubContent{Name: "c.md"}},
+	}
+	indexes, err := parseSelection("3,1", len(fetched))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, f := range selectFetched(fetched, indexes) {
+		names = append(names, f.item.Name)
+	}
+	if got := strings.Join(names, ","); got != "a.md,c.md" {
+		t.Errorf("selected %s, want a.md,c.md in list order", got)
+	}
+}
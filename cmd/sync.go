@@ -3,13 +3,16 @@ package cmd
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/kennyg/tome/internal/artifact"
 	"github.com/kennyg/tome/internal/config"
 	"github.com/kennyg/tome/internal/fetch"
 	"github.com/kennyg/tome/internal/source"
@@ -17,13 +20,20 @@ import (
 )
 
 var syncCmd = &cobra.Command{
-	Use:     "renew",
+	Use:     "renew [name]",
 	Aliases: []string{"refresh", "sync", "update"},
 	Short:   "Renew inscriptions from their sources",
-	Long: `Renew all inscribed artifacts from their original sources.
+	Long: `Renew inscribed artifacts from their original sources.
 
-Checks for updates and downloads newer versions if available.`,
-	Run: runSync,
+Re-fetches each artifact's source and rewrites the installed file only if
+its content changed. With a name, renews just that artifact.
+
+Examples:
+  tome renew                     # Renew everything
+  tome update my-skill           # Renew one artifact
+  tome renew --dry-run           # Show what would change`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runSync,
 }
 
 var syncDry bool
@@ -48,98 +58,83 @@ func runSync(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	var only *artifact.InstalledArtifact
+	if len(args) == 1 {
+		only, err = state.LookupInstalled(args[0])
+		if err != nil {
+			exitWithError(err.Error())
+		}
+	}
+
 	fmt.Println()
 	fmt.Println(ui.SectionHeader("Renewing Inscriptions", 56))
 	fmt.Println()
 
-	client := fetch.NewClient()
+	client := newFetchClient()
 	var updated, unchanged, failed int
 
 	for i := range state.Installed {
 		a := &state.Installed[i]
+		if only != nil && (a.Name != only.Name || a.Type != only.Type) {
+			continue
+		}
 		badge := getBadge(a.Type)
 		fmt.Printf("  %s %s ", badge, ui.Highlight.Render(a.Name))
 
-		// Determine the URL to fetch
-		var fetchURL string
-
-		// Check if this is a local source (by source field or source_url)
-		if isLocalPath(a.SourceURL) || isLocalPath(a.Source) {
-			fmt.Println(ui.Muted.Render("↷ local"))
+		content, newContent, err := fetchFromSource(client, a, paths)
+		var srcErr *sourceError
+		if errors.As(err, &srcErr) && srcErr.skip {
+			fmt.Println(ui.Muted.Render("↷ " + srcErr.status))
 			unchanged++
 			continue
 		}
-
-		// Prefer stored source_url if available
-		if a.SourceURL != "" {
-			// Strip any token params from URL (they expire)
-			fetchURL = stripTokenFromURL(a.SourceURL)
-		} else {
-			// Fall back to parsing source
-			src, err := source.Parse(a.Source)
-			if err != nil {
-				fmt.Println(ui.Warning.Render("⚠ invalid source"))
-				failed++
-				continue
-			}
-
-			switch src.Type {
-			case source.TypeGitHub:
-				fetchURL = src.GitHubRawURL("")
-			case source.TypeURL:
-				fetchURL = src.URL
-			case source.TypeLocal:
-				// Skip local sources - they don't need syncing
-				fmt.Println(ui.Muted.Render("↷ local"))
-				unchanged++
-				continue
-			}
-		}
-
-		// Fetch current content
-		content, err := client.FetchURL(fetchURL)
 		if err != nil {
-			fmt.Println(ui.Warning.Render("⚠ fetch failed"))
+			fmt.Println(ui.Warning.Render("⚠ " + srcErr.status))
 			failed++
 			continue
 		}
 
-		// Compare with installed version using hash
-		newHash := hashContent(content)
-		oldHash := a.Hash
-		if oldHash == "" {
-			// Compute hash from local file for legacy entries
-			localContent, err := os.ReadFile(a.LocalPath)
-			if err == nil {
-				oldHash = hashContent(localContent)
-			}
-		}
-
-		if newHash == oldHash {
+		// Compare against what's on disk
+		localContent, readErr := os.ReadFile(a.LocalPath)
+		isNew := readErr != nil
+		if !isNew && string(localContent) == newContent {
 			fmt.Println(ui.Muted.Render("✓ up to date"))
 			unchanged++
 			continue
 		}
 
+		status := ui.StatusUpdate()
+		if isNew {
+			status = ui.StatusNew()
+		}
+
 		// Update available
 		if syncDry {
-			fmt.Println(ui.Info.Render("↑ update available"))
+			fmt.Println(status + " " + ui.Info.Render("update available"))
 			updated++
 			continue
 		}
 
 		// Apply update
-		if err := os.WriteFile(a.LocalPath, content, 0644); err != nil {
+		if err := os.MkdirAll(filepath.Dir(a.LocalPath), 0755); err != nil {
+			fmt.Println(ui.Warning.Render("⚠ write failed"))
+			failed++
+			continue
+		}
+		if err := os.WriteFile(a.LocalPath, []byte(newContent), 0644); err != nil {
 			fmt.Println(ui.Warning.Render("⚠ write failed"))
 			failed++
 			continue
 		}
 
-		// Update state
-		a.Hash = newHash
+		// Update state, and tome.lock if the project pins the artifact
+		a.Hash = hashContent(content)
 		a.UpdatedAt = time.Now()
+		if inProject(a.LocalPath) {
+			repinArtifact(client, a, content)
+		}
 
-		fmt.Println(ui.Success.Render("↑ updated"))
+		fmt.Println(status + " " + ui.Success.Render("updated"))
 		updated++
 	}
 
@@ -168,17 +163,94 @@ func runSync(cmd *cobra.Command, args []string) {
 	fmt.Println(ui.PageFooter())
 }
 
-func hashContent(content []byte) string {
-	h := sha256.Sum256(content)
-	return hex.EncodeToString(h[:])
+// sourceError explains why an installed artifact couldn't be rebuilt from its source
+type sourceError struct {
+	status string // short status, e.g. "fetch failed"
+	skip   bool   // the artifact isn't renewable (local or flattened) rather than broken
+	err    error
+}
+
+func (e *sourceError) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("%s: %v", e.status, e.err)
+	}
+	return e.status
 }
 
-func stripTokenFromURL(url string) string {
-	// Remove ?token=... from GitHub URLs (tokens expire)
-	if idx := strings.Index(url, "?token="); idx != -1 {
-		return url[:idx]
+func (e *sourceError) Unwrap() error { return e.err }
+
+// fetchFromSource re-fetches an installed artifact from its source and
+// returns the fetched content along with exactly what install would write
+// for it. Failures are always a *sourceError.
+func fetchFromSource(client *fetch.Client, a *artifact.InstalledArtifact, paths *config.Paths) ([]byte, string, error) {
+	// Local sources don't need syncing
+	if isLocalPath(a.SourceURL) || isLocalPath(a.Source) {
+		return nil, "", &sourceError{status: "local", skip: true}
 	}
-	return url
+
+	// Flattened skills bundle their includes; a raw refetch would drop them
+	if a.Flattened {
+		return nil, "", &sourceError{status: "flattened (re-learn with --flatten-skill to update)", skip: true}
+	}
+
+	// Prefer stored source_url if available
+	var fetchURL string
+	if a.SourceURL != "" {
+		// Strip any token params from URL (they expire)
+		fetchURL = fetch.StripToken(a.SourceURL)
+	} else {
+		// Fall back to parsing source
+		src, err := source.Parse(a.Source)
+		if err != nil {
+			return nil, "", &sourceError{status: "invalid source", err: err}
+		}
+
+		switch src.Type {
+		case source.TypeRepo:
+			fetchURL = src.RawURL("")
+		case source.TypeURL:
+			fetchURL = src.URL
+		case source.TypeLocal:
+			return nil, "", &sourceError{status: "local", skip: true}
+		}
+	}
+
+	// Fetch current content
+	content, err := client.FetchURL(fetchURL)
+	if err != nil {
+		return nil, "", &sourceError{status: "fetch failed", err: err}
+	}
+
+	// Re-parse and build exactly what install would write
+	filename := a.Filename
+	if filename == "" {
+		filename = filepath.Base(a.LocalPath)
+	}
+	art, err := parseArtifact(content, filename, fetchURL)
+	if err != nil {
+		return nil, "", &sourceError{status: "parse failed", err: err}
+	}
+	art.Source = a.Source
+	if art.Name != a.Name {
+		renameArtifact(art, a.Name) // Installed with --rename or to resolve a conflict
+	}
+
+	// Convert for the agent the artifact was learned for
+	if a.Agent != "" && config.Agent(a.Agent) != paths.Agent {
+		if agentPaths, err := config.GetPathsForAgent(config.Agent(a.Agent)); err == nil {
+			paths = agentPaths
+		}
+	}
+	newContent := art.Content
+	if result, ok := convertArtifact(art, paths); ok {
+		newContent = string(result.Content)
+	}
+	return content, newContent, nil
+}
+
+func hashContent(content []byte) string {
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:])
 }
 
 // isLocalPath returns true if the path looks like a local filesystem path
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+)
+
+// renewFixture installs the deploy and review commands with stale content
+// whose sources now serve newer versions, returning the global paths
+func renewFixture(t *testing.T) *config.Paths {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(t.TempDir())
+	t.Cleanup(func() { noCache, syncDry = false, false })
+	noCache = true
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSuffix(filepath.Base(r.URL.Path), ".md")
+		w.Write([]byte("---\ndescription: " + name + " v2\n---\n# " + name + " v2\n"))
+	}))
+	t.Cleanup(srv.Close)
+
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &config.State{Version: "1"}
+	for _, name := range []string{"deploy", "review"} {
+		localPath := filepath.Join(paths.CommandsDir, name+".md")
+		writeFiles(t, paths.CommandsDir, map[string]string{name + ".md": "# " + name + " v1\n"})
+		state.Installed = append(state.Installed, artifact.InstalledArtifact{
+			Artifact: artifact.Artifact{
+				Name:      name,
+				Type:      artifact.TypeCommand,
+				Filename:  name + ".md",
+				Source:    "owner/repo",
+				SourceURL: srv.URL + "/commands/" + name + ".md",
+			},
+			LocalPath: localPath,
+			Hash:      "stale",
+		})
+	}
+	if err := config.SaveState(paths.StateFile, state); err != nil {
+		t.Fatal(err)
+	}
+	return paths
+}
+
+func readInstalled(t *testing.T, paths *config.Paths, name string) (string, *artifact.InstalledArtifact) {
+	t.Helper()
+	state, err := config.LoadState(paths.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := state.FindInstalled(name)
+	if a == nil {
+		t.Fatalf("%s not in state", name)
+	}
+	content, err := os.ReadFile(a.LocalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content), a
+}
+
+func TestRunSync_Name(t *testing.T) {
+	paths := renewFixture(t)
+
+	out := captureStdout(t, func() { runSync(syncCmd, []string{"deploy"}) })
+	if !strings.Contains(out, "Renewed 1 artifact(s)") {
+		t.Errorf("output = %q, want one artifact renewed", out)
+	}
+
+	content, deploy := readInstalled(t, paths, "deploy")
+	if !strings.Contains(content, "deploy v2") || deploy.Hash == "stale" || deploy.UpdatedAt.IsZero() {
+		t.Errorf("deploy = %q (hash %s, updated %v), want v2 recorded in state", content, deploy.Hash, deploy.UpdatedAt)
+	}
+	if content, review := readInstalled(t, paths, "review"); content != "# review v1\n" || review.Hash != "stale" {
+		t.Errorf("review = %q (hash %s), want it left alone", content, review.Hash)
+	}
+}
+
+func TestRunSync_DryRun(t *testing.T) {
+	paths := renewFixture(t)
+	syncDry = true
+
+	out := captureStdout(t, func() { runSync(syncCmd, nil) })
+	if strings.Count(out, "update available") != 2 || !strings.Contains(out, "no changes made") {
+		t.Errorf("output = %q, want both artifacts reported as changed", out)
+	}
+	for _, name := range []string{"deploy", "review"} {
+		if content, a := readInstalled(t, paths, name); content != "# "+name+" v1\n" || a.Hash != "stale" {
+			t.Errorf("%s = %q (hash %s), want it unchanged by a dry run", name, content, a.Hash)
+		}
+	}
+}
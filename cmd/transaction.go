@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/kennyg/tome/internal/ui"
+)
+
+// activeInstall is the install transaction in progress, if any. A fatal
+// error (exitWithError) rolls it back.
+var activeInstall *installTx
+
+// installTx records what an install of several artifacts writes, so a
+// failure partway through can put the filesystem back: files it created are
+// removed, files it overwrote (state included) get their old content back,
+// and directories it created are removed once empty. Methods work on a nil
+// *installTx, writing without recording anything.
+type installTx struct {
+	created []string          // files that didn't exist before
+	saved   map[string][]byte // prior content of files that did
+	modes   map[string]fs.FileMode
+	dirs    []string // directories created, parents first
+	learned int      // len(learnedArtifacts) when the install began
+}
+
+// beginInstall starts recording an install
+func beginInstall() *installTx {
+	activeInstall = &installTx{saved: map[string][]byte{}, modes: map[string]fs.FileMode{}, learned: len(learnedArtifacts)}
+	return activeInstall
+}
+
+// commit keeps everything written and stops recording
+func (tx *installTx) commit() {
+	if activeInstall == tx {
+		activeInstall = nil
+	}
+}
+
+// track remembers path's current content, or that it doesn't exist, the
+// first time the transaction is about to change it
+func (tx *installTx) track(path string) error {
+	if tx == nil {
+		return nil
+	}
+	if _, ok := tx.saved[path]; ok {
+		return nil
+	}
+	for _, p := range tx.created {
+		if p == path {
+			return nil
+		}
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		tx.created = append(tx.created, path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	tx.saved[path] = data
+	tx.modes[path] = info.Mode().Perm()
+	return nil
+}
+
+// writeFile writes a file like os.WriteFile, recording it first
+func (tx *installTx) writeFile(path string, data []byte, perm fs.FileMode) error {
+	if err := tx.track(path); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// mkdirAll creates a directory like os.MkdirAll, recording each one created
+func (tx *installTx) mkdirAll(dir string) error {
+	if tx == nil {
+		return os.MkdirAll(dir, 0755)
+	}
+
+	var missing []string
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		tx.dirs = append(tx.dirs, missing[i])
+	}
+	return nil
+}
+
+// rollback undoes everything recorded and stops recording
+func (tx *installTx) rollback() {
+	if tx == nil {
+		return
+	}
+	tx.commit()
+
+	// What was rolled back is no longer installed, as far as --json goes
+	if len(learnedArtifacts) > tx.learned {
+		learnedArtifacts = learnedArtifacts[:tx.learned]
+	}
+	for _, path := range tx.created {
+		os.Remove(path)
+	}
+	for path, data := range tx.saved {
+		os.WriteFile(path, data, tx.modes[path])
+	}
+	// Deepest first, leaving any a failure left non-empty
+	for i := len(tx.dirs) - 1; i >= 0; i-- {
+		os.Remove(tx.dirs[i])
+	}
+
+	if n := len(tx.created) + len(tx.saved); n > 0 {
+		fmt.Fprintln(os.Stderr, ui.Muted.Render(fmt.Sprintf("  Rolled back %d file(s) written by the partial install", n)))
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/schema"
+)
+
+func TestInstallTx_RollbackAfterPartialInstall(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Installed by an earlier run, outside the transaction
+	deploy := &artifact.Artifact{Name: "deploy", Type: artifact.TypeCommand, Source: "acme/tools", Content: "# v1\n"}
+	if _, ok := doInstallWithExtraReqs(deploy, paths, nil, nil); !ok {
+		t.Fatal("deploy install skipped")
+	}
+	deployPath, _ := getInstallPath(deploy, paths, false)
+	stateBefore, err := os.ReadFile(paths.StateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := beginInstall()
+	pdf := &artifact.Artifact{Name: "pdf", Type: artifact.TypeSkill, Source: "acme/tools", Content: "---\nname: pdf\n---\n# PDF\n"}
+	includes := []fetch.IncludedFile{{Path: "scripts/extract.sh", Content: []byte("#!/bin/sh\n")}}
+	if _, ok := doInstallWithExtraReqs(pdf, paths, includes, nil); !ok {
+		t.Fatal("pdf install skipped")
+	}
+	pdfPath, _ := getInstallPath(pdf, paths, false)
+	update := &artifact.Artifact{Name: "deploy", Type: artifact.TypeCommand, Source: "acme/tools", Content: "# v2\n"}
+	if _, ok := doInstallWithExtraReqs(update, paths, nil, nil); !ok {
+		t.Fatal("deploy update skipped")
+	}
+
+	// The next artifact fails fatally
+	tx.rollback()
+
+	if activeInstall != nil {
+		t.Error("transaction still active after rollback")
+	}
+	if _, err := os.Stat(filepath.Dir(pdfPath)); !os.IsNotExist(err) {
+		t.Errorf("pdf skill directory left behind: %v", err)
+	}
+	if data, _ := os.ReadFile(deployPath); string(data) != "# v1\n" {
+		t.Errorf("deploy = %q, want the content from before the install", data)
+	}
+	if data, _ := os.ReadFile(paths.StateFile); string(data) != string(stateBefore) {
+		t.Errorf("state not restored:\n%s", data)
+	}
+}
+
+func TestInstallTx_Nil(t *testing.T) {
+	var tx *installTx
+	dir := filepath.Join(t.TempDir(), "a", "b")
+	if err := tx.mkdirAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.writeFile(filepath.Join(dir, "f.md"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tx.rollback()
+	if _, err := os.Stat(filepath.Join(dir, "f.md")); err != nil {
+		t.Errorf("nil transaction rolled back a write: %v", err)
+	}
+}
+
+func TestInstallTx_RollsBackInstructionsAndLock(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { learnLock, learnLockPath = nil, "" })
+	paths, err := config.GetPathsForAgent(config.AgentClaude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, home, map[string]string{".claude/CLAUDE.md": "My own instructions.\n"})
+	claudeMD := filepath.Join(home, ".claude", "CLAUDE.md")
+	project := t.TempDir()
+	writeFiles(t, project, map[string]string{config.LockfileName: "{\"version\": 1, \"artifacts\": []}\n"})
+	learnLockPath = filepath.Join(project, config.LockfileName)
+	inst, err := schema.ParseInstructionsAuto([]byte("Use tabs.\n"), "CLAUDE.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := beginInstall()
+	if _, err := installInstructions(inst, "acme/tools/CLAUDE.md", paths, false); err != nil {
+		t.Fatal(err)
+	}
+	learnLock = &config.Lockfile{Version: 1}
+	learnLock.Set(config.LockEntry{Name: "deploy", Type: artifact.TypeCommand, Source: "acme/tools"})
+	saveLockUpdate()
+	tx.rollback()
+
+	if data, _ := os.ReadFile(claudeMD); string(data) != "My own instructions.\n" {
+		t.Errorf("CLAUDE.md = %q, want it as it was before the install", data)
+	}
+	if data, _ := os.ReadFile(learnLockPath); string(data) != "{\"version\": 1, \"artifacts\": []}\n" {
+		t.Errorf("%s = %q, want it as it was before the install", config.LockfileName, data)
+	}
+}
@@ -2,13 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 
-	"github.com/kennyg/tome/internal/fetch"
 	"github.com/kennyg/tome/internal/schema"
 	"github.com/kennyg/tome/internal/source"
 	"github.com/kennyg/tome/internal/ui"
@@ -25,6 +26,8 @@ Supports conversion between:
   - OpenCode (.opencode/skill/*/SKILL.md, opencode.json)
   - GitHub Copilot (agents/*.agent.md)
   - Cursor (.cursor/rules/*.md, .cursor/mcp.json)
+  - Windsurf (.windsurf/rules/*.md, .windsurf/workflows/*.md, mcp_config.json)
+  - Zed (.zed/rules/*.md, .rules)
 
 Artifact types:
   - Skills (SKILL.md, .agent.md, .md rules)
@@ -35,29 +38,49 @@ Sources can be:
   - Local file path
   - Local directory
   - GitHub repository (owner/repo)
+  - "-" to read a skill from stdin (needs --from) and write it to stdout
 
 Examples:
   tome transmogrify agents/CSharp.agent.md --to claude
   tome transmogrify ./copilot-skills/ --to claude --output ./converted/
   tome transmogrify github/awesome-copilot --to claude --dry-run
+  tome transmogrify skills/pdf/SKILL.md --to zed
   tome transmogrify .mcp.json --to opencode
-  tome transmogrify opencode.json --to claude`,
+  tome transmogrify opencode.json --to claude
+  tome transmogrify .vscode/mcp.json --to claude --inline-env-files
+  tome transmogrify .mcp.json --to opencode --verify-commands
+  tome transmogrify .mcp.json --to copilot --secret-inputs
+  tome transmogrify . --to claude --in-place --force   # Migrate a repo
+  cat SKILL.md | tome transmogrify - --from claude --to copilot > pdf.agent.md`,
 	Args: cobra.ExactArgs(1),
 	Run:  runTransmogrify,
 }
 
 var (
 	transmogrifyTo     string
+	transmogrifyFrom   string
 	transmogrifyOutput string
 	transmogrifyDryRun bool
 	transmogrifyForce  bool
+	transmogrifyEnv    bool
+	transmogrifyVerify bool
+	transmogrifySecret bool
+	transmogrifyToken  string
+
+	transmogrifyInPlace bool
 )
 
 func init() {
-	transmogrifyCmd.Flags().StringVar(&transmogrifyTo, "to", "", "Target format (claude, opencode, copilot, cursor)")
+	transmogrifyCmd.Flags().StringVar(&transmogrifyTo, "to", "", "Target format (claude, opencode, copilot, cursor, windsurf, zed)")
+	transmogrifyCmd.Flags().StringVar(&transmogrifyFrom, "from", "", "Source format, instead of detecting it (required when reading stdin)")
 	transmogrifyCmd.Flags().StringVarP(&transmogrifyOutput, "output", "o", "", "Output directory (default: stdout for single file)")
 	transmogrifyCmd.Flags().BoolVar(&transmogrifyDryRun, "dry-run", false, "Show what would be converted without doing it")
 	transmogrifyCmd.Flags().BoolVarP(&transmogrifyForce, "force", "f", false, "Overwrite existing files")
+	transmogrifyCmd.Flags().BoolVar(&transmogrifyEnv, "inline-env-files", false, "Inline variables from Copilot MCP envFile references into env")
+	transmogrifyCmd.Flags().BoolVar(&transmogrifyVerify, "verify-commands", false, "Warn about MCP servers whose command isn't on PATH")
+	transmogrifyCmd.Flags().BoolVar(&transmogrifySecret, "secret-inputs", false, "Move MCP env values named like credentials (*_TOKEN, *_API_KEY) into Copilot password inputs")
+	transmogrifyCmd.Flags().BoolVar(&transmogrifyInPlace, "in-place", false, "Replace each source file with its converted form, renamed and moved for the target format (needs --force)")
+	transmogrifyCmd.Flags().StringVar(&transmogrifyToken, "token", "", "GitHub token for private repos (overrides GITHUB_TOKEN, GH_TOKEN and gh CLI config)")
 
 	transmogrifyCmd.MarkFlagRequired("to")
 
@@ -65,6 +88,11 @@ func init() {
 }
 
 func runTransmogrify(cmd *cobra.Command, args []string) {
+	if args[0] == "-" {
+		transmogrifyStdin(cmd)
+		return
+	}
+
 	fmt.Println()
 	fmt.Println(ui.SectionHeader("Transmogrify", 56))
 	fmt.Println()
@@ -72,10 +100,13 @@ func runTransmogrify(cmd *cobra.Command, args []string) {
 	// Validate target format
 	targetFormat := schema.Format(transmogrifyTo)
 	if !targetFormat.IsValid() {
-		exitWithError(fmt.Sprintf("invalid target format: %s (valid: claude, opencode, copilot, cursor)", transmogrifyTo))
+		exitWithError(fmt.Sprintf("invalid target format: %s (valid: claude, opencode, copilot, cursor, windsurf, zed)", transmogrifyTo))
 	}
 
 	sourceArg := args[0]
+	if transmogrifyInPlace {
+		checkInPlace()
+	}
 
 	// Determine source type
 	src, err := source.Parse(sourceArg)
@@ -89,7 +120,13 @@ func runTransmogrify(cmd *cobra.Command, args []string) {
 	}
 
 	switch src.Type {
-	case source.TypeGitHub:
+	case source.TypeRepo:
+		if !src.IsGitHub() {
+			exitWithError(fmt.Sprintf("transmogrify only supports GitHub repositories, not %s", src.Provider))
+		}
+		if transmogrifyInPlace {
+			exitWithError("--in-place only converts local files")
+		}
 		transmogrifyGitHub(src, targetFormat)
 	case source.TypeLocal:
 		transmogrifyLocal(src.Path, targetFormat)
@@ -98,6 +135,125 @@ func runTransmogrify(cmd *cobra.Command, args []string) {
 	}
 }
 
+// transmogrifyStdin converts a skill read from stdin, writing only the
+// converted content to stdout so it can sit in a pipeline; warnings go to
+// stderr
+func transmogrifyStdin(cmd *cobra.Command) {
+	if transmogrifyFrom == "" {
+		exitWithError("reading stdin needs --from (claude, opencode, copilot, cursor, windsurf, zed)")
+	}
+	if transmogrifyOutput != "" {
+		exitWithError("--output can't be used with stdin; redirect stdout instead")
+	}
+	if transmogrifyInPlace {
+		exitWithError("--in-place can't be used with stdin")
+	}
+	from := parseFromFormat()
+	targetFormat := schema.Format(transmogrifyTo)
+	if !targetFormat.IsValid() {
+		exitWithError(fmt.Sprintf("invalid target format: %s (valid: claude, opencode, copilot, cursor, windsurf, zed)", transmogrifyTo))
+	}
+
+	content, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		exitWithError(fmt.Sprintf("failed to read stdin: %v", err))
+	}
+	skill, err := schema.Parse(content, from)
+	if err != nil {
+		exitWithError(fmt.Sprintf("failed to parse: %v", err))
+	}
+	result, err := schema.ConvertWithInfo(skill, targetFormat)
+	if err != nil {
+		exitWithError(fmt.Sprintf("conversion failed: %v", err))
+	}
+
+	for _, w := range result.Warnings {
+		fmt.Fprintln(cmd.ErrOrStderr(), ui.WarningLine(w))
+	}
+	cmd.OutOrStdout().Write(result.Content)
+}
+
+// checkInPlace validates --in-place, which destroys its sources and so must
+// be confirmed with --force unless it's a dry run
+func checkInPlace() {
+	if transmogrifyOutput != "" {
+		exitWithError("--in-place and --output can't be used together")
+	}
+	if !transmogrifyForce && !transmogrifyDryRun {
+		exitWithError("--in-place overwrites and deletes source files; add --force to proceed (or --dry-run to preview)")
+	}
+}
+
+// inPlacePath returns where the skill at src goes when converted in place:
+// the target format's layout (skills/<name>/SKILL.md, agents/<name>.agent.md,
+// ...) under the project root src sits in. The root is src's directory with
+// the source format's layout stripped; a file outside that layout converts
+// next to where it is.
+func inPlacePath(src string, skill schema.Skill, targetFormat schema.Format) string {
+	dir := filepath.Dir(src)
+	layout := schema.OutputDirectory(skill, skill.GetFormat())
+	if strings.EqualFold(filepath.Base(src), "SKILL.md") {
+		// A skill's directory is part of the skill, whatever it's named
+		dir, layout = filepath.Dir(dir), path.Dir(layout)
+	}
+	root := trimLayout(dir, layout)
+	return filepath.Join(root, schema.OutputDirectory(skill, targetFormat), schema.OutputFilename(skill, targetFormat))
+}
+
+// inPlaceMCPPath returns where the MCP config at src goes when converted in
+// place, found the same way as inPlacePath
+func inPlaceMCPPath(src string, from, targetFormat schema.Format) string {
+	root := trimLayout(filepath.Dir(src), schema.MCPOutputDirectory(from))
+	return filepath.Join(root, schema.MCPOutputDirectory(targetFormat), schema.MCPOutputFilename(targetFormat))
+}
+
+// trimLayout strips the slash-separated layout from the end of dir, leaving
+// dir as is when it doesn't end with it
+func trimLayout(dir, layout string) string {
+	if layout == "" || layout == "." {
+		return dir
+	}
+	suffix := filepath.FromSlash(layout)
+	if dir == suffix {
+		return "."
+	}
+	if root, ok := strings.CutSuffix(dir, string(filepath.Separator)+suffix); ok {
+		return root
+	}
+	return dir
+}
+
+// replaceInPlace writes content to dst and removes src when it's a different
+// file, along with the skill directory it leaves empty
+func replaceInPlace(src, dst string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, content, 0644); err != nil {
+		return err
+	}
+	if filepath.Clean(src) == filepath.Clean(dst) {
+		return nil
+	}
+	if err := os.Remove(src); err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Base(src), "SKILL.md") {
+		// Fails harmlessly when the skill has other files
+		os.Remove(filepath.Dir(src))
+	}
+	return nil
+}
+
+// parseFromFormat validates --from
+func parseFromFormat() schema.Format {
+	from := schema.Format(transmogrifyFrom)
+	if !from.IsValid() {
+		exitWithError(fmt.Sprintf("invalid source format: %s (valid: claude, opencode, copilot, cursor, windsurf, zed)", transmogrifyFrom))
+	}
+	return from
+}
+
 func transmogrifyLocal(path string, targetFormat schema.Format) {
 	info, err := os.Stat(path)
 	if err != nil {
@@ -128,8 +284,13 @@ func transmogrifyFile(path string, targetFormat schema.Format) {
 		return
 	}
 
-	// Parse (auto-detect format)
-	skill, err := schema.ParseAuto(content, path)
+	// Parse (auto-detect format unless --from names it)
+	var skill schema.Skill
+	if transmogrifyFrom != "" {
+		skill, err = schema.Parse(content, parseFromFormat())
+	} else {
+		skill, err = schema.ParseAuto(content, path)
+	}
 	if err != nil {
 		exitWithError(fmt.Sprintf("failed to parse: %v", err))
 	}
@@ -152,6 +313,9 @@ func transmogrifyFile(path string, targetFormat schema.Format) {
 	if transmogrifyDryRun {
 		fmt.Println(ui.Muted.Render("  [dry-run] Would convert:"))
 		fmt.Println(ui.Muted.Render(fmt.Sprintf("    %s → %s", result.SourceFormat, result.TargetFormat)))
+		if transmogrifyInPlace {
+			fmt.Println(ui.Muted.Render(fmt.Sprintf("    %s → %s", path, inPlacePath(path, skill, targetFormat))))
+		}
 		fmt.Println()
 		fmt.Println(ui.SuccessLine("Dry run complete"))
 		fmt.Println(ui.PageFooter())
@@ -159,7 +323,13 @@ func transmogrifyFile(path string, targetFormat schema.Format) {
 	}
 
 	// Output
-	if transmogrifyOutput == "" {
+	if transmogrifyInPlace {
+		outPath := inPlacePath(path, skill, targetFormat)
+		if err := replaceInPlace(path, outPath, result.Content); err != nil {
+			exitWithError(fmt.Sprintf("failed to convert in place: %v", err))
+		}
+		fmt.Println(ui.SuccessLine(fmt.Sprintf("Replaced %s with %s", path, outPath)))
+	} else if transmogrifyOutput == "" {
 		// Print to stdout
 		fmt.Println(ui.Muted.Render("  Output:"))
 		fmt.Println()
@@ -219,7 +389,7 @@ func transmogrifyMCPFile(path string, content []byte, targetFormat schema.Format
 	fmt.Println()
 
 	// Convert
-	result, err := schema.ConvertMCPWithInfo(config, targetFormat)
+	result, err := schema.ConvertMCPWithOptions(config, targetFormat, mcpConversionOptions(path))
 	if err != nil {
 		exitWithError(fmt.Sprintf("conversion failed: %v", err))
 	}
@@ -232,6 +402,9 @@ func transmogrifyMCPFile(path string, content []byte, targetFormat schema.Format
 	if transmogrifyDryRun {
 		fmt.Println(ui.Muted.Render("  [dry-run] Would convert:"))
 		fmt.Println(ui.Muted.Render(fmt.Sprintf("    %s → %s (%d servers)", result.SourceFormat, result.TargetFormat, result.ServerCount)))
+		if transmogrifyInPlace {
+			fmt.Println(ui.Muted.Render(fmt.Sprintf("    %s → %s", path, inPlaceMCPPath(path, config.GetFormat(), targetFormat))))
+		}
 		fmt.Println()
 		fmt.Println(ui.SuccessLine("Dry run complete"))
 		fmt.Println(ui.PageFooter())
@@ -239,7 +412,13 @@ func transmogrifyMCPFile(path string, content []byte, targetFormat schema.Format
 	}
 
 	// Output
-	if transmogrifyOutput == "" {
+	if transmogrifyInPlace {
+		outPath := inPlaceMCPPath(path, config.GetFormat(), targetFormat)
+		if err := replaceInPlace(path, outPath, result.Content); err != nil {
+			exitWithError(fmt.Sprintf("failed to convert in place: %v", err))
+		}
+		fmt.Println(ui.SuccessLine(fmt.Sprintf("Replaced %s with %s", path, outPath)))
+	} else if transmogrifyOutput == "" {
 		// Print to stdout
 		fmt.Println(ui.Muted.Render("  Output:"))
 		fmt.Println()
@@ -247,10 +426,8 @@ func transmogrifyMCPFile(path string, content []byte, targetFormat schema.Format
 	} else {
 		// Write to file
 		outDir := filepath.Join(transmogrifyOutput, schema.MCPOutputDirectory(targetFormat))
-		if outDir != transmogrifyOutput {
-			if err := os.MkdirAll(outDir, 0755); err != nil {
-				exitWithError(fmt.Sprintf("failed to create output directory: %v", err))
-			}
+		if err := os.MkdirAll(outDir, 0755); err != nil {
+			exitWithError(fmt.Sprintf("failed to create output directory: %v", err))
 		}
 
 		outPath := filepath.Join(outDir, schema.MCPOutputFilename(targetFormat))
@@ -272,6 +449,16 @@ func transmogrifyMCPFile(path string, content []byte, targetFormat schema.Format
 	fmt.Println(ui.PageFooter())
 }
 
+// mcpConversionOptions builds conversion options for the MCP config at path
+func mcpConversionOptions(path string) schema.MCPConversionOptions {
+	return schema.MCPConversionOptions{
+		InlineEnvFiles: transmogrifyEnv,
+		BaseDir:        filepath.Dir(path),
+		VerifyCommands: transmogrifyVerify,
+		SecretInputs:   transmogrifySecret,
+	}
+}
+
 func transmogrifyDirectory(path string, targetFormat schema.Format) {
 	fmt.Println(ui.InfoLine(fmt.Sprintf("Source: %s/", path)))
 	fmt.Println(ui.InfoLine(fmt.Sprintf("Target: %s", targetFormat)))
@@ -332,7 +519,7 @@ func transmogrifyDirectory(path string, targetFormat schema.Format) {
 				continue
 			}
 
-			mcpResult, err := schema.ConvertMCPWithInfo(mcpConfig, targetFormat)
+			mcpResult, err := schema.ConvertMCPWithOptions(mcpConfig, targetFormat, mcpConversionOptions(file))
 			if err != nil {
 				fmt.Println(ui.Warning.Render(fmt.Sprintf("  ! %s: %v", filepath.Base(file), err)))
 				failed++
@@ -340,6 +527,9 @@ func transmogrifyDirectory(path string, targetFormat schema.Format) {
 			}
 
 			outFilename := schema.MCPOutputFilename(targetFormat)
+			if transmogrifyInPlace {
+				outFilename = inPlaceMCPPath(file, mcpConfig.GetFormat(), targetFormat)
+			}
 
 			if transmogrifyDryRun {
 				fmt.Printf("  %s %s → %s (%d servers)\n",
@@ -351,17 +541,19 @@ func transmogrifyDirectory(path string, targetFormat schema.Format) {
 				continue
 			}
 
-			if transmogrifyOutput != "" {
-				outDir := filepath.Join(transmogrifyOutput, schema.MCPOutputDirectory(targetFormat))
-				if outDir != transmogrifyOutput && outDir != "" {
-					if err := os.MkdirAll(outDir, 0755); err != nil {
-						fmt.Println(ui.Warning.Render(fmt.Sprintf("  ! %s: %v", filepath.Base(file), err)))
-						failed++
-						continue
-					}
+			if transmogrifyInPlace {
+				if err := replaceInPlace(file, outFilename, mcpResult.Content); err != nil {
+					fmt.Println(ui.Warning.Render(fmt.Sprintf("  ! %s: %v", filepath.Base(file), err)))
+					failed++
+					continue
 				}
-				if outDir == "" {
-					outDir = transmogrifyOutput
+				fmt.Printf("  %s %s → %s\n", ui.Success.Render("✓"), relPath, outFilename)
+			} else if transmogrifyOutput != "" {
+				outDir := filepath.Join(transmogrifyOutput, schema.MCPOutputDirectory(targetFormat))
+				if err := os.MkdirAll(outDir, 0755); err != nil {
+					fmt.Println(ui.Warning.Render(fmt.Sprintf("  ! %s: %v", filepath.Base(file), err)))
+					failed++
+					continue
 				}
 
 				outPath := filepath.Join(outDir, outFilename)
@@ -397,16 +589,28 @@ func transmogrifyDirectory(path string, targetFormat schema.Format) {
 			continue
 		}
 
+		outFilename := schema.OutputFilename(skill, targetFormat)
+		if transmogrifyInPlace {
+			outFilename = inPlacePath(file, skill, targetFormat)
+		}
+
 		if transmogrifyDryRun {
 			fmt.Printf("  %s %s → %s\n",
 				ui.Success.Render("✓"),
 				relPath,
-				schema.OutputFilename(skill, targetFormat))
+				outFilename)
 			converted++
 			continue
 		}
 
-		if transmogrifyOutput != "" {
+		if transmogrifyInPlace {
+			if err := replaceInPlace(file, outFilename, result.Content); err != nil {
+				fmt.Println(ui.Warning.Render(fmt.Sprintf("  ! %s: %v", skill.GetName(), err)))
+				failed++
+				continue
+			}
+			fmt.Printf("  %s %s → %s\n", ui.Success.Render("✓"), relPath, outFilename)
+		} else if transmogrifyOutput != "" {
 			outDir := filepath.Join(transmogrifyOutput, schema.OutputDirectory(skill, targetFormat))
 			if err := os.MkdirAll(outDir, 0755); err != nil {
 				fmt.Println(ui.Warning.Render(fmt.Sprintf("  ! %s: %v", skill.GetName(), err)))
@@ -448,7 +652,10 @@ func transmogrifyGitHub(src *source.Source, targetFormat schema.Format) {
 	fmt.Println(ui.InfoLine(fmt.Sprintf("Target: %s", targetFormat)))
 	fmt.Println()
 
-	client := fetch.NewClient()
+	client := newFetchClient()
+	if transmogrifyToken != "" {
+		client.SetToken(transmogrifyToken)
+	}
 	apiURL := src.GitHubAPIURL()
 
 	fmt.Println(ui.Muted.Render("  Scanning repository..."))
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/schema"
+)
+
+func TestTransmogrifyFile_ClaudeMCPToOpenCode(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, ".mcp.json")
+	input := `{
+  "mcpServers": {
+    "filesystem": {
+      "command": "npx",
+      "args": ["-y", "@modelcontextprotocol/server-filesystem", "/tmp"],
+      "env": {"DEBUG": "true"}
+    }
+  }
+}`
+	if err := os.WriteFile(src, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(dir, "out")
+	transmogrifyOutput, transmogrifyDryRun, transmogrifyForce = outDir, false, false
+	t.Cleanup(func() { transmogrifyOutput = "" })
+
+	transmogrifyFile(src, schema.FormatOpenCode)
+
+	outPath := filepath.Join(outDir, schema.MCPOutputDirectory(schema.FormatOpenCode), schema.MCPOutputFilename(schema.FormatOpenCode))
+	content, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("expected %s to be written: %v", outPath, err)
+	}
+
+	var parsed schema.OpenCodeMCPConfig
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("output is not OpenCode JSON: %v", err)
+	}
+	server := parsed.MCP["filesystem"]
+	if server == nil {
+		t.Fatalf("missing filesystem server in %s", content)
+	}
+	if server.Type != "local" {
+		t.Errorf("type = %q, want local", server.Type)
+	}
+	if len(server.Command) != 4 || server.Command[0] != "npx" {
+		t.Errorf("command = %v, want npx with 3 args", server.Command)
+	}
+	if server.Environment["DEBUG"] != "true" {
+		t.Errorf("environment DEBUG = %q, want true", server.Environment["DEBUG"])
+	}
+}
+
+func TestTransmogrifyStdin(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	transmogrifyCmd.SetIn(bytes.NewBufferString("---\nname: pdf\ndescription: Work with PDFs\nallowed-tools: [Read]\n---\n# PDF\n\nUse pdftotext.\n"))
+	transmogrifyCmd.SetOut(&stdout)
+	transmogrifyCmd.SetErr(&stderr)
+	transmogrifyFrom, transmogrifyTo = "claude", "copilot"
+	t.Cleanup(func() {
+		transmogrifyCmd.SetIn(nil)
+		transmogrifyCmd.SetOut(nil)
+		transmogrifyCmd.SetErr(nil)
+		transmogrifyFrom, transmogrifyTo = "", ""
+	})
+
+	runTransmogrify(transmogrifyCmd, []string{"-"})
+
+	out := stdout.String()
+	if got := schema.DetectFormat("pdf.agent.md", stdout.Bytes()); got != schema.FormatCopilot {
+		t.Errorf("output format = %s, want copilot:\n%s", got, out)
+	}
+	if !strings.HasPrefix(out, "---\n") || !strings.Contains(out, "description: Work with PDFs") || !strings.Contains(out, "Use pdftotext.") {
+		t.Errorf("stdout = %q, want only the converted agent", out)
+	}
+	if !strings.Contains(stderr.String(), "allowed-tools") {
+		t.Errorf("stderr = %q, want the dropped allowed-tools warning", stderr.String())
+	}
+}
+
+func TestTransmogrifyFile_InPlace(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "agents", "pdf.agent.md")
+	if err := os.MkdirAll(filepath.Dir(src), 0755); err != nil {
+		t.Fatal(err)
+	}
+	input := "---\nname: pdf\ndescription: Work with PDFs\n---\n# PDF\n\nUse pdftotext.\n"
+	if err := os.WriteFile(src, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	transmogrifyInPlace, transmogrifyForce = true, true
+	t.Cleanup(func() { transmogrifyInPlace, transmogrifyForce = false, false })
+
+	transmogrifyFile(src, schema.FormatClaude)
+
+	outPath := filepath.Join(dir, "skills", "pdf", "SKILL.md")
+	content, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("expected %s to be written: %v", outPath, err)
+	}
+	if got := schema.DetectFormat(outPath, content); got != schema.FormatClaude {
+		t.Errorf("output format = %s, want claude:\n%s", got, content)
+	}
+	if !strings.Contains(string(content), "Use pdftotext.") {
+		t.Errorf("output lost the body:\n%s", content)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Errorf("original %s still exists (err = %v)", src, err)
+	}
+}
+
+func TestInPlacePath(t *testing.T) {
+	agent, err := schema.ParseAuto([]byte("---\nname: pdf\ndescription: Work with PDFs\n---\nBody\n"), "pdf.agent.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	skill, err := schema.ParseAuto([]byte("---\nname: pdf\ndescription: Work with PDFs\n---\nBody\n"), "SKILL.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		src   string
+		skill schema.Skill
+		to    schema.Format
+		want  string
+	}{
+		{"agent to skill", "repo/agents/pdf.agent.md", agent, schema.FormatClaude, "repo/skills/pdf/SKILL.md"},
+		{"agent outside layout", "repo/docs/pdf.agent.md", agent, schema.FormatClaude, "repo/docs/skills/pdf/SKILL.md"},
+		{"skill to agent", "repo/skills/pdf-tools/SKILL.md", skill, schema.FormatCopilot, "repo/agents/pdf.agent.md"},
+		{"skill to cursor", "repo/skills/pdf/SKILL.md", skill, schema.FormatCursor, "repo/.cursor/rules/pdf.md"},
+		{"same format", "repo/skills/pdf/SKILL.md", skill, schema.FormatClaude, "repo/skills/pdf/SKILL.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := inPlacePath(filepath.FromSlash(tt.src), tt.skill, tt.to)
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("inPlacePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
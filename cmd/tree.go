@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/schema"
+	"github.com/kennyg/tome/internal/source"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var treeCmd = &cobra.Command{
+	Use:     "tree <source>",
+	Aliases: []string{"survey"},
+	Short:   "Show what learning a source would install",
+	Long: `Survey a source's artifacts without installing anything.
+
+Runs the same discovery as learn (plugins and marketplaces included) and
+prints what it finds grouped by type, with the format each file is in. Only
+directory listings are fetched, not the artifacts themselves, so names are
+the directory or file names learn starts from.
+
+Sources can be:
+  owner/repo              GitHub repository
+  owner/repo:path         Specific path in a repo
+  ./local/path            Local directory
+
+Examples:
+  tome tree anthropics/skills
+  tome tree owner/marketplace            # Each plugin's artifacts
+  tome tree owner/repo --json            # For scripts and agents`,
+	Args: cobra.ExactArgs(1),
+	Run:  runTree,
+}
+
+var treeJSON bool
+
+func init() {
+	treeCmd.Flags().BoolVar(&treeJSON, "json", false, "Output as JSON")
+}
+
+// treeArtifact is one artifact learn would install
+type treeArtifact struct {
+	Name   string        `json:"name"`
+	Type   artifact.Type `json:"type"`
+	Path   string        `json:"path"`
+	Format schema.Format `json:"format"`
+}
+
+// treePlugin is a plugin a marketplace lists. Artifacts are only surveyed
+// for plugins inside the marketplace's repo.
+type treePlugin struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Source      string         `json:"source"`
+	Artifacts   []treeArtifact `json:"artifacts"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// treeResult is what tome tree found, and its --json output
+type treeResult struct {
+	Source    string         `json:"source"`
+	Kind      string         `json:"kind"` // "collection", "plugin" or "marketplace"
+	Artifacts []treeArtifact `json:"artifacts"`
+	Plugins   []treePlugin   `json:"plugins,omitempty"`
+}
+
+// treeTypeOrder is the order artifact groups are shown in
+var treeTypeOrder = []artifact.Type{artifact.TypeSkill, artifact.TypeCommand, artifact.TypeAgent, artifact.TypePrompt, artifact.TypeHook}
+
+func runTree(cmd *cobra.Command, args []string) {
+	src, err := source.Parse(args[0])
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	// Keep stdout clean for JSON; resolving a range reports on stderr
+	if treeJSON {
+		rootCmd.SetOut(os.Stderr)
+		defer rootCmd.SetOut(nil)
+	}
+	client := newFetchClient()
+	resolveSourceRange(client, src)
+
+	var result *treeResult
+	switch {
+	case src.Type == source.TypeLocal:
+		result, err = surveyLocal(src)
+	case src.Type == source.TypeRepo && src.IsGitHub():
+		result, err = surveyGitHub(client, src, src.GitHubAPIURL())
+	default:
+		err = fmt.Errorf("tree needs artifact discovery, which only GitHub repos and local directories support; try tome peek %s", src.String())
+	}
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	if treeJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			exitWithError("Failed to encode JSON: " + err.Error())
+		}
+		fmt.Println(string(data))
+		return
+	}
+	displayTree(result)
+}
+
+// surveyGitHub lists what learn would install from the GitHub directory at
+// apiURL: a marketplace's plugins, or a plugin's or collection's artifacts
+func surveyGitHub(client *fetch.Client, src *source.Source, apiURL string) (*treeResult, error) {
+	result := &treeResult{Source: src.String(), Kind: "collection", Artifacts: []treeArtifact{}}
+
+	if src.Path != "" && fetch.IsMarkdownFile(src.Path) {
+		item := fetch.GitHubContent{Name: path.Base(src.Path), Path: src.Path, Type: "file"}
+		result.Artifacts = treeArtifacts([]fetch.GitHubContent{item}, "", src.Repo)
+		return result, nil
+	}
+
+	if client.IsMarketplace(apiURL) {
+		marketplace, err := client.FetchMarketplace(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch marketplace: %w", err)
+		}
+		result.Kind = "marketplace"
+		for _, entry := range marketplace.Plugins {
+			result.Plugins = append(result.Plugins, surveyMarketplacePlugin(client, src, marketplace, entry))
+		}
+		return result, nil
+	}
+	if client.IsPlugin(apiURL) {
+		result.Kind = "plugin"
+	}
+
+	items, err := client.FindArtifacts(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan source: %w", err)
+	}
+	result.Artifacts = treeArtifacts(items, src.Path, path.Base("/"+src.Repo+"/"+src.Path))
+	return result, nil
+}
+
+// surveyMarketplacePlugin lists a marketplace plugin's artifacts when it
+// lives in the marketplace's repo; other sources are only named
+func surveyMarketplacePlugin(client *fetch.Client, src *source.Source, m *artifact.Marketplace, entry artifact.MarketplacePlugin) treePlugin {
+	plugin := treePlugin{Name: entry.Name, Description: entry.Description, Artifacts: []treeArtifact{}}
+
+	pluginSrc, err := marketplacePluginSource(src, m, entry)
+	if err != nil {
+		plugin.Error = err.Error()
+		return plugin
+	}
+	plugin.Source = pluginSrc.String()
+	if !pluginSrc.SameRepo(src) {
+		return plugin
+	}
+
+	items, err := client.FindArtifacts(pluginSrc.GitHubAPIURL())
+	if err != nil {
+		plugin.Error = err.Error()
+		return plugin
+	}
+	plugin.Artifacts = treeArtifacts(items, pluginSrc.Path, entry.Name)
+	return plugin
+}
+
+// surveyLocal lists what learn would install from a local directory
+func surveyLocal(src *source.Source) (*treeResult, error) {
+	info, err := os.Stat(src.Path)
+	if err != nil {
+		return nil, err
+	}
+	result := &treeResult{Source: src.String(), Kind: "collection", Artifacts: []treeArtifact{}}
+	if !info.IsDir() {
+		item := fetch.GitHubContent{Name: filepath.Base(src.Path), Path: filepath.Base(src.Path), Type: "file"}
+		result.Artifacts = treeArtifacts([]fetch.GitHubContent{item}, "", filepath.Base(filepath.Dir(src.Path)))
+		return result, nil
+	}
+
+	files, err := fetch.FindLocalArtifacts(src.Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var items []fetch.GitHubContent
+	for _, file := range files {
+		rel, err := filepath.Rel(src.Path, file)
+		if err != nil {
+			continue
+		}
+		items = append(items, fetch.GitHubContent{Name: filepath.Base(file), Path: filepath.ToSlash(rel), Type: "file"})
+	}
+	result.Artifacts = treeArtifacts(items, "", filepath.Base(src.Path))
+	return result, nil
+}
+
+// treeArtifacts describes discovered artifacts from their listings alone.
+// Paths are shown relative to base, the directory surveyed; a SKILL.md at its
+// root is named rootName.
+func treeArtifacts(items []fetch.GitHubContent, base, rootName string) []treeArtifact {
+	artifacts := []treeArtifact{}
+	for _, item := range items {
+		rel := strings.TrimPrefix(strings.TrimPrefix(item.Path, base), "/")
+		t := discoveredType(rel)
+		if t == artifact.TypeSkill && item.SkillDir == "" {
+			item.SkillDir = path.Dir(rel)
+			if item.SkillDir == "." {
+				item.SkillDir = rootName
+			}
+		}
+		name := discoveredName(item)
+		if t == artifact.TypeHook {
+			name = strings.TrimSuffix(item.Name, path.Ext(item.Name))
+		}
+		artifacts = append(artifacts, treeArtifact{
+			Name:   name,
+			Type:   t,
+			Path:   rel,
+			Format: schema.DetectFormat(rel, nil),
+		})
+	}
+	return artifacts
+}
+
+// discoveredType classifies a discovered artifact by where it sits: skills
+// by their SKILL.md, agents, prompts and hooks by their directory, and any
+// other markdown as a command
+func discoveredType(rel string) artifact.Type {
+	if strings.EqualFold(path.Base(rel), artifact.SkillFilename) {
+		return artifact.TypeSkill
+	}
+	for _, dir := range strings.Split(path.Dir(rel), "/") {
+		switch dir {
+		case artifact.AgentsDirName:
+			return artifact.TypeAgent
+		case artifact.PromptsDirName:
+			return artifact.TypePrompt
+		case artifact.HooksDirName:
+			return artifact.TypeHook
+		}
+	}
+	return artifact.TypeCommand
+}
+
+// displayTree prints a survey grouped by type, or by plugin for a marketplace
+func displayTree(result *treeResult) {
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Surveying", 56))
+	fmt.Println()
+	fmt.Println(ui.InfoLine("Source: " + result.Source))
+	fmt.Println()
+
+	switch result.Kind {
+	case "marketplace":
+		fmt.Println(ui.PluginBadge() + "  " + ui.Info.Render(fmt.Sprintf("Marketplace listing %d plugin(s)", len(result.Plugins))))
+		fmt.Println()
+		for _, p := range result.Plugins {
+			fmt.Println("  " + ui.Highlight.Render(p.Name) + ui.Muted.Render("  "+p.Source))
+			if p.Description != "" {
+				fmt.Println(ui.Muted.Render("    " + ui.Truncate(p.Description, 60)))
+			}
+			switch {
+			case p.Error != "":
+				fmt.Println(ui.Warning.Render("    " + p.Error))
+			case len(p.Artifacts) > 0:
+				displayTreeGroups(p.Artifacts, "    ")
+			}
+			fmt.Println()
+		}
+	default:
+		if result.Kind == "plugin" {
+			fmt.Println(ui.PluginBadge() + "  " + ui.Info.Render("Plugin detected"))
+			fmt.Println()
+		}
+		if len(result.Artifacts) == 0 {
+			fmt.Println(ui.Muted.Render("  No artifacts found"))
+			fmt.Println(ui.PageFooter())
+			return
+		}
+		displayTreeGroups(result.Artifacts, "  ")
+		fmt.Println()
+	}
+
+	fmt.Println(ui.Dim.Render(fmt.Sprintf("  Run `tome learn %s` to install", result.Source)))
+	fmt.Println(ui.PageFooter())
+}
+
+// displayTreeGroups prints artifacts as a tree under one heading per type
+func displayTreeGroups(artifacts []treeArtifact, indent string) {
+	for _, t := range treeTypeOrder {
+		var group []treeArtifact
+		for _, a := range artifacts {
+			if a.Type == t {
+				group = append(group, a)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		fmt.Printf("%s%s %s\n", indent, getBadge(t), ui.Muted.Render(fmt.Sprintf("%d %s(s)", len(group), t)))
+		for i, a := range group {
+			branch := "├─"
+			if i == len(group)-1 {
+				branch = "└─"
+			}
+			fmt.Printf("%s%s %s %s\n", indent, ui.Muted.Render(branch), ui.Highlight.Render(a.Name), ui.Muted.Render(fmt.Sprintf("%s (%s)", a.Path, a.Format)))
+		}
+	}
+}
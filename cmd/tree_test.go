@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/schema"
+	"github.com/kennyg/tome/internal/source"
+)
+
+func TestTreeArtifacts(t *testing.T) {
+	items := []fetch.GitHubContent{
+		{Name: "SKILL.md", Path: "tools/SKILL.md"},
+		{Name: "SKILL.md", Path: "tools/skills/pdf/SKILL.md", SkillDir: "skills/pdf"},
+		{Name: "commit.md", Path: "tools/commands/commit.md"},
+		{Name: "reviewer.md", Path: "tools/.claude/agents/reviewer.md"},
+		{Name: "review.prompt.md", Path: "tools/.github/prompts/review.prompt.md"},
+		{Name: "pre-compact.sh", Path: "tools/hooks/pre-compact.sh"},
+	}
+
+	got := treeArtifacts(items, "tools", "tools")
+	want := []treeArtifact{
+		{Name: "tools", Type: artifact.TypeSkill, Path: "SKILL.md", Format: schema.FormatClaude},
+		{Name: "pdf", Type: artifact.TypeSkill, Path: "skills/pdf/SKILL.md", Format: schema.FormatClaude},
+		{Name: "commit", Type: artifact.TypeCommand, Path: "commands/commit.md", Format: schema.FormatClaude},
+		{Name: "reviewer", Type: artifact.TypeAgent, Path: ".claude/agents/reviewer.md", Format: schema.FormatClaude},
+		{Name: "review-prompt", Type: artifact.TypePrompt, Path: ".github/prompts/review.prompt.md", Format: schema.FormatCopilot},
+		{Name: "pre-compact", Type: artifact.TypeHook, Path: "hooks/pre-compact.sh", Format: schema.FormatClaude},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("treeArtifacts() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("treeArtifacts()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSurveyGitHub(t *testing.T) {
+	var srv *httptest.Server
+	entry := func(name, typ, path string) string {
+		return fmt.Sprintf(`{"name": %q, "type": %q, "path": %q, "download_url": "%s/raw/%s"}`, name, typ, path, srv.URL, path)
+	}
+	listings := map[string]func() []string{
+		"/repo": func() []string {
+			return []string{entry(".claude-plugin", "dir", ".claude-plugin"), entry("skills", "dir", "skills"), entry("commands", "dir", "commands"), entry("README.md", "file", "README.md")}
+		},
+		"/repo/.claude-plugin": func() []string { return []string{entry("plugin.json", "file", ".claude-plugin/plugin.json")} },
+		"/repo/skills":         func() []string { return []string{entry("pdf", "dir", "skills/pdf")} },
+		"/repo/skills/pdf":     func() []string { return []string{entry("SKILL.md", "file", "skills/pdf/SKILL.md")} },
+		"/repo/commands":       func() []string { return []string{entry("commit.md", "file", "commands/commit.md")} },
+	}
+	var fetched []string
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if listing, ok := listings[r.URL.Path]; ok {
+			w.Write([]byte("[" + strings.Join(listing(), ",") + "]"))
+			return
+		}
+		fetched = append(fetched, r.URL.Path)
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	src := &source.Source{Type: source.TypeRepo, Owner: "acme", Repo: "tools"}
+	result, err := surveyGitHub(fetch.NewClient(), src, srv.URL+"/repo")
+	if err != nil {
+		t.Fatalf("surveyGitHub() error = %v", err)
+	}
+
+	if result.Kind != "plugin" {
+		t.Errorf("kind = %q, want plugin", result.Kind)
+	}
+	var names []string
+	for _, a := range result.Artifacts {
+		names = append(names, fmt.Sprintf("%s:%s", a.Type, a.Name))
+	}
+	if strings.Join(names, ",") != "skill:pdf,command:commit" {
+		t.Errorf("artifacts = %v, want the skill and the command", names)
+	}
+	for _, path := range fetched {
+		if strings.HasPrefix(path, "/raw/skills") || strings.HasPrefix(path, "/raw/commands") {
+			t.Errorf("surveying downloaded %s; it should only list directories", path)
+		}
+	}
+}
+
+func TestSurveyLocal(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my-skill")
+	files := map[string]string{
+		"SKILL.md":          "---\nname: my-skill\n---\n",
+		"commands/greet.md": "Say hello.\n",
+		"docs/guide.md":     "Not an artifact.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := surveyLocal(&source.Source{Type: source.TypeLocal, Path: dir})
+	if err != nil {
+		t.Fatalf("surveyLocal() error = %v", err)
+	}
+	want := []treeArtifact{
+		{Name: "my-skill", Type: artifact.TypeSkill, Path: "SKILL.md", Format: schema.FormatClaude},
+		{Name: "greet", Type: artifact.TypeCommand, Path: "commands/greet.md", Format: schema.FormatClaude},
+	}
+	if len(result.Artifacts) != len(want) {
+		t.Fatalf("artifacts = %+v, want %+v", result.Artifacts, want)
+	}
+	for i := range want {
+		if result.Artifacts[i] != want[i] {
+			t.Errorf("artifacts[%d] = %+v, want %+v", i, result.Artifacts[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/detect"
+	"github.com/kennyg/tome/internal/fetch"
+	"github.com/kennyg/tome/internal/schema"
+	"github.com/kennyg/tome/internal/source"
+	"github.com/kennyg/tome/internal/ui"
+)
+
+var verifySourceCmd = &cobra.Command{
+	Use:     "verify-source <source>",
+	Aliases: []string{"audit"},
+	Short:   "Audit what a source would install, without installing it",
+	Long: `Fetch a source's artifacts and report what learning it would do, writing
+nothing. For each skill and command it shows:
+
+  - The type and format it's detected as
+  - The setup requirements found in it (packages, commands, env vars)
+  - Includes tome refuses to install: absolute paths, .. traversal, and
+    disallowed file types
+  - Anything that fails to parse
+
+Exits with status 1 when an include is rejected or a file can't be read or
+parsed, so a cautious script can audit before learning.
+
+Examples:
+  tome verify-source owner/repo
+  tome verify-source ./downloaded-skills
+  tome verify-source owner/repo --json && tome learn owner/repo`,
+	Args: cobra.ExactArgs(1),
+	Run:  runVerifySource,
+}
+
+var verifySourceJSON bool
+
+func init() {
+	verifySourceCmd.Flags().BoolVar(&verifySourceJSON, "json", false, "Output the audit as JSON")
+}
+
+// verifiedFile is what auditing one artifact file found
+type verifiedFile struct {
+	Path             string               `json:"path"`
+	Name             string               `json:"name,omitempty"`
+	Type             artifact.Type        `json:"type,omitempty"`
+	Format           schema.Format        `json:"format,omitempty"`
+	Requirements     []detect.Requirement `json:"requirements"`
+	RejectedIncludes []string             `json:"rejected_includes"`
+	Errors           []string             `json:"errors"`
+}
+
+// problems counts what makes a file unsafe or unusable to install
+func (f verifiedFile) problems() int {
+	return len(f.RejectedIncludes) + len(f.Errors)
+}
+
+// verifyReport is tome verify-source's result, and its --json output
+type verifyReport struct {
+	Source   string         `json:"source"`
+	Problems int            `json:"problems"`
+	Files    []verifiedFile `json:"files"`
+}
+
+func runVerifySource(cmd *cobra.Command, args []string) {
+	src, err := source.Parse(args[0])
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	// Keep stdout clean for JSON; resolving a range reports on stderr
+	if verifySourceJSON {
+		rootCmd.SetOut(os.Stderr)
+		defer rootCmd.SetOut(nil)
+	}
+	client := newFetchClient()
+	resolveSourceRange(client, src)
+
+	report, err := verifySource(client, src)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	if verifySourceJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			exitWithError("Failed to encode JSON: " + err.Error())
+		}
+		fmt.Println(string(data))
+	} else {
+		displayVerifyReport(report)
+	}
+
+	if report.Problems > 0 {
+		os.Exit(1)
+	}
+}
+
+// verifySource fetches the artifact files learn would install from src and
+// audits each one
+func verifySource(client *fetch.Client, src *source.Source) (*verifyReport, error) {
+	targets, err := collectLintTargets(client, src)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &verifyReport{Source: src.String(), Files: []verifiedFile{}}
+	for _, t := range targets {
+		file := verifyFile(t)
+		report.Problems += file.problems()
+		report.Files = append(report.Files, file)
+	}
+	return report, nil
+}
+
+// verifyFile runs one file through the parsing and requirement detection
+// learn would, recording the includes ValidateIncludePath rejects
+func verifyFile(t lintTarget) verifiedFile {
+	file := verifiedFile{Path: t.path, Requirements: []detect.Requirement{}, RejectedIncludes: []string{}, Errors: []string{}}
+	if t.err != nil {
+		file.Errors = append(file.Errors, t.err.Error())
+		return file
+	}
+	filename := filepath.Base(t.path)
+	file.Type = fetch.DetectArtifactType(filename)
+	if file.Type != artifact.TypeSkill {
+		file.Type = artifact.TypeCommand // As parseArtifact treats other markdown
+	}
+	file.Format = schema.DetectFormat(t.path, t.content)
+	file.Requirements = append(file.Requirements, detect.FromContent(string(t.content))...)
+
+	// Lint reports every include ValidateIncludePath rejects, where parsing
+	// stops at the first
+	for _, issue := range fetch.LintArtifact(t.content, filename) {
+		switch {
+		case issue.Field == "includes":
+			file.RejectedIncludes = append(file.RejectedIncludes, issue.Message)
+		case issue.Field == "frontmatter" && issue.Severity == fetch.LintError:
+			file.Errors = append(file.Errors, issue.Message)
+		}
+	}
+
+	art, err := parseArtifact(t.content, filename, t.path)
+	switch {
+	case err == nil:
+		file.Name = art.Name
+		file.Requirements = append(file.Requirements, detect.FromIncludes(art.Includes)...)
+	case len(file.RejectedIncludes) == 0:
+		file.Errors = append(file.Errors, err.Error())
+	}
+
+	var schemaErr error
+	switch file.Type {
+	case artifact.TypeSkill:
+		_, schemaErr = schema.ParseAuto(t.content, t.path)
+	case artifact.TypeCommand:
+		_, schemaErr = schema.ParseCommandAuto(t.content, t.path)
+	}
+	if schemaErr != nil {
+		file.Errors = append(file.Errors, fmt.Sprintf("%s format: %v", file.Format, schemaErr))
+	}
+	return file
+}
+
+func displayVerifyReport(report *verifyReport) {
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Auditing", 56))
+	fmt.Println()
+	fmt.Println(ui.InfoLine("Source: " + report.Source))
+	fmt.Println()
+
+	for _, file := range report.Files {
+		status := ui.Success.Render("✓")
+		if file.problems() > 0 {
+			status = ui.Error.Render("✗")
+		}
+		detail := ""
+		if file.Type != "" {
+			detail = ui.Muted.Render(fmt.Sprintf("  %s (%s)", file.Type, file.Format))
+			if file.Name != "" {
+				detail = ui.Muted.Render(fmt.Sprintf("  %s %s (%s)", file.Type, file.Name, file.Format))
+			}
+		}
+		fmt.Printf("  %s %s%s\n", status, ui.Highlight.Render(file.Path), detail)
+
+		for _, req := range file.Requirements {
+			fmt.Println(ui.Muted.Render(fmt.Sprintf("      requires %s: %s", req.Type, req.Label())))
+		}
+		for _, inc := range file.RejectedIncludes {
+			fmt.Println(ui.Error.Render("      rejected include: " + inc))
+		}
+		for _, msg := range file.Errors {
+			fmt.Println(ui.Error.Render("      error: " + msg))
+		}
+	}
+
+	fmt.Println()
+	summary := fmt.Sprintf("%d file(s), %d problem(s); nothing was installed", len(report.Files), report.Problems)
+	if report.Problems > 0 {
+		fmt.Println(ui.ErrorLine(summary))
+	} else {
+		fmt.Println(ui.SuccessLine(summary))
+		fmt.Println(ui.Dim.Render(fmt.Sprintf("  Run `tome learn %s` to install", report.Source)))
+	}
+	fmt.Println(ui.PageFooter())
+}
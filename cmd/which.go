@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+)
+
+var whichCmd = &cobra.Command{
+	Use:   "which <name>",
+	Short: "Print where an artifact is installed",
+	Long: `Print the install path of an artifact, and nothing else, for scripts.
+
+The project tome is searched before the global one, as the project's copy
+is the one in effect. Exits with status 1 when the artifact isn't installed.
+
+With --all, prints every installed copy of the artifact (each agent's, in
+both tomes), or with no name, the path of every installed artifact.
+
+Examples:
+  tome which commit
+  $EDITOR "$(tome which commit)"
+  tome which --all pdf       # Every copy of pdf
+  tome which --all --global  # Every artifact in the global tome`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if whichAll {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: runWhich,
+}
+
+var (
+	whichAll     bool
+	whichGlobal  bool
+	whichProject bool
+)
+
+func init() {
+	whichCmd.Flags().BoolVarP(&whichAll, "all", "a", false, "Print every installed copy, or every artifact with no name")
+	whichCmd.Flags().BoolVarP(&whichGlobal, "global", "g", false, "Search only the global tome")
+	whichCmd.Flags().BoolVarP(&whichProject, "project", "p", false, "Search only the project tome")
+	whichCmd.MarkFlagsMutuallyExclusive("global", "project")
+}
+
+func runWhich(cmd *cobra.Command, args []string) {
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	paths, err := whichPaths(whichStates(), name, whichAll)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	for _, path := range paths {
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+	}
+}
+
+// whichStates loads the tomes --global and --project select, the project's
+// first
+func whichStates() []*config.State {
+	agent := config.DefaultAgent()
+	if whichProject && !config.IsAttuned(agent) {
+		exitWithError("not attuned to this project; run 'tome attune' first")
+	}
+
+	var states []*config.State
+	load := func(paths *config.Paths, err error) {
+		if err != nil {
+			exitWithError(err.Error())
+		}
+		state, err := config.LoadState(paths.StateFile)
+		if err != nil {
+			exitWithError(err.Error())
+		}
+		states = append(states, state)
+	}
+	if !whichGlobal && config.IsAttuned(agent) {
+		load(config.GetLocalPaths(agent))
+	}
+	if !whichProject {
+		load(config.GetPaths())
+	}
+	return states
+}
+
+// whichPaths returns the install path of the artifact called name in the
+// first state that has it. With all, it returns the path of every copy in
+// every state instead, or of every artifact when name is empty.
+func whichPaths(states []*config.State, name string, all bool) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(a *artifact.InstalledArtifact) {
+		if a.LocalPath != "" && !seen[a.LocalPath] {
+			seen[a.LocalPath] = true
+			paths = append(paths, a.LocalPath)
+		}
+	}
+
+	var lookupErr error
+	for _, state := range states {
+		if name == "" {
+			for i := range state.Installed {
+				add(&state.Installed[i])
+			}
+			continue
+		}
+
+		found, err := state.LookupInstalled(name)
+		if err != nil {
+			if lookupErr == nil {
+				lookupErr = err
+			}
+			continue
+		}
+		if !all {
+			add(found)
+			return paths, nil
+		}
+		for i := range state.Installed {
+			if a := &state.Installed[i]; a.Name == found.Name && a.Type == found.Type {
+				add(a)
+			}
+		}
+	}
+
+	if len(paths) == 0 && name != "" {
+		if lookupErr == nil {
+			lookupErr = fmt.Errorf("artifact not found: %s", name)
+		}
+		return nil, lookupErr
+	}
+	return paths, nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/config"
+)
+
+func installedAt(name string, t artifact.Type, agent, path string) artifact.InstalledArtifact {
+	return artifact.InstalledArtifact{Artifact: artifact.Artifact{Name: name, Type: t}, Agent: agent, LocalPath: path}
+}
+
+func TestWhichPaths(t *testing.T) {
+	project := &config.State{Installed: []artifact.InstalledArtifact{
+		installedAt("commit", artifact.TypeCommand, "claude", "/proj/.claude/commands/commit.md"),
+	}}
+	global := &config.State{Installed: []artifact.InstalledArtifact{
+		installedAt("commit", artifact.TypeCommand, "claude", "/home/.claude/commands/commit.md"),
+		installedAt("commit", artifact.TypeCommand, "opencode", "/home/.opencode/command/commit.md"),
+		installedAt("pdf", artifact.TypeSkill, "claude", "/home/.claude/skills/pdf/SKILL.md"),
+	}}
+	states := []*config.State{project, global}
+
+	tests := []struct {
+		name string
+		arg  string
+		all  bool
+		want []string
+	}{
+		{"project copy in effect", "commit", false, []string{"/proj/.claude/commands/commit.md"}},
+		{"ignores case", "PDF", false, []string{"/home/.claude/skills/pdf/SKILL.md"}},
+		{"every copy", "commit", true, []string{"/proj/.claude/commands/commit.md", "/home/.claude/commands/commit.md", "/home/.opencode/command/commit.md"}},
+		{"every artifact", "", true, []string{"/proj/.claude/commands/commit.md", "/home/.claude/commands/commit.md", "/home/.opencode/command/commit.md", "/home/.claude/skills/pdf/SKILL.md"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := whichPaths(states, tt.arg, tt.all)
+			if err != nil {
+				t.Fatalf("whichPaths() error = %v", err)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("whichPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := whichPaths(states, "missing", false); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("whichPaths(missing) error = %v, want not found", err)
+	}
+	if _, err := whichPaths(states, "missing", true); err == nil {
+		t.Error("whichPaths(missing, all) succeeded, want not found")
+	}
+}
+
+func TestRunWhich_PlainOutput(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Chdir(t.TempDir())
+
+	paths, err := config.GetPaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed := filepath.Join(paths.CommandsDir, "commit.md")
+	state := &config.State{Installed: []artifact.InstalledArtifact{installedAt("commit", artifact.TypeCommand, "claude", installed)}}
+	if err := config.SaveState(paths.StateFile, state); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	whichCmd.SetOut(&stdout)
+	t.Cleanup(func() { whichCmd.SetOut(nil) })
+
+	runWhich(whichCmd, []string{"commit"})
+	if got := stdout.String(); got != installed+"\n" {
+		t.Errorf("stdout = %q, want only the path", got)
+	}
+}
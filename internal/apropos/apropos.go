@@ -13,8 +13,12 @@ import (
 	"github.com/kennyg/tome/internal/artifact"
 )
 
+// IndexVersion is bumped when the index format changes; older indexes are stale
+const IndexVersion = 2
+
 // Index holds the apropos index data
 type Index struct {
+	Version   int       `yaml:"version"`
 	Generated time.Time `yaml:"generated"`
 	Skills    []Skill   `yaml:"skills"`
 }
@@ -25,15 +29,37 @@ type Skill struct {
 	Path        string   `yaml:"path"`
 	Description string   `yaml:"description"`
 	Keywords    []string `yaml:"keywords"`
+	Tags        []string `yaml:"tags,omitempty"`
 	ModTime     int64    `yaml:"mod_time"`
 }
 
 // Frontmatter represents the YAML frontmatter of a SKILL.md
 type Frontmatter struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description"`
+	Tags        TagList `yaml:"tags"`
 }
 
+// TagList accepts tags as a YAML list or a comma-separated string
+type TagList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (t *TagList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = strings.Split(node.Value, ",")
+		return nil
+	}
+	var tags []string
+	if err := node.Decode(&tags); err != nil {
+		return err
+	}
+	*t = tags
+	return nil
+}
+
+// TagPrefix scopes a query term to skill tags (e.g. "tag:pdf")
+const TagPrefix = "tag:"
+
 const IndexFileName = ".apropos"
 
 // common stopwords to filter out when extracting keywords
@@ -80,23 +106,44 @@ func SaveIndex(skillsDir string, index *Index) error {
 	return os.WriteFile(indexPath, append([]byte(header), data...), 0644)
 }
 
-// IsStale checks if the index is stale (any SKILL.md newer than index)
-func IsStale(skillsDir string, index *Index) (bool, error) {
-	if index == nil {
+// IsStale checks if the index is stale (any SKILL.md in skillsDirs newer than index)
+func IsStale(skillsDirs []string, index *Index) (bool, error) {
+	if index == nil || index.Version < IndexVersion {
 		return true, nil
 	}
 
-	entries, err := os.ReadDir(skillsDir)
-	if err != nil {
-		return true, err
-	}
-
 	// Build a map of indexed skills by path for quick lookup
 	indexed := make(map[string]int64)
 	for _, s := range index.Skills {
 		indexed[s.Path] = s.ModTime
 	}
 
+	for _, skillsDir := range skillsDirs {
+		stale, err := staleIn(skillsDir, indexed)
+		if err != nil || stale {
+			return true, err
+		}
+	}
+
+	// If there are skills in the index that don't exist anymore, it's stale
+	if len(indexed) > 0 {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// staleIn checks one skills directory against the index, removing
+// up-to-date entries from indexed as it goes
+func staleIn(skillsDir string, indexed map[string]int64) (bool, error) {
+	entries, err := os.ReadDir(skillsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return true, err
+	}
+
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
@@ -122,17 +169,19 @@ func IsStale(skillsDir string, index *Index) (bool, error) {
 		delete(indexed, skillPath)
 	}
 
-	// If there are skills in the index that don't exist anymore, it's stale
-	if len(indexed) > 0 {
-		return true, nil
-	}
-
 	return false, nil
 }
 
 // BuildIndex scans skills directories and builds a fresh index
 func BuildIndex(skillsDirs []string) (*Index, error) {
+	return BuildIndexWithTags(skillsDirs, nil)
+}
+
+// BuildIndexWithTags builds a fresh index, merging extra tags (such as
+// collection tags recorded at install time) into skills by name
+func BuildIndexWithTags(skillsDirs []string, extraTags map[string][]string) (*Index, error) {
 	index := &Index{
+		Version:   IndexVersion,
 		Generated: time.Now(),
 		Skills:    []Skill{},
 	}
@@ -142,6 +191,9 @@ func BuildIndex(skillsDirs []string) (*Index, error) {
 		if err != nil {
 			continue // skip dirs that don't exist
 		}
+		for i := range skills {
+			skills[i].Tags = normalizeTags(append(skills[i].Tags, extraTags[skills[i].Name]...))
+		}
 		index.Skills = append(index.Skills, skills...)
 	}
 
@@ -195,6 +247,7 @@ func parseSkill(skillPath string) (*Skill, error) {
 		Path:        skillPath,
 		Description: frontmatter.Description,
 		Keywords:    keywords,
+		Tags:        normalizeTags(frontmatter.Tags),
 		ModTime:     info.ModTime().Unix(),
 	}, nil
 }
@@ -263,23 +316,46 @@ func extractKeywords(description string) []string {
 	return keywords
 }
 
+// normalizeTags lowercases, trims, and dedupes tags, keeping their order
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
 // SearchResult represents a search match
 type SearchResult struct {
 	Skill Skill
 	Score int // higher is better
 }
 
-// Search searches the index for skills matching the query
+// Search searches the index for skills matching the query.
+// Terms prefixed with "tag:" only match skills carrying that exact tag.
 func Search(index *Index, query string) []SearchResult {
 	if index == nil || len(index.Skills) == 0 {
 		return nil
 	}
 
-	queryWords := strings.Fields(strings.ToLower(query))
+	queryWords, tagFilters := parseQuery(query)
 	var results []SearchResult
 
 	for _, skill := range index.Skills {
+		if !hasTags(skill, tagFilters) {
+			continue
+		}
 		score := scoreMatch(skill, queryWords)
+		if len(queryWords) > 0 && score == 0 {
+			continue
+		}
+		score += 40 * len(tagFilters)
 		if score > 0 {
 			results = append(results, SearchResult{
 				Skill: skill,
@@ -300,6 +376,37 @@ func Search(index *Index, query string) []SearchResult {
 	return results
 }
 
+// parseQuery splits a query into plain search words and tag: filters
+func parseQuery(query string) (words []string, tags []string) {
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		if tag, ok := strings.CutPrefix(field, TagPrefix); ok {
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+			continue
+		}
+		words = append(words, field)
+	}
+	return words, tags
+}
+
+// hasTags reports whether the skill carries every one of tags
+func hasTags(skill Skill, tags []string) bool {
+	for _, want := range tags {
+		found := false
+		for _, tag := range skill.Tags {
+			if tag == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func scoreMatch(skill Skill, queryWords []string) int {
 	score := 0
 	nameLower := strings.ToLower(skill.Name)
@@ -318,6 +425,13 @@ func scoreMatch(skill Skill, queryWords []string) int {
 			score += 10
 		}
 
+		// Exact tag match ranks just below an exact name
+		for _, tag := range skill.Tags {
+			if tag == qw {
+				score += 30
+			}
+		}
+
 		// Keyword match
 		for _, kw := range skill.Keywords {
 			if kw == qw {
@@ -0,0 +1,137 @@
+package apropos
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeSkill(t *testing.T, skillsDir, name string) {
+	t.Helper()
+	dir := filepath.Join(skillsDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: " + name + "\ndescription: Skill " + name + "\n---\n"
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildIndex_MultipleDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalDir := filepath.Join(tmpDir, "global")
+	projectDir := filepath.Join(tmpDir, "project")
+	writeSkill(t, globalDir, "global-skill")
+	writeSkill(t, projectDir, "project-skill")
+
+	dirs := []string{globalDir, projectDir, filepath.Join(tmpDir, "missing")}
+	index, err := BuildIndex(dirs)
+	if err != nil {
+		t.Fatalf("BuildIndex() error = %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, s := range index.Skills {
+		found[s.Name] = true
+	}
+	if !found["global-skill"] || !found["project-skill"] {
+		t.Errorf("expected skills from every dir, got %v", found)
+	}
+
+	stale, err := IsStale(dirs, index)
+	if err != nil || stale {
+		t.Errorf("IsStale() = %v, %v; want fresh index across all dirs", stale, err)
+	}
+
+	// A new skill in a secondary dir makes the index stale
+	writeSkill(t, projectDir, "another-skill")
+	stale, err = IsStale(dirs, index)
+	if err != nil || !stale {
+		t.Errorf("IsStale() = %v, %v; want stale after adding project skill", stale, err)
+	}
+}
+
+func writeSkillContent(t *testing.T, skillsDir, name, frontmatter string) {
+	t.Helper()
+	dir := filepath.Join(skillsDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: " + name + "\n" + frontmatter + "---\n"
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildIndex_Tags(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeSkillContent(t, skillsDir, "pdf-tools", "description: Work with documents\ntags: [PDF, docs]\n")
+	writeSkillContent(t, skillsDir, "charts", "description: Make charts\ntags: viz, docs\n")
+	writeSkillContent(t, skillsDir, "plain", "description: No tags here\n")
+
+	extra := map[string][]string{"plain": {"Collection"}, "charts": {"docs"}}
+	index, err := BuildIndexWithTags([]string{skillsDir}, extra)
+	if err != nil {
+		t.Fatalf("BuildIndexWithTags() error = %v", err)
+	}
+
+	tags := make(map[string][]string)
+	for _, s := range index.Skills {
+		tags[s.Name] = s.Tags
+	}
+	want := map[string][]string{
+		"pdf-tools": {"pdf", "docs"},
+		"charts":    {"viz", "docs"},
+		"plain":     {"collection"},
+	}
+	for name, w := range want {
+		if !reflect.DeepEqual(tags[name], w) {
+			t.Errorf("%s tags = %v, want %v", name, tags[name], w)
+		}
+	}
+}
+
+func TestSearch_Tags(t *testing.T) {
+	index := &Index{Skills: []Skill{
+		{Name: "pdf-tools", Description: "Work with documents", Tags: []string{"pdf", "docs"}},
+		{Name: "charts", Description: "Make charts for pdf reports", Tags: []string{"viz", "docs"}},
+		{Name: "notes", Description: "Take notes"},
+	}}
+
+	names := func(results []SearchResult) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Skill.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"tag:pdf", []string{"pdf-tools"}},
+		{"TAG:docs", []string{"pdf-tools", "charts"}},
+		{"tag:docs tag:viz", []string{"charts"}},
+		{"tag:docs charts", []string{"charts"}},
+		{"tag:missing", nil},
+		// An exact tag match outranks a description mention
+		{"pdf", []string{"pdf-tools", "charts"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := names(Search(index, tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsStale_OldIndexVersion(t *testing.T) {
+	stale, err := IsStale(nil, &Index{Version: IndexVersion - 1})
+	if err != nil || !stale {
+		t.Errorf("IsStale() = %v, %v; want stale for an older index version", stale, err)
+	}
+}
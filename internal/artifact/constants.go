@@ -17,4 +17,10 @@ const (
 
 	// HooksDirName is the standard directory name for hooks
 	HooksDirName = "hooks"
+
+	// PromptsDirName is the standard directory name for prompts
+	PromptsDirName = "prompts"
+
+	// ManifestFilename is the collection manifest at the root of a tome
+	ManifestFilename = "tome.yaml"
 )
@@ -1,6 +1,10 @@
 package artifact
 
 import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/kennyg/tome/internal/detect"
@@ -28,7 +32,7 @@ type Artifact struct {
 	Author      string `yaml:"author,omitempty" json:"author,omitempty"`
 
 	// Source information
-	Source    string `yaml:"-" json:"source"`              // Where it was installed from
+	Source    string `yaml:"-" json:"source"`               // Where it was installed from
 	SourceURL string `yaml:"-" json:"source_url,omitempty"` // Original URL if applicable
 
 	// File information
@@ -41,7 +45,9 @@ type Artifact struct {
 
 	// Skill-specific fields
 	Globs    []string `yaml:"globs,omitempty" json:"globs,omitempty"`
-	Includes []string `yaml:"includes,omitempty" json:"includes,omitempty"` // Files installed with this skill
+	Includes []string `yaml:"includes,omitempty" json:"includes,omitempty"` // Files installed with this skill; once installed, those written, relative to the skill directory
+	Tags     []string `yaml:"tags,omitempty" json:"tags,omitempty"`         // From frontmatter, plus the collection's from its tome.yaml
+	Requires []string `yaml:"requires,omitempty" json:"requires,omitempty"` // Sources learn installs along with this skill
 
 	// Command-specific fields
 	Arguments []Argument `yaml:"arguments,omitempty" json:"arguments,omitempty"`
@@ -63,7 +69,8 @@ type ArtifactSummary struct {
 	Name        string `yaml:"name" json:"name"`
 	Type        Type   `yaml:"type" json:"type"`
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
-	Hash        string `yaml:"hash,omitempty" json:"hash,omitempty"` // sha256:... for integrity verification
+	Source      string `yaml:"source,omitempty" json:"source,omitempty"` // Where an exported artifact was originally installed from
+	Hash        string `yaml:"hash,omitempty" json:"hash,omitempty"`     // sha256:... for integrity verification
 }
 
 // Manifest represents the tome.yaml file in a repository
@@ -77,21 +84,45 @@ type Manifest struct {
 	Homepage    string   `yaml:"homepage,omitempty" json:"homepage,omitempty"`
 	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 
+	// Requires lists sources (as given to learn) the collection depends on;
+	// learn installs them too
+	Requires []string `yaml:"requires,omitempty" json:"requires,omitempty"`
+
 	// Optional custom paths (defaults: commands/, skills/)
 	CommandsDir string `yaml:"commands_dir,omitempty" json:"commands_dir,omitempty"`
 	SkillsDir   string `yaml:"skills_dir,omitempty" json:"skills_dir,omitempty"`
 
 	// Artifact index (written by 'tome bind --write')
 	Artifacts []ArtifactSummary `yaml:"artifacts,omitempty" json:"artifacts,omitempty"`
+
+	// Checksums maps artifact paths (or names) to the sha256 of their content
+	Checksums map[string]string `yaml:"checksums,omitempty" json:"checksums,omitempty"`
+}
+
+// Checksum returns the expected sha256 (hex) for an artifact, looked up by
+// its repo path, then its name. Empty if none. The artifact index's hashes
+// only record what was bound, so they aren't enforced.
+func (m *Manifest) Checksum(path, name string) string {
+	if m == nil {
+		return ""
+	}
+	sum := m.Checksums[path]
+	if sum == "" {
+		sum = m.Checksums[name]
+	}
+	return strings.ToLower(strings.TrimPrefix(sum, "sha256:"))
 }
 
 // InstalledArtifact tracks what's been installed
 type InstalledArtifact struct {
 	Artifact
-	LocalPath    string                `json:"local_path"`
-	Hash         string                `json:"hash,omitempty"` // For update detection
-	Requirements []detect.Requirement  `json:"requirements,omitempty"` // Auto-detected setup requirements
-	SetupDone    bool                  `json:"setup_done,omitempty"`   // User confirmed setup complete
+	LocalPath    string               `json:"local_path"`
+	Agent        string               `json:"agent,omitempty"`        // Agent whose directories LocalPath is in
+	Flattened    bool                 `json:"flattened,omitempty"`    // Text includes were inlined into the skill body
+	SingleFile   bool                 `json:"single_file,omitempty"`  // Skill written as <name>.md rather than in its own directory
+	Hash         string               `json:"hash,omitempty"`         // For update detection
+	Requirements []detect.Requirement `json:"requirements,omitempty"` // Auto-detected setup requirements
+	SetupDone    bool                 `json:"setup_done,omitempty"`   // User confirmed setup complete
 }
 
 // PluginManifest represents .claude-plugin/plugin.json
@@ -116,6 +147,66 @@ type PluginRepo struct {
 	URL  string `json:"url,omitempty"`
 }
 
+// Marketplace represents .claude-plugin/marketplace.json, which lists
+// plugins kept in the repo's subdirectories or in other repos
+type Marketplace struct {
+	Name     string              `json:"name"`
+	Owner    PluginAuthor        `json:"owner,omitempty"`
+	Metadata MarketplaceMetadata `json:"metadata,omitempty"`
+	Plugins  []MarketplacePlugin `json:"plugins"`
+}
+
+// MarketplaceMetadata holds a marketplace's optional metadata
+type MarketplaceMetadata struct {
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	PluginRoot  string `json:"pluginRoot,omitempty"` // Prepended to relative plugin sources
+}
+
+// MarketplacePlugin is one plugin listed in a marketplace
+type MarketplacePlugin struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Source      MarketplaceSource `json:"source"`
+}
+
+// MarketplaceSource is where a marketplace plugin lives: a path within the
+// marketplace repo ("./plugins/foo"), a GitHub repo, or a git URL
+type MarketplaceSource struct {
+	Path string // Relative to the marketplace root
+	Repo string // owner/repo, for {"source": "github"}
+	URL  string // For {"source": "url"}
+}
+
+// UnmarshalJSON accepts a plain path or a {"source": ...} object
+func (s *MarketplaceSource) UnmarshalJSON(data []byte) error {
+	var p string
+	if err := json.Unmarshal(data, &p); err == nil {
+		*s = MarketplaceSource{Path: p}
+		return nil
+	}
+	var obj struct {
+		Repo string `json:"repo"`
+		URL  string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*s = MarketplaceSource{Repo: obj.Repo, URL: obj.URL}
+	return nil
+}
+
+// PluginPath returns where a plugin with a relative source lives within the
+// marketplace repo ("." for its root), applying metadata.pluginRoot. It's
+// empty for plugins in other repos.
+func (m *Marketplace) PluginPath(p MarketplacePlugin) string {
+	if p.Source.Path == "" {
+		return ""
+	}
+	return path.Clean(path.Join(m.Metadata.PluginRoot, p.Source.Path))
+}
+
 // Plugin represents a complete plugin with all its artifacts
 type Plugin struct {
 	Manifest PluginManifest
@@ -127,3 +218,39 @@ type Plugin struct {
 	Agents   []Artifact
 	Hooks    []Artifact
 }
+
+// MergeTags returns tags followed by those in more it doesn't already have,
+// compared case-insensitively
+func MergeTags(tags, more []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, tag := range append(append([]string{}, tags...), more...) {
+		key := strings.ToLower(strings.TrimSpace(tag))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
+
+// SanitizeFilename makes a filename safe for the filesystem
+func SanitizeFilename(name string) string {
+	// Replace unsafe characters
+	re := regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+	safe := re.ReplaceAllString(name, "-")
+
+	// Remove multiple dashes
+	re = regexp.MustCompile(`-+`)
+	safe = re.ReplaceAllString(safe, "-")
+
+	// Trim dashes from ends
+	safe = strings.Trim(safe, "-")
+
+	if safe == "" {
+		safe = "unnamed"
+	}
+
+	return safe
+}
@@ -0,0 +1,47 @@
+package artifact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"simple", "simple"},
+		{"with-dash", "with-dash"},
+		{"with_underscore", "with_underscore"},
+		{"UPPERCASE", "UPPERCASE"},
+		{"MixedCase123", "MixedCase123"},
+		{"spaces here", "spaces-here"},
+		{"special@#$chars", "special-chars"},
+		{"multiple---dashes", "multiple-dashes"},
+		{"-leading-dash", "leading-dash"},
+		{"trailing-dash-", "trailing-dash"},
+		{"-both-sides-", "both-sides"},
+		{"", "unnamed"},
+		{"@#$%", "unnamed"},
+		{"hello world!", "hello-world"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeFilename(tt.name)
+			if got != tt.want {
+				t.Errorf("SanitizeFilename(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := MergeTags([]string{"pdf", "Docs"}, []string{"docs", "office", "", "pdf"})
+	if strings.Join(got, ",") != "pdf,Docs,office" {
+		t.Errorf("MergeTags() = %q, want pdf, Docs, office", got)
+	}
+	if got := MergeTags(nil, nil); got != nil {
+		t.Errorf("MergeTags(nil, nil) = %q, want nil", got)
+	}
+}
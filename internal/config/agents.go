@@ -137,7 +137,7 @@ func KnownAgents() []AgentConfig {
 			DisplayName: "Windsurf",
 			ConfigDir:   ".windsurf",
 			SkillsDir:   "skills",
-			CommandsDir: "commands",
+			CommandsDir: "workflows", // Commands are workflows (see CommandFormat)
 			Capabilities: AgentCapabilities{
 				Skills:   true,
 				Commands: true,
@@ -212,8 +212,12 @@ func DetectInstalledAgents() []AgentConfig {
 }
 
 // DefaultAgent returns the default agent to use
-// Prefers Claude, falls back to first detected agent
+// Prefers the one set with tome config, then Claude, then the first detected agent
 func DefaultAgent() Agent {
+	if cfg, err := LoadUserConfig(); err == nil && GetAgentConfig(cfg.DefaultAgent) != nil {
+		return cfg.DefaultAgent
+	}
+
 	// Check if Claude is installed
 	home, _ := os.UserHomeDir()
 	claudePath := filepath.Join(home, ".claude")
@@ -261,3 +265,33 @@ func AgentToFormat(agent Agent) schema.Format {
 		return schema.FormatClaude
 	}
 }
+
+// CommandFormat returns the format an agent's commands are written in. It
+// matches AgentToFormat except where an agent keeps commands in a format of
+// their own, like Windsurf's workflows.
+func CommandFormat(agent Agent) schema.Format {
+	if agent == AgentWindsurf {
+		return schema.FormatWindsurf
+	}
+	return AgentToFormat(agent)
+}
+
+// InstructionsFormat returns the format of the instruction files an agent
+// reads (CLAUDE.md, AGENTS.md, Copilot's .instructions.md, Cursor and
+// Windsurf rules), or "" when tome can't write them
+func InstructionsFormat(agent Agent) schema.Format {
+	switch agent {
+	case AgentClaude:
+		return schema.FormatClaude
+	case AgentOpenCode:
+		return schema.FormatOpenCode
+	case AgentCopilot:
+		return schema.FormatCopilot
+	case AgentCursor:
+		return schema.FormatCursor
+	case AgentWindsurf:
+		return schema.FormatWindsurf
+	default:
+		return ""
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/kennyg/tome/internal/artifact"
@@ -25,8 +26,62 @@ const (
 	ConfigDir = "tome"
 	// StateFile is the filename for tracking installed artifacts
 	StateFile = "state.json"
+	// StateEnvVar overrides the state file location when set
+	StateEnvVar = "TOME_STATE"
 )
 
+// stateOverride is set by the --state flag and takes precedence over StateEnvVar
+var stateOverride string
+
+// SetStateOverride sets an explicit state file path for the current run.
+// An empty path clears the override.
+func SetStateOverride(path string) {
+	stateOverride = path
+}
+
+// StateOverride returns the explicit state file path, if any.
+//
+// Precedence (highest first):
+//  1. --state flag (SetStateOverride)
+//  2. $TOME_STATE
+//
+// When neither is set, project-local installs use .config/tome/state.json
+// and global installs use ~/.config/tome/state.json.
+func StateOverride() string {
+	path := stateOverride
+	if path == "" {
+		path = os.Getenv(StateEnvVar)
+	}
+	if path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// resolveStateFile returns the state override if set, otherwise defaultPath
+func resolveStateFile(defaultPath string) string {
+	if override := StateOverride(); override != "" {
+		return override
+	}
+	return defaultPath
+}
+
+// ValidateStatePath checks that a state file path is usable:
+// it must not be a directory and its parent directory must be creatable.
+func ValidateStatePath(path string) error {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return fmt.Errorf("state path is a directory: %s", path)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create state directory %s: %w", dir, err)
+	}
+	return nil
+}
+
 // Paths holds the various paths tome uses
 type Paths struct {
 	// Home is the user's home directory
@@ -47,6 +102,10 @@ type Paths struct {
 	AgentDir    string // e.g., ~/.claude, ~/.opencode
 	SkillsDir   string // e.g., ~/.claude/skills
 	CommandsDir string // e.g., ~/.claude/commands
+
+	// SkillDirs lists every directory the agent reads skills from, in search
+	// order. SkillsDir (the install target) is always first.
+	SkillDirs []string
 }
 
 // State represents the current installation state
@@ -81,18 +140,43 @@ func GetPathsForAgent(agent Agent) (*Paths, error) {
 	// Get agent-specific paths
 	agentDir, skillsDir, commandsDir := AgentPaths(home, agent)
 
+	// The agent also reads skills from the current project, if it has any
+	var projectSkillsDir string
+	if projectRoot := findProjectRoot(); projectRoot != "" {
+		cfg := GetAgentConfig(agent)
+		if cfg == nil {
+			cfg = GetAgentConfig(AgentClaude)
+		}
+		projectSkillsDir = filepath.Join(projectRoot, cfg.ConfigDir, cfg.SkillsDir)
+	}
+
 	return &Paths{
 		Home:             home,
 		UserConfigDir:    userConfigDir,
-		StateFile:        filepath.Join(userConfigDir, StateFile),
+		StateFile:        resolveStateFile(filepath.Join(userConfigDir, StateFile)),
 		ProjectConfigDir: projectConfigDir,
 		Agent:            agent,
 		AgentDir:         agentDir,
 		SkillsDir:        skillsDir,
 		CommandsDir:      commandsDir,
+		SkillDirs:        skillSearchDirs(skillsDir, projectSkillsDir),
 	}, nil
 }
 
+// skillSearchDirs returns primary followed by any other candidates that exist
+func skillSearchDirs(primary string, candidates ...string) []string {
+	dirs := []string{primary}
+	for _, dir := range candidates {
+		if dir == "" || dir == primary {
+			continue
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
 // findProjectConfig looks for .config/tome in the current directory or parents
 func findProjectConfig() string {
 	projectRoot := findProjectRoot()
@@ -138,6 +222,18 @@ func findProjectRoot() string {
 	return ""
 }
 
+// ProjectRoot returns the root of the current project, the nearest directory
+// holding .config/tome or .git, or "" outside a project
+func ProjectRoot() string {
+	return findProjectRoot()
+}
+
+// ProjectConfigDir returns the current project's .config/tome directory, or
+// "" when the project isn't attuned
+func ProjectConfigDir() string {
+	return findProjectConfig()
+}
+
 // IsAttuned returns true if we're in an attuned project with local agent dirs
 func IsAttuned(agent Agent) bool {
 	projectRoot := findProjectRoot()
@@ -189,15 +285,19 @@ func GetLocalPaths(agent Agent) (*Paths, error) {
 	skillsDir := filepath.Join(agentDir, cfg.SkillsDir)
 	commandsDir := filepath.Join(agentDir, cfg.CommandsDir)
 
+	// Global skills stay visible to the agent alongside project ones
+	_, globalSkillsDir, _ := AgentPaths(home, agent)
+
 	return &Paths{
 		Home:             home,
 		UserConfigDir:    userConfigDir,
-		StateFile:        filepath.Join(projectConfigDir, StateFile), // Project-local state
+		StateFile:        resolveStateFile(filepath.Join(projectConfigDir, StateFile)), // Project-local state
 		ProjectConfigDir: projectConfigDir,
 		Agent:            agent,
 		AgentDir:         agentDir,
 		SkillsDir:        skillsDir,
 		CommandsDir:      commandsDir,
+		SkillDirs:        skillSearchDirs(skillsDir, globalSkillsDir),
 	}, nil
 }
 
@@ -219,6 +319,16 @@ func (p *Paths) EnsureDirs() error {
 	return nil
 }
 
+// IsSkillsRoot returns true if dir is one of the agent's skill search directories
+func (p *Paths) IsSkillsRoot(dir string) bool {
+	for _, d := range p.SkillDirs {
+		if filepath.Clean(d) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
 // HasProjectConfig returns true if a project-level config exists
 func (p *Paths) HasProjectConfig() bool {
 	return p.ProjectConfigDir != ""
@@ -353,11 +463,18 @@ func SaveState(path string, state *State) error {
 	return nil
 }
 
-// AddInstalled adds an artifact to the installed list
+// AddInstalled adds an artifact to the installed list, replacing any entry
+// with the same name and type installed for the same agent. Entries recorded
+// without an agent (before agents were tracked) match any agent.
 func (s *State) AddInstalled(a artifact.InstalledArtifact) {
-	// Remove existing entry with same name and type
-	s.RemoveInstalled(a.Name, a.Type)
-	s.Installed = append(s.Installed, a)
+	filtered := make([]artifact.InstalledArtifact, 0, len(s.Installed)+1)
+	for _, existing := range s.Installed {
+		sameAgent := existing.Agent == a.Agent || existing.Agent == "" || a.Agent == ""
+		if !(existing.Name == a.Name && existing.Type == a.Type && sameAgent) {
+			filtered = append(filtered, existing)
+		}
+	}
+	s.Installed = append(filtered, a)
 }
 
 // RemoveInstalled removes an artifact from the installed list
@@ -380,3 +497,44 @@ func (s *State) FindInstalled(name string) *artifact.InstalledArtifact {
 	}
 	return nil
 }
+
+// LookupInstalled finds the installed artifact a user refers to by name: an
+// exact match first, otherwise one whose name matches ignoring case or
+// whose on-disk name (see artifact.SanitizeFilename) does. It's an error when
+// nothing matches, or when the looser match fits more than one artifact.
+func (s *State) LookupInstalled(name string) (*artifact.InstalledArtifact, error) {
+	if a := s.FindInstalled(name); a != nil {
+		return a, nil
+	}
+
+	type key struct {
+		name string
+		typ  artifact.Type
+	}
+	var matches []*artifact.InstalledArtifact
+	seen := make(map[key]bool)
+	sanitized := artifact.SanitizeFilename(name)
+	for i := range s.Installed {
+		a := &s.Installed[i]
+		if !strings.EqualFold(a.Name, name) && !strings.EqualFold(artifact.SanitizeFilename(a.Name), sanitized) {
+			continue
+		}
+		// Copies learned for several agents are the same artifact
+		if k := (key{a.Name, a.Type}); !seen[k] {
+			seen[k] = true
+			matches = append(matches, a)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("artifact '%s' not found", name)
+	case 1:
+		return matches[0], nil
+	}
+	candidates := make([]string, len(matches))
+	for i, a := range matches {
+		candidates[i] = fmt.Sprintf("%s (%s)", a.Name, a.Type)
+	}
+	return nil, fmt.Errorf("'%s' matches %d artifacts: %s; use the exact name", name, len(matches), strings.Join(candidates, ", "))
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -174,6 +176,52 @@ func TestState_FindInstalled(t *testing.T) {
 	}
 }
 
+func TestState_LookupInstalled(t *testing.T) {
+	state := &State{
+		Version: "1",
+		Installed: []artifact.InstalledArtifact{
+			{Artifact: artifact.Artifact{Name: "commit", Type: artifact.TypeCommand}, Agent: "claude"},
+			{Artifact: artifact.Artifact{Name: "commit", Type: artifact.TypeCommand}, Agent: "cursor"},
+			{Artifact: artifact.Artifact{Name: "PDF Tools", Type: artifact.TypeSkill}},
+			{Artifact: artifact.Artifact{Name: "deploy", Type: artifact.TypeSkill}},
+			{Artifact: artifact.Artifact{Name: "Deploy", Type: artifact.TypeCommand}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		want     string
+		wantType artifact.Type
+	}{
+		{"commit", "commit", artifact.TypeCommand},
+		{"Commit", "commit", artifact.TypeCommand}, // Same artifact for two agents
+		{"PDF Tools", "PDF Tools", artifact.TypeSkill},
+		{"pdf tools", "PDF Tools", artifact.TypeSkill},
+		{"PDF-Tools", "PDF Tools", artifact.TypeSkill}, // On-disk name
+		{"pdf-tools", "PDF Tools", artifact.TypeSkill},
+		{"Deploy", "Deploy", artifact.TypeCommand}, // Exact match wins
+	}
+	for _, tt := range tests {
+		got, err := state.LookupInstalled(tt.name)
+		if err != nil {
+			t.Errorf("LookupInstalled(%q) error = %v", tt.name, err)
+			continue
+		}
+		if got.Name != tt.want || got.Type != tt.wantType {
+			t.Errorf("LookupInstalled(%q) = %s (%s), want %s (%s)", tt.name, got.Name, got.Type, tt.want, tt.wantType)
+		}
+	}
+
+	_, err := state.LookupInstalled("DEPLOY")
+	if err == nil || !strings.Contains(err.Error(), "deploy (skill), Deploy (command)") {
+		t.Errorf("ambiguous LookupInstalled() error = %v, want both candidates listed", err)
+	}
+
+	if _, err := state.LookupInstalled("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("LookupInstalled(missing) error = %v, want not found", err)
+	}
+}
+
 func TestSaveState_AtomicWrite(t *testing.T) {
 	tmpDir := t.TempDir()
 	statePath := filepath.Join(tmpDir, "state.json")
@@ -324,3 +372,426 @@ func TestAcquireLock_StaleLock(t *testing.T) {
 		t.Error("Lock file should exist after acquiring lock")
 	}
 }
+
+func TestStateOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	envPath := filepath.Join(tmpDir, "env", "state.json")
+	flagPath := filepath.Join(tmpDir, "flag", "state.json")
+
+	t.Cleanup(func() { SetStateOverride("") })
+
+	tests := []struct {
+		name string
+		env  string
+		flag string
+		want string
+	}{
+		{name: "no override", want: ""},
+		{name: "env only", env: envPath, want: envPath},
+		{name: "flag only", flag: flagPath, want: flagPath},
+		{name: "flag beats env", env: envPath, flag: flagPath, want: flagPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(StateEnvVar, tt.env)
+			SetStateOverride(tt.flag)
+
+			if got := StateOverride(); got != tt.want {
+				t.Errorf("StateOverride() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPathsForAgent_StateOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	statePath := filepath.Join(tmpDir, "isolated", "state.json")
+
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv(StateEnvVar, "")
+	t.Cleanup(func() { SetStateOverride("") })
+
+	paths, err := GetPathsForAgent(AgentClaude)
+	if err != nil {
+		t.Fatalf("GetPathsForAgent() error = %v", err)
+	}
+	wantDefault := filepath.Join(tmpDir, "config", ConfigDir, StateFile)
+	if paths.StateFile != wantDefault {
+		t.Errorf("StateFile = %q, want %q", paths.StateFile, wantDefault)
+	}
+
+	SetStateOverride(statePath)
+	paths, err = GetPathsForAgent(AgentClaude)
+	if err != nil {
+		t.Fatalf("GetPathsForAgent() error = %v", err)
+	}
+	if paths.StateFile != statePath {
+		t.Errorf("StateFile = %q, want %q", paths.StateFile, statePath)
+	}
+}
+
+func TestGetLocalPaths_SeparateState(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(filepath.Join(projectDir, ".config", ConfigDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv(StateEnvVar, "")
+	t.Chdir(projectDir)
+
+	local, err := GetLocalPaths(AgentClaude)
+	if err != nil {
+		t.Fatalf("GetLocalPaths() error = %v", err)
+	}
+	global, err := GetPathsForAgent(AgentClaude)
+	if err != nil {
+		t.Fatalf("GetPathsForAgent() error = %v", err)
+	}
+
+	if local.StateFile == global.StateFile {
+		t.Errorf("project and global state should differ, both = %q", local.StateFile)
+	}
+	// Resolve symlinks (e.g. /tmp on macOS) before comparing
+	gotDir, _ := filepath.EvalSymlinks(filepath.Dir(local.StateFile))
+	wantDir, _ := filepath.EvalSymlinks(filepath.Join(projectDir, ".config", ConfigDir))
+	if gotDir != wantDir || filepath.Base(local.StateFile) != StateFile {
+		t.Errorf("local StateFile = %q, want it in %q", local.StateFile, wantDir)
+	}
+}
+
+func TestValidateStatePath(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Nested directories are created
+	nested := filepath.Join(tmpDir, "a", "b", "state.json")
+	if err := ValidateStatePath(nested); err != nil {
+		t.Errorf("ValidateStatePath(%q) error = %v", nested, err)
+	}
+	if _, err := os.Stat(filepath.Dir(nested)); err != nil {
+		t.Errorf("state directory was not created: %v", err)
+	}
+
+	// A directory is not a valid state file
+	if err := ValidateStatePath(tmpDir); err == nil {
+		t.Error("ValidateStatePath(dir) expected error, got nil")
+	}
+
+	// Parent that is a regular file cannot be created
+	blocker := filepath.Join(tmpDir, "blocker")
+	if err := os.WriteFile(blocker, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateStatePath(filepath.Join(blocker, "state.json")); err == nil {
+		t.Error("ValidateStatePath() under a file expected error, got nil")
+	}
+}
+
+func TestSkillDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := filepath.Join(tmpDir, "home")
+	projectDir := filepath.Join(tmpDir, "project")
+	globalSkills := filepath.Join(home, ".claude", "skills")
+	projectSkills := filepath.Join(projectDir, ".claude", "skills")
+	for _, dir := range []string{globalSkills, projectSkills, filepath.Join(projectDir, ".git")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Chdir(projectDir)
+
+	// Resolve symlinks (e.g. /tmp on macOS) before comparing
+	resolve := func(dirs []string) []string {
+		var out []string
+		for _, d := range dirs {
+			r, err := filepath.EvalSymlinks(d)
+			if err != nil {
+				r = d
+			}
+			out = append(out, r)
+		}
+		return out
+	}
+
+	global, err := GetPathsForAgent(AgentClaude)
+	if err != nil {
+		t.Fatalf("GetPathsForAgent() error = %v", err)
+	}
+	local, err := GetLocalPaths(AgentClaude)
+	if err != nil {
+		t.Fatalf("GetLocalPaths() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		paths *Paths
+		want  []string
+	}{
+		{"global searches project too", global, []string{globalSkills, projectSkills}},
+		{"local searches global too", local, []string{projectSkills, globalSkills}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolve(tt.paths.SkillDirs)
+			want := resolve(tt.want)
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("SkillDirs = %v, want %v", got, want)
+			}
+			if tt.paths.SkillDirs[0] != tt.paths.SkillsDir {
+				t.Errorf("SkillDirs[0] = %q, want install dir %q", tt.paths.SkillDirs[0], tt.paths.SkillsDir)
+			}
+			for _, dir := range tt.paths.SkillDirs {
+				if !tt.paths.IsSkillsRoot(dir) {
+					t.Errorf("IsSkillsRoot(%q) = false", dir)
+				}
+			}
+			if tt.paths.IsSkillsRoot(filepath.Join(globalSkills, "my-skill")) {
+				t.Error("IsSkillsRoot() should be false for a skill subdirectory")
+			}
+		})
+	}
+}
+
+func TestSkillDirs_MissingProjectDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(filepath.Join(projectDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", filepath.Join(tmpDir, "home"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Chdir(projectDir)
+
+	paths, err := GetPathsForAgent(AgentClaude)
+	if err != nil {
+		t.Fatalf("GetPathsForAgent() error = %v", err)
+	}
+	if len(paths.SkillDirs) != 1 || paths.SkillDirs[0] != paths.SkillsDir {
+		t.Errorf("SkillDirs = %v, want only %q", paths.SkillDirs, paths.SkillsDir)
+	}
+}
+
+func TestState_AddInstalled_PerAgent(t *testing.T) {
+	state := &State{}
+	cmd := func(agent, path string) artifact.InstalledArtifact {
+		return artifact.InstalledArtifact{
+			Artifact:  artifact.Artifact{Name: "deploy", Type: artifact.TypeCommand},
+			Agent:     agent,
+			LocalPath: path,
+		}
+	}
+
+	// A legacy entry without an agent is replaced by the next install
+	state.AddInstalled(cmd("", "/old/deploy.md"))
+	state.AddInstalled(cmd("claude", "/claude/deploy.md"))
+	state.AddInstalled(cmd("cursor", "/cursor/deploy.md"))
+	state.AddInstalled(cmd("claude", "/claude/deploy.md"))
+
+	if len(state.Installed) != 2 {
+		t.Fatalf("expected one entry per agent, got %+v", state.Installed)
+	}
+	for _, a := range state.Installed {
+		if a.LocalPath == "/old/deploy.md" {
+			t.Errorf("legacy entry was kept: %+v", a)
+		}
+	}
+}
+
+func TestEnableHooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	fixture := `{
+  "model": "opus",
+  "permissions": {"allow": ["Bash(git status)"]},
+  "hooks": {
+    "PreToolUse": [
+      {"matcher": "Bash", "hooks": [{"type": "command", "command": "audit.sh", "timeout": 30}]}
+    ]
+  }
+}`
+	if err := os.WriteFile(path, []byte(fixture), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	hooks, err := ParseHooks([]byte(`{"hooks": {
+		"PreToolUse": [
+			{"matcher": "Bash", "hooks": [
+				{"type": "command", "command": "audit.sh", "timeout": 30},
+				{"type": "command", "command": "guard.sh"}
+			]},
+			{"matcher": "Write|Edit", "hooks": [{"type": "command", "command": "fmt.sh"}]}
+		],
+		"Stop": [{"hooks": [{"type": "prompt", "prompt": "Summarize"}]}]
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks) != 4 {
+		t.Fatalf("ParseHooks() = %d hooks, want 4", len(hooks))
+	}
+
+	added, err := EnableHooks(path, hooks)
+	if err != nil {
+		t.Fatalf("EnableHooks() error = %v", err)
+	}
+	if added != 3 {
+		t.Errorf("added = %d, want 3 (audit.sh was already there)", added)
+	}
+	if added, _ := EnableHooks(path, hooks); added != 0 {
+		t.Errorf("second EnableHooks() added %d, want 0", added)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want the original 0600", info.Mode().Perm())
+	}
+	var settings struct {
+		Model       string `json:"model"`
+		Permissions struct {
+			Allow []string `json:"allow"`
+		} `json:"permissions"`
+		Hooks map[string][]struct {
+			Matcher string           `json:"matcher"`
+			Hooks   []map[string]any `json:"hooks"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Model != "opus" || len(settings.Permissions.Allow) != 1 {
+		t.Errorf("other settings clobbered:\n%s", data)
+	}
+
+	pre := settings.Hooks["PreToolUse"]
+	if len(pre) != 2 || pre[0].Matcher != "Bash" || len(pre[0].Hooks) != 2 || pre[1].Matcher != "Write|Edit" {
+		t.Errorf("PreToolUse = %+v, want guard.sh added to Bash and a Write|Edit matcher", pre)
+	}
+	if stop := settings.Hooks["Stop"]; len(stop) != 1 || stop[0].Hooks[0]["prompt"] != "Summarize" {
+		t.Errorf("Stop = %+v, want the prompt hook", stop)
+	}
+}
+
+func TestEnableHooks_CreatesSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".claude", "settings.json")
+	added, err := EnableHooks(path, []Hook{{Event: "PreCompact", Entry: map[string]any{"type": "command", "command": "/hooks/pre-compact.sh"}}})
+	if err != nil || added != 1 {
+		t.Fatalf("EnableHooks() = %d, %v; want 1 added", added, err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "/hooks/pre-compact.sh") {
+		t.Errorf("settings.json = %s, want the hook", data)
+	}
+}
+
+func TestLockfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LockfileName)
+
+	lock, err := LoadLockfile(path)
+	if err != nil {
+		t.Fatalf("LoadLockfile() on a missing file error = %v", err)
+	}
+	if len(lock.Artifacts) != 0 {
+		t.Fatalf("missing lockfile has entries: %+v", lock.Artifacts)
+	}
+
+	lock.Set(LockEntry{Name: "pdf", Type: artifact.TypeSkill, Source: "acme/skills", Ref: "main", Commit: "aaa", SHA256: "1"})
+	lock.Set(LockEntry{Name: "commit", Type: artifact.TypeCommand, Source: "acme/tools", SHA256: "2"})
+	lock.Set(LockEntry{Name: "pdf", Type: artifact.TypeSkill, Source: "acme/skills", Ref: "main", Commit: "bbb", SHA256: "3"})
+	if len(lock.Artifacts) != 2 {
+		t.Fatalf("Set() kept %d entries, want the pdf entry replaced", len(lock.Artifacts))
+	}
+
+	if err := SaveLockfile(path, lock); err != nil {
+		t.Fatalf("SaveLockfile() error = %v", err)
+	}
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLockfile(path)
+	if err != nil {
+		t.Fatalf("LoadLockfile() error = %v", err)
+	}
+	if loaded.Version != 1 || len(loaded.Artifacts) != 2 {
+		t.Fatalf("loaded = %+v", loaded)
+	}
+	if loaded.Artifacts[0].Name != "commit" || loaded.Artifacts[1].Commit != "bbb" {
+		t.Errorf("entries = %+v, want sorted by name with the latest pdf", loaded.Artifacts)
+	}
+
+	// Saving the same entries again writes the same bytes
+	if err := SaveLockfile(path, loaded); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := os.ReadFile(path)
+	if string(first) != string(second) {
+		t.Errorf("lockfile changed on resave:\n%s\n---\n%s", first, second)
+	}
+}
+
+func TestUserConfig_SetGetRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := LoadUserConfig()
+	if err != nil {
+		t.Fatalf("LoadUserConfig() with no file error = %v", err)
+	}
+	for key, value := range map[string]string{"default-agent": "opencode", "default-scope": ScopeGlobal, "jobs": "16"} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("Set(%s, %s) error = %v", key, value, err)
+		}
+	}
+	if err := SaveUserConfig(cfg); err != nil {
+		t.Fatalf("SaveUserConfig() error = %v", err)
+	}
+
+	loaded, err := LoadUserConfig()
+	if err != nil {
+		t.Fatalf("LoadUserConfig() error = %v", err)
+	}
+	for key, want := range map[string]string{"default-agent": "opencode", "default-scope": ScopeGlobal, "jobs": "16"} {
+		if got, err := loaded.Get(key); err != nil || got != want {
+			t.Errorf("Get(%s) = %q, %v, want %q", key, got, err, want)
+		}
+	}
+	if got := DefaultAgent(); got != AgentOpenCode {
+		t.Errorf("DefaultAgent() = %s, want the configured opencode", got)
+	}
+
+	// An empty value unsets
+	if err := loaded.Set("jobs", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loaded.Get("jobs"); got != "" {
+		t.Errorf("Get(jobs) after unset = %q, want empty", got)
+	}
+}
+
+func TestUserConfig_SetValidates(t *testing.T) {
+	tests := []struct{ key, value string }{
+		{"default-agent", "emacs"},
+		{"default-scope", "everywhere"},
+		{"jobs", "0"},
+		{"jobs", "many"},
+		{"color", "blue"},
+	}
+	for _, tt := range tests {
+		var cfg UserConfig
+		if err := cfg.Set(tt.key, tt.value); err == nil {
+			t.Errorf("Set(%s, %s) succeeded, want an error", tt.key, tt.value)
+		}
+		if cfg != (UserConfig{}) {
+			t.Errorf("Set(%s, %s) changed the config to %+v", tt.key, tt.value, cfg)
+		}
+	}
+}
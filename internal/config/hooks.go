@@ -0,0 +1,173 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SettingsFilename is the agent settings file hooks are registered in
+const SettingsFilename = "settings.json"
+
+// Hook is one hook handler to register for an event, as in the hooks
+// section of settings.json: event → [{matcher, hooks: [entry]}]
+type Hook struct {
+	Event   string
+	Matcher string
+	Entry   map[string]any // e.g. {"type": "command", "command": "..."}
+}
+
+// ParseHooks reads the handlers from a hooks.json ({"hooks": {...}}), by
+// event name and then in the order they appear
+func ParseHooks(data []byte) ([]Hook, error) {
+	var file struct {
+		Hooks map[string][]struct {
+			Matcher string           `json:"matcher"`
+			Hooks   []map[string]any `json:"hooks"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse hooks.json: %w", err)
+	}
+
+	events := make([]string, 0, len(file.Hooks))
+	for event := range file.Hooks {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+
+	var hooks []Hook
+	for _, event := range events {
+		for _, m := range file.Hooks[event] {
+			for _, entry := range m.Hooks {
+				hooks = append(hooks, Hook{Event: event, Matcher: m.Matcher, Entry: entry})
+			}
+		}
+	}
+	return hooks, nil
+}
+
+// EnableHooks registers hooks in the settings file at path, creating it if
+// needed. Handlers already registered for the same event and matcher are
+// left alone, as is every other setting. The file is replaced atomically.
+// Returns how many handlers were added.
+func EnableHooks(path string, hooks []Hook) (int, error) {
+	settings := map[string]json.RawMessage{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	// Decode generically so fields tome doesn't know about survive
+	events := map[string][]map[string]any{}
+	if raw, ok := settings["hooks"]; ok {
+		if err := json.Unmarshal(raw, &events); err != nil {
+			return 0, fmt.Errorf("failed to parse hooks in %s: %w", path, err)
+		}
+	}
+
+	added := 0
+	for _, h := range hooks {
+		if addHook(events, h) {
+			added++
+		}
+	}
+	if added == 0 {
+		return 0, nil
+	}
+
+	raw, err := json.Marshal(events)
+	if err != nil {
+		return 0, err
+	}
+	settings["hooks"] = raw
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := writeFileAtomic(path, append(data, '\n')); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// addHook adds h's handler under its event and matcher unless an identical
+// one is there, reporting whether it was added
+func addHook(events map[string][]map[string]any, h Hook) bool {
+	// Compare handlers as JSON would see them, so 30 and 30.0 match
+	entry, err := normalizeJSON(h.Entry)
+	if err != nil {
+		return false
+	}
+
+	for _, m := range events[h.Event] {
+		if matcher, _ := m["matcher"].(string); matcher != h.Matcher {
+			continue
+		}
+		handlers, _ := m["hooks"].([]any)
+		for _, existing := range handlers {
+			if reflect.DeepEqual(existing, entry) {
+				return false
+			}
+		}
+		m["hooks"] = append(handlers, entry)
+		return true
+	}
+
+	events[h.Event] = append(events[h.Event], map[string]any{
+		"matcher": h.Matcher,
+		"hooks":   []any{entry},
+	})
+	return true
+}
+
+// normalizeJSON round-trips v through JSON into generic values
+func normalizeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// writeFileAtomic writes data to a temp file beside path, then renames it
+// over path so readers never see a partial file. An existing file's
+// permissions are kept.
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/kennyg/tome/internal/artifact"
+)
+
+// LockfileName is the file at a project's root that pins what it installs
+const LockfileName = "tome.lock"
+
+// Lockfile pins each artifact a project installs to the exact content it
+// was learned with, so everyone running tome install gets the same files
+type Lockfile struct {
+	Version   int         `json:"version"`
+	Artifacts []LockEntry `json:"artifacts"`
+}
+
+// LockEntry pins one installed artifact
+type LockEntry struct {
+	Name     string        `json:"name"`               // Name installed under
+	Selector string        `json:"selector,omitempty"` // Name in the source, when installed under another (--rename)
+	Type     artifact.Type `json:"type"`
+	Source   string        `json:"source"`           // Source as learned (owner/repo@^1.2, a URL, ...)
+	Ref      string        `json:"ref,omitempty"`    // Branch, tag or range the source named
+	Commit   string        `json:"commit,omitempty"` // Commit Ref resolved to (GitHub sources)
+	SHA256   string        `json:"sha256"`           // Checksum of the fetched content
+}
+
+// LoadLockfile reads a lockfile, returning an empty one if it doesn't exist
+func LoadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Lockfile{Version: 1}, nil
+		}
+		return nil, err
+	}
+
+	var lock Lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// SaveLockfile writes a lockfile atomically, its entries sorted by name and
+// type so it diffs cleanly
+func SaveLockfile(path string, lock *Lockfile) error {
+	sort.SliceStable(lock.Artifacts, func(i, j int) bool {
+		a, b := lock.Artifacts[i], lock.Artifacts[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Type < b.Type
+	})
+	if lock.Version == 0 {
+		lock.Version = 1
+	}
+
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// Set records an entry, replacing any with the same name and type
+func (l *Lockfile) Set(entry LockEntry) {
+	for i, e := range l.Artifacts {
+		if e.Name == entry.Name && e.Type == entry.Type {
+			l.Artifacts[i] = entry
+			return
+		}
+	}
+	l.Artifacts = append(l.Artifacts, entry)
+}
+
+// Get returns the entry with name and type
+func (l *Lockfile) Get(name string, t artifact.Type) (LockEntry, bool) {
+	for _, e := range l.Artifacts {
+		if e.Name == name && e.Type == t {
+			return e, true
+		}
+	}
+	return LockEntry{}, false
+}
+
+// Remove drops the entry with name and type, reporting whether there was one
+func (l *Lockfile) Remove(name string, t artifact.Type) bool {
+	for i, e := range l.Artifacts {
+		if e.Name == name && e.Type == t {
+			l.Artifacts = append(l.Artifacts[:i], l.Artifacts[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package config
+
+import "path/filepath"
+
+// MCPConfigFiles returns the files where agent keeps MCP servers: the
+// user-level one under home and the project-level one under projectRoot.
+// Either is empty when the agent has no config at that scope, or when
+// projectRoot is empty.
+func MCPConfigFiles(agent Agent, home, projectRoot string) (global, project string) {
+	switch agent {
+	case AgentClaude:
+		global, project = filepath.Join(home, ".claude.json"), ".mcp.json"
+	case AgentCursor:
+		global, project = filepath.Join(home, ".cursor", "mcp.json"), filepath.Join(".cursor", "mcp.json")
+	case AgentOpenCode:
+		global, project = filepath.Join(home, ".config", "opencode", "opencode.json"), "opencode.json"
+	case AgentWindsurf:
+		// Windsurf only reads its user-level config
+		global = filepath.Join(home, ".codeium", "windsurf", "mcp_config.json")
+	case AgentCopilot:
+		// VS Code's user settings live outside home in a per-OS location
+		project = filepath.Join(".vscode", "mcp.json")
+	}
+
+	if project != "" {
+		if projectRoot == "" {
+			project = ""
+		} else {
+			project = filepath.Join(projectRoot, project)
+		}
+	}
+	return global, project
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// UserConfigFile is the filename for the user's defaults, set with tome config
+const UserConfigFile = "config.json"
+
+// Install scopes, for the default-scope setting
+const (
+	ScopeGlobal  = "global"
+	ScopeProject = "project"
+)
+
+// UserConfig holds the defaults set with tome config; a zero field is unset
+type UserConfig struct {
+	DefaultAgent Agent  `json:"default_agent,omitempty"`
+	DefaultScope string `json:"default_scope,omitempty"`
+	Jobs         int    `json:"jobs,omitempty"`
+}
+
+// ConfigKeys lists the settings tome config can get and set, in display order
+var ConfigKeys = []string{"default-agent", "default-scope", "jobs"}
+
+// UserConfigPath returns ~/.config/tome/config.json (or under $XDG_CONFIG_HOME)
+func UserConfigPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, ConfigDir, UserConfigFile), nil
+}
+
+// LoadUserConfig reads the user's defaults; a missing file sets none
+func LoadUserConfig() (*UserConfig, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &UserConfig{}, nil
+		}
+		return nil, err
+	}
+
+	var cfg UserConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// SaveUserConfig writes the user's defaults, replacing the file atomically
+func SaveUserConfig(cfg *UserConfig) error {
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// Get returns the value of a setting, or "" when it's unset
+func (c *UserConfig) Get(key string) (string, error) {
+	switch key {
+	case "default-agent":
+		return string(c.DefaultAgent), nil
+	case "default-scope":
+		return c.DefaultScope, nil
+	case "jobs":
+		if c.Jobs == 0 {
+			return "", nil
+		}
+		return strconv.Itoa(c.Jobs), nil
+	}
+	return "", unknownKey(key)
+}
+
+// Set validates and changes a setting; an empty value unsets it
+func (c *UserConfig) Set(key, value string) error {
+	switch key {
+	case "default-agent":
+		if value != "" && GetAgentConfig(Agent(value)) == nil {
+			var names []string
+			for _, a := range KnownAgents() {
+				names = append(names, string(a.Name))
+			}
+			return fmt.Errorf("unknown agent %q (known: %s)", value, strings.Join(names, ", "))
+		}
+		c.DefaultAgent = Agent(value)
+	case "default-scope":
+		if value != "" && value != ScopeGlobal && value != ScopeProject {
+			return fmt.Errorf("invalid scope %q (use %s or %s)", value, ScopeGlobal, ScopeProject)
+		}
+		c.DefaultScope = value
+	case "jobs":
+		if value == "" {
+			c.Jobs = 0
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid jobs %q (use a positive number)", value)
+		}
+		c.Jobs = n
+	default:
+		return unknownKey(key)
+	}
+	return nil
+}
+
+// unknownKey is the error for a setting tome config doesn't have
+func unknownKey(key string) error {
+	return fmt.Errorf("unknown setting %q (settings: %s)", key, strings.Join(ConfigKeys, ", "))
+}
@@ -6,21 +6,29 @@ package detect
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kennyg/tome/internal/markdown"
 )
 
 // RequirementType represents the kind of requirement detected
 type RequirementType string
 
 const (
-	TypeCommand RequirementType = "command" // Binary must exist on PATH
-	TypeNPM     RequirementType = "npm"     // Node.js package (npm, bun, yarn, pnpm)
-	TypePip     RequirementType = "pip"     // Python package
-	TypeBrew    RequirementType = "brew"    // Homebrew formula
-	TypeCargo   RequirementType = "cargo"   // Rust crate
-	TypeEnv     RequirementType = "env"     // Environment variable
-	TypeRuntime RequirementType = "runtime" // Runtime (node, python, etc.)
+	TypeCommand   RequirementType = "command"   // Binary must exist on PATH
+	TypeNPM       RequirementType = "npm"       // Node.js package (npm, bun, yarn, pnpm)
+	TypePip       RequirementType = "pip"       // Python package
+	TypeBrew      RequirementType = "brew"      // Homebrew formula
+	TypeCargo     RequirementType = "cargo"     // Rust crate
+	TypeGo        RequirementType = "go"        // Go module installed with go install
+	TypeGem       RequirementType = "gem"       // Ruby gem
+	TypeEnv       RequirementType = "env"       // Environment variable
+	TypeRuntime   RequirementType = "runtime"   // Runtime (node, python, etc.)
+	TypeExtension RequirementType = "extension" // Editor extension (VS Code publisher.name)
 )
 
 // PackageManager tracks which package manager was used
@@ -43,57 +51,179 @@ type Requirement struct {
 	Line           int             `json:"line"`                      // Line number (0 if not from content)
 	Context        string          `json:"context"`                   // The line/snippet where it was found
 	PackageManager PackageManager  `json:"package_manager,omitempty"` // Which package manager (npm, bun, yarn, pnpm, pip, pip3)
+	Version        string          `json:"version,omitempty"`         // Version constraint as written: ^2.0 (npm), >=1.4,<2 (pip)
+	Ignored        bool            `json:"ignored,omitempty"`         // Suppressed via .tomeignore or ignore-requirements
+}
+
+// Label returns the requirement's value with its version constraint, if any:
+// foo (^2.0)
+func (r Requirement) Label() string {
+	if r.Version == "" {
+		return r.Value
+	}
+	return r.Value + " (" + r.Version + ")"
 }
 
 // VerifyResult contains the result of verifying a requirement
 type VerifyResult struct {
-	Requirement Requirement
-	Satisfied   bool
-	Message     string // Help message if not satisfied
+	Requirement Requirement `json:"requirement"`
+	Satisfied   bool        `json:"satisfied"`
+	Message     string      `json:"message,omitempty"` // Help message if not satisfied
 }
 
+// Package fragments shared by the install patterns. Each captures the name
+// and an optional version, and allows the quotes a version range needs in a
+// shell ("foo@>=2", 'bar>=1.4').
+const (
+	npmPackage = `["']?((?:@[a-zA-Z0-9_.-]+/)?[a-zA-Z0-9_-]+)(?:@([a-zA-Z0-9^~<>=.*_-]+))?`
+	pipPackage = `["']?([a-zA-Z0-9_-]+)(\s*` + pipClause + `(?:\s*,\s*` + pipClause + `)*)?`
+	pipClause  = `(?:===|~=|==|!=|<=|>=|<|>)\s*[0-9][a-zA-Z0-9.*+!]*`
+)
+
 // Patterns for detecting requirements
 var (
 	// Package manager install patterns - capture the package manager name
-	npmInstallRe   = regexp.MustCompile(`(npm)\s+(?:install|i)\s+(?:-[gGdD]\s+)?([a-zA-Z0-9@/_-]+)`)
-	bunInstallRe   = regexp.MustCompile(`(bun)\s+(?:add|install)\s+(?:-[gGdD]\s+)?([a-zA-Z0-9@/_-]+)`)
-	yarnInstallRe  = regexp.MustCompile(`(yarn)\s+add\s+(?:-[gGdD]\s+)?([a-zA-Z0-9@/_-]+)`)
-	pnpmInstallRe  = regexp.MustCompile(`(pnpm)\s+(?:add|install)\s+(?:-[gGdD]\s+)?([a-zA-Z0-9@/_-]+)`)
-	pipInstallRe   = regexp.MustCompile(`(pip3?)\s+install\s+([a-zA-Z0-9_-]+)`)
-	pythonPipRe    = regexp.MustCompile(`python3?\s+-m\s+(pip)\s+install\s+([a-zA-Z0-9_-]+)`)
+	// and the package, then any version: foo@^2.0, @scope/foo@1.2.3, bar==1.4
+	npmInstallRe   = regexp.MustCompile(`(npm)\s+(?:install|i)\s+(?:-[gGdD]\s+)?` + npmPackage)
+	bunInstallRe   = regexp.MustCompile(`(bun)\s+(?:add|install)\s+(?:-[gGdD]\s+)?` + npmPackage)
+	yarnInstallRe  = regexp.MustCompile(`(yarn)\s+add\s+(?:-[gGdD]\s+)?` + npmPackage)
+	pnpmInstallRe  = regexp.MustCompile(`(pnpm)\s+(?:add|install)\s+(?:-[gGdD]\s+)?` + npmPackage)
+	pipInstallRe   = regexp.MustCompile(`(pip3?)\s+install\s+` + pipPackage)
+	pythonPipRe    = regexp.MustCompile(`python3?\s+-m\s+(pip)\s+install\s+` + pipPackage)
 	brewInstallRe  = regexp.MustCompile(`brew\s+install\s+([a-zA-Z0-9_-]+)`)
 	cargoInstallRe = regexp.MustCompile(`cargo\s+install\s+([a-zA-Z0-9_-]+)`)
+	goInstallRe    = regexp.MustCompile(`\bgo\s+install\s+([a-zA-Z0-9-]+\.[a-zA-Z0-9.-]+/[a-zA-Z0-9._~/-]+(?:@[a-zA-Z0-9._-]+)?)`)
+	gemInstallRe   = regexp.MustCompile(`\bgem\s+install\s+([a-zA-Z0-9_-]+)`)
+
+	// Container usage: "docker run ...", "docker compose up" and the standalone
+	// "docker-compose up". Only known subcommands count, so prose like
+	// "a docker image" doesn't.
+	dockerRe = regexp.MustCompile(`\b(docker-compose|docker)\s+(?:compose|run|build|pull|push|exec|start|stop|up|down|ps|logs|images|volume|network|login|tag|rmi|rm)\b`)
+
+	// Editor extension patterns: "the Python extension (`ms-python.python`)"
+	// and "code --install-extension ms-python.python"
+	extensionMentionRe = regexp.MustCompile(`(?i)extension[^(\n]*\(\s*` + "`?" + `([a-zA-Z0-9][a-zA-Z0-9-]*\.[a-zA-Z0-9][a-zA-Z0-9.-]*)` + "`?" + `\s*\)`)
+	extensionInstallRe = regexp.MustCompile(`--install-extension\s+([a-zA-Z0-9][a-zA-Z0-9-]*\.[a-zA-Z0-9][a-zA-Z0-9.-]*)`)
 
 	// Environment variable patterns
 	envVarRe    = regexp.MustCompile(`\$\{?([A-Z][A-Z0-9_]{2,})\}?`)
 	envExportRe = regexp.MustCompile(`export\s+([A-Z][A-Z0-9_]+)=`)
+	// ${VAR:-default}, ${VAR:=default} and ${VAR:+alt} work with VAR unset
+	envDefaultRe = regexp.MustCompile(`\$\{([A-Z][A-Z0-9_]{2,}):?[-=+]`)
+	// Shell that sets a variable itself (FOO=bar, local FOO, read FOO, for FOO
+	// in); its $FOO is a local, not something the user provides. export
+	// isn't among them: exporting is how docs tell users to set a variable.
+	shellLocalVarRes = []*regexp.Regexp{
+		regexp.MustCompile(`(?:^\s*|[;&|(]\s*|\b(?:local|declare|typeset|readonly)\s+(?:-[a-zA-Z]+\s+)*)([A-Z][A-Z0-9_]*)=`),
+		regexp.MustCompile(`\bread\s+(?:-[a-zA-Z]+\s+)*([A-Z][A-Z0-9_]*)\b`),
+		regexp.MustCompile(`\bfor\s+([A-Z][A-Z0-9_]*)\s+in\b`),
+	}
+	// Code block languages of settings files rather than shell
+	settingsLangs = map[string]bool{
+		"env": true, "dotenv": true, "ini": true, "properties": true,
+		"toml": true, "yaml": true, "yml": true,
+	}
+	// A heredoc with a quoted delimiter (<<'EOF', <<"EOF", <<\EOF) is written
+	// out as is, so the variables in it aren't expanded
+	quotedHeredocRe = regexp.MustCompile(`<<-?\s*(?:'([A-Za-z_][A-Za-z0-9_]*)'|"([A-Za-z_][A-Za-z0-9_]*)"|\\([A-Za-z_][A-Za-z0-9_]*))`)
 	// Match env var mentions only when they look like actual env vars:
 	// - Must be UPPER_CASE (not lowercase/mixed like "Secrets")
 	// - Must have word boundaries
 	// - Common patterns: OPENAI_API_KEY, MY_SECRET, AUTH_TOKEN
 	apiKeyMention = regexp.MustCompile(`\b([A-Z][A-Z0-9]*_(?:API_KEY|SECRET|TOKEN|KEY))\b`)
+	// The same suffixes on a whole variable name, which may have several parts
+	// (GITHUB_PERSONAL_ACCESS_TOKEN)
+	secretEnvName = regexp.MustCompile(`^[A-Z][A-Z0-9_]*_(?:API_KEY|SECRET|TOKEN|KEY)$`)
 
-	// Common env vars to ignore (too generic or system-level)
+	// Common env vars to ignore: set by the system, the shell or CI, so
+	// nobody needs to provide them. Credentials (GITHUB_TOKEN) are never
+	// here; a CI runner may set them, but a user's shell won't.
 	ignoredEnvVars = map[string]bool{
-		"PATH": true, "HOME": true, "USER": true, "SHELL": true,
+		// System and login
+		"PATH": true, "HOME": true, "USER": true, "LOGNAME": true, "SHELL": true,
 		"PWD": true, "OLDPWD": true, "TERM": true, "LANG": true,
-		"LC_ALL": true, "EDITOR": true, "VISUAL": true,
-		"XDG_CONFIG_HOME": true, "XDG_DATA_HOME": true,
+		"LC_ALL": true, "EDITOR": true, "VISUAL": true, "PAGER": true,
+		"HOSTNAME": true, "DISPLAY": true, "COLUMNS": true, "LINES": true,
+		"SSH_AUTH_SOCK":   true,
+		"XDG_CONFIG_HOME": true, "XDG_DATA_HOME": true, "XDG_CACHE_HOME": true,
+		"XDG_STATE_HOME": true, "XDG_RUNTIME_DIR": true,
 		"TMPDIR": true, "TMP": true, "TEMP": true,
+		// Shell
+		"BASH_SOURCE": true, "BASH_VERSION": true, "FUNCNAME": true, "LINENO": true,
+		"RANDOM": true, "SECONDS": true, "PPID": true, "UID": true, "EUID": true,
+		"IFS": true, "PIPESTATUS": true, "REPLY": true, "OPTARG": true, "OPTIND": true,
+		// CI runners
+		"CI": true, "GITHUB_ACTIONS": true, "GITHUB_WORKSPACE": true,
+		"GITHUB_OUTPUT": true, "GITHUB_ENV": true, "GITHUB_PATH": true,
+		"GITHUB_STEP_SUMMARY": true, "GITHUB_SHA": true, "GITHUB_REF": true,
+		"GITHUB_REF_NAME": true, "GITHUB_REPOSITORY": true, "GITHUB_RUN_ID": true,
+		"GITHUB_EVENT_PATH": true, "RUNNER_OS": true, "RUNNER_TEMP": true,
 	}
 )
 
-// FromContent scans markdown/text content for setup requirements
+// DetectInCodeBlocks is the `detect-requirements` frontmatter value that
+// scopes FromContent to fenced code blocks
+const DetectInCodeBlocks = "code-blocks"
+
+// Options controls how FromContentOptions scans content
+type Options struct {
+	// ScopedToCodeBlocks only scans lines inside fenced code blocks (``` or ~~~),
+	// so prose that mentions a package or variable isn't reported. Extensions
+	// declared in frontmatter are detected either way.
+	ScopedToCodeBlocks bool
+}
+
+// FromContent scans markdown/text content for setup requirements, looking
+// at every line of the document unless its frontmatter sets
+// `detect-requirements: code-blocks`
 func FromContent(content string) []Requirement {
+	return FromContentOptions(content, Options{
+		ScopedToCodeBlocks: parseFrontmatter(content).DetectRequirements == DetectInCodeBlocks,
+	})
+}
+
+// FromContentOptions scans markdown/text content for setup requirements
+func FromContentOptions(content string, opts Options) []Requirement {
 	var reqs []Requirement
 	seen := make(map[string]bool) // Dedupe by type:value
 
+	// Editor extensions declared in frontmatter
+	for _, ext := range frontmatterExtensions(content) {
+		key := "extension:" + strings.ToLower(ext)
+		if !seen[key] {
+			seen[key] = true
+			reqs = append(reqs, Requirement{
+				Type:    TypeExtension,
+				Value:   ext,
+				Source:  "frontmatter",
+				Context: "extensions: " + ext,
+			})
+		}
+	}
+
+	var fence codeFence
+	var heredoc string // Delimiter of the quoted heredoc being scanned
 	lines := strings.Split(content, "\n")
+	local := shellLocalVars(lines)
 	for i, line := range lines {
 		lineNum := i + 1
 
+		// A quoted heredoc's body is literal text, not variables to expand
+		inHeredoc := heredoc != ""
+		if inHeredoc && strings.TrimSpace(line) == heredoc {
+			heredoc = ""
+		} else if m := quotedHeredocRe.FindStringSubmatch(line); m != nil && !inHeredoc {
+			heredoc = m[1] + m[2] + m[3]
+		}
+
+		// Fence lines open or close a block and hold no commands themselves
+		isFence := fence.toggle(line)
+		if opts.ScopedToCodeBlocks && (isFence || !fence.open()) {
+			continue
+		}
+
 		// Check for Node.js package managers (npm, bun, yarn, pnpm)
-		// Each captures: [full match, package manager, package name]
+		// Each captures: [full match, package manager, package name, version]
 		for _, re := range []*regexp.Regexp{npmInstallRe, bunInstallRe, yarnInstallRe, pnpmInstallRe} {
 			if matches := re.FindAllStringSubmatch(line, -1); matches != nil {
 				for _, m := range matches {
@@ -109,13 +239,14 @@ func FromContent(content string) []Requirement {
 							Line:           lineNum,
 							Context:        strings.TrimSpace(line),
 							PackageManager: pm,
+							Version:        m[3],
 						})
 					}
 				}
 			}
 		}
 
-		// Check for pip install (captures: [full match, pip/pip3, package])
+		// Check for pip install (captures: [full match, pip/pip3, package, version])
 		if matches := pipInstallRe.FindAllStringSubmatch(line, -1); matches != nil {
 			for _, m := range matches {
 				pm := PackageManager(m[1])
@@ -130,6 +261,7 @@ func FromContent(content string) []Requirement {
 						Line:           lineNum,
 						Context:        strings.TrimSpace(line),
 						PackageManager: pm,
+						Version:        pipVersion(m[3]),
 					})
 				}
 			}
@@ -149,6 +281,7 @@ func FromContent(content string) []Requirement {
 						Line:           lineNum,
 						Context:        strings.TrimSpace(line),
 						PackageManager: PMpip,
+						Version:        pipVersion(m[3]),
 					})
 				}
 			}
@@ -188,11 +321,86 @@ func FromContent(content string) []Requirement {
 			}
 		}
 
-		// Check for environment variables
-		if matches := envVarRe.FindAllStringSubmatch(line, -1); matches != nil {
+		// Check for go install
+		if matches := goInstallRe.FindAllStringSubmatch(line, -1); matches != nil {
+			for _, m := range matches {
+				key := "go:" + m[1]
+				if !seen[key] {
+					seen[key] = true
+					reqs = append(reqs, Requirement{
+						Type:    TypeGo,
+						Value:   m[1],
+						Source:  "content",
+						Line:    lineNum,
+						Context: strings.TrimSpace(line),
+					})
+				}
+			}
+		}
+
+		// Check for gem install
+		if matches := gemInstallRe.FindAllStringSubmatch(line, -1); matches != nil {
+			for _, m := range matches {
+				key := "gem:" + m[1]
+				if !seen[key] {
+					seen[key] = true
+					reqs = append(reqs, Requirement{
+						Type:    TypeGem,
+						Value:   m[1],
+						Source:  "content",
+						Line:    lineNum,
+						Context: strings.TrimSpace(line),
+					})
+				}
+			}
+		}
+
+		// Check for docker and docker-compose (captures: [full match, binary])
+		if matches := dockerRe.FindAllStringSubmatch(line, -1); matches != nil {
+			for _, m := range matches {
+				key := "command:" + m[1]
+				if !seen[key] {
+					seen[key] = true
+					reqs = append(reqs, Requirement{
+						Type:    TypeCommand,
+						Value:   m[1],
+						Source:  "content",
+						Line:    lineNum,
+						Context: strings.TrimSpace(line),
+					})
+				}
+			}
+		}
+
+		// Check for editor extensions
+		for _, re := range []*regexp.Regexp{extensionMentionRe, extensionInstallRe} {
+			if matches := re.FindAllStringSubmatch(line, -1); matches != nil {
+				for _, m := range matches {
+					key := "extension:" + strings.ToLower(m[1])
+					if !seen[key] {
+						seen[key] = true
+						reqs = append(reqs, Requirement{
+							Type:    TypeExtension,
+							Value:   m[1],
+							Source:  "content",
+							Line:    lineNum,
+							Context: strings.TrimSpace(line),
+						})
+					}
+				}
+			}
+		}
+
+		// Check for environment variables, skipping those with a default and
+		// those the snippet sets itself
+		defaulted := make(map[string]bool)
+		for _, m := range envDefaultRe.FindAllStringSubmatch(line, -1) {
+			defaulted[m[1]] = true
+		}
+		if matches := envVarRe.FindAllStringSubmatch(line, -1); matches != nil && !inHeredoc {
 			for _, m := range matches {
 				varName := m[1]
-				if ignoredEnvVars[varName] {
+				if ignoredEnvVars[varName] || defaulted[varName] || local[varName] {
 					continue
 				}
 				key := "env:" + varName
@@ -252,6 +460,92 @@ func FromContent(content string) []Requirement {
 	return reqs
 }
 
+// shellLocalVars returns the variables shell in the content sets for itself.
+// Code blocks of settings (a .env to copy) assign variables for the user to
+// provide, so they don't count.
+func shellLocalVars(lines []string) map[string]bool {
+	local := make(map[string]bool)
+	var fence codeFence
+	for _, line := range lines {
+		if fence.toggle(line) || (fence.open() && settingsLangs[fence.lang]) {
+			continue
+		}
+		for _, re := range shellLocalVarRes {
+			for _, m := range re.FindAllStringSubmatch(line, -1) {
+				local[m[1]] = true
+			}
+		}
+	}
+	return local
+}
+
+// codeFence tracks whether scanning is inside a fenced code block
+type codeFence struct {
+	marker string // the opening fence (e.g. "```" or "~~~~"); empty outside a block
+	lang   string // the opening fence's info string's first word (e.g. "bash")
+}
+
+func (f *codeFence) open() bool {
+	return f.marker != ""
+}
+
+// toggle updates the fence state for line, reporting whether it was a fence.
+// A block closes on a fence of the same character at least as long as the one
+// that opened it, as in CommonMark.
+func (f *codeFence) toggle(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "```") && !strings.HasPrefix(trimmed, "~~~") {
+		return false
+	}
+	marker := trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+
+	if !f.open() {
+		f.marker = marker
+		f.lang = ""
+		if fields := strings.Fields(trimmed[len(marker):]); len(fields) > 0 {
+			f.lang = strings.ToLower(fields[0])
+		}
+		return true
+	}
+	// Closing fences carry no info string
+	if marker[0] == f.marker[0] && len(marker) >= len(f.marker) && strings.TrimSpace(trimmed[len(marker):]) == "" {
+		f.marker = ""
+		return true
+	}
+	return false
+}
+
+// frontmatter holds the frontmatter fields relevant to requirement detection
+type frontmatter struct {
+	Extensions         []string `yaml:"extensions"`
+	IgnoreRequirements []string `yaml:"ignore-requirements"`
+	DetectRequirements string   `yaml:"detect-requirements"`
+}
+
+// parseFrontmatter extracts detection-related fields from YAML frontmatter, if any
+func parseFrontmatter(content string) frontmatter {
+	var fm frontmatter
+	content = markdown.Normalize([]byte(content))
+	if !strings.HasPrefix(content, "---") {
+		return fm
+	}
+	rest := content[3:]
+	idx := strings.Index(rest, "\n---")
+	if idx == -1 {
+		return fm
+	}
+
+	if err := yaml.Unmarshal([]byte(rest[:idx]), &fm); err != nil {
+		return frontmatter{}
+	}
+	return fm
+}
+
+// frontmatterExtensions returns the `extensions` list from YAML frontmatter, if any
+func frontmatterExtensions(content string) []string {
+	return parseFrontmatter(content).Extensions
+}
+
 // FromIncludes infers requirements from included file types
 func FromIncludes(includes []string) []Requirement {
 	var reqs []Requirement
@@ -336,23 +630,7 @@ func Verify(req Requirement) VerifyResult {
 		cmd := exec.Command("node", "-e", "require('"+req.Value+"')")
 		result.Satisfied = cmd.Run() == nil
 		if !result.Satisfied {
-			// Use the original package manager from the instructions
-			pm := req.PackageManager
-			if pm == "" {
-				pm = PMnpm // default
-			}
-			var installCmd string
-			switch pm {
-			case PMbun:
-				installCmd = "bun add " + req.Value
-			case PMyarn:
-				installCmd = "yarn add " + req.Value
-			case PMpnpm:
-				installCmd = "pnpm add " + req.Value
-			default:
-				installCmd = "npm install " + req.Value
-			}
-			result.Message = "Node package not installed: " + req.Value + "\n  Run: " + installCmd
+			result.Message = "Node package not installed: " + req.Value + "\n  Run: " + installCommandString(req)
 		}
 
 	case TypePip:
@@ -360,11 +638,12 @@ func Verify(req Requirement) VerifyResult {
 		cmd := exec.Command("python3", "-c", "import "+req.Value)
 		result.Satisfied = cmd.Run() == nil
 		if !result.Satisfied {
-			pm := req.PackageManager
-			if pm == "" {
-				pm = PMpip
-			}
-			result.Message = "Python package not installed: " + req.Value + "\n  Run: " + string(pm) + " install " + req.Value
+			result.Message = "Python package not installed: " + req.Value + "\n  Run: " + installCommandString(req)
+			break
+		}
+		if installed := installedPipVersion(req.Value); req.Version != "" && installed != "" && !pipVersionSatisfies(installed, req.Version) {
+			result.Satisfied = false
+			result.Message = "Python package " + req.Value + " is " + installed + ", want " + req.Version + "\n  Run: " + installCommandString(req)
 		}
 
 	case TypeBrew:
@@ -372,7 +651,7 @@ func Verify(req Requirement) VerifyResult {
 		_, err := exec.LookPath(req.Value)
 		result.Satisfied = err == nil
 		if !result.Satisfied {
-			result.Message = "Command not found: " + req.Value + "\n  Run: brew install " + req.Value
+			result.Message = "Command not found: " + req.Value + "\n  Run: " + installCommandString(req)
 		}
 
 	case TypeCargo:
@@ -380,7 +659,42 @@ func Verify(req Requirement) VerifyResult {
 		_, err := exec.LookPath(req.Value)
 		result.Satisfied = err == nil
 		if !result.Satisfied {
-			result.Message = "Command not found: " + req.Value + "\n  Run: cargo install " + req.Value
+			result.Message = "Command not found: " + req.Value + "\n  Run: " + installCommandString(req)
+		}
+
+	case TypeGo:
+		// go install puts the binary on PATH (via GOBIN/GOPATH/bin)
+		bin := GoBinaryName(req.Value)
+		_, err := exec.LookPath(bin)
+		result.Satisfied = err == nil
+		if !result.Satisfied {
+			result.Message = "Command not found: " + bin + "\n  Run: " + installCommandString(req)
+		}
+
+	case TypeGem:
+		cmd := exec.Command("gem", "list", "-i", "^"+regexp.QuoteMeta(req.Value)+"$")
+		result.Satisfied = cmd.Run() == nil
+		if !result.Satisfied {
+			result.Message = "Ruby gem not installed: " + req.Value + "\n  Run: " + installCommandString(req)
+		}
+
+	case TypeExtension:
+		if _, err := exec.LookPath("code"); err != nil {
+			// Can't verify without the VS Code CLI; report it for the user to check
+			result.Message = "Cannot verify editor extension (code CLI not found): " + req.Value + "\n  Install it from your editor's extension marketplace"
+			break
+		}
+		out, err := exec.Command("code", "--list-extensions").Output()
+		if err == nil {
+			for _, ext := range strings.Fields(string(out)) {
+				if strings.EqualFold(ext, req.Value) {
+					result.Satisfied = true
+					break
+				}
+			}
+		}
+		if !result.Satisfied {
+			result.Message = "Editor extension not installed: " + req.Value + "\n  Run: " + installCommandString(req)
 		}
 
 	default:
@@ -390,11 +704,120 @@ func Verify(req Requirement) VerifyResult {
 	return result
 }
 
-// VerifyAll checks all requirements and returns results
+// InstallCommand returns the command that installs a requirement, using the
+// package manager it records (see ProjectPackageManager for filling that in
+// from a project). Returns nil for requirements that can't be installed
+// automatically (commands, runtimes, env vars).
+func InstallCommand(req Requirement) []string {
+	switch req.Type {
+	case TypeNPM:
+		pkg := req.Value
+		if req.Version != "" {
+			pkg += "@" + req.Version
+		}
+		switch req.PackageManager {
+		case PMbun:
+			return []string{"bun", "add", pkg}
+		case PMyarn:
+			return []string{"yarn", "add", pkg}
+		case PMpnpm:
+			return []string{"pnpm", "add", pkg}
+		default:
+			return []string{"npm", "install", pkg}
+		}
+	case TypePip:
+		pm := req.PackageManager
+		if pm == "" {
+			pm = PMpip
+		}
+		return []string{string(pm), "install", req.Value + req.Version}
+	case TypeBrew:
+		return []string{"brew", "install", req.Value}
+	case TypeCargo:
+		return []string{"cargo", "install", req.Value}
+	case TypeGo:
+		// go install needs an explicit version outside a module
+		module := req.Value
+		if !strings.Contains(module, "@") {
+			module += "@latest"
+		}
+		return []string{"go", "install", module}
+	case TypeGem:
+		return []string{"gem", "install", req.Value}
+	case TypeExtension:
+		return []string{"code", "--install-extension", req.Value}
+	default:
+		return nil
+	}
+}
+
+// projectLockfiles maps each JavaScript package manager's lockfile to it, in
+// the order they're checked
+var projectLockfiles = []struct {
+	name string
+	pm   PackageManager
+}{
+	{"bun.lockb", PMbun},
+	{"bun.lock", PMbun},
+	{"pnpm-lock.yaml", PMpnpm},
+	{"yarn.lock", PMyarn},
+	{"package-lock.json", PMnpm},
+}
+
+// ProjectPackageManager returns the JavaScript package manager whose
+// lockfile is in dir, or "" when there's none
+func ProjectPackageManager(dir string) PackageManager {
+	for _, lf := range projectLockfiles {
+		if _, err := os.Stat(filepath.Join(dir, lf.name)); err == nil {
+			return lf.pm
+		}
+	}
+	return ""
+}
+
+// GoBinaryName returns the binary go install produces for a module path,
+// e.g. golang.org/x/tools/cmd/goimports@latest -> goimports and
+// example.com/tool/v2 -> tool
+func GoBinaryName(module string) string {
+	module, _, _ = strings.Cut(module, "@")
+	parts := strings.Split(strings.TrimSuffix(module, "/"), "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && majorVersionRe.MatchString(name) {
+		name = parts[len(parts)-2]
+	}
+	return name
+}
+
+// majorVersionRe matches a Go module major version suffix like v2
+var majorVersionRe = regexp.MustCompile(`^v[0-9]+$`)
+
+// installCommandString returns InstallCommand as a single shell-ready string,
+// quoting arguments like bar>=1.4 the shell would otherwise mangle
+func installCommandString(req Requirement) string {
+	args := InstallCommand(req)
+	for i, arg := range args {
+		if strings.ContainsAny(arg, "<>|&;*!^~ ") {
+			args[i] = "'" + arg + "'"
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// IsSecretEnv reports whether an environment variable's name marks it as a
+// credential, by the same API_KEY/SECRET/TOKEN/KEY suffixes env var
+// detection recognizes
+func IsSecretEnv(name string) bool {
+	return secretEnvName.MatchString(name)
+}
+
+// VerifyAll checks all requirements and returns results, skipping ignored ones
 func VerifyAll(reqs []Requirement) []VerifyResult {
-	results := make([]VerifyResult, len(reqs))
-	for i, req := range reqs {
-		results[i] = Verify(req)
+	results := make([]VerifyResult, 0, len(reqs))
+	for _, req := range reqs {
+		if req.Ignored {
+			continue
+		}
+		results = append(results, Verify(req))
 	}
 	return results
 }
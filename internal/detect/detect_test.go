@@ -1,6 +1,9 @@
 package detect
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -94,6 +97,62 @@ You can also use $ANTHROPIC_API_KEY for Claude.
 	}
 }
 
+func TestFromContent_ShellHeavySkill(t *testing.T) {
+	content := "# Release\n\n" +
+		"Set `$RELEASE_BUCKET` first. Copy this into `.env`:\n\n" +
+		"```env\nSENTRY_DSN=https://key@sentry.example.com/1\n```\n\n" +
+		"```bash\n" +
+		"#!/usr/bin/env bash\n" +
+		"set -euo pipefail\n" +
+		"VERSION=$(git describe --tags)\n" +
+		"OUT_DIR=\"${TMPDIR:-/tmp}/release-$VERSION\"\n" +
+		"LOG_LEVEL=\"${LOG_LEVEL:-info}\"\n" +
+		"read -r CONFIRM\n" +
+		"for TARGET in linux darwin; do\n" +
+		"  local ARCHIVE=\"$OUT_DIR/$TARGET.tar.gz\"\n" +
+		"  echo \"Building $TARGET ($RANDOM) into $ARCHIVE at $LINENO\"\n" +
+		"done\n" +
+		"cat > \"$OUT_DIR/notes.sh\" <<'EOF'\n" +
+		"echo \"$DEPLOY_HOST\"\n" +
+		"EOF\n" +
+		"echo \"$GITHUB_OUTPUT $CI\"\n" +
+		"aws s3 cp \"$OUT_DIR\" \"s3://${RELEASE_BUCKET}/$VERSION\" --profile \"$AWS_PROFILE\"\n" +
+		"curl -H \"Authorization: $SENTRY_DSN\" -d \"$CONFIRM\" \"$SLACK_WEBHOOK_URL\"\n" +
+		"```\n\n" +
+		"Needs `$GITHUB_TOKEN` to create the release.\n"
+
+	found := make(map[string]bool)
+	for _, req := range FromContent(content) {
+		if req.Type == TypeEnv {
+			found[req.Value] = true
+		}
+	}
+
+	for _, want := range []string{"RELEASE_BUCKET", "AWS_PROFILE", "SENTRY_DSN", "SLACK_WEBHOOK_URL", "GITHUB_TOKEN"} {
+		if !found[want] {
+			t.Errorf("expected to find %s", want)
+		}
+	}
+	// Locals, defaulted and shell- or CI-provided variables, and those in a
+	// quoted heredoc, are not requirements
+	for _, notWant := range []string{"VERSION", "OUT_DIR", "LOG_LEVEL", "CONFIRM", "TARGET", "ARCHIVE", "TMPDIR", "RANDOM", "LINENO", "DEPLOY_HOST", "GITHUB_OUTPUT", "CI"} {
+		if found[notWant] {
+			t.Errorf("should not detect %s", notWant)
+		}
+	}
+}
+
+func TestFromContent_UnquotedHeredocExpands(t *testing.T) {
+	content := "```sh\ncat <<EOF > config.json\n{\"host\": \"$DEPLOY_HOST\"}\nEOF\n```\n"
+	var found bool
+	for _, req := range FromContent(content) {
+		found = found || (req.Type == TypeEnv && req.Value == "DEPLOY_HOST")
+	}
+	if !found {
+		t.Error("expected to find DEPLOY_HOST, which an unquoted heredoc expands")
+	}
+}
+
 func TestFromContent_IgnoresSystemEnvVars(t *testing.T) {
 	content := `
 The command runs in $HOME directory.
@@ -279,3 +338,473 @@ func TestFromContent_PackageManagerPreserved(t *testing.T) {
 		}
 	}
 }
+
+func TestFromContent_ExtensionFrontmatter(t *testing.T) {
+	content := `---
+name: python-lint
+extensions:
+  - ms-python.python
+  - charliermarsh.ruff
+---
+
+# Python Lint
+
+Install the Python extension (` + "`ms-python.python`" + `) first.
+`
+
+	reqs := FromContent(content)
+
+	found := make(map[string]string)
+	for _, req := range reqs {
+		if req.Type == TypeExtension {
+			found[req.Value] = req.Source
+		}
+	}
+
+	if len(found) != 2 {
+		t.Errorf("expected 2 extension requirements, got %d: %v", len(found), found)
+	}
+	for _, ext := range []string{"ms-python.python", "charliermarsh.ruff"} {
+		if found[ext] != "frontmatter" {
+			t.Errorf("expected %s from frontmatter, got source %q", ext, found[ext])
+		}
+	}
+}
+
+func TestFromContent_ExtensionFrontmatterCRLF(t *testing.T) {
+	content := "\ufeff---\r\nname: python-lint\r\nextensions:\r\n  - ms-python.python\r\n---\r\n# Python Lint\r\n"
+
+	reqs := FromContent(content)
+
+	for _, req := range reqs {
+		if req.Type == TypeExtension && req.Value == "ms-python.python" && req.Source == "frontmatter" {
+			return
+		}
+	}
+	t.Errorf("extension from CRLF frontmatter not detected: %+v", reqs)
+}
+
+func TestFromContent_ExtensionMention(t *testing.T) {
+	testCases := []struct {
+		content string
+		ext     string
+	}{
+		{"Install the ESLint extension (`dbaeumer.vscode-eslint`)", "dbaeumer.vscode-eslint"},
+		{"Requires the Go extension (golang.go) to be enabled", "golang.go"},
+		{"code --install-extension esbenp.prettier-vscode", "esbenp.prettier-vscode"},
+	}
+
+	for _, tc := range testCases {
+		reqs := FromContent(tc.content)
+		found := false
+		for _, req := range reqs {
+			if req.Type == TypeExtension && req.Value == tc.ext {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected to find extension '%s' in '%s'", tc.ext, tc.content)
+		}
+	}
+}
+
+func TestApplyIgnores(t *testing.T) {
+	reqs := []Requirement{
+		{Type: TypeCommand, Value: "jq"},
+		{Type: TypeEnv, Value: "OPENAI_API_KEY"},
+		{Type: TypeEnv, Value: "GITHUB_TOKEN"},
+		{Type: TypeNPM, Value: "prettier"},
+		{Type: TypePip, Value: "Requests"},
+	}
+
+	tests := []struct {
+		name        string
+		patterns    []string
+		wantIgnored []string
+	}{
+		{"no patterns", nil, nil},
+		{"exact match", []string{"command:jq"}, []string{"jq"}},
+		{"type wildcard", []string{"env:*"}, []string{"OPENAI_API_KEY", "GITHUB_TOKEN"}},
+		{"case insensitive", []string{"PIP:requests"}, []string{"Requests"}},
+		{"type must match", []string{"npm:jq"}, nil},
+		{"malformed pattern", []string{"prettier"}, nil},
+		{"multiple", []string{"npm:prettier", " command : jq "}, []string{"jq", "prettier"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplyIgnores(reqs, tt.patterns)
+			if len(got) != len(reqs) {
+				t.Fatalf("ApplyIgnores() dropped requirements: got %d, want %d", len(got), len(reqs))
+			}
+
+			var ignored []string
+			for _, r := range got {
+				if r.Ignored {
+					ignored = append(ignored, r.Value)
+				}
+			}
+			if strings.Join(ignored, ",") != strings.Join(tt.wantIgnored, ",") {
+				t.Errorf("ignored = %v, want %v", ignored, tt.wantIgnored)
+			}
+		})
+	}
+
+	// Original slice must be untouched
+	for _, r := range reqs {
+		if r.Ignored {
+			t.Errorf("ApplyIgnores() mutated input requirement %s", r.Value)
+		}
+	}
+}
+
+func TestIgnoresFromContent(t *testing.T) {
+	content := `---
+name: my-skill
+ignore-requirements:
+  - command:optional-tool
+  - env:*
+---
+
+Requires optional-tool and $SOME_TOKEN.
+`
+
+	got := IgnoresFromContent(content)
+	if len(got) != 2 || got[0] != "command:optional-tool" || got[1] != "env:*" {
+		t.Errorf("IgnoresFromContent() = %v", got)
+	}
+
+	if got := IgnoresFromContent("# No frontmatter"); got != nil {
+		t.Errorf("expected nil without frontmatter, got %v", got)
+	}
+}
+
+func TestLoadIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, IgnoreFileName)
+	content := "# optional tools\ncommand:jq\n\n  env:DEBUG_TOKEN  \npath:drafts/*.md\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadIgnoreFile(path)
+	if err != nil {
+		t.Fatalf("LoadIgnoreFile() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "command:jq" || got[1] != "env:DEBUG_TOKEN" {
+		t.Errorf("LoadIgnoreFile() = %v", got)
+	}
+
+	missing, err := LoadIgnoreFile(filepath.Join(dir, "missing"))
+	if err != nil || missing != nil {
+		t.Errorf("missing file: got %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestVerifyAll_SkipsIgnored(t *testing.T) {
+	reqs := []Requirement{
+		{Type: TypeEnv, Value: "TOME_TEST_UNSET_VAR_A", Ignored: true},
+		{Type: TypeEnv, Value: "TOME_TEST_UNSET_VAR_B"},
+	}
+
+	results := VerifyAll(reqs)
+	if len(results) != 1 || results[0].Requirement.Value != "TOME_TEST_UNSET_VAR_B" {
+		t.Errorf("VerifyAll() = %+v, want only the non-ignored requirement", results)
+	}
+	if !HasUnsatisfied(results) {
+		t.Error("expected unsatisfied result for non-ignored env var")
+	}
+
+	if HasUnsatisfied(VerifyAll(reqs[:1])) {
+		t.Error("ignored requirement should not be reported as unsatisfied")
+	}
+}
+
+func TestProjectPackageManager(t *testing.T) {
+	tests := []struct {
+		lockfile string
+		want     PackageManager
+	}{
+		{"bun.lockb", PMbun},
+		{"bun.lock", PMbun},
+		{"yarn.lock", PMyarn},
+		{"pnpm-lock.yaml", PMpnpm},
+		{"package-lock.json", PMnpm},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lockfile, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.lockfile != "" {
+				if err := os.WriteFile(filepath.Join(dir, tt.lockfile), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := ProjectPackageManager(dir); got != tt.want {
+				t.Errorf("ProjectPackageManager() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Requirement
+		want string
+	}{
+		{"npm default", Requirement{Type: TypeNPM, Value: "foo"}, "npm install foo"},
+		{"npm recorded", Requirement{Type: TypeNPM, Value: "foo", PackageManager: PMnpm}, "npm install foo"},
+		{"bun", Requirement{Type: TypeNPM, Value: "foo", PackageManager: PMbun}, "bun add foo"},
+		{"yarn", Requirement{Type: TypeNPM, Value: "foo", PackageManager: PMyarn}, "yarn add foo"},
+		{"pnpm", Requirement{Type: TypeNPM, Value: "foo", PackageManager: PMpnpm}, "pnpm add foo"},
+		{"pip default", Requirement{Type: TypePip, Value: "requests"}, "pip install requests"},
+		{"pip3", Requirement{Type: TypePip, Value: "requests", PackageManager: PMpip3}, "pip3 install requests"},
+		{"npm with version", Requirement{Type: TypeNPM, Value: "@scope/foo", Version: "^2.0"}, "npm install @scope/foo@^2.0"},
+		{"pip with version", Requirement{Type: TypePip, Value: "bar", Version: ">=1.4,<2"}, "pip install bar>=1.4,<2"},
+		{"brew", Requirement{Type: TypeBrew, Value: "jq"}, "brew install jq"},
+		{"cargo", Requirement{Type: TypeCargo, Value: "ripgrep"}, "cargo install ripgrep"},
+		{"extension", Requirement{Type: TypeExtension, Value: "golang.go"}, "code --install-extension golang.go"},
+		{"go with version", Requirement{Type: TypeGo, Value: "golang.org/x/tools/cmd/goimports@v0.20.0"}, "go install golang.org/x/tools/cmd/goimports@v0.20.0"},
+		{"go without version", Requirement{Type: TypeGo, Value: "example.com/cmd/tool"}, "go install example.com/cmd/tool@latest"},
+		{"gem", Requirement{Type: TypeGem, Value: "rubocop"}, "gem install rubocop"},
+		{"command", Requirement{Type: TypeCommand, Value: "jq"}, ""},
+		{"runtime", Requirement{Type: TypeRuntime, Value: "python3"}, ""},
+		{"env", Requirement{Type: TypeEnv, Value: "API_KEY"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(InstallCommand(tt.req), " ")
+			if got != tt.want {
+				t.Errorf("InstallCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromContent_VersionPinned(t *testing.T) {
+	content := `
+npm install foo@^2.0
+npm i -D @scope/bar@1.2.3
+yarn add baz
+pnpm add "qux@>=3"
+pip install requests==2.31.0
+pip3 install "numpy>=1.24, <2"
+python -m pip install 'pandas~=2.1'
+pip install flask
+`
+	tests := []struct {
+		value, version string
+		typ            RequirementType
+	}{
+		{"foo", "^2.0", TypeNPM},
+		{"@scope/bar", "1.2.3", TypeNPM},
+		{"baz", "", TypeNPM},
+		{"qux", ">=3", TypeNPM},
+		{"requests", "==2.31.0", TypePip},
+		{"numpy", ">=1.24,<2", TypePip},
+		{"pandas", "~=2.1", TypePip},
+		{"flask", "", TypePip},
+	}
+
+	found := make(map[string]Requirement)
+	for _, req := range FromContent(content) {
+		found[req.Value] = req
+	}
+	for _, tt := range tests {
+		req, ok := found[tt.value]
+		if !ok {
+			t.Errorf("expected to find %s", tt.value)
+			continue
+		}
+		if req.Type != tt.typ || req.Version != tt.version {
+			t.Errorf("%s = %s %q, want %s %q", tt.value, req.Type, req.Version, tt.typ, tt.version)
+		}
+	}
+
+	if got := found["numpy"].Label(); got != "numpy (>=1.24,<2)" {
+		t.Errorf("Label() = %q, want %q", got, "numpy (>=1.24,<2)")
+	}
+	if got := installCommandString(found["numpy"]); got != "pip3 install 'numpy>=1.24,<2'" {
+		t.Errorf("installCommandString() = %q", got)
+	}
+}
+
+func TestFromContent_GoAndGem(t *testing.T) {
+	content := `
+# Setup
+
+go install golang.org/x/tools/cmd/goimports@latest
+go install github.com/owner/tool/v2@v2.1.0
+gem install rubocop
+cargo install ripgrep
+go install ./...
+`
+
+	reqs := FromContent(content)
+
+	found := make(map[string]RequirementType)
+	for _, req := range reqs {
+		found[req.Value] = req.Type
+	}
+
+	expected := map[string]RequirementType{
+		"golang.org/x/tools/cmd/goimports@latest": TypeGo,
+		"github.com/owner/tool/v2@v2.1.0":         TypeGo,
+		"rubocop":                                 TypeGem,
+		"ripgrep":                                 TypeCargo,
+	}
+	for value, typ := range expected {
+		if found[value] != typ {
+			t.Errorf("expected %s requirement %q, got %q", typ, value, found[value])
+		}
+	}
+	for value, typ := range found {
+		if typ == TypeGo && expected[value] != TypeGo {
+			t.Errorf("unexpected go requirement %q", value)
+		}
+	}
+}
+
+func TestFromContent_Docker(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"docker run", "docker run --rm -v $(pwd):/work pandoc/core input.md", []string{"docker"}},
+		{"docker compose", "docker compose up -d", []string{"docker"}},
+		{"docker-compose", "docker-compose up -d\ndocker-compose logs", []string{"docker-compose"}},
+		{"both", "docker build -t app .\ndocker-compose up", []string{"docker", "docker-compose"}},
+		{"prose", "Package it as a docker image.", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, req := range FromContent(tt.content) {
+				if req.Type == TypeCommand {
+					got = append(got, req.Value)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("command requirements = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoBinaryName(t *testing.T) {
+	tests := []struct {
+		module string
+		want   string
+	}{
+		{"golang.org/x/tools/cmd/goimports@latest", "goimports"},
+		{"github.com/owner/tool/v2@v2.1.0", "tool"},
+		{"example.com/cmd/tool", "tool"},
+		{"example.com/tool/", "tool"},
+	}
+
+	for _, tt := range tests {
+		if got := GoBinaryName(tt.module); got != tt.want {
+			t.Errorf("GoBinaryName(%q) = %q, want %q", tt.module, got, tt.want)
+		}
+	}
+}
+
+func TestIsSecretEnv(t *testing.T) {
+	tests := map[string]bool{
+		"GITHUB_TOKEN":                 true,
+		"GITHUB_PERSONAL_ACCESS_TOKEN": true,
+		"OPENAI_API_KEY":               true,
+		"MY_SECRET":                    true,
+		"STRIPE_KEY":                   true,
+		"TOKEN":                        false,
+		"GITHUB_HOST":                  false,
+		"TOKEN_URL":                    false,
+		"github_token":                 false,
+	}
+	for name, want := range tests {
+		if got := IsSecretEnv(name); got != want {
+			t.Errorf("IsSecretEnv(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFromContentOptions_ScopedToCodeBlocks(t *testing.T) {
+	content := "---\nextensions: [ms-python.python]\n---\n" +
+		"# Setup\n\n" +
+		"You could run npm install left-pad, or set your API_KEY.\n\n" +
+		"```bash\n" +
+		"npm install prettier\n" +
+		"export GITHUB_TOKEN=...\n" +
+		"```\n\n" +
+		"Afterwards, pip install requests is optional.\n\n" +
+		"~~~~sh\n" +
+		"~~~\n" + // shorter than the opening fence, so the block stays open
+		"brew install jq\n" +
+		"~~~~\n"
+
+	has := func(reqs []Requirement, typ RequirementType, value string) bool {
+		for _, r := range reqs {
+			if r.Type == typ && r.Value == value {
+				return true
+			}
+		}
+		return false
+	}
+
+	scoped := FromContentOptions(content, Options{ScopedToCodeBlocks: true})
+	for _, want := range []struct {
+		typ   RequirementType
+		value string
+	}{
+		{TypeNPM, "prettier"},
+		{TypeEnv, "GITHUB_TOKEN"},
+		{TypeBrew, "jq"},
+		{TypeExtension, "ms-python.python"},
+	} {
+		if !has(scoped, want.typ, want.value) {
+			t.Errorf("scoped detection missed %s:%s in a code block; got %+v", want.typ, want.value, scoped)
+		}
+	}
+	for _, prose := range []struct {
+		typ   RequirementType
+		value string
+	}{
+		{TypeNPM, "left-pad"},
+		{TypeEnv, "API_KEY"},
+		{TypePip, "requests"},
+	} {
+		if has(scoped, prose.typ, prose.value) {
+			t.Errorf("scoped detection reported %s:%s from prose", prose.typ, prose.value)
+		}
+	}
+
+	// The default still scans the whole document
+	all := FromContent(content)
+	if !has(all, TypeNPM, "left-pad") || !has(all, TypeNPM, "prettier") {
+		t.Errorf("FromContent() = %+v, want both prose and code block packages", all)
+	}
+}
+
+func TestFromContent_FrontmatterScopesToCodeBlocks(t *testing.T) {
+	body := "Run npm install left-pad first.\n\n```bash\nnpm install prettier\n```\n"
+
+	values := func(reqs []Requirement) []string {
+		var got []string
+		for _, r := range reqs {
+			got = append(got, r.Value)
+		}
+		return got
+	}
+	if got := values(FromContent("---\ndetect-requirements: code-blocks\n---\n" + body)); len(got) != 1 || got[0] != "prettier" {
+		t.Errorf("with detect-requirements: code-blocks, got %v, want only prettier", got)
+	}
+	if got := values(FromContent("---\nname: demo\n---\n" + body)); len(got) != 2 {
+		t.Errorf("without it, got %v, want left-pad and prettier", got)
+	}
+}
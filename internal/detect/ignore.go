@@ -0,0 +1,92 @@
+package detect
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// IgnoreFileName is the file listing requirements to suppress, one type:value
+// per line. At a collection's root it can also list paths not to install, as
+// path:<glob> lines (see IgnorePathPrefix).
+const IgnoreFileName = ".tomeignore"
+
+// IgnorePathPrefix marks an ignore file line as a path glob rather than a
+// requirement, e.g. path:drafts/*.md
+const IgnorePathPrefix = "path:"
+
+// IgnoresFromContent returns the `ignore-requirements` list from YAML frontmatter, if any
+func IgnoresFromContent(content string) []string {
+	return parseFrontmatter(content).IgnoreRequirements
+}
+
+// LoadIgnoreFile reads type:value patterns from an ignore file.
+// Blank lines, lines starting with # and path: lines are skipped. A missing
+// file is not an error.
+func LoadIgnoreFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, IgnorePathPrefix) {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// ApplyIgnores marks requirements matching any type:value pattern as ignored.
+// A value of * matches every requirement of that type.
+func ApplyIgnores(reqs []Requirement, patterns []string) []Requirement {
+	if len(patterns) == 0 {
+		return reqs
+	}
+
+	result := make([]Requirement, len(reqs))
+	for i, req := range reqs {
+		if !req.Ignored {
+			req.Ignored = matchesIgnore(req, patterns)
+		}
+		result[i] = req
+	}
+	return result
+}
+
+// Active returns the requirements that are not ignored
+func Active(reqs []Requirement) []Requirement {
+	var result []Requirement
+	for _, req := range reqs {
+		if !req.Ignored {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+// matchesIgnore reports whether a requirement matches any type:value pattern
+func matchesIgnore(req Requirement, patterns []string) bool {
+	for _, p := range patterns {
+		typ, value, ok := strings.Cut(p, ":")
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(strings.TrimSpace(typ), string(req.Type)) {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "*" || strings.EqualFold(value, req.Value) {
+			return true
+		}
+	}
+	return false
+}
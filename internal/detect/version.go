@@ -0,0 +1,141 @@
+package detect
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// pipOps are the PEP 440 comparison operators, longest first
+var pipOps = []string{"===", "~=", "==", "!=", "<=", ">=", "<", ">"}
+
+// pipVersion normalizes a captured pip specifier, dropping the spaces
+// between clauses and operators: " >= 1.4 , <2" becomes ">=1.4,<2"
+func pipVersion(spec string) string {
+	return strings.Join(strings.Fields(spec), "")
+}
+
+// installedPipVersion returns the installed version of a Python distribution,
+// or "" when it can't be determined
+func installedPipVersion(name string) string {
+	out, err := exec.Command("python3", "-c", "import importlib.metadata as m; print(m.version('"+name+"'))").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// pipVersionSatisfies reports whether installed meets every clause of a pip
+// specifier like >=1.4,<2 or ==1.4.*. Only release numbers are compared, so
+// 2.0rc1 counts as 2.0. Clauses it can't parse are ignored.
+func pipVersionSatisfies(installed, spec string) bool {
+	have := releaseNumbers(installed)
+	for _, clause := range strings.Split(spec, ",") {
+		clause = strings.TrimSpace(clause)
+		op := ""
+		for _, candidate := range pipOps {
+			if strings.HasPrefix(clause, candidate) {
+				op = candidate
+				break
+			}
+		}
+		want := strings.TrimSpace(strings.TrimPrefix(clause, op))
+		if op == "" || want == "" {
+			continue
+		}
+
+		if op == "===" {
+			if installed != want {
+				return false
+			}
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(want, ".*"); ok && (op == "==" || op == "!=") {
+			if hasReleasePrefix(have, releaseNumbers(prefix)) != (op == "==") {
+				return false
+			}
+			continue
+		}
+
+		wantNums := releaseNumbers(want)
+		cmp := compareRelease(have, wantNums)
+		var ok bool
+		switch op {
+		case "==":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case "<=":
+			ok = cmp <= 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case ">":
+			ok = cmp > 0
+		case "~=":
+			// ~=1.4.2 means >=1.4.2,==1.4.*
+			ok = cmp >= 0 && (len(wantNums) < 2 || hasReleasePrefix(have, wantNums[:len(wantNums)-1]))
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// releaseNumbers parses the release segment of a version (1.4.2 in
+// 1!1.4.2rc1+local), stopping at the first part that isn't a number
+func releaseNumbers(v string) []int {
+	if _, after, ok := strings.Cut(v, "!"); ok {
+		v = after
+	}
+	var nums []int
+	for _, part := range strings.Split(v, ".") {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(part[:end])
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+		if end < len(part) {
+			break
+		}
+	}
+	return nums
+}
+
+// compareRelease compares release numbers, padding the shorter with zeros
+func compareRelease(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// hasReleasePrefix reports whether v starts with prefix, treating missing
+// parts of v as zeros
+func hasReleasePrefix(v, prefix []int) bool {
+	for i, n := range prefix {
+		have := 0
+		if i < len(v) {
+			have = v[i]
+		}
+		if have != n {
+			return false
+		}
+	}
+	return true
+}
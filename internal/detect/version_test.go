@@ -0,0 +1,34 @@
+package detect
+
+import "testing"
+
+func TestPipVersionSatisfies(t *testing.T) {
+	tests := []struct {
+		installed, spec string
+		want            bool
+	}{
+		{"1.4", "==1.4", true},
+		{"1.4.0", "==1.4", true},
+		{"1.5", "==1.4", false},
+		{"1.4.7", "==1.4.*", true},
+		{"1.5.0", "==1.4.*", false},
+		{"1.5.0", "!=1.4.*", true},
+		{"2.0", ">=1.4,<2", false},
+		{"1.9.9", ">=1.4,<2", true},
+		{"1.3", ">=1.4", false},
+		{"2.1.5", "~=2.1", true},
+		{"3.0", "~=2.1", false},
+		{"1.4.5", "~=1.4.2", true},
+		{"1.5.0", "~=1.4.2", false},
+		{"2.0rc1", ">=2.0", true},
+		{"1!1.0", "<2", true},
+		{"1.0", "===1.0", true},
+		{"1.0.0", "===1.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := pipVersionSatisfies(tt.installed, tt.spec); got != tt.want {
+			t.Errorf("pipVersionSatisfies(%q, %q) = %v, want %v", tt.installed, tt.spec, got, tt.want)
+		}
+	}
+}
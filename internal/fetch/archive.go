@@ -0,0 +1,187 @@
+package fetch
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Archive size limits. Files over MaxArchiveFileSize are left out of the
+// snapshot; an archive expanding past MaxArchiveSize is rejected.
+const (
+	MaxArchiveFileSize = 10 * 1024 * 1024  // 10MB
+	MaxArchiveSize     = 200 * 1024 * 1024 // 200MB
+)
+
+// Archive is an in-memory snapshot of a repository, read from the tarball
+// GitHub serves for a ref. Paths are relative to the repo root.
+type Archive struct {
+	files map[string][]byte
+}
+
+// ReadArchive reads a gzipped repository tarball. The top-level directory
+// GitHub wraps the tree in (repo-ref/) is stripped from every path.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	archive := &Archive{files: make(map[string][]byte)}
+	var totalSize int64
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > MaxArchiveFileSize {
+			continue
+		}
+
+		_, rel, ok := strings.Cut(hdr.Name, "/")
+		rel = path.Clean(rel)
+		if !ok || rel == "." || strings.HasPrefix(rel, "../") || path.IsAbs(rel) {
+			continue
+		}
+
+		totalSize += hdr.Size
+		if totalSize > MaxArchiveSize {
+			return nil, fmt.Errorf("archive exceeds max size (%d bytes)", MaxArchiveSize)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from archive: %w", rel, err)
+		}
+		archive.files[rel] = content
+	}
+
+	return archive, nil
+}
+
+// Len returns the number of files in the archive
+func (a *Archive) Len() int {
+	return len(a.files)
+}
+
+// FetchArchive downloads and reads a gzipped repository tarball
+func (c *Client) FetchArchive(archiveURL string) (*Archive, error) {
+	resp, err := c.get(archiveURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", archiveURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %w", archiveURL, c.statusError(archiveURL, resp.StatusCode))
+	}
+	return ReadArchive(resp.Body)
+}
+
+// repoIndex serves directory listings for one repo from a snapshot of its
+// tree, and file contents too when the snapshot is an archive
+type repoIndex struct {
+	archive  *Archive                   // nil when only the tree is known
+	apiBase  string                     // contents API URL of the repo root, without query
+	rawBase  string                     // raw URL prefix for the snapshot's ref
+	paths    map[string]string          // raw URL → repo path
+	listings map[string][]GitHubContent // repo dir ("" for root) → entries
+}
+
+// newRepoIndex indexes files and submodules (entries with repo-relative
+// paths) so every ancestor directory is listable. apiURL and rawURL are as
+// for UseArchive.
+func newRepoIndex(apiURL string, rawURL RawURLFunc, entries []GitHubContent) *repoIndex {
+	apiBase, _, _ := strings.Cut(apiURL, "?")
+	idx := &repoIndex{
+		apiBase:  strings.TrimSuffix(apiBase, "/"),
+		rawBase:  rawURL(""),
+		paths:    make(map[string]string),
+		listings: make(map[string][]GitHubContent),
+	}
+
+	seenDirs := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Type == "file" {
+			entry.DownloadURL = rawURL(entry.Path)
+			idx.paths[entry.DownloadURL] = entry.Path
+		}
+		idx.addEntry(entry)
+
+		// Make each ancestor directory listable from its parent
+		for dir := path.Dir(entry.Path); dir != "." && !seenDirs[dir]; dir = path.Dir(dir) {
+			seenDirs[dir] = true
+			idx.addEntry(GitHubContent{Name: path.Base(dir), Path: dir, Type: "dir"})
+		}
+	}
+	for dir := range idx.listings {
+		sort.Slice(idx.listings[dir], func(i, j int) bool {
+			return idx.listings[dir][i].Name < idx.listings[dir][j].Name
+		})
+	}
+
+	return idx
+}
+
+// UseArchive makes the client answer ListGitHubContents and FetchURL from
+// archive instead of the network. apiURL is the contents API URL of the
+// archived repo's root. rawURL maps a repo path to the raw URL the rest of
+// tome uses for it (and records as an artifact's source URL); listings point
+// their download URLs there. Requests for other repos still go to the network.
+func (c *Client) UseArchive(archive *Archive, apiURL string, rawURL RawURLFunc) {
+	entries := make([]GitHubContent, 0, len(archive.files))
+	for p := range archive.files {
+		entries = append(entries, GitHubContent{Name: path.Base(p), Path: p, Type: "file"})
+	}
+
+	idx := newRepoIndex(apiURL, rawURL, entries)
+	idx.archive = archive
+	c.index = idx
+}
+
+func (idx *repoIndex) addEntry(item GitHubContent) {
+	dir := path.Dir(item.Path)
+	if dir == "." {
+		dir = ""
+	}
+	idx.listings[dir] = append(idx.listings[dir], item)
+}
+
+// listing answers a contents API URL from the index; ok is false for URLs
+// outside the indexed repo
+func (idx *repoIndex) listing(apiURL string) (contents []GitHubContent, ok bool, err error) {
+	base, _, _ := strings.Cut(apiURL, "?")
+	dir, found := strings.CutPrefix(base, idx.apiBase)
+	if !found || (dir != "" && !strings.HasPrefix(dir, "/")) {
+		return nil, false, nil
+	}
+
+	dir = strings.Trim(dir, "/")
+	contents, exists := idx.listings[dir]
+	if !exists {
+		return nil, true, fmt.Errorf("failed to list contents: %s not found in repo tree", dir)
+	}
+	return contents, true, nil
+}
+
+// file answers a raw URL from the archive; ok is false for URLs outside it,
+// or for every URL when the index has no archive
+func (idx *repoIndex) file(rawURL string) (content []byte, ok bool, err error) {
+	if idx.archive == nil || !strings.HasPrefix(rawURL, idx.rawBase) {
+		return nil, false, nil
+	}
+	p, exists := idx.paths[rawURL]
+	if !exists {
+		return nil, true, fmt.Errorf("failed to fetch %s: not found in archive", rawURL)
+	}
+	return idx.archive.files[p], true, nil
+}
@@ -0,0 +1,117 @@
+package fetch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/kennyg/tome/internal/ghclient"
+)
+
+// AccessError reports a request the provider refused (401, 403) or couldn't
+// find (404). For a private repo that usually means missing or insufficient
+// credentials, so the message says how to supply them.
+type AccessError struct {
+	StatusCode    int
+	Host          string
+	Authenticated bool // credentials were available for the host
+}
+
+func (e *AccessError) Error() string {
+	var msg string
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		msg = "authentication failed"
+	case http.StatusForbidden:
+		msg = "access denied"
+	default:
+		msg = "not found; the repo may not exist, or it's private and you lack access"
+	}
+	return fmt.Sprintf("status %d: %s; %s", e.StatusCode, msg, e.hint())
+}
+
+// hint says which credentials the host reads, or to check them if they were sent
+func (e *AccessError) hint() string {
+	host := strings.ToLower(e.Host)
+	switch {
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		if e.Authenticated {
+			return "check that GITLAB_TOKEN can read this project"
+		}
+		return "set GITLAB_TOKEN"
+	case host == "bitbucket.org" || host == "api.bitbucket.org":
+		if e.Authenticated {
+			return "check that BITBUCKET_TOKEN can read this repo"
+		}
+		return "set BITBUCKET_TOKEN"
+	default:
+		if e.Authenticated {
+			return "check that your GitHub token can read this repo"
+		}
+		return "set GITHUB_TOKEN or pass --token"
+	}
+}
+
+// statusError describes an unsuccessful response to a request for rawURL,
+// as an AccessError for 401, 403 and 404
+func (c *Client) statusError(rawURL string, statusCode int) error {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+	default:
+		return fmt.Errorf("status %d", statusCode)
+	}
+
+	var host string
+	if u, err := url.Parse(rawURL); err == nil {
+		host = u.Hostname()
+	}
+	return &AccessError{
+		StatusCode:    statusCode,
+		Host:          host,
+		Authenticated: c.authenticatedFor(host),
+	}
+}
+
+// authenticatedFor reports whether the client has credentials for host
+func (c *Client) authenticatedFor(host string) bool {
+	switch h := strings.ToLower(host); {
+	case h == "gitlab.com" || strings.HasPrefix(h, "gitlab."):
+		return os.Getenv("GITLAB_TOKEN") != ""
+	case h == "bitbucket.org" || h == "api.bitbucket.org":
+		return os.Getenv("BITBUCKET_TOKEN") != ""
+	default:
+		return c.gh.IsAuthenticated()
+	}
+}
+
+// SetToken authenticates GitHub API requests with token instead of the one
+// found in the environment or gh CLI config
+func (c *Client) SetToken(token string) {
+	c.token = token
+	c.gh = c.newGitHubClient("")
+}
+
+// SetUserAgent changes the User-Agent sent with every request, GitHub API
+// ones included, e.g. to tome/1.2.0
+func (c *Client) SetUserAgent(ua string) {
+	c.userAgent = ua
+	c.gh.SetUserAgent(ua)
+}
+
+// ghForHost returns the GitHub client for hostname ("" for github.com)
+func (c *Client) ghForHost(hostname string) *ghclient.Client {
+	if hostname == "" {
+		return c.gh
+	}
+	return c.newGitHubClient(hostname)
+}
+
+// newGitHubClient creates a GitHub client for hostname ("" for github.com)
+// with the client's token, transport and User-Agent
+func (c *Client) newGitHubClient(hostname string) *ghclient.Client {
+	gh := ghclient.NewForHostWithTransport(hostname, c.token, c.transport)
+	gh.SetUserAgent(c.userAgent)
+	return gh
+}
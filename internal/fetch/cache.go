@@ -0,0 +1,125 @@
+package fetch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Cache is an on-disk store of fetched file bodies keyed by URL. Entries
+// carry the response's ETag so FetchURL can revalidate them with
+// If-None-Match and reuse the body when the server answers 304. URLs are
+// stored without their ?token= parameter (see StripToken).
+type Cache struct {
+	dir string
+}
+
+// cacheEntry is the metadata stored beside a cached body
+type cacheEntry struct {
+	URL  string `json:"url"`
+	ETag string `json:"etag"`
+}
+
+// DefaultCacheDir returns tome's fetch cache directory under the user's cache dir
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache directory: %w", err)
+	}
+	return filepath.Join(dir, "tome", "fetch"), nil
+}
+
+// NewCache returns a cache stored in dir, which is created on first write
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir returns the directory the cache is stored in
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// StripToken removes the token query parameter that GitHub adds to raw URLs
+// for private repos. Tokens expire, and mustn't be written to disk.
+func StripToken(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || !u.Query().Has("token") {
+		return rawURL
+	}
+	q := u.Query()
+	q.Del("token")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// path returns the file for rawURL's entry with the given extension
+func (c *Cache) path(rawURL, ext string) string {
+	sum := sha256.Sum256([]byte(StripToken(rawURL)))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+ext)
+}
+
+// Get returns the ETag and body cached for rawURL
+func (c *Cache) Get(rawURL string) (etag string, body []byte, ok bool) {
+	data, err := os.ReadFile(c.path(rawURL, ".json"))
+	if err != nil {
+		return "", nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != StripToken(rawURL) || entry.ETag == "" {
+		return "", nil, false
+	}
+	body, err = os.ReadFile(c.path(rawURL, ".body"))
+	if err != nil {
+		return "", nil, false
+	}
+	return entry.ETag, body, true
+}
+
+// Put stores body under rawURL with its ETag. The body is written before the
+// metadata, so a partial write is never served.
+func (c *Cache) Put(rawURL, etag string, body []byte) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	meta, err := json.Marshal(cacheEntry{URL: StripToken(rawURL), ETag: etag})
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.path(rawURL, ".body"), body); err != nil {
+		return err
+	}
+	return writeFileAtomic(c.path(rawURL, ".json"), meta)
+}
+
+// Clear removes every cached entry
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.dir)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// SetCache makes FetchURL revalidate and store file bodies in cache; nil
+// disables caching
+func (c *Client) SetCache(cache *Cache) {
+	c.cache = cache
+}
@@ -0,0 +1,51 @@
+package fetch
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTimeout bounds each request a new client makes
+const DefaultTimeout = 30 * time.Second
+
+// SetContext makes every request the client sends, GitHub API calls
+// included, stop when ctx is cancelled (e.g. on Ctrl-C). Retries and rate
+// limit waits stop too.
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetTimeout overrides how long each request may take (DefaultTimeout); zero
+// means no limit
+func (c *Client) SetTimeout(d time.Duration) {
+	c.http.Timeout = d
+}
+
+// context returns the context requests run under
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// apiContext returns the context for one GitHub API call: the client's,
+// bounded by the request timeout the go-github client doesn't enforce
+func (c *Client) apiContext() (context.Context, context.CancelFunc) {
+	if c.http != nil && c.http.Timeout > 0 {
+		return context.WithTimeout(c.context(), c.http.Timeout)
+	}
+	return context.WithCancel(c.context())
+}
+
+// sleep waits for d, returning early with the context's error if it's cancelled
+func (c *Client) sleep(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-c.context().Done():
+		return c.context().Err()
+	}
+}
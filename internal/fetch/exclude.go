@@ -0,0 +1,52 @@
+package fetch
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/kennyg/tome/internal/detect"
+)
+
+// ParseIgnorePatterns returns the path globs in .tomeignore content, the
+// lines starting with detect.IgnorePathPrefix. Its other lines are comments
+// or requirements to ignore (see detect.LoadIgnoreFile).
+func ParseIgnorePatterns(content []byte) []string {
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if glob, ok := strings.CutPrefix(line, detect.IgnorePathPrefix); ok && strings.TrimSpace(glob) != "" {
+			patterns = append(patterns, strings.TrimSpace(glob))
+		}
+	}
+	return patterns
+}
+
+// IsExcludedPath reports whether relPath (slash-separated, relative to the
+// collection root) matches any exclude pattern. Patterns are matched with
+// filepath.Match the way .gitignore matches them: a pattern containing a
+// slash is anchored at the root and matched against the path and each of its
+// leading directories, so "drafts/*.md" and "drafts" both exclude drafts/a.md.
+// A pattern without a slash matches a file or directory name at any depth.
+func IsExcludedPath(relPath string, patterns []string) bool {
+	parts := strings.Split(strings.Trim(filepath.ToSlash(relPath), "/"), "/")
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, "/")
+		anchored := strings.Contains(pattern, "/")
+		pattern = filepath.FromSlash(strings.TrimPrefix(pattern, "/"))
+
+		for i := range parts {
+			candidate := parts[i]
+			if anchored {
+				candidate = strings.Join(parts[:i+1], "/")
+			}
+			if ok, _ := filepath.Match(pattern, filepath.FromSlash(candidate)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
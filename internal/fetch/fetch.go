@@ -4,50 +4,210 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/detect"
 	"github.com/kennyg/tome/internal/ghclient"
+	"github.com/kennyg/tome/internal/markdown"
 )
 
 // Client handles fetching artifacts from remote sources
 type Client struct {
 	http *http.Client
 	gh   *ghclient.Client
+
+	// token, when set, authenticates GitHub requests in place of the environment's
+	token string
+
+	// FollowSubmodules makes discovery scan the repos referenced by root-level submodules
+	FollowSubmodules bool
+
+	// SkippedSubmodules records submodule entries that discovery did not follow
+	SkippedSubmodules []GitHubContent
+	submodulesMu      sync.Mutex
+
+	// DuplicateSkills counts skills discovery collapsed because the repo
+	// mirrors them in several directories (see dedupeSkills)
+	DuplicateSkills int
+
+	// Exclude holds glob patterns (relative to the scanned directory) for
+	// artifacts discovery should skip, in addition to the source's .tomeignore
+	Exclude []string
+
+	// NoListingCache disables caching of directory listings by API URL
+	NoListingCache bool
+
+	listingsMu sync.Mutex
+	listings   map[string][]GitHubContent
+
+	// retries is how many times transient request failures are retried
+	retries    int
+	retryDelay time.Duration
+
+	// index, when set, serves a repo's listings (and files, for archives)
+	index *repoIndex
+
+	// cache, when set, keeps fetched file bodies between runs (see SetCache)
+	cache *Cache
+
+	// transport carries every request, GitHub API ones included (see SetCABundle)
+	transport http.RoundTripper
+
+	// ctx, when set, cancels in-flight requests (see SetContext)
+	ctx context.Context
+
+	// maxFileSize caps the bytes FetchURL reads from a response; 0 is no limit
+	maxFileSize int64
+
+	// userAgent identifies tome in every request (see SetUserAgent)
+	userAgent string
+}
+
+// DefaultUserAgent is the User-Agent a new client sends; tome's commands
+// add the version (see SetUserAgent)
+const DefaultUserAgent = "tome"
+
+// githubAPIAccept is the media type GitHub recommends asking its REST API for
+const githubAPIAccept = "application/vnd.github+json"
+
+// DefaultMaxFileSize is the largest file a new client fetches (10MB), far
+// more than any artifact needs
+const DefaultMaxFileSize = 10 * 1024 * 1024
+
+// ErrFileTooLarge is returned, wrapped, for a file over the client's size
+// limit (see SetMaxFileSize)
+var ErrFileTooLarge = errors.New("file too large")
+
+// SetMaxFileSize changes the largest file FetchURL reads (DefaultMaxFileSize)
+// before failing with ErrFileTooLarge; zero or less means no limit
+func (c *Client) SetMaxFileSize(n int64) {
+	c.maxFileSize = max(n, 0)
 }
 
-// NewClient creates a new fetch client
+// readLimited reads r to the end, failing once it's read more than the
+// client's size limit rather than holding all of a huge file in memory
+func (c *Client) readLimited(r io.Reader) ([]byte, error) {
+	if c.maxFileSize == 0 {
+		return io.ReadAll(r)
+	}
+	body, err := io.ReadAll(io.LimitReader(r, c.maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxFileSize {
+		return nil, c.tooLarge()
+	}
+	return body, nil
+}
+
+// tooLarge is the error for a file over the client's size limit
+func (c *Client) tooLarge() error {
+	return fmt.Errorf("%w: over the %d byte limit", ErrFileTooLarge, c.maxFileSize)
+}
+
+// NewClient creates a new fetch client that retries transient failures
+// DefaultRetries times
 func NewClient() *Client {
-	return &Client{
+	return NewClientWithRetries(DefaultRetries)
+}
+
+// NewClientWithRetries creates a new fetch client that retries requests
+// failing with a connection error or 5xx response up to retries times,
+// backing off exponentially. Each attempt times out after DefaultTimeout.
+// Requests go through the proxy $HTTPS_PROXY / $HTTP_PROXY name, unless
+// $NO_PROXY excludes the host.
+func NewClientWithRetries(retries int) *Client {
+	transport := newTransport(nil)
+	c := &Client{
 		http: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   DefaultTimeout,
+			Transport: transport,
 		},
-		gh: ghclient.New(),
+		retries:     max(retries, 0),
+		retryDelay:  defaultRetryDelay,
+		transport:   transport,
+		maxFileSize: DefaultMaxFileSize,
+		userAgent:   DefaultUserAgent,
 	}
+	c.gh = c.newGitHubClient("")
+	return c
 }
 
-// FetchURL fetches content from a URL
+// FetchURL fetches content from a URL. A github.com page for a file (a
+// blob/ or tree/ URL) is fetched from raw.githubusercontent.com instead, and
+// an HTML page served for a GitHub file, e.g. after a redirect, is retried
+// from the file's raw URL.
 func (c *Client) FetchURL(rawURL string) ([]byte, error) {
-	// Try direct fetch first
-	resp, err := c.http.Get(rawURL)
+	if u, err := url.Parse(rawURL); err == nil && strings.EqualFold(u.Hostname(), "github.com") {
+		if raw, ok := RawGitHubURL(rawURL); ok {
+			rawURL = raw
+		}
+	}
+
+	if c.index != nil {
+		if content, ok, err := c.index.file(rawURL); ok {
+			return content, err
+		}
+	}
+
+	// Revalidate a cached copy rather than downloading it again
+	var etag string
+	var cached []byte
+	if c.cache != nil {
+		etag, cached, _ = c.cache.Get(rawURL)
+	}
+
+	// Try direct fetch first (with GitLab/Bitbucket tokens when available)
+	resp, err := c.getIfNoneMatch(rawURL, etag)
 	if err == nil {
 		defer resp.Body.Close()
-		if resp.StatusCode == http.StatusOK {
-			return io.ReadAll(resp.Body)
+		switch {
+		case resp.StatusCode == http.StatusNotModified && etag != "":
+			return cached, nil
+		case resp.StatusCode == http.StatusOK:
+			if isHTML(resp) {
+				if raw, ok := RawGitHubURL(resp.Request.URL.String()); ok {
+					return c.FetchURL(raw)
+				}
+			}
+			if c.maxFileSize > 0 && resp.ContentLength > c.maxFileSize {
+				return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, c.tooLarge())
+			}
+			body, err := c.readLimited(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+			}
+			if c.cache != nil {
+				if etag := resp.Header.Get("ETag"); etag != "" {
+					c.cache.Put(rawURL, etag, body) // Best effort; the fetch succeeded
+				}
+			}
+			return body, nil
 		}
 	}
 
 	// If failed and it's a GitHub URL, try go-github
 	if strings.Contains(rawURL, "github.com") || strings.Contains(rawURL, "githubusercontent.com") {
 		content, ghErr := c.fetchWithGitHub(rawURL)
+		if errors.Is(ghErr, ErrFileTooLarge) {
+			return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, ghErr)
+		}
 		if ghErr == nil {
 			return content, nil
 		}
@@ -56,10 +216,73 @@ func (c *Client) FetchURL(rawURL string) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
 	}
-	return nil, fmt.Errorf("failed to fetch %s: status %d", rawURL, resp.StatusCode)
+	return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, c.statusError(rawURL, resp.StatusCode))
+}
+
+// githubFilePage matches the path of a GitHub page showing a file,
+// /owner/repo/blob/ref/path, or tree/ in place of blob
+var githubFilePage = regexp.MustCompile(`^/([^/]+)/([^/]+)/(?:blob|tree)/(.+)$`)
+
+// RawGitHubURL rewrites the URL of a GitHub page showing a file, like
+// https://github.com/owner/repo/blob/main/SKILL.md, to the URL serving the
+// file itself: on raw.githubusercontent.com for github.com, and under /raw/ on
+// other hosts (GitHub Enterprise). It returns false for any other URL.
+func RawGitHubURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	m := githubFilePage.FindStringSubmatch(u.Path)
+	if m == nil {
+		return "", false
+	}
+	if strings.EqualFold(u.Hostname(), "github.com") {
+		return "https://raw.githubusercontent.com/" + m[1] + "/" + m[2] + "/" + m[3], true
+	}
+	raw := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/" + m[1] + "/" + m[2] + "/raw/" + m[3]}
+	return raw.String(), true
+}
+
+// isHTML reports whether resp is a web page rather than a file's content
+func isHTML(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mediaType == "text/html"
+}
+
+// getWithProviderAuth performs a GET, authenticating to GitLab or Bitbucket
+// from $GITLAB_TOKEN / $BITBUCKET_TOKEN when the URL points at those hosts.
+// GitHub authentication is handled by the go-github fallback instead. A
+// non-empty etag is sent as If-None-Match.
+func (c *Client) getWithProviderAuth(rawURL, etag string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(c.context(), http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	host := strings.ToLower(req.URL.Hostname())
+	if host == "api.github.com" || strings.HasPrefix(req.URL.Path, "/api/v3/") {
+		req.Header.Set("Accept", githubAPIAccept)
+	}
+	switch {
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+	case host == "bitbucket.org" || host == "api.bitbucket.org":
+		if token := os.Getenv("BITBUCKET_TOKEN"); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	return c.http.Do(req)
 }
 
-// fetchWithGitHub fetches file content using go-github
+// fetchWithGitHub fetches file content using go-github, reading no more
+// than the client's size limit
 func (c *Client) fetchWithGitHub(rawURL string) ([]byte, error) {
 	owner, repo, path, hostname, err := ghclient.ParseGitHubURL(rawURL)
 	if err != nil {
@@ -67,12 +290,40 @@ func (c *Client) fetchWithGitHub(rawURL string) ([]byte, error) {
 	}
 
 	// Use appropriate client for the host
-	client := c.gh
-	if hostname != "" {
-		client = ghclient.NewForHost(hostname)
+	client := c.ghForHost(hostname)
+
+	ctx, cancel := c.apiContext()
+	defer cancel()
+	body, err := client.OpenContents(ctx, owner, repo, path)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return c.readLimited(body)
+}
+
+// ListGitHubTags returns the tag names of the GitHub repo apiURL points into
+// (a contents API URL, as for ListGitHubContents)
+func (c *Client) ListGitHubTags(apiURL string) ([]string, error) {
+	owner, repo, _, hostname, err := ghclient.ParseGitHubURL(apiURL)
+	if err != nil {
+		return nil, err
 	}
+	ctx, cancel := c.apiContext()
+	defer cancel()
+	return c.ghForHost(hostname).ListTags(ctx, owner, repo)
+}
 
-	return client.GetContents(context.Background(), owner, repo, path, nil)
+// ResolveGitHubCommit returns the commit SHA ref points to in the GitHub
+// repo apiURL points into
+func (c *Client) ResolveGitHubCommit(apiURL, ref string) (string, error) {
+	owner, repo, _, hostname, err := ghclient.ParseGitHubURL(apiURL)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := c.apiContext()
+	defer cancel()
+	return c.ghForHost(hostname).ResolveCommit(ctx, owner, repo, ref)
 }
 
 // base64Decode decodes base64 content (handles newlines in GitHub's response)
@@ -86,15 +337,115 @@ func base64Decode(s string) ([]byte, error) {
 
 // GitHubContent represents a file/directory in GitHub API response
 type GitHubContent struct {
-	Name        string `json:"name"`
-	Path        string `json:"path"`
-	Type        string `json:"type"` // "file" or "dir"
-	DownloadURL string `json:"download_url"`
-	SkillDir    string `json:"-"` // For skills: the directory containing SKILL.md
+	Name            string `json:"name"`
+	Path            string `json:"path"`
+	Type            string `json:"type"` // "file", "dir", or "submodule"
+	SHA             string `json:"sha"`
+	DownloadURL     string `json:"download_url"`
+	SubmoduleGitURL string `json:"submodule_git_url,omitempty"`
+	SkillDir        string `json:"-"` // For skills: the directory containing SKILL.md
+	RepoAPIURL      string `json:"-"` // For artifacts from a followed submodule: the referenced repo's contents URL
+}
+
+// IsSubmodule reports whether the entry is a git submodule rather than a file or directory
+func (g GitHubContent) IsSubmodule() bool {
+	return g.Type == "submodule" || g.SubmoduleGitURL != ""
 }
 
-// ListGitHubContents lists files in a GitHub directory
+// SubmoduleAPIURL converts a submodule's git URL to a GitHub contents API URL
+// pinned to the submodule commit
+func SubmoduleAPIURL(gitURL, sha string) (string, error) {
+	var host, repoPath string
+	switch {
+	case strings.HasPrefix(gitURL, "https://"), strings.HasPrefix(gitURL, "http://"):
+		u, err := url.Parse(gitURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid submodule URL: %w", err)
+		}
+		host, repoPath = u.Host, u.Path
+	case strings.HasPrefix(gitURL, "git@"):
+		// git@github.com:owner/repo.git
+		hostAndPath := strings.SplitN(strings.TrimPrefix(gitURL, "git@"), ":", 2)
+		if len(hostAndPath) != 2 {
+			return "", fmt.Errorf("invalid submodule URL: %s", gitURL)
+		}
+		host, repoPath = hostAndPath[0], hostAndPath[1]
+	default:
+		return "", fmt.Errorf("unsupported submodule URL: %s", gitURL)
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(repoPath, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid submodule URL: %s", gitURL)
+	}
+
+	var apiURL string
+	if host == "github.com" {
+		apiURL = fmt.Sprintf("https://api.github.com/repos/%s/%s/contents", parts[0], parts[1])
+	} else {
+		apiURL = fmt.Sprintf("https://%s/api/v3/repos/%s/%s/contents", host, parts[0], parts[1])
+	}
+	if sha != "" {
+		apiURL += "?ref=" + sha
+	}
+	return apiURL, nil
+}
+
+// ListGitHubContents lists files in a GitHub directory.
+// Successful listings are cached per API URL for the lifetime of the client,
+// since discovery re-lists the same directories (IsPlugin, FetchManifest, FindArtifacts).
 func (c *Client) ListGitHubContents(apiURL string) ([]GitHubContent, error) {
+	if c.index != nil {
+		if contents, ok, err := c.index.listing(apiURL); ok {
+			return contents, err
+		}
+	}
+	if contents, ok := c.cachedListing(apiURL); ok {
+		return contents, nil
+	}
+
+	contents, err := c.listGitHubContents(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cacheListing(apiURL, contents)
+	return contents, nil
+}
+
+// ClearListingCache drops all cached directory listings
+func (c *Client) ClearListingCache() {
+	c.listingsMu.Lock()
+	defer c.listingsMu.Unlock()
+	c.listings = nil
+}
+
+// cachedListing returns the cached listing for apiURL, if any
+func (c *Client) cachedListing(apiURL string) ([]GitHubContent, bool) {
+	if c.NoListingCache {
+		return nil, false
+	}
+	c.listingsMu.Lock()
+	defer c.listingsMu.Unlock()
+	contents, ok := c.listings[apiURL]
+	return contents, ok
+}
+
+// cacheListing stores a listing for apiURL unless caching is disabled
+func (c *Client) cacheListing(apiURL string, contents []GitHubContent) {
+	if c.NoListingCache {
+		return
+	}
+	c.listingsMu.Lock()
+	defer c.listingsMu.Unlock()
+	if c.listings == nil {
+		c.listings = make(map[string][]GitHubContent)
+	}
+	c.listings[apiURL] = contents
+}
+
+// listGitHubContents fetches a directory listing without consulting the cache
+func (c *Client) listGitHubContents(apiURL string) ([]GitHubContent, error) {
 	// Try go-github first for authenticated access
 	contents, err := c.listWithGitHub(apiURL)
 	if err == nil {
@@ -102,14 +453,14 @@ func (c *Client) ListGitHubContents(apiURL string) ([]GitHubContent, error) {
 	}
 
 	// Fall back to direct HTTP (unauthenticated)
-	resp, err := c.http.Get(apiURL)
+	resp, err := c.get(apiURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list contents: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list contents: status %d", resp.StatusCode)
+		return nil, fmt.Errorf("failed to list contents: %w", c.statusError(apiURL, resp.StatusCode))
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&contents); err != nil {
@@ -127,12 +478,11 @@ func (c *Client) listWithGitHub(apiURL string) ([]GitHubContent, error) {
 	}
 
 	// Use appropriate client for the host
-	client := c.gh
-	if hostname != "" {
-		client = ghclient.NewForHost(hostname)
-	}
+	client := c.ghForHost(hostname)
 
-	repoContents, err := client.ListContents(context.Background(), owner, repo, path, nil)
+	ctx, cancel := c.apiContext()
+	defer cancel()
+	repoContents, err := client.ListContents(ctx, owner, repo, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +491,9 @@ func (c *Client) listWithGitHub(apiURL string) ([]GitHubContent, error) {
 	var contents []GitHubContent
 	for _, rc := range repoContents {
 		content := GitHubContent{
-			Type: rc.GetType(),
+			Type:            rc.GetType(),
+			SHA:             rc.GetSHA(),
+			SubmoduleGitURL: rc.GetSubmoduleGitURL(),
 		}
 		if rc.Name != nil {
 			content.Name = *rc.Name
@@ -179,7 +531,11 @@ func appendPath(baseURL, segment string) string {
 //   - agents/: Any *.md file → agent
 //   - prompts/: Any *.md file → prompt
 //   - hooks/: *.sh files or hooks.json → hook
+//   - Submodules: skipped, or scanned as their own repo when FollowSubmodules is set
 //   - Everything else: IGNORED (docs/, .github/workflows/, src/, etc.)
+//
+// A skill mirrored into several directories (skills/pdf and .claude/skills/pdf)
+// is returned once, counted in DuplicateSkills.
 func (c *Client) FindArtifacts(apiURL string) ([]GitHubContent, error) {
 	contents, err := c.ListGitHubContents(apiURL)
 	if err != nil {
@@ -189,6 +545,12 @@ func (c *Client) FindArtifacts(apiURL string) ([]GitHubContent, error) {
 	var artifacts []GitHubContent
 
 	for _, item := range contents {
+		// Submodules point at another repo; never treat them as files or dirs
+		if item.IsSubmodule() {
+			c.followSubmodule(item, &artifacts)
+			continue
+		}
+
 		// Root level: Only SKILL.md is an artifact
 		if item.Type == "file" && strings.EqualFold(item.Name, artifact.SkillFilename) {
 			artifacts = append(artifacts, item)
@@ -214,8 +576,8 @@ func (c *Client) FindArtifacts(apiURL string) ([]GitHubContent, error) {
 		}
 
 		// Scan prompts/ directory for .md files
-		if item.Type == "dir" && item.Name == "prompts" {
-			c.scanMarkdownDir(apiURL, "prompts", &artifacts)
+		if item.Type == "dir" && item.Name == artifact.PromptsDirName {
+			c.scanMarkdownDir(apiURL, artifact.PromptsDirName, &artifacts)
 			continue
 		}
 
@@ -231,10 +593,137 @@ func (c *Client) FindArtifacts(apiURL string) ([]GitHubContent, error) {
 		}
 	}
 
+	artifacts, duplicates := dedupeSkills(c.excludeArtifacts(contents, artifacts))
+	c.DuplicateSkills += duplicates
 	return artifacts, nil
 }
 
-// scanMarkdownDir scans a directory for .md files (commands, agents, prompts)
+// dedupeSkills collapses skills that are the same file (by git blob SHA) or
+// live in same-named skill directories, as when a repo copies skills/pdf
+// into .claude/skills/pdf for Claude. The copy outside hidden directories,
+// tome's standard skills/, is kept. It returns the artifacts, in discovery
+// order, and how many duplicates were dropped.
+func dedupeSkills(artifacts []GitHubContent) ([]GitHubContent, int) {
+	var kept []GitHubContent
+	seen := make(map[string]int) // Key -> index in kept
+	duplicates := 0
+	for _, item := range artifacts {
+		if !strings.EqualFold(item.Name, artifact.SkillFilename) {
+			kept = append(kept, item)
+			continue
+		}
+
+		var keys []string
+		if item.SHA != "" {
+			keys = append(keys, "sha:"+item.SHA)
+		}
+		if item.SkillDir != "" {
+			keys = append(keys, "name:"+strings.ToLower(path.Base(item.SkillDir)))
+		}
+
+		i, dup := -1, false
+		for _, key := range keys {
+			if i, dup = seen[key]; dup {
+				break
+			}
+		}
+		if !dup {
+			i = len(kept)
+			kept = append(kept, item)
+		} else {
+			duplicates++
+			if inHiddenDir(kept[i].Path) && !inHiddenDir(item.Path) {
+				kept[i] = item
+			}
+		}
+		for _, key := range keys {
+			if _, ok := seen[key]; !ok {
+				seen[key] = i
+			}
+		}
+	}
+	return kept, duplicates
+}
+
+// inHiddenDir reports whether a repo path is inside a dot directory, like
+// .claude/skills/pdf/SKILL.md
+func inHiddenDir(p string) bool {
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		if strings.HasPrefix(dir, ".") && dir != "." {
+			return true
+		}
+	}
+	return false
+}
+
+// excludeArtifacts drops artifacts matching c.Exclude or the .tomeignore in
+// the scanned directory. contents is that directory's listing; its entries'
+// parent is the root that patterns are relative to.
+func (c *Client) excludeArtifacts(contents, artifacts []GitHubContent) []GitHubContent {
+	patterns := c.Exclude
+	root := ""
+	for _, item := range contents {
+		if dir := path.Dir(item.Path); dir != "." {
+			root = dir + "/"
+		}
+		if item.Type == "file" && item.Name == detect.IgnoreFileName && item.DownloadURL != "" {
+			if content, err := c.FetchURL(item.DownloadURL); err == nil {
+				patterns = append(ParseIgnorePatterns(content), patterns...)
+			}
+		}
+	}
+	if len(patterns) == 0 {
+		return artifacts
+	}
+
+	var kept []GitHubContent
+	for _, item := range artifacts {
+		if !IsExcludedPath(strings.TrimPrefix(item.Path, root), patterns) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// skipSubmodule records a submodule that discovery did not follow. Include
+// discovery may run concurrently, so appends are serialized.
+func (c *Client) skipSubmodule(item GitHubContent) {
+	c.submodulesMu.Lock()
+	defer c.submodulesMu.Unlock()
+	c.SkippedSubmodules = append(c.SkippedSubmodules, item)
+}
+
+// followSubmodule scans the repo referenced by a submodule, or records it as skipped
+func (c *Client) followSubmodule(item GitHubContent, artifacts *[]GitHubContent) {
+	if !c.FollowSubmodules {
+		c.skipSubmodule(item)
+		return
+	}
+
+	subURL, err := SubmoduleAPIURL(item.SubmoduleGitURL, item.SHA)
+	if err != nil {
+		c.skipSubmodule(item)
+		return
+	}
+
+	// Disable following while scanning so nested submodules can't recurse forever
+	c.FollowSubmodules = false
+	subArtifacts, err := c.FindArtifacts(subURL)
+	c.FollowSubmodules = true
+	if err != nil {
+		c.skipSubmodule(item)
+		return
+	}
+
+	for _, art := range subArtifacts {
+		if art.RepoAPIURL == "" {
+			art.RepoAPIURL = subURL
+		}
+		*artifacts = append(*artifacts, art)
+	}
+}
+
+// scanMarkdownDir scans a directory for markdown files (commands, agents, prompts)
 func (c *Client) scanMarkdownDir(apiURL string, dirName string, artifacts *[]GitHubContent) {
 	subURL := appendPath(apiURL, dirName)
 	subContents, err := c.ListGitHubContents(subURL)
@@ -243,9 +732,9 @@ func (c *Client) scanMarkdownDir(apiURL string, dirName string, artifacts *[]Git
 	}
 
 	for _, sub := range subContents {
-		if sub.Type == "file" && strings.HasSuffix(strings.ToLower(sub.Name), ".md") {
+		if sub.Type == "file" && IsMarkdownFile(sub.Name) {
 			// Skip meta/documentation files that shouldn't be artifacts
-			if isExcludedFile(sub.Name) {
+			if IsExcludedFile(sub.Name) {
 				continue
 			}
 			*artifacts = append(*artifacts, sub)
@@ -313,6 +802,11 @@ func (c *Client) scanSkillsDir(apiURL string, skillsPath string, artifacts *[]Gi
 			*artifacts = append(*artifacts, sub)
 			continue
 		}
+		// Submodules inside skills/ are only followed from the repo root
+		if sub.IsSubmodule() {
+			c.skipSubmodule(sub)
+			continue
+		}
 		// Check for skill subdirectories with SKILL.md
 		if sub.Type == "dir" {
 			skillURL := appendPath(subURL, sub.Name)
@@ -378,12 +872,15 @@ type Frontmatter struct {
 	Globs        []string `yaml:"globs,omitempty"`
 	Includes     []string `yaml:"includes,omitempty"`      // Optional: limit which files to install
 	AllowedTools []string `yaml:"allowed-tools,omitempty"` // Pre-approved tools for Claude Code
+	Requires     []string `yaml:"requires,omitempty"`      // Sources the skill depends on
+	Tags         []string `yaml:"tags,omitempty"`
 }
 
 // Allowed file extensions for skill includes (security whitelist)
 var allowedExtensions = map[string]bool{
 	// Safe text files
 	".md":   true,
+	".mdx":  true,
 	".txt":  true,
 	".json": true,
 	".yaml": true,
@@ -426,7 +923,15 @@ func ValidateIncludePath(path string) error {
 	// Check extension whitelist
 	ext := strings.ToLower(filepath.Ext(path))
 	if !allowedExtensions[ext] {
-		return fmt.Errorf("file type not allowed: %s (allowed: .md, .txt, .json, .yaml, .yml, .toml, .tmpl)", ext)
+		return fmt.Errorf("file type not allowed: %s (allowed: .md, .mdx, .txt, .json, .yaml, .yml, .toml, .tmpl)", ext)
+	}
+	return nil
+}
+
+// ValidateGlob checks that a frontmatter glob is a well-formed pattern
+func ValidateGlob(glob string) error {
+	if _, err := path.Match(glob, "x"); err != nil {
+		return fmt.Errorf("invalid glob %q: %v", glob, err)
 	}
 	return nil
 }
@@ -481,13 +986,19 @@ func (c *Client) FetchSkillIncludes(baseURL string, skillDir string, includes []
 	return files, nil
 }
 
-// DiscoverSkillFiles auto-discovers all files in a skill directory
-func (c *Client) DiscoverSkillFiles(apiURL string, skillDir string) ([]IncludedFile, error) {
+// RawURLFunc builds the raw content URL for a repo-relative file path
+type RawURLFunc func(path string) string
+
+// DiscoverSkillFiles auto-discovers all files in a skill directory.
+// When rawURL is set, files are fetched from the URLs it builds rather than
+// the listing's download URLs, which may point at the default branch instead
+// of the ref that was listed.
+func (c *Client) DiscoverSkillFiles(apiURL string, skillDir string, rawURL RawURLFunc) ([]IncludedFile, error) {
 	var files []IncludedFile
 	var totalSize int64
 
 	// Recursively discover files
-	err := c.discoverFilesRecursive(apiURL, skillDir, "", &files, &totalSize)
+	err := c.discoverFilesRecursive(apiURL, skillDir, "", rawURL, &files, &totalSize)
 	if err != nil {
 		return nil, err
 	}
@@ -495,7 +1006,7 @@ func (c *Client) DiscoverSkillFiles(apiURL string, skillDir string) ([]IncludedF
 	return files, nil
 }
 
-func (c *Client) discoverFilesRecursive(apiURL string, skillDir string, subPath string, files *[]IncludedFile, totalSize *int64) error {
+func (c *Client) discoverFilesRecursive(apiURL string, skillDir string, subPath string, rawURL RawURLFunc, files *[]IncludedFile, totalSize *int64) error {
 	// Build URL for this directory
 	dirURL := apiURL
 	if skillDir != "" {
@@ -516,9 +1027,15 @@ func (c *Client) discoverFilesRecursive(apiURL string, skillDir string, subPath
 			relPath = subPath + "/" + item.Name
 		}
 
+		if item.IsSubmodule() {
+			// Submodule contents live in another repo; don't treat as an include
+			c.skipSubmodule(item)
+			continue
+		}
+
 		if item.Type == "dir" {
 			// Recurse into subdirectory
-			if err := c.discoverFilesRecursive(apiURL, skillDir, relPath, files, totalSize); err != nil {
+			if err := c.discoverFilesRecursive(apiURL, skillDir, relPath, rawURL, files, totalSize); err != nil {
 				// Skip directories we can't access
 				continue
 			}
@@ -534,8 +1051,12 @@ func (c *Client) discoverFilesRecursive(apiURL string, skillDir string, subPath
 				continue
 			}
 
-			// Fetch the file
-			content, err := c.FetchURL(item.DownloadURL)
+			// Fetch the file, pinned to the listed ref when possible
+			fileURL := item.DownloadURL
+			if rawURL != nil && item.Path != "" {
+				fileURL = rawURL(item.Path)
+			}
+			content, err := c.FetchURL(fileURL)
 			if err != nil {
 				continue // Skip files we can't fetch
 			}
@@ -578,6 +1099,15 @@ func ParseSkill(content []byte, sourceURL string) (*artifact.Artifact, error) {
 		description = extractDescriptionFromContent(body)
 	}
 
+	// Frontmatter wins; README-style skills often put these in the body
+	author, version := extractMetadataFromContent(body)
+	if fm.Author != "" {
+		author = fm.Author
+	}
+	if fm.Version != "" {
+		version = fm.Version
+	}
+
 	// Validate includes
 	var validIncludes []string
 	for _, inc := range fm.Includes {
@@ -587,14 +1117,27 @@ func ParseSkill(content []byte, sourceURL string) (*artifact.Artifact, error) {
 		validIncludes = append(validIncludes, inc)
 	}
 
+	// Validate globs, listing every bad one
+	var badGlobs []string
+	for _, glob := range fm.Globs {
+		if ValidateGlob(glob) != nil {
+			badGlobs = append(badGlobs, strconv.Quote(glob))
+		}
+	}
+	if len(badGlobs) > 0 {
+		return nil, fmt.Errorf("invalid globs: %s", strings.Join(badGlobs, ", "))
+	}
+
 	return &artifact.Artifact{
 		Name:        name,
 		Type:        artifact.TypeSkill,
 		Description: description,
-		Version:     fm.Version,
-		Author:      fm.Author,
+		Version:     version,
+		Author:      author,
 		Globs:       fm.Globs,
 		Includes:    validIncludes,
+		Requires:    fm.Requires,
+		Tags:        fm.Tags,
 		SourceURL:   sourceURL,
 		Content:     string(content),
 		Filename:    artifact.SkillFilename,
@@ -611,7 +1154,7 @@ func ParseCommand(content []byte, filename string, sourceURL string) (*artifact.
 	name := fm.Name
 	if name == "" {
 		// Use filename without extension as name
-		name = strings.TrimSuffix(filename, ".md")
+		name = TrimMarkdownExt(filename)
 	}
 
 	description := fm.Description
@@ -619,12 +1162,21 @@ func ParseCommand(content []byte, filename string, sourceURL string) (*artifact.
 		description = extractDescriptionFromContent(body)
 	}
 
+	author, version := extractMetadataFromContent(body)
+	if fm.Author != "" {
+		author = fm.Author
+	}
+	if fm.Version != "" {
+		version = fm.Version
+	}
+
 	return &artifact.Artifact{
 		Name:        name,
 		Type:        artifact.TypeCommand,
 		Description: description,
-		Version:     fm.Version,
-		Author:      fm.Author,
+		Version:     version,
+		Author:      author,
+		Tags:        fm.Tags,
 		SourceURL:   sourceURL,
 		Content:     string(content),
 		Filename:    filename,
@@ -633,7 +1185,7 @@ func ParseCommand(content []byte, filename string, sourceURL string) (*artifact.
 
 // parseFrontmatter extracts YAML frontmatter from content
 func parseFrontmatter(content []byte) (*Frontmatter, string, error) {
-	text := string(content)
+	text := markdown.Normalize(content)
 	fm := &Frontmatter{}
 
 	// Check for frontmatter delimiter
@@ -659,6 +1211,34 @@ func parseFrontmatter(content []byte) (*Frontmatter, string, error) {
 	return fm, body, nil
 }
 
+// frontmatterName matches the top-level name: line of YAML frontmatter
+var frontmatterName = regexp.MustCompile(`(?m)^name:.*$`)
+
+// RenameInFrontmatter returns content with the name: in its frontmatter set
+// to name, so a renamed artifact presents its new name to the agent. Content
+// whose frontmatter has no name is returned unchanged.
+func RenameInFrontmatter(content, name string) string {
+	if !strings.HasPrefix(strings.TrimPrefix(content, markdown.BOM), "---") {
+		return content
+	}
+	start := strings.Index(content, "---") + 3
+	end := strings.Index(content[start:], "\n---")
+	if end == -1 {
+		return content
+	}
+	end += start
+
+	loc := frontmatterName.FindStringIndex(content[start:end])
+	if loc == nil {
+		return content
+	}
+	line := "name: " + name
+	if strings.HasSuffix(content[start+loc[0]:start+loc[1]], "\r") {
+		line += "\r"
+	}
+	return content[:start+loc[0]] + line + content[start+loc[1]:]
+}
+
 // extractNameFromContent tries to extract a name from the content
 func extractNameFromContent(body string) string {
 	// Look for first H1 heading
@@ -678,8 +1258,9 @@ func extractDescriptionFromContent(body string) string {
 	lines := strings.Split(body, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		// Skip empty lines, headings, bullets, numbered lists, code blocks, horizontal rules
-		if line == "" ||
+		// Skip empty lines, headings, bullets, numbered lists, code blocks,
+		// horizontal rules and Author:/Version: lines
+		if line == "" || metadataLine.MatchString(line) ||
 			strings.HasPrefix(line, "#") ||
 			strings.HasPrefix(line, "- ") ||
 			strings.HasPrefix(line, "* ") ||
@@ -698,6 +1279,45 @@ func extractDescriptionFromContent(body string) string {
 	return ""
 }
 
+// metadataLine matches an author or version line in a markdown body, like
+// "**Author:** Jane", "**Version**: 1.2" or "- Author: Jane"
+var metadataLine = regexp.MustCompile(`(?i)^(?:[-*+]\s+)?(?:\*{1,2}|_{1,2})?(author|version)(?:\*{1,2}|_{1,2})?\s*:\s*(?:\*{1,2}|_{1,2})?\s*(\S.*?)\s*$`)
+
+// markdownLink matches a value that is only a link, like [Jane](https://...)
+var markdownLink = regexp.MustCompile(`^\[([^\]]+)\]\([^)]*\)$`)
+
+// extractMetadataFromContent returns the first author and version lines in a
+// body outside code blocks, for artifacts without them in frontmatter
+func extractMetadataFromContent(body string) (author, version string) {
+	inCode := false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		m := metadataLine.FindStringSubmatch(line)
+		if inCode || m == nil {
+			continue
+		}
+		value := m[2]
+		if link := markdownLink.FindStringSubmatch(value); link != nil {
+			value = link[1]
+		}
+		switch strings.ToLower(m[1]) {
+		case "author":
+			if author == "" {
+				author = value
+			}
+		case "version":
+			if version == "" {
+				version = value
+			}
+		}
+	}
+	return author, version
+}
+
 // DetectArtifactType detects the type of artifact from a filename
 func DetectArtifactType(filename string) artifact.Type {
 	lower := strings.ToLower(filename)
@@ -708,14 +1328,47 @@ func DetectArtifactType(filename string) artifact.Type {
 		return artifact.TypeSkill
 	}
 
-	// Any other .md file is a command
-	if strings.HasSuffix(lower, ".md") {
+	// Any other markdown file is a command
+	if IsMarkdownFile(lower) {
 		return artifact.TypeCommand
 	}
 
 	return ""
 }
 
+// DetectArtifactTypeFromContent is DetectArtifactType with a fallback for
+// filenames without an extension, like a URL ending in /index, that guesses
+// from the content. Frontmatter with a name or fields only skills have
+// (allowed-tools, includes, globs, requires) is a skill, as is a body under a
+// top-level heading; other frontmatter is a command.
+func DetectArtifactTypeFromContent(filename string, content []byte) artifact.Type {
+	if t := DetectArtifactType(filename); t != "" || path.Ext(filename) != "" {
+		return t
+	}
+
+	fm, body, err := parseFrontmatter(content)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case len(fm.AllowedTools) > 0 || len(fm.Includes) > 0 || len(fm.Globs) > 0 || len(fm.Requires) > 0:
+		return artifact.TypeSkill
+	case fm.Name != "":
+		return artifact.TypeSkill
+	case fm.Description != "":
+		return artifact.TypeCommand
+	case hasHeadedBody(body):
+		return artifact.TypeSkill
+	}
+	return ""
+}
+
+// hasHeadedBody reports whether body starts with an H1 that has text under it
+func hasHeadedBody(body string) bool {
+	heading, rest, _ := strings.Cut(strings.TrimSpace(body), "\n")
+	return strings.HasPrefix(heading, "# ") && strings.TrimSpace(rest) != ""
+}
+
 // IsArtifactFile checks if a filename is a potential artifact
 // IsArtifactFile returns true only for SKILL.md files at root level.
 // Other artifacts (commands, agents, prompts) are discovered via directory scanning,
@@ -749,36 +1402,31 @@ var excludedFiles = map[string]bool{
 	"manifest.md":            true,
 }
 
-// isExcludedFile returns true if the filename is a meta/documentation file
+// IsExcludedFile returns true if the filename is a meta/documentation file
 // that should not be treated as an artifact (command, agent, prompt, etc.)
-func isExcludedFile(filename string) bool {
+func IsExcludedFile(filename string) bool {
 	lower := strings.ToLower(filepath.Base(filename))
-	return excludedFiles[lower]
+	return IsMarkdownFile(lower) && excludedFiles[TrimMarkdownExt(lower)+".md"]
 }
 
-// CommandNameFromFile extracts a command name from a filename
-func CommandNameFromFile(filename string) string {
-	base := filepath.Base(filename)
-	name := strings.TrimSuffix(base, filepath.Ext(base))
-	return SanitizeFilename(name)
+// IsMarkdownFile reports whether filename has an extension artifacts are
+// written in: .md, or .mdx for collections using MDX
+func IsMarkdownFile(filename string) bool {
+	ext := strings.ToLower(path.Ext(filename))
+	return ext == ".md" || ext == ".mdx"
 }
 
-// SanitizeFilename makes a filename safe for the filesystem
-func SanitizeFilename(name string) string {
-	// Replace unsafe characters
-	re := regexp.MustCompile(`[^a-zA-Z0-9_-]`)
-	safe := re.ReplaceAllString(name, "-")
-
-	// Remove multiple dashes
-	re = regexp.MustCompile(`-+`)
-	safe = re.ReplaceAllString(safe, "-")
-
-	// Trim dashes from ends
-	safe = strings.Trim(safe, "-")
-
-	if safe == "" {
-		safe = "unnamed"
+// TrimMarkdownExt removes a .md or .mdx extension from filename
+func TrimMarkdownExt(filename string) string {
+	if IsMarkdownFile(filename) {
+		return filename[:len(filename)-len(path.Ext(filename))]
 	}
+	return filename
+}
 
-	return safe
+// CommandNameFromFile extracts a command name from a filename
+func CommandNameFromFile(filename string) string {
+	base := filepath.Base(filename)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	return artifact.SanitizeFilename(name)
 }
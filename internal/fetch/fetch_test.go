@@ -1,9 +1,32 @@
 package fetch
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/detect"
+	"github.com/kennyg/tome/internal/ghclient"
 )
 
 func TestBase64Decode(t *testing.T) {
@@ -151,6 +174,7 @@ func TestValidateIncludePath(t *testing.T) {
 		{"valid ts", "script.ts", false},
 		{"valid rb", "script.rb", false},
 		{"nested valid", "path/to/file.md", false},
+		{"valid mdx", "guide.mdx", false},
 
 		// Invalid paths
 		{"absolute path", "/etc/passwd", true},
@@ -187,6 +211,9 @@ func TestDetectArtifactType(t *testing.T) {
 		{"commit.md", artifact.TypeCommand},
 		{"review-pr.md", artifact.TypeCommand},
 		{"path/to/command.md", artifact.TypeCommand},
+		{"commit.mdx", artifact.TypeCommand},
+		{"path/to/command.MDX", artifact.TypeCommand},
+		{"SKILL.mdx", artifact.TypeCommand},
 		{"readme.txt", ""},
 		{"script.py", ""},
 		{"noextension", ""},
@@ -202,6 +229,53 @@ func TestDetectArtifactType(t *testing.T) {
 	}
 }
 
+func TestDetectArtifactTypeFromContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     artifact.Type
+	}{
+		{"skill fields", "index", "---\nallowed-tools:\n  - Read\n---\nDo things.\n", artifact.TypeSkill},
+		{"includes", "raw", "---\ndescription: Helps\nincludes:\n  - scripts/run.sh\n---\n", artifact.TypeSkill},
+		{"named", "index", "---\nname: helper\ndescription: Helps\n---\nBody\n", artifact.TypeSkill},
+		{"description only", "index", "---\ndescription: Commit staged changes\n---\nWrite a commit.\n", artifact.TypeCommand},
+		{"heading and body", "index", "# Go Testing\n\nWrite table-driven tests.\n", artifact.TypeSkill},
+		{"heading alone", "index", "# Go Testing\n", ""},
+		{"plain text", "index", "just some text\n", ""},
+		{"bad frontmatter", "index", "---\nname: [\n---\n", ""},
+		{"filename wins", "commit.md", "# Go Testing\n\nWrite table-driven tests.\n", artifact.TypeCommand},
+		{"other extension", "script.py", "# comment\n\nprint()\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectArtifactTypeFromContent(tt.filename, []byte(tt.content))
+			if got != tt.want {
+				t.Errorf("DetectArtifactTypeFromContent(%q) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameInFrontmatter(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"rewrites name", "---\nname: pdf\ndescription: PDFs\n---\n# PDF\n", "---\nname: pdf-acme\ndescription: PDFs\n---\n# PDF\n"},
+		{"keeps CRLF", "---\r\nname: pdf\r\n---\r\nBody\r\n", "---\r\nname: pdf-acme\r\n---\r\nBody\r\n"},
+		{"only the frontmatter", "---\ndescription: PDFs\n---\nname: pdf\n", "---\ndescription: PDFs\n---\nname: pdf\n"},
+		{"no frontmatter", "# PDF\nname: pdf\n", "# PDF\nname: pdf\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenameInFrontmatter(tt.content, "pdf-acme"); got != tt.want {
+				t.Errorf("RenameInFrontmatter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsArtifactFile(t *testing.T) {
 	// Note: IsArtifactFile now only returns true for SKILL.md files.
 	// Other artifacts (commands, agents, prompts) are discovered by
@@ -218,6 +292,7 @@ func TestIsArtifactFile(t *testing.T) {
 		{"commit.md", false},
 		{"review-pr.md", false},
 		{"custom-command.md", false},
+		{"commit.mdx", false},
 
 		// Non-artifacts (excluded files)
 		{"README.md", false},
@@ -271,23 +346,29 @@ func TestIsExcludedFile(t *testing.T) {
 		{"Readme.md", true},
 		{"ReadMe.MD", true},
 
+		// MDX versions of the same files
+		{"README.mdx", true},
+		{"CHANGELOG.mdx", true},
+
 		// Actual commands/artifacts should NOT be excluded
 		{"commit.md", false},
 		{"review-pr.md", false},
 		{"custom-command.md", false},
 		{"my-skill.md", false},
 		{"SKILL.md", false},
+		{"commit.mdx", false},
 
 		// Non-markdown files (not excluded by this function, handled elsewhere)
 		{"script.py", false},
 		{"config.yaml", false},
+		{"readme", false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.filename, func(t *testing.T) {
-			got := isExcludedFile(tt.filename)
+			got := IsExcludedFile(tt.filename)
 			if got != tt.want {
-				t.Errorf("isExcludedFile(%q) = %v, want %v", tt.filename, got, tt.want)
+				t.Errorf("IsExcludedFile(%q) = %v, want %v", tt.filename, got, tt.want)
 			}
 		})
 	}
@@ -304,6 +385,8 @@ func TestCommandNameFromFile(t *testing.T) {
 		{"My Command.md", "My-Command"},
 		{"special@chars!.md", "special-chars"},
 		{".md", "unnamed"},
+		{"commit.mdx", "commit"},
+		{"path/to/review-pr.mdx", "review-pr"},
 	}
 
 	for _, tt := range tests {
@@ -316,37 +399,6 @@ func TestCommandNameFromFile(t *testing.T) {
 	}
 }
 
-func TestSanitizeFilename(t *testing.T) {
-	tests := []struct {
-		name string
-		want string
-	}{
-		{"simple", "simple"},
-		{"with-dash", "with-dash"},
-		{"with_underscore", "with_underscore"},
-		{"UPPERCASE", "UPPERCASE"},
-		{"MixedCase123", "MixedCase123"},
-		{"spaces here", "spaces-here"},
-		{"special@#$chars", "special-chars"},
-		{"multiple---dashes", "multiple-dashes"},
-		{"-leading-dash", "leading-dash"},
-		{"trailing-dash-", "trailing-dash"},
-		{"-both-sides-", "both-sides"},
-		{"", "unnamed"},
-		{"@#$%", "unnamed"},
-		{"hello world!", "hello-world"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := SanitizeFilename(tt.name)
-			if got != tt.want {
-				t.Errorf("SanitizeFilename(%q) = %q, want %q", tt.name, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestParseFrontmatter(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -538,6 +590,64 @@ func TestExtractDescriptionFromContent(t *testing.T) {
 	}
 }
 
+func TestExtractMetadataFromContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantAuthor  string
+		wantVersion string
+	}{
+		{"bold labels", "# PDF\n\n**Author:** Jane Doe\n**Version:** 1.2.0\n", "Jane Doe", "1.2.0"},
+		{"bold label before colon", "**Author**: Jane Doe  \n**Version**: v2\n", "Jane Doe", "v2"},
+		{"plain lines", "Author: Jane Doe\nversion: 0.3\n", "Jane Doe", "0.3"},
+		{"bullets", "- **Author:** Jane Doe\n* Version: 1.0\n", "Jane Doe", "1.0"},
+		{"linked author", "**Author:** [Jane Doe](https://example.com/jane)\n", "Jane Doe", ""},
+		{"first wins", "Author: Jane\nAuthor: John\n", "Jane", ""},
+		{"ignores code blocks", "```yaml\nversion: 3\n```\nAuthor: Jane\n", "Jane", ""},
+		{"needs a label", "Written by Jane, version 2 of the skill.\n", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			author, version := extractMetadataFromContent(tt.content)
+			if author != tt.wantAuthor || version != tt.wantVersion {
+				t.Errorf("extractMetadataFromContent() = %q, %q, want %q, %q", author, version, tt.wantAuthor, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestParseSkill_MetadataFromBody(t *testing.T) {
+	body := "# PDF Tools\n\n**Author:** Jane Doe\n**Version:** 1.2.0\n\nWork with PDF files.\n"
+	art, err := ParseSkill([]byte(body), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if art.Author != "Jane Doe" || art.Version != "1.2.0" {
+		t.Errorf("author, version = %q, %q, want Jane Doe, 1.2.0", art.Author, art.Version)
+	}
+	if art.Description != "Work with PDF files." {
+		t.Errorf("description = %q, want the paragraph, not a metadata line", art.Description)
+	}
+
+	// Frontmatter stays authoritative
+	art, err = ParseSkill([]byte("---\nname: pdf\nauthor: John\n---\n"+body), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if art.Author != "John" || art.Version != "1.2.0" {
+		t.Errorf("author, version = %q, %q, want frontmatter's John and the body's 1.2.0", art.Author, art.Version)
+	}
+
+	cmd, err := ParseCommand([]byte("Version: 3\n\nDeploy the app.\n"), "deploy.md", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Version != "3" || cmd.Description != "Deploy the app." {
+		t.Errorf("command version, description = %q, %q", cmd.Version, cmd.Description)
+	}
+}
+
 func TestParseSkill(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -561,6 +671,20 @@ This skill helps with things.`,
 			wantName:  "my-skill",
 			wantDesc:  "Does something cool",
 		},
+		{
+			name:      "skill with CRLF frontmatter",
+			content:   "---\r\nname: windows-skill\r\ndescription: Written on Windows\r\n---\r\n# Windows Skill\r\n",
+			sourceURL: "https://github.com/owner/repo",
+			wantName:  "windows-skill",
+			wantDesc:  "Written on Windows",
+		},
+		{
+			name:      "skill with BOM before frontmatter",
+			content:   "\ufeff---\r\nname: bom-skill\r\ndescription: Starts with a BOM\r\n---\r\nBody\r\n",
+			sourceURL: "https://github.com/owner/repo",
+			wantName:  "bom-skill",
+			wantDesc:  "Starts with a BOM",
+		},
 		{
 			name: "skill without frontmatter",
 			content: `# Auto Named Skill
@@ -577,6 +701,28 @@ name: bad-skill
 includes:
   - ../../../etc/passwd
 ---
+Content`,
+			sourceURL: "https://github.com/owner/repo",
+			wantErr:   true,
+		},
+		{
+			name: "skill with valid globs",
+			content: `---
+name: pdf
+description: Work with PDFs
+globs: ["**/*.pdf", "docs/[a-z]*.md"]
+---
+Content`,
+			sourceURL: "https://github.com/owner/repo",
+			wantName:  "pdf",
+			wantDesc:  "Work with PDFs",
+		},
+		{
+			name: "skill with malformed glob",
+			content: `---
+name: pdf
+globs: ["**/*.pdf", "[unterminated"]
+---
 Content`,
 			sourceURL: "https://github.com/owner/repo",
 			wantErr:   true,
@@ -589,6 +735,8 @@ Content`,
 			if tt.wantErr {
 				if err == nil {
 					t.Error("expected error, got nil")
+				} else if strings.Contains(tt.content, "[unterminated") && !strings.Contains(err.Error(), `"[unterminated"`) {
+					t.Errorf("error = %v, want it to name the bad glob", err)
 				}
 				return
 			}
@@ -643,6 +791,16 @@ This command helps review PRs.`,
 			wantName:  "review-pr",
 			wantDesc:  "This command helps review PRs.",
 		},
+		{
+			name: "mdx command without frontmatter",
+			content: `# Review PR
+
+This command helps review PRs.`,
+			filename:  "review-pr.mdx",
+			sourceURL: "https://github.com/owner/repo",
+			wantName:  "review-pr",
+			wantDesc:  "This command helps review PRs.",
+		},
 	}
 
 	for _, tt := range tests {
@@ -681,6 +839,281 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClient_HonorsProxyEnvironment(t *testing.T) {
+	transport, ok := NewClient().http.Transport.(*http.Transport)
+	if !ok || transport.Proxy == nil {
+		t.Fatal("client transport doesn't read the proxy from the environment")
+	}
+}
+
+func TestRawGitHubURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		want   string
+		wantOK bool
+	}{
+		{"https://github.com/owner/repo/blob/main/skills/pdf/SKILL.md", "https://raw.githubusercontent.com/owner/repo/main/skills/pdf/SKILL.md", true},
+		{"https://github.com/owner/repo/tree/v1.2/SKILL.md", "https://raw.githubusercontent.com/owner/repo/v1.2/SKILL.md", true},
+		{"https://github.com/owner/repo/blob/main/SKILL.md?plain=1#L3", "https://raw.githubusercontent.com/owner/repo/main/SKILL.md", true},
+		{"https://github.company.com/team/repo/blob/main/SKILL.md", "https://github.company.com/team/repo/raw/main/SKILL.md", true},
+		{"https://raw.githubusercontent.com/owner/repo/main/SKILL.md", "", false},
+		{"https://github.com/owner/repo", "", false},
+		{"https://gitlab.com/group/repo/-/blob/main/SKILL.md", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := RawGitHubURL(tt.url)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("RawGitHubURL(%q) = %q, %v; want %q, %v", tt.url, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestFetchURL_HTMLFallsBackToRaw(t *testing.T) {
+	const skill = "---\nname: pdf\n---\n# PDF\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/go/pdf":
+			http.Redirect(w, r, "/owner/repo/blob/main/SKILL.md", http.StatusFound)
+		case "/owner/repo/blob/main/SKILL.md":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, "<!DOCTYPE html><html>page</html>")
+		case "/owner/repo/raw/main/SKILL.md":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprint(w, skill)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithRetries(0)
+	for _, path := range []string{"/owner/repo/blob/main/SKILL.md", "/go/pdf"} {
+		body, err := client.FetchURL(server.URL + path)
+		if err != nil {
+			t.Fatalf("FetchURL(%s) error = %v", path, err)
+		}
+		if string(body) != skill {
+			t.Errorf("FetchURL(%s) = %q, want the raw file", path, body)
+		}
+	}
+}
+
+func TestClient_SendsUserAgent(t *testing.T) {
+	agents := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents[r.URL.Path] = r.Header.Get("User-Agent")
+		if r.URL.Path == "/repos/owner/repo/git/trees/main" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"sha": "abc", "truncated": false, "tree": []}`))
+			return
+		}
+		w.Write([]byte("# Skill\n"))
+	}))
+	defer srv.Close()
+
+	gh, err := ghclient.NewForBaseURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClientWithRetries(0)
+	if _, err := client.FetchURL(srv.URL + "/default.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	client.gh = gh
+	client.SetUserAgent("tome/1.2.3")
+	if _, err := client.FetchURL(srv.URL + "/SKILL.md"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UseTree("https://api.github.com/repos/owner/repo/contents?ref=main", func(path string) string { return srv.URL + "/" + path }); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"/default.md":                      DefaultUserAgent,
+		"/SKILL.md":                        "tome/1.2.3",
+		"/repos/owner/repo/git/trees/main": "tome/1.2.3",
+	}
+	for path, ua := range want {
+		if agents[path] != ua {
+			t.Errorf("User-Agent for %s = %q, want %q", path, agents[path], ua)
+		}
+	}
+}
+
+func TestFetchURL_MaxFileSize(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked" {
+			// Flushing first leaves the length unknown, so only reading finds it
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	client := NewClientWithRetries(0)
+	client.SetMaxFileSize(64)
+	for _, path := range []string{"/sized", "/chunked"} {
+		_, err := client.FetchURL(server.URL + path)
+		if !errors.Is(err, ErrFileTooLarge) {
+			t.Errorf("FetchURL(%s) error = %v, want ErrFileTooLarge", path, err)
+		}
+	}
+
+	client.SetMaxFileSize(100)
+	if got, err := client.FetchURL(server.URL + "/chunked"); err != nil || string(got) != body {
+		t.Errorf("FetchURL() at the limit = %q, %v, want the file", got, err)
+	}
+	client.SetMaxFileSize(0)
+	if _, err := client.FetchURL(server.URL + "/sized"); err != nil {
+		t.Errorf("FetchURL() with no limit error = %v", err)
+	}
+}
+
+// roundTripFunc serves a client's direct requests from a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestFetchURL_MaxFileSizeThroughGitHubAPI(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/contents/big.md" || r.Header.Get("Accept") != "application/vnd.github.raw" {
+			http.NotFound(w, r)
+			return
+		}
+		// Flushing first leaves the length unknown, so only reading finds it
+		w.(http.Flusher).Flush()
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	gh, err := ghclient.NewForBaseURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClientWithRetries(0)
+	client.gh = gh
+	// The raw URL fails, so the file comes from the contents API instead
+	client.http.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: r}, nil
+	})
+	rawURL := "https://raw.githubusercontent.com/owner/repo/main/big.md"
+
+	client.SetMaxFileSize(64)
+	if _, err := client.FetchURL(rawURL); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("FetchURL() error = %v, want ErrFileTooLarge", err)
+	}
+	client.SetMaxFileSize(100)
+	if got, err := client.FetchURL(rawURL); err != nil || string(got) != body {
+		t.Errorf("FetchURL() at the limit = %q, %v, want the file", got, err)
+	}
+}
+
+func TestSetContext_CancelAbortsFetch(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClient()
+	client.SetContext(ctx)
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.FetchURL(server.URL)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("FetchURL() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("FetchURL() took %v after cancellation", elapsed)
+	}
+}
+
+func TestSetTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClientWithRetries(0)
+	client.SetTimeout(50 * time.Millisecond)
+	if _, err := client.FetchURL(server.URL); err == nil {
+		t.Fatal("FetchURL() outlived the timeout")
+	}
+}
+
+func TestSetCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "trusted")
+	}))
+	defer server.Close()
+
+	// The server's self-signed certificate stands in for a corporate CA
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, cert, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClientWithRetries(0)
+	if _, err := client.FetchURL(server.URL); err == nil {
+		t.Fatal("FetchURL() trusted the server without its CA")
+	}
+
+	if err := client.SetCABundle(bundle); err != nil {
+		t.Fatalf("SetCABundle() error = %v", err)
+	}
+	transport, ok := client.http.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil || transport.TLSClientConfig.RootCAs == nil {
+		t.Fatal("SetCABundle() didn't configure the transport's root CAs")
+	}
+	if transport.Proxy == nil {
+		t.Error("SetCABundle() dropped the proxy setting")
+	}
+	if client.gh == nil {
+		t.Error("SetCABundle() left no GitHub client")
+	}
+
+	body, err := client.FetchURL(server.URL)
+	if err != nil {
+		t.Fatalf("FetchURL() error = %v", err)
+	}
+	if string(body) != "trusted" {
+		t.Errorf("FetchURL() = %q, want %q", body, "trusted")
+	}
+
+	t.Run("bundle without certificates", func(t *testing.T) {
+		empty := filepath.Join(t.TempDir(), "empty.pem")
+		if err := os.WriteFile(empty, []byte("not a certificate\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewClient().SetCABundle(empty); err == nil {
+			t.Error("SetCABundle() accepted a file with no certificates")
+		}
+	})
+
+	t.Run("missing bundle", func(t *testing.T) {
+		if err := NewClient().SetCABundle(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+			t.Error("SetCABundle() accepted a missing file")
+		}
+	})
+}
+
 func TestGitHubContent(t *testing.T) {
 	content := GitHubContent{
 		Name:        "SKILL.md",
@@ -698,6 +1131,289 @@ func TestGitHubContent(t *testing.T) {
 	}
 }
 
+func TestGitHubContent_IsSubmodule(t *testing.T) {
+	tests := []struct {
+		name    string
+		content GitHubContent
+		want    bool
+	}{
+		{"file", GitHubContent{Name: "SKILL.md", Type: "file"}, false},
+		{"dir", GitHubContent{Name: "skills", Type: "dir"}, false},
+		{"submodule type", GitHubContent{Name: "shared", Type: "submodule"}, true},
+		{"file with submodule url", GitHubContent{Name: "shared", Type: "file", SubmoduleGitURL: "https://github.com/o/r.git"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.content.IsSubmodule(); got != tt.want {
+				t.Errorf("IsSubmodule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubmoduleAPIURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		gitURL  string
+		sha     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "https github",
+			gitURL: "https://github.com/owner/shared-skills.git",
+			sha:    "abc123",
+			want:   "https://api.github.com/repos/owner/shared-skills/contents?ref=abc123",
+		},
+		{
+			name:   "ssh github",
+			gitURL: "git@github.com:owner/shared-skills.git",
+			want:   "https://api.github.com/repos/owner/shared-skills/contents",
+		},
+		{
+			name:   "enterprise host",
+			gitURL: "https://github.example.com/team/skills",
+			sha:    "def456",
+			want:   "https://github.example.com/api/v3/repos/team/skills/contents?ref=def456",
+		},
+		{
+			name:    "relative url",
+			gitURL:  "../shared.git",
+			wantErr: true,
+		},
+		{
+			name:    "missing repo",
+			gitURL:  "https://github.com/owner",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SubmoduleAPIURL(tt.gitURL, tt.sha)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SubmoduleAPIURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindArtifacts_SkipsSubmodules(t *testing.T) {
+	listing := `[
+		{"name": "SKILL.md", "path": "SKILL.md", "type": "file", "download_url": "https://example.com/SKILL.md"},
+		{"name": "shared", "path": "shared", "type": "submodule", "sha": "abc123",
+		 "submodule_git_url": "https://github.com/owner/shared-skills.git"},
+		{"name": "skills", "path": "skills", "type": "file", "sha": "def456",
+		 "submodule_git_url": "https://github.com/owner/more-skills.git"}
+	]`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/listing" {
+			t.Errorf("unexpected request for %s (submodule should not be followed)", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(listing))
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	artifacts, err := client.FindArtifacts(srv.URL + "/listing")
+	if err != nil {
+		t.Fatalf("FindArtifacts() error = %v", err)
+	}
+
+	if len(artifacts) != 1 || artifacts[0].Name != "SKILL.md" {
+		t.Errorf("expected only SKILL.md, got %+v", artifacts)
+	}
+	if len(client.SkippedSubmodules) != 2 {
+		t.Fatalf("expected 2 skipped submodules, got %d", len(client.SkippedSubmodules))
+	}
+	if client.SkippedSubmodules[0].SubmoduleGitURL != "https://github.com/owner/shared-skills.git" {
+		t.Errorf("SubmoduleGitURL = %q", client.SkippedSubmodules[0].SubmoduleGitURL)
+	}
+}
+
+func TestDiscoverSkillFiles_FetchesFromPinnedRef(t *testing.T) {
+	var srv *httptest.Server
+	var fetched []string
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/contents/skills/demo":
+			// Download URLs point at the default branch, not the pinned ref
+			fmt.Fprintf(w, `[
+				{"name": "SKILL.md", "path": "skills/demo/SKILL.md", "type": "file", "download_url": "%[1]s/raw/main/skills/demo/SKILL.md"},
+				{"name": "guide.md", "path": "skills/demo/guide.md", "type": "file", "download_url": "%[1]s/raw/main/skills/demo/guide.md"},
+				{"name": "scripts", "path": "skills/demo/scripts", "type": "dir"}
+			]`, srv.URL)
+		case r.URL.Path == "/contents/skills/demo/scripts":
+			fmt.Fprintf(w, `[
+				{"name": "run.sh", "path": "skills/demo/scripts/run.sh", "type": "file", "download_url": "%s/raw/main/skills/demo/scripts/run.sh"}
+			]`, srv.URL)
+		case strings.HasPrefix(r.URL.Path, "/raw/"):
+			fetched = append(fetched, r.URL.Path)
+			w.Write([]byte("content"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	rawURL := func(path string) string {
+		return srv.URL + "/raw/v1.2.0/" + path
+	}
+
+	client := NewClient()
+	files, err := client.DiscoverSkillFiles(srv.URL+"/contents?ref=v1.2.0", "skills/demo", rawURL)
+	if err != nil {
+		t.Fatalf("DiscoverSkillFiles() error = %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("expected 2 included files, got %+v", files)
+	}
+	if len(fetched) != 2 {
+		t.Fatalf("expected 2 file fetches, got %v", fetched)
+	}
+	for _, path := range fetched {
+		if !strings.Contains(path, "/v1.2.0/") {
+			t.Errorf("include fetched from %q, want the pinned ref v1.2.0", path)
+		}
+	}
+}
+
+func TestListGitHubContents_CachesListings(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"name": "SKILL.md", "path": "SKILL.md", "type": "file"}]`))
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	first, err := client.ListGitHubContents(srv.URL + "/listing")
+	if err != nil {
+		t.Fatalf("ListGitHubContents() error = %v", err)
+	}
+	second, err := client.ListGitHubContents(srv.URL + "/listing")
+	if err != nil {
+		t.Fatalf("ListGitHubContents() error = %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	if len(second) != 1 || &first[0] != &second[0] {
+		t.Errorf("expected the cached slice on second call, got %+v", second)
+	}
+
+	client.ClearListingCache()
+	if _, err := client.ListGitHubContents(srv.URL + "/listing"); err != nil {
+		t.Fatalf("ListGitHubContents() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected a new request after ClearListingCache, got %d requests", requests)
+	}
+}
+
+func TestListGitHubContents_NoListingCache(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	client.NoListingCache = true
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListGitHubContents(srv.URL + "/listing"); err != nil {
+			t.Fatalf("ListGitHubContents() error = %v", err)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 requests with caching disabled, got %d", requests)
+	}
+}
+
+func TestListGitHubContents_DoesNotCacheErrors(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	// Without retries, the first call sees the 503
+	client := NewClientWithRetries(0)
+	if _, err := client.ListGitHubContents(srv.URL + "/listing"); err == nil {
+		t.Fatal("expected error on first call")
+	}
+	if _, err := client.ListGitHubContents(srv.URL + "/listing"); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+}
+
+func TestListGitHubContents_RateLimited(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		http.Error(w, "API rate limit exceeded", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	_, err := client.ListGitHubContents(srv.URL + "/listing")
+	if err == nil {
+		t.Fatal("expected rate limit error")
+	}
+
+	var rlErr *RateLimitError
+	if !errors.As(err, &rlErr) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if want := reset.Local().Format(time.Kitchen); !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not include reset time %q", err, want)
+	}
+
+	if _, err := client.FetchURL(srv.URL + "/SKILL.md"); err == nil || !errors.As(err, &rlErr) {
+		t.Errorf("FetchURL() expected RateLimitError, got %v", err)
+	}
+}
+
+func TestFetchURL_RetriesWhenRateLimitResetsSoon(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+			http.Error(w, "API rate limit exceeded", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	content, err := NewClient().FetchURL(srv.URL + "/SKILL.md")
+	if err != nil {
+		t.Fatalf("FetchURL() error = %v", err)
+	}
+	if string(content) != "ok" || requests != 2 {
+		t.Errorf("got %q after %d requests, want ok after 2", content, requests)
+	}
+}
+
 func TestFrontmatter(t *testing.T) {
 	fm := Frontmatter{
 		Name:         "test-skill",
@@ -743,3 +1459,856 @@ func TestConstants(t *testing.T) {
 		t.Errorf("MaxTotalIncludeSize = %d, want %d", MaxTotalIncludeSize, 1024*1024)
 	}
 }
+
+func TestFlattenSkill(t *testing.T) {
+	content := "---\nname: test\n---\n\n# Test Skill\n"
+	includes := []IncludedFile{
+		{Path: "reference.md", Content: []byte("Some `inline` notes\n")},
+		{Path: "config/settings.json", Content: []byte(`{"a": 1}`)},
+		{Path: "scripts/run.sh", Content: []byte("#!/bin/bash\necho hi\n")},
+		{Path: "tool", Content: []byte("#!/usr/bin/env node\n")},
+		{Path: "data.bin", Content: []byte{0x00, 0x01, 0x02}},
+	}
+
+	got, kept := FlattenSkill(content, includes)
+
+	if !strings.Contains(got, BundledFilesHeading) {
+		t.Errorf("missing bundled files heading:\n%s", got)
+	}
+	if !strings.Contains(got, "### `reference.md`\n\n```markdown\nSome `inline` notes\n```") {
+		t.Errorf("reference.md not inlined as expected:\n%s", got)
+	}
+	if !strings.Contains(got, "```json\n{\"a\": 1}\n```") {
+		t.Errorf("settings.json not inlined as expected:\n%s", got)
+	}
+	if !strings.HasPrefix(got, "---\nname: test\n---\n\n# Test Skill\n\n") {
+		t.Errorf("original content not preserved:\n%s", got)
+	}
+
+	var keptPaths []string
+	for _, inc := range kept {
+		keptPaths = append(keptPaths, inc.Path)
+	}
+	want := []string{"scripts/run.sh", "tool", "data.bin"}
+	if strings.Join(keptPaths, ",") != strings.Join(want, ",") {
+		t.Errorf("kept = %v, want %v", keptPaths, want)
+	}
+}
+
+func TestFlattenSkill_NothingToInline(t *testing.T) {
+	content := "# Skill\n"
+	includes := []IncludedFile{{Path: "run.py", Content: []byte("print(1)\n")}}
+
+	got, kept := FlattenSkill(content, includes)
+	if got != content {
+		t.Errorf("content changed with nothing to inline: %q", got)
+	}
+	if len(kept) != 1 {
+		t.Errorf("expected script to be kept, got %d", len(kept))
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"plain text", "```"},
+		{"has `code` here", "```"},
+		{"nested ```go\nx\n``` fence", "````"},
+		{"five ````` ticks", "``````"},
+	}
+
+	for _, tt := range tests {
+		if got := codeFence(tt.body); got != tt.want {
+			t.Errorf("codeFence(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestLintArtifact(t *testing.T) {
+	longDesc := strings.Repeat("a", MaxDescriptionLength+1)
+
+	tests := []struct {
+		name       string
+		filename   string
+		content    string
+		wantFields []string // field of each issue, in order
+		wantErrors bool
+	}{
+		{
+			name:     "clean skill",
+			filename: "SKILL.md",
+			content:  "---\nname: pdf\ndescription: Work with PDFs\nglobs: [\"**/*.pdf\"]\nincludes: [ref.md]\n---\n# PDF\n",
+		},
+		{
+			name:     "clean skill from Windows",
+			filename: "SKILL.md",
+			content:  "\ufeff---\r\nname: pdf\r\ndescription: Work with PDFs\r\n---\r\n# PDF\r\n",
+		},
+		{
+			name:       "unclosed frontmatter",
+			filename:   "SKILL.md",
+			content:    "---\nname: pdf\n# PDF\n",
+			wantFields: []string{"frontmatter"},
+			wantErrors: true,
+		},
+		{
+			name:       "malformed yaml",
+			filename:   "SKILL.md",
+			content:    "---\nname: [pdf\n---\n# PDF\n",
+			wantFields: []string{"frontmatter"},
+			wantErrors: true,
+		},
+		{
+			name:       "no frontmatter",
+			filename:   "SKILL.md",
+			content:    "# PDF\n\nWork with PDFs.\n",
+			wantFields: []string{"frontmatter", "name", "description"},
+		},
+		{
+			name:       "command without name is fine",
+			filename:   "deploy.md",
+			content:    "---\ndescription: Deploy\n---\n# Deploy\n",
+			wantFields: nil,
+		},
+		{
+			name:       "long description",
+			filename:   "SKILL.md",
+			content:    "---\nname: pdf\ndescription: " + longDesc + "\n---\n",
+			wantFields: []string{"description"},
+		},
+		{
+			name:       "bad includes and globs",
+			filename:   "SKILL.md",
+			content:    "---\nname: pdf\ndescription: PDFs\nincludes: [../secret.md, run.exe]\nglobs: [\"[unclosed\"]\n---\n",
+			wantFields: []string{"includes", "includes", "globs"},
+			wantErrors: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := LintArtifact([]byte(tt.content), tt.filename)
+			var fields []string
+			for _, issue := range issues {
+				fields = append(fields, issue.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("LintArtifact() issues = %+v, want fields %v", issues, tt.wantFields)
+			}
+			if got := HasLintErrors(issues); got != tt.wantErrors {
+				t.Errorf("HasLintErrors() = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestIsExcludedPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		patterns []string
+		want     bool
+	}{
+		{"commands/deploy.md", nil, false},
+		{"drafts/idea.md", []string{"drafts/*.md"}, true},
+		{"drafts/idea.md", []string{"drafts"}, true},
+		{"drafts/idea.md", []string{"drafts/"}, true},
+		{"commands/drafts/idea.md", []string{"drafts"}, true},
+		{"commands/drafts/idea.md", []string{"drafts/*.md"}, false},
+		{"commands/drafts/idea.md", []string{"commands/drafts/*.md"}, true},
+		{"commands/drafts/idea.md", []string{"*/drafts"}, true},
+		{"skills/wip/SKILL.md", []string{"skills/wip"}, true},
+		{"skills/wip/SKILL.md", []string{"/skills/wip"}, true},
+		{"skills/done/SKILL.md", []string{"skills/wip"}, false},
+		{"commands/idea.draft.md", []string{"*.draft.md"}, true},
+		{"commands/deploy.md", []string{"*.draft.md", "[", "deploy.md"}, true},
+	}
+	for _, tt := range tests {
+		if got := IsExcludedPath(tt.path, tt.patterns); got != tt.want {
+			t.Errorf("IsExcludedPath(%q, %q) = %v, want %v", tt.path, tt.patterns, got, tt.want)
+		}
+	}
+}
+
+func TestParseIgnorePatterns(t *testing.T) {
+	got := ParseIgnorePatterns([]byte("# drafts\npath:drafts/*.md\n\n  path: *.wip.md  \nenv:API_KEY\ncommand:*\nnotes.md\npath:\n"))
+	want := []string{"drafts/*.md", "*.wip.md"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ParseIgnorePatterns() = %q, want %q", got, want)
+	}
+}
+
+func TestFindLocalArtifacts_Excludes(t *testing.T) {
+	dir := t.TempDir()
+	for _, rel := range []string{
+		"commands/deploy.md",
+		"commands/experiment.md",
+		"commands/review.mdx",
+		"commands/README.mdx",
+		"skills/pdf/SKILL.md",
+		"skills/wip/SKILL.md",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("# "+rel+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, detect.IgnoreFileName), []byte("path:skills/wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := FindLocalArtifacts(dir, []string{"experiment.md"})
+	if err != nil {
+		t.Fatalf("FindLocalArtifacts() error = %v", err)
+	}
+	var rel []string
+	for _, file := range files {
+		r, _ := filepath.Rel(dir, file)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	want := []string{"commands/deploy.md", "commands/review.mdx", "skills/pdf/SKILL.md"}
+	if strings.Join(rel, ",") != strings.Join(want, ",") {
+		t.Errorf("FindLocalArtifacts() = %v, want %v", rel, want)
+	}
+}
+
+func TestLocalSkillFiles_SkipsExcludedDirectories(t *testing.T) {
+	dir := t.TempDir()
+	for _, rel := range []string{
+		"node_modules/pkg/SKILL.md",
+		"tools/pdf/SKILL.md",
+		"tools/drafts/wip/SKILL.md",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("# "+rel+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exclude := []string{"node_modules", "tools/drafts"}
+	if files := localSkillFiles(dir, "node_modules", exclude); len(files) != 0 {
+		t.Errorf("walked into excluded node_modules: %v", files)
+	}
+	files := localSkillFiles(dir, "tools", exclude)
+	if len(files) != 1 || files[0] != filepath.Join(dir, "tools", "pdf", "SKILL.md") {
+		t.Errorf("localSkillFiles(tools) = %v, want only tools/pdf/SKILL.md", files)
+	}
+}
+
+func TestFindArtifacts_Excludes(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contents/pack":
+			fmt.Fprintf(w, `[
+				{"name": ".tomeignore", "path": "pack/.tomeignore", "type": "file", "download_url": "%s/raw/pack/.tomeignore"},
+				{"name": "commands", "path": "pack/commands", "type": "dir"}
+			]`, srv.URL)
+		case "/contents/pack/commands":
+			w.Write([]byte(`[
+				{"name": "deploy.md", "path": "pack/commands/deploy.md", "type": "file"},
+				{"name": "draft.md", "path": "pack/commands/draft.md", "type": "file"},
+				{"name": "experiment.md", "path": "pack/commands/experiment.md", "type": "file"}
+			]`))
+		case "/raw/pack/.tomeignore":
+			// Relative to the scanned directory, not the repo root
+			w.Write([]byte("path:commands/draft.md\nenv:API_KEY\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	client.Exclude = []string{"experiment.md"}
+	artifacts, err := client.FindArtifacts(srv.URL + "/contents/pack")
+	if err != nil {
+		t.Fatalf("FindArtifacts() error = %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[0].Name != "deploy.md" {
+		t.Errorf("expected only deploy.md, got %+v", artifacts)
+	}
+}
+
+func TestFindArtifacts_DedupesMirroredSkills(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contents":
+			w.Write([]byte(`[
+				{"name": ".claude", "path": ".claude", "type": "dir"},
+				{"name": "skills", "path": "skills", "type": "dir"}
+			]`))
+		case "/contents/.claude":
+			w.Write([]byte(`[{"name": "skills", "path": ".claude/skills", "type": "dir"}]`))
+		case "/contents/.claude/skills":
+			w.Write([]byte(`[
+				{"name": "pdf", "path": ".claude/skills/pdf", "type": "dir"},
+				{"name": "docs", "path": ".claude/skills/docs", "type": "dir"},
+				{"name": "claude-only", "path": ".claude/skills/claude-only", "type": "dir"}
+			]`))
+		case "/contents/.claude/skills/pdf":
+			w.Write([]byte(`[{"name": "SKILL.md", "path": ".claude/skills/pdf/SKILL.md", "type": "file", "sha": "drifted"}]`))
+		case "/contents/.claude/skills/docs":
+			w.Write([]byte(`[{"name": "SKILL.md", "path": ".claude/skills/docs/SKILL.md", "type": "file", "sha": "same"}]`))
+		case "/contents/.claude/skills/claude-only":
+			w.Write([]byte(`[{"name": "SKILL.md", "path": ".claude/skills/claude-only/SKILL.md", "type": "file", "sha": "other"}]`))
+		case "/contents/skills":
+			w.Write([]byte(`[
+				{"name": "pdf", "path": "skills/pdf", "type": "dir"},
+				{"name": "documentation", "path": "skills/documentation", "type": "dir"}
+			]`))
+		case "/contents/skills/pdf":
+			w.Write([]byte(`[{"name": "SKILL.md", "path": "skills/pdf/SKILL.md", "type": "file", "sha": "pdf"}]`))
+		case "/contents/skills/documentation":
+			// Renamed, but the same file as .claude/skills/docs
+			w.Write([]byte(`[{"name": "SKILL.md", "path": "skills/documentation/SKILL.md", "type": "file", "sha": "same"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	artifacts, err := client.FindArtifacts(srv.URL + "/contents")
+	if err != nil {
+		t.Fatalf("FindArtifacts() error = %v", err)
+	}
+
+	var got []string
+	for _, a := range artifacts {
+		got = append(got, a.Path)
+	}
+	want := []string{"skills/pdf/SKILL.md", "skills/documentation/SKILL.md", ".claude/skills/claude-only/SKILL.md"}
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("artifacts = %v, want %v", got, want)
+	}
+	if client.DuplicateSkills != 2 {
+		t.Errorf("DuplicateSkills = %d, want 2", client.DuplicateSkills)
+	}
+}
+
+func TestFetchURL_RetriesTransientFailures(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch {
+		case r.URL.Path == "/missing":
+			http.NotFound(w, r)
+		case r.URL.Path == "/down.md" || attempts <= 2:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("# Recovered"))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClientWithRetries(2)
+	client.retryDelay = time.Millisecond
+
+	content, err := client.FetchURL(srv.URL + "/flaky.md")
+	if err != nil {
+		t.Fatalf("FetchURL() error = %v", err)
+	}
+	if string(content) != "# Recovered" || attempts != 3 {
+		t.Errorf("got %q after %d attempts, want success on attempt 3", content, attempts)
+	}
+
+	// 4xx responses are not retried
+	attempts = 0
+	if _, err := client.FetchURL(srv.URL + "/missing"); err == nil {
+		t.Error("expected an error for a 404")
+	}
+	if attempts != 1 {
+		t.Errorf("404 was attempted %d times, want 1", attempts)
+	}
+
+	// Retries run out
+	attempts = 0
+	client = NewClientWithRetries(1)
+	client.retryDelay = time.Millisecond
+	if _, err := client.FetchURL(srv.URL + "/down.md"); err == nil || !strings.Contains(err.Error(), "status 503") {
+		t.Errorf("FetchURL() error = %v, want status 503 after retries", err)
+	}
+	if attempts != 2 {
+		t.Errorf("made %d attempts, want 2", attempts)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{&http.Response{StatusCode: http.StatusOK}, nil, false},
+		{&http.Response{StatusCode: http.StatusNotFound}, nil, false},
+		{&http.Response{StatusCode: http.StatusTooManyRequests}, nil, false},
+		{&http.Response{StatusCode: http.StatusBadGateway}, nil, true},
+		{nil, &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, true},
+		{nil, &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		{nil, &url.Error{Op: "Get", URL: "https://example.com", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}, true},
+		{nil, io.ErrUnexpectedEOF, true},
+		{nil, errors.New("unsupported protocol scheme"), false},
+		{nil, &url.Error{Op: "Get", URL: "https://nowhere.invalid", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.resp, tt.err); got != tt.want {
+			t.Errorf("isTransient(%v, %v) = %v, want %v", tt.resp, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFetchURL_DoesNotRetryPermanentErrors(t *testing.T) {
+	// An untrusted certificate fails the TLS handshake on every connection
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	client := NewClientWithRetries(2)
+	client.retryDelay = time.Millisecond
+	if _, err := client.FetchURL(srv.URL + "/SKILL.md"); err == nil {
+		t.Fatal("expected a certificate error")
+	}
+	if got := conns.Load(); got != 1 {
+		t.Errorf("TLS failure made %d attempts, want 1", got)
+	}
+
+	// Neither is a host that doesn't resolve
+	var attempts int
+	client.http.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: r.URL.Host, IsNotFound: true}}
+	})
+	if _, err := client.FetchURL("https://nowhere.invalid/SKILL.md"); err == nil {
+		t.Fatal("expected an unknown host error")
+	}
+	if attempts != 1 {
+		t.Errorf("unknown host made %d attempts, want 1", attempts)
+	}
+}
+
+// repoTarball builds a gzipped tarball laid out the way GitHub serves repo archives
+func repoTarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "repo-main/", Typeflag: tar.TypeDir, Mode: 0755})
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: "repo-main/" + name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestUseArchive_ServesDiscovery(t *testing.T) {
+	tarball := repoTarball(t, map[string]string{
+		"README.md":           "# Skills\n",
+		"SKILL.md":            "---\nname: root\n---\n# Root\n",
+		"commands/deploy.md":  "---\ndescription: Deploy\n---\n# Deploy\n",
+		"skills/pdf/SKILL.md": "---\nname: pdf\n---\n# PDF\n",
+		"skills/pdf/ref.md":   "# Reference\n",
+	})
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(tarball)
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	archive, err := client.FetchArchive(srv.URL + "/owner/repo/archive/main.tar.gz")
+	if err != nil {
+		t.Fatalf("FetchArchive() error = %v", err)
+	}
+	if archive.Len() != 5 {
+		t.Errorf("archive has %d files, want 5", archive.Len())
+	}
+
+	apiURL := "https://api.github.com/repos/owner/repo/contents"
+	rawURL := func(path string) string {
+		return "https://raw.githubusercontent.com/owner/repo/main/" + path
+	}
+	client.UseArchive(archive, apiURL+"?ref=main", rawURL)
+
+	artifacts, err := client.FindArtifacts(apiURL + "?ref=main")
+	if err != nil {
+		t.Fatalf("FindArtifacts() error = %v", err)
+	}
+	var paths []string
+	for _, item := range artifacts {
+		paths = append(paths, item.Path)
+	}
+	if want := "SKILL.md,commands/deploy.md,skills/pdf/SKILL.md"; strings.Join(paths, ",") != want {
+		t.Errorf("FindArtifacts() = %v, want %s", paths, want)
+	}
+
+	content, err := client.FetchURL(rawURL("commands/deploy.md"))
+	if err != nil || !strings.Contains(string(content), "# Deploy") {
+		t.Errorf("FetchURL() = %q, %v", content, err)
+	}
+	if _, err := client.FetchURL(rawURL("missing.md")); err == nil {
+		t.Error("expected an error for a file missing from the archive")
+	}
+
+	files, err := client.DiscoverSkillFiles(apiURL+"?ref=main", "skills/pdf", rawURL)
+	if err != nil || len(files) != 1 || files[0].Path != "ref.md" {
+		t.Errorf("DiscoverSkillFiles() = %+v, %v; want ref.md", files, err)
+	}
+
+	if requests != 1 {
+		t.Errorf("made %d requests, want only the archive download", requests)
+	}
+}
+
+func TestUseTree_ServesDiscovery(t *testing.T) {
+	files := map[string]string{
+		"SKILL.md":            "---\nname: root\n---\n# Root\n",
+		"commands/deploy.md":  "---\ndescription: Deploy\n---\n# Deploy\n",
+		"skills/pdf/SKILL.md": "---\nname: pdf\n---\n# PDF\n",
+		"skills/pdf/ref.md":   "# Reference\n",
+	}
+	tree := `{"sha": "abc", "truncated": %s, "tree": [
+		{"path": "SKILL.md", "type": "blob", "sha": "1"},
+		{"path": "commands", "type": "tree", "sha": "2"},
+		{"path": "commands/deploy.md", "type": "blob", "sha": "3"},
+		{"path": "skills", "type": "tree", "sha": "4"},
+		{"path": "skills/pdf", "type": "tree", "sha": "5"},
+		{"path": "skills/pdf/SKILL.md", "type": "blob", "sha": "6"},
+		{"path": "skills/pdf/ref.md", "type": "blob", "sha": "7"},
+		{"path": "vendor", "type": "commit", "sha": "8"}
+	]}`
+
+	truncated := "false"
+	var treeRequests, listRequests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/owner/repo/git/trees/main":
+			treeRequests++
+			if r.URL.Query().Get("recursive") != "1" {
+				t.Errorf("tree request without recursive=1: %s", r.URL)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, tree, truncated)
+		case strings.HasPrefix(r.URL.Path, "/raw/"):
+			content, ok := files[strings.TrimPrefix(r.URL.Path, "/raw/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(content))
+		default:
+			listRequests++
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	gh, err := ghclient.NewForBaseURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClientWithRetries(0)
+	client.gh = gh
+
+	apiURL := "https://api.github.com/repos/owner/repo/contents?ref=main"
+	rawURL := func(path string) string { return srv.URL + "/raw/" + path }
+	if err := client.UseTree(apiURL, rawURL); err != nil {
+		t.Fatalf("UseTree() error = %v", err)
+	}
+
+	artifacts, err := client.FindArtifacts(apiURL)
+	if err != nil {
+		t.Fatalf("FindArtifacts() error = %v", err)
+	}
+	var paths []string
+	for _, item := range artifacts {
+		paths = append(paths, item.Path)
+	}
+	if want := "SKILL.md,commands/deploy.md,skills/pdf/SKILL.md"; strings.Join(paths, ",") != want {
+		t.Errorf("FindArtifacts() = %v, want %s", paths, want)
+	}
+	if len(client.SkippedSubmodules) != 1 || client.SkippedSubmodules[0].Path != "vendor" {
+		t.Errorf("SkippedSubmodules = %+v, want vendor", client.SkippedSubmodules)
+	}
+
+	included, err := client.DiscoverSkillFiles(apiURL, "skills/pdf", rawURL)
+	if err != nil || len(included) != 1 || included[0].Path != "ref.md" || string(included[0].Content) != "# Reference\n" {
+		t.Errorf("DiscoverSkillFiles() = %+v, %v; want ref.md", included, err)
+	}
+
+	if treeRequests != 1 || listRequests != 0 {
+		t.Errorf("made %d tree and %d listing requests, want 1 and 0", treeRequests, listRequests)
+	}
+
+	// A truncated tree, or submodules to follow, leave discovery to the contents API
+	truncated = "true"
+	fresh := NewClientWithRetries(0)
+	fresh.gh = gh
+	if err := fresh.UseTree(apiURL, rawURL); err == nil || fresh.index != nil {
+		t.Errorf("UseTree() with a truncated tree = %v, want an error and no index", err)
+	}
+	truncated = "false"
+	fresh.FollowSubmodules = true
+	if err := fresh.UseTree(apiURL, rawURL); err == nil || fresh.index != nil {
+		t.Errorf("UseTree() following submodules = %v, want an error and no index", err)
+	}
+}
+
+func TestAccessErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		status int
+		token  string
+		want   string // empty: not an AccessError
+	}{
+		{404, "", "status 404: not found; the repo may not exist, or it's private and you lack access; set GITHUB_TOKEN or pass --token"},
+		{401, "", "status 401: authentication failed; set GITHUB_TOKEN or pass --token"},
+		{403, "", "status 403: access denied; set GITHUB_TOKEN or pass --token"},
+		{404, "ghp_test", "status 404: not found; the repo may not exist, or it's private and you lack access; check that your GitHub token can read this repo"},
+		{400, "", ""},
+	}
+	for _, tt := range tests {
+		client := NewClientWithRetries(0)
+		if tt.token != "" {
+			client.SetToken(tt.token)
+		}
+		url := fmt.Sprintf("%s/%d", srv.URL, tt.status)
+
+		for name, fetchErr := range map[string]error{
+			"FetchURL":           func() error { _, err := client.FetchURL(url); return err }(),
+			"ListGitHubContents": func() error { _, err := client.ListGitHubContents(url); return err }(),
+		} {
+			var accessErr *AccessError
+			isAccess := errors.As(fetchErr, &accessErr)
+			switch {
+			case tt.want == "" && isAccess:
+				t.Errorf("%s status %d: got AccessError %v", name, tt.status, fetchErr)
+			case tt.want == "" && !strings.Contains(fmt.Sprint(fetchErr), fmt.Sprintf("status %d", tt.status)):
+				t.Errorf("%s status %d: error = %v", name, tt.status, fetchErr)
+			case tt.want != "" && (!isAccess || accessErr.Error() != tt.want):
+				t.Errorf("%s status %d: error = %v, want %q", name, tt.status, fetchErr, tt.want)
+			}
+		}
+	}
+
+	gitlab := &AccessError{StatusCode: 404, Host: "gitlab.com"}
+	if !strings.HasSuffix(gitlab.Error(), "set GITLAB_TOKEN") {
+		t.Errorf("GitLab hint = %q, want GITLAB_TOKEN", gitlab.Error())
+	}
+}
+
+func TestFetchURL_RevalidatesCache(t *testing.T) {
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("# Skill"))
+	}))
+	defer srv.Close()
+
+	cache := NewCache(filepath.Join(t.TempDir(), "cache"))
+	client := NewClientWithRetries(0)
+	client.SetCache(cache)
+
+	for i := 0; i < 2; i++ {
+		content, err := client.FetchURL(srv.URL + "/SKILL.md")
+		if err != nil {
+			t.Fatalf("FetchURL() #%d error = %v", i+1, err)
+		}
+		if string(content) != "# Skill" {
+			t.Errorf("FetchURL() #%d = %q, want %q", i+1, content, "# Skill")
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("got %d requests, %d answered 304; want the second revalidated", requests, notModified)
+	}
+
+	// Without a cache, the request is unconditional
+	notModified = 0
+	if _, err := NewClientWithRetries(0).FetchURL(srv.URL + "/SKILL.md"); err != nil {
+		t.Fatalf("FetchURL() error = %v", err)
+	}
+	if notModified != 0 {
+		t.Error("uncached client sent If-None-Match")
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, _, ok := cache.Get(srv.URL + "/SKILL.md"); ok {
+		t.Error("Get() after Clear() found an entry")
+	}
+}
+
+func TestCache_StripsToken(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(dir)
+	if err := cache.Put("https://raw.githubusercontent.com/o/r/main/SKILL.md?token=SECRET1", `"v1"`, []byte("# Skill")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	// A fresh token finds the same entry
+	etag, body, ok := cache.Get("https://raw.githubusercontent.com/o/r/main/SKILL.md?token=SECRET2")
+	if !ok || etag != `"v1"` || string(body) != "# Skill" {
+		t.Errorf("Get() with a new token = %q, %q, %v; want the cached entry", etag, body, ok)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "SECRET") {
+			t.Errorf("%s contains the token: %s", e.Name(), data)
+		}
+	}
+}
+
+func TestStripToken(t *testing.T) {
+	tests := map[string]string{
+		"https://raw.githubusercontent.com/o/r/main/a.md?token=abc": "https://raw.githubusercontent.com/o/r/main/a.md",
+		"https://example.com/a.md?ref=main&token=abc":               "https://example.com/a.md?ref=main",
+		"https://example.com/a.md?ref=main":                         "https://example.com/a.md?ref=main",
+		"https://example.com/a.md":                                  "https://example.com/a.md",
+	}
+	for in, want := range tests {
+		if got := StripToken(in); got != want {
+			t.Errorf("StripToken(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFetchMarketplace(t *testing.T) {
+	var srv *httptest.Server
+	var listings map[string][]string
+	dir := func(name string) string {
+		return fmt.Sprintf(`{"name": %q, "type": "dir"}`, name)
+	}
+	file := func(name string) string {
+		return fmt.Sprintf(`{"name": %q, "type": "file", "download_url": "%s/raw/%s"}`, name, srv.URL, name)
+	}
+	raw := map[string]string{
+		"marketplace.json": `{
+			"name": "acme-tools",
+			"owner": {"name": "Acme"},
+			"metadata": {"pluginRoot": "./plugins"},
+			"plugins": [
+				{"name": "formatter", "description": "Format code", "source": "formatter"},
+				{"name": "linter", "source": "./linter"},
+				{"name": "remote", "source": {"source": "github", "repo": "acme/remote-plugin"}}
+			]
+		}`,
+		"plugin.json": `{"name": "plugin"}`,
+		"fmt.md":      "---\ndescription: Format files\n---\nFormat them.\n",
+		"lint.md":     "---\ndescription: Lint files\n---\nLint them.\n",
+	}
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if listing, ok := listings[r.URL.Path]; ok {
+			w.Write([]byte("[" + strings.Join(listing, ",") + "]"))
+			return
+		}
+		if content, ok := raw[strings.TrimPrefix(r.URL.Path, "/raw/")]; ok {
+			w.Write([]byte(content))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	listings = map[string][]string{
+		"/repo":                                  {dir(".claude-plugin"), dir("plugins")},
+		"/repo/.claude-plugin":                   {file("marketplace.json")},
+		"/repo/plugins/formatter":                {dir(".claude-plugin"), dir("commands")},
+		"/repo/plugins/formatter/.claude-plugin": {file("plugin.json")},
+		"/repo/plugins/formatter/commands":       {file("fmt.md")},
+		"/repo/plugins/linter":                   {dir(".claude-plugin"), dir("commands")},
+		"/repo/plugins/linter/.claude-plugin":    {file("plugin.json")},
+		"/repo/plugins/linter/commands":          {file("lint.md")},
+	}
+
+	client := NewClient()
+	apiURL := srv.URL + "/repo"
+	if !client.IsMarketplace(apiURL) {
+		t.Fatal("IsMarketplace() = false")
+	}
+	if client.IsPlugin(apiURL) {
+		t.Error("IsPlugin() = true for a marketplace without plugin.json")
+	}
+
+	marketplace, err := client.FetchMarketplace(apiURL)
+	if err != nil {
+		t.Fatalf("FetchMarketplace() error = %v", err)
+	}
+	if marketplace.Name != "acme-tools" || len(marketplace.Plugins) != 3 {
+		t.Fatalf("marketplace = %+v, want acme-tools with 3 plugins", marketplace)
+	}
+	if remote := marketplace.Plugins[2]; remote.Source.Repo != "acme/remote-plugin" || marketplace.PluginPath(remote) != "" {
+		t.Errorf("remote plugin = %+v, want a GitHub source outside the repo", remote)
+	}
+
+	for i, want := range []struct{ path, command string }{
+		{"plugins/formatter", "fmt"},
+		{"plugins/linter", "lint"},
+	} {
+		p := marketplace.Plugins[i]
+		if got := marketplace.PluginPath(p); got != want.path {
+			t.Errorf("PluginPath(%s) = %q, want %q", p.Name, got, want.path)
+			continue
+		}
+		pluginURL := appendPath(apiURL, want.path)
+		if !client.IsPlugin(pluginURL) {
+			t.Errorf("IsPlugin(%s) = false", want.path)
+			continue
+		}
+		plugin, err := client.FetchPlugin(pluginURL, "acme/tools:"+want.path)
+		if err != nil {
+			t.Fatalf("FetchPlugin(%s) error = %v", want.path, err)
+		}
+		if len(plugin.Commands) != 1 || plugin.Commands[0].Name != want.command {
+			t.Errorf("%s commands = %+v, want %s", p.Name, plugin.Commands, want.command)
+		}
+	}
+}
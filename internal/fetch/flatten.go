@@ -0,0 +1,89 @@
+package fetch
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// BundledFilesHeading is the section appended to a flattened skill
+const BundledFilesHeading = "## Bundled files"
+
+// fenceLanguages maps include extensions to code fence info strings
+var fenceLanguages = map[string]string{
+	".py":   "python",
+	".sh":   "bash",
+	".js":   "javascript",
+	".ts":   "typescript",
+	".rb":   "ruby",
+	".json": "json",
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".toml": "toml",
+	".md":   "markdown",
+	".mdx":  "mdx",
+	".txt":  "text",
+}
+
+// FlattenSkill inlines text includes into the skill body as fenced sections.
+// Returns the flattened content and the includes that must still be written
+// as separate files (scripts and binary files can't be inlined).
+func FlattenSkill(content string, includes []IncludedFile) (string, []IncludedFile) {
+	var inlined, kept []IncludedFile
+	for _, inc := range includes {
+		if CanInline(inc) {
+			inlined = append(inlined, inc)
+		} else {
+			kept = append(kept, inc)
+		}
+	}
+
+	if len(inlined) == 0 {
+		return content, kept
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(content, "\n"))
+	b.WriteString("\n\n" + BundledFilesHeading + "\n")
+
+	for _, inc := range inlined {
+		body := strings.TrimRight(string(inc.Content), "\n")
+		fence := codeFence(body)
+		lang := fenceLanguages[strings.ToLower(filepath.Ext(inc.Path))]
+
+		fmt.Fprintf(&b, "\n### `%s`\n\n", inc.Path)
+		fmt.Fprintf(&b, "%s%s\n%s\n%s\n", fence, lang, body, fence)
+	}
+
+	return b.String(), kept
+}
+
+// CanInline reports whether an include is text that can be safely inlined.
+// Scripts are excluded since they need to exist on disk to be executed.
+func CanInline(inc IncludedFile) bool {
+	if IsScriptFile(inc.Path) || bytes.HasPrefix(inc.Content, []byte("#!")) {
+		return false
+	}
+	return utf8.Valid(inc.Content) && !bytes.Contains(inc.Content, []byte{0})
+}
+
+// codeFence returns a backtick fence longer than any backtick run in body
+func codeFence(body string) string {
+	longest, run := 0, 0
+	for _, r := range body {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
@@ -0,0 +1,93 @@
+package fetch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/markdown"
+)
+
+// Lint issue severities. Errors make an artifact unpublishable; warnings are
+// problems tome can work around (usually by guessing).
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// MaxDescriptionLength is the longest description agents reliably load
+const MaxDescriptionLength = 1024
+
+// LintIssue is a single problem found in an artifact
+type LintIssue struct {
+	Severity string `json:"severity"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+// LintArtifact checks a skill or command file's frontmatter and returns any
+// issues found. The artifact type is detected from filename.
+func LintArtifact(content []byte, filename string) []LintIssue {
+	var issues []LintIssue
+	add := func(severity, field, format string, args ...any) {
+		issues = append(issues, LintIssue{severity, field, fmt.Sprintf(format, args...)})
+	}
+
+	text := markdown.Normalize(content)
+	if strings.HasPrefix(text, "---") && !strings.Contains(text[3:], "\n---") {
+		add(LintError, "frontmatter", "frontmatter is missing its closing ---")
+		return issues
+	}
+
+	fm, body, err := parseFrontmatter(content)
+	if err != nil {
+		add(LintError, "frontmatter", "%v", err)
+		return issues
+	}
+	if !strings.HasPrefix(text, "---") {
+		add(LintWarning, "frontmatter", "no frontmatter; name and description are guessed from the content")
+	}
+
+	isSkill := DetectArtifactType(filename) == artifact.TypeSkill
+	if isSkill && fm.Name == "" {
+		add(LintWarning, "name", "missing name; falls back to %q", extractNameFromContent(body))
+	}
+
+	switch {
+	case fm.Description == "":
+		add(LintWarning, "description", "missing description; agents use it to decide when to load the %s", artifactNoun(isSkill))
+	case len(fm.Description) > MaxDescriptionLength:
+		add(LintWarning, "description", "description is %d characters (max %d)", len(fm.Description), MaxDescriptionLength)
+	}
+
+	for _, inc := range fm.Includes {
+		if err := ValidateIncludePath(inc); err != nil {
+			add(LintError, "includes", "%v", err)
+		}
+	}
+
+	for _, glob := range fm.Globs {
+		if err := ValidateGlob(glob); err != nil {
+			add(LintError, "globs", "%v", err)
+		}
+	}
+
+	return issues
+}
+
+func artifactNoun(isSkill bool) string {
+	if isSkill {
+		return "skill"
+	}
+	return "command"
+}
+
+// HasLintErrors reports whether any issue is an error
+func HasLintErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
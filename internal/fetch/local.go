@@ -0,0 +1,209 @@
+package fetch
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kennyg/tome/internal/artifact"
+	"github.com/kennyg/tome/internal/detect"
+)
+
+// ReadLocalManifest reads the tome.yaml (or tome.yml) manifest of a local
+// collection directory. It returns nil, without error, when there's none.
+func ReadLocalManifest(dir string) (*artifact.Manifest, error) {
+	for _, name := range []string{artifact.ManifestFilename, "tome.yml"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		var manifest artifact.Manifest
+		if err := yaml.Unmarshal(content, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		return &manifest, nil
+	}
+	return nil, nil
+}
+
+// FindLocalArtifacts finds artifact files in a local collection directory,
+// following the same layout rules as FindArtifacts: a root SKILL.md, and
+// commands/, agents/, and prompts/ markdown files. Skills are found by
+// walking the remaining (non-hidden) subdirectories, so skills/foo/SKILL.md
+// and deeper layouts like tools/pdf/SKILL.md are both picked up. Paths
+// matching exclude or the directory's .tomeignore are left out, and the walk
+// doesn't descend into excluded directories (node_modules, say). Returned
+// paths are joined onto dir.
+func FindLocalArtifacts(dir string, exclude []string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if ignore, err := os.ReadFile(filepath.Join(dir, detect.IgnoreFileName)); err == nil {
+		exclude = append(ParseIgnorePatterns(ignore), exclude...)
+	}
+
+	var artifacts []string
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if !entry.IsDir() {
+			if IsArtifactFile(name) {
+				artifacts = append(artifacts, filepath.Join(dir, name))
+			}
+			continue
+		}
+
+		switch {
+		case name == artifact.CommandsDirName, name == "command", name == artifact.AgentsDirName, name == artifact.PromptsDirName:
+			artifacts = append(artifacts, localMarkdownFiles(filepath.Join(dir, name))...)
+		case !strings.HasPrefix(name, "."):
+			artifacts = append(artifacts, localSkillFiles(dir, name, exclude)...)
+		}
+	}
+
+	if len(exclude) == 0 {
+		return artifacts, nil
+	}
+	kept := artifacts[:0]
+	for _, file := range artifacts {
+		if rel, err := filepath.Rel(dir, file); err == nil && IsExcludedPath(rel, exclude) {
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept, nil
+}
+
+// localMarkdownFiles lists the non-excluded markdown files directly in dir
+func localMarkdownFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !IsMarkdownFile(entry.Name()) {
+			continue
+		}
+		if IsExcludedFile(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	return files
+}
+
+// localSkillFiles walks sub, a directory in root, for SKILL.md files,
+// skipping hidden directories and those matching exclude (relative to root).
+// A directory holding a SKILL.md is a skill; everything beneath it belongs
+// to that skill, so the walk doesn't descend into it looking for more.
+func localSkillFiles(root, sub string, exclude []string) []string {
+	dir := filepath.Join(root, sub)
+	var files []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil // Skip entries we can't read; files are found via their directory
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if rel, err := filepath.Rel(root, path); err == nil && IsExcludedPath(rel, exclude) {
+			return filepath.SkipDir
+		}
+		skillPath := filepath.Join(path, artifact.SkillFilename)
+		if info, err := os.Stat(skillPath); err == nil && !info.IsDir() {
+			files = append(files, skillPath)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return files
+}
+
+// DiscoverLocalSkillFiles collects the files alongside a local SKILL.md,
+// applying the same extension and size limits as DiscoverSkillFiles
+func DiscoverLocalSkillFiles(skillDir string) ([]IncludedFile, error) {
+	var files []IncludedFile
+	var totalSize int64
+
+	err := filepath.WalkDir(skillDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip entries we can't read
+		}
+		if d.IsDir() {
+			if path != skillDir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(skillDir, path)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		// SKILL.md is handled separately as the main file
+		if strings.EqualFold(relPath, artifact.SkillFilename) {
+			return nil
+		}
+		if ValidateIncludePath(relPath) != nil {
+			return nil // Skip files with disallowed extensions
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil || len(content) > MaxIncludeFileSize {
+			return nil // Skip unreadable or oversized files
+		}
+
+		totalSize += int64(len(content))
+		if totalSize > MaxTotalIncludeSize {
+			return fmt.Errorf("total skill size exceeds max (%d bytes)", MaxTotalIncludeSize)
+		}
+
+		files = append(files, IncludedFile{Path: relPath, Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// ReadLocalSkillIncludes reads exactly the includes a local skill lists,
+// applying the same limits as FetchSkillIncludes
+func ReadLocalSkillIncludes(skillDir string, includes []string) ([]IncludedFile, error) {
+	var files []IncludedFile
+	var totalSize int64
+
+	for _, inc := range includes {
+		if err := ValidateIncludePath(inc); err != nil {
+			return nil, err
+		}
+
+		content, err := os.ReadFile(filepath.Join(skillDir, filepath.FromSlash(inc)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read include %s: %w", inc, err)
+		}
+		if len(content) > MaxIncludeFileSize {
+			return nil, fmt.Errorf("include %s exceeds max size (%d > %d bytes)", inc, len(content), MaxIncludeFileSize)
+		}
+
+		totalSize += int64(len(content))
+		if totalSize > MaxTotalIncludeSize {
+			return nil, fmt.Errorf("total include size exceeds max (%d > %d bytes)", totalSize, MaxTotalIncludeSize)
+		}
+
+		files = append(files, IncludedFile{Path: inc, Content: content})
+	}
+
+	return files, nil
+}
@@ -10,28 +10,57 @@ import (
 
 // IsPlugin checks if a GitHub repo/path is a plugin by looking for .claude-plugin/plugin.json
 func (c *Client) IsPlugin(apiURL string) bool {
-	// Check for .claude-plugin directory
+	_, ok := c.pluginFile(apiURL, "plugin.json")
+	return ok
+}
+
+// IsMarketplace checks if a GitHub repo/path is a plugin marketplace by
+// looking for .claude-plugin/marketplace.json
+func (c *Client) IsMarketplace(apiURL string) bool {
+	_, ok := c.pluginFile(apiURL, "marketplace.json")
+	return ok
+}
+
+// pluginFile finds a file in the .claude-plugin directory under apiURL
+func (c *Client) pluginFile(apiURL, name string) (GitHubContent, bool) {
 	contents, err := c.ListGitHubContents(apiURL)
 	if err != nil {
-		return false
+		return GitHubContent{}, false
 	}
 
 	for _, item := range contents {
 		if item.Type == "dir" && item.Name == ".claude-plugin" {
-			// Check for plugin.json inside
-			pluginDirURL := appendPath(apiURL, ".claude-plugin")
-			pluginContents, err := c.ListGitHubContents(pluginDirURL)
+			pluginContents, err := c.ListGitHubContents(appendPath(apiURL, ".claude-plugin"))
 			if err != nil {
-				return false
+				return GitHubContent{}, false
 			}
 			for _, pItem := range pluginContents {
-				if pItem.Type == "file" && pItem.Name == "plugin.json" {
-					return true
+				if pItem.Type == "file" && pItem.Name == name {
+					return pItem, true
 				}
 			}
 		}
 	}
-	return false
+	return GitHubContent{}, false
+}
+
+// FetchMarketplace fetches and parses a repo's .claude-plugin/marketplace.json
+func (c *Client) FetchMarketplace(apiURL string) (*artifact.Marketplace, error) {
+	item, ok := c.pluginFile(apiURL, "marketplace.json")
+	if !ok {
+		return nil, fmt.Errorf("marketplace.json not found in .claude-plugin/")
+	}
+
+	content, err := c.FetchURL(item.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch marketplace.json: %w", err)
+	}
+
+	var marketplace artifact.Marketplace
+	if err := json.Unmarshal(content, &marketplace); err != nil {
+		return nil, fmt.Errorf("failed to parse marketplace.json: %w", err)
+	}
+	return &marketplace, nil
 }
 
 // FetchPlugin fetches and parses a complete plugin from a GitHub repo
@@ -68,10 +97,40 @@ func (c *Client) FetchPlugin(apiURL string, source string) (*artifact.Plugin, er
 		return nil, fmt.Errorf("failed to parse plugin.json: %w", err)
 	}
 
+	if err := c.fetchPluginArtifacts(apiURL, plugin); err != nil {
+		return nil, err
+	}
+	return plugin, nil
+}
+
+// FetchMarketplacePlugin fetches a plugin listed in a marketplace. Its
+// plugin.json is optional: without one, the marketplace entry describes it.
+func (c *Client) FetchMarketplacePlugin(apiURL string, source string, entry artifact.MarketplacePlugin) (*artifact.Plugin, error) {
+	if c.IsPlugin(apiURL) {
+		return c.FetchPlugin(apiURL, source)
+	}
+
+	plugin := &artifact.Plugin{
+		Source: source,
+		Manifest: artifact.PluginManifest{
+			Name:        entry.Name,
+			Description: entry.Description,
+			Version:     entry.Version,
+		},
+	}
+	if err := c.fetchPluginArtifacts(apiURL, plugin); err != nil {
+		return nil, err
+	}
+	return plugin, nil
+}
+
+// fetchPluginArtifacts fills in a plugin's skills, commands, agents and hooks
+// from the directories under apiURL
+func (c *Client) fetchPluginArtifacts(apiURL string, plugin *artifact.Plugin) error {
 	// List root contents to find artifact directories
 	contents, err := c.ListGitHubContents(apiURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list plugin contents: %w", err)
+		return fmt.Errorf("failed to list plugin contents: %w", err)
 	}
 
 	// Process each artifact directory
@@ -104,7 +163,7 @@ func (c *Client) FetchPlugin(apiURL string, source string) (*artifact.Plugin, er
 		}
 	}
 
-	return plugin, nil
+	return nil
 }
 
 // fetchPluginSkills fetches all skills from a plugin's skills/ directory
@@ -171,7 +230,7 @@ func (c *Client) fetchPluginCommands(apiURL string) ([]artifact.Artifact, error)
 	}
 
 	for _, item := range contents {
-		if item.Type == "file" && strings.HasSuffix(strings.ToLower(item.Name), ".md") {
+		if item.Type == "file" && IsMarkdownFile(item.Name) {
 			content, err := c.FetchURL(item.DownloadURL)
 			if err != nil {
 				continue
@@ -200,7 +259,7 @@ func (c *Client) fetchPluginAgents(apiURL string) ([]artifact.Artifact, error) {
 	}
 
 	for _, item := range contents {
-		if item.Type == "file" && strings.HasSuffix(strings.ToLower(item.Name), ".md") {
+		if item.Type == "file" && IsMarkdownFile(item.Name) {
 			content, err := c.FetchURL(item.DownloadURL)
 			if err != nil {
 				continue
@@ -304,7 +363,7 @@ func ParseAgent(content []byte, filename string, sourceURL string) (*artifact.Ar
 
 	name := fm.Name
 	if name == "" {
-		name = strings.TrimSuffix(filename, ".md")
+		name = TrimMarkdownExt(filename)
 	}
 
 	description := fm.Description
@@ -0,0 +1,84 @@
+package fetch
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// rateLimitMaxWait is the longest FetchURL and ListGitHubContents will sleep
+// for a rate limit to reset before retrying once
+const rateLimitMaxWait = 5 * time.Second
+
+// RateLimitError reports an exhausted GitHub API rate limit
+type RateLimitError struct {
+	Reset time.Time // when the limit resets; zero if GitHub didn't say
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "GitHub API rate limit exceeded"
+	if !e.Reset.IsZero() {
+		msg += fmt.Sprintf(" (resets at %s)", e.Reset.Local().Format(time.Kitchen))
+	}
+	return msg + "; set GITHUB_TOKEN or run 'gh auth login' for a higher limit"
+}
+
+// checkRateLimit returns a RateLimitError if resp was rejected because the
+// rate limit is exhausted (403/429 with X-RateLimit-Remaining: 0)
+func checkRateLimit(resp *http.Response) *RateLimitError {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return nil
+	}
+
+	rlErr := &RateLimitError{}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rlErr.Reset = time.Unix(reset, 0)
+	}
+	return rlErr
+}
+
+// get performs a GET via getWithRetry, sleeping and retrying once when the
+// rate limit resets within rateLimitMaxWait. A rate-limited response is
+// returned as a RateLimitError rather than a response.
+func (c *Client) get(rawURL string) (*http.Response, error) {
+	return c.getIfNoneMatch(rawURL, "")
+}
+
+// getIfNoneMatch is get with a conditional request: a non-empty etag is sent
+// as If-None-Match, so the response may be a 304
+func (c *Client) getIfNoneMatch(rawURL, etag string) (*http.Response, error) {
+	resp, err := c.getWithRetry(rawURL, etag)
+	if err != nil {
+		return nil, err
+	}
+
+	rlErr := checkRateLimit(resp)
+	if rlErr == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	wait := time.Until(rlErr.Reset)
+	if rlErr.Reset.IsZero() || wait > rateLimitMaxWait {
+		return nil, rlErr
+	}
+	if wait > 0 {
+		if err := c.sleep(wait); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err = c.getWithRetry(rawURL, etag)
+	if err != nil {
+		return nil, err
+	}
+	if rlErr := checkRateLimit(resp); rlErr != nil {
+		resp.Body.Close()
+		return nil, rlErr
+	}
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package fetch
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// DefaultRetries is how many times NewClient retries a request that failed
+// with a connection error or a 5xx response
+const DefaultRetries = 3
+
+// defaultRetryDelay is the backoff before the first retry; it doubles after each attempt
+const defaultRetryDelay = 500 * time.Millisecond
+
+// isTransient reports whether a failed request is worth retrying: timeouts,
+// refused or reset connections, connections dropped mid-response, and 5xx
+// responses. Errors that would fail the same way again (TLS verification,
+// unknown hosts, bad URLs) and 4xx responses never are.
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+		return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// getWithRetry performs a GET via getWithProviderAuth, retrying transient
+// failures up to c.retries times with exponential backoff. Each attempt is
+// bounded by the HTTP client's timeout. After the last attempt, or once the
+// client's context is cancelled, the final response or error is returned
+// as-is.
+func (c *Client) getWithRetry(rawURL, etag string) (*http.Response, error) {
+	delay := c.retryDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := c.getWithProviderAuth(rawURL, etag)
+		if attempt >= c.retries || !isTransient(resp, err) || c.context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := c.sleep(delay); err != nil {
+			return nil, err
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,61 @@
+package fetch
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// CABundleEnv names the environment variable holding the path of a PEM
+// bundle of extra CA certificates to trust, e.g. a corporate proxy's
+const CABundleEnv = "TOME_CA_BUNDLE"
+
+// newTransport returns an HTTP transport that routes requests through the
+// proxy named by $HTTPS_PROXY, $HTTP_PROXY and $NO_PROXY. A non-nil roots
+// replaces the system certificate pool for verifying servers.
+func newTransport(roots *x509.CertPool) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	if roots != nil {
+		t.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	}
+	return t
+}
+
+// LoadCABundle returns the system certificate pool with the PEM
+// certificates in path added
+func LoadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", path)
+	}
+	return roots, nil
+}
+
+// SetCABundle makes the client, including its GitHub API requests, trust
+// the CA certificates in the PEM file at path as well as the system's
+func (c *Client) SetCABundle(path string) error {
+	roots, err := LoadCABundle(path)
+	if err != nil {
+		return err
+	}
+	c.setTransport(newTransport(roots))
+	return nil
+}
+
+// setTransport sends every request the client makes through t
+func (c *Client) setTransport(t *http.Transport) {
+	c.transport = t
+	c.http.Transport = t
+	c.gh = c.newGitHubClient("")
+}
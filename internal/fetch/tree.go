@@ -0,0 +1,69 @@
+package fetch
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/kennyg/tome/internal/ghclient"
+)
+
+// UseTree lists a GitHub repo's whole tree with one Git Trees API request
+// and serves ListGitHubContents for the repo from it, replacing a contents
+// API call per directory during discovery. Files are still fetched from the
+// network. apiURL and rawURL are as for UseArchive.
+//
+// It returns an error, leaving the client unchanged, when the tree can't be
+// fetched, is truncated, or has submodules to follow (the tree doesn't carry
+// their URLs); discovery then lists directories one by one as before.
+func (c *Client) UseTree(apiURL string, rawURL RawURLFunc) error {
+	owner, repo, _, hostname, err := ghclient.ParseGitHubURL(apiURL)
+	if err != nil {
+		return err
+	}
+	var ref string
+	if u, err := url.Parse(apiURL); err == nil {
+		ref = u.Query().Get("ref")
+	}
+
+	client := c.ghForHost(hostname)
+
+	ctx, cancel := c.apiContext()
+	defer cancel()
+	entries, truncated, err := client.GetTree(ctx, owner, repo, ref)
+	if err != nil {
+		return err
+	}
+	if truncated {
+		return fmt.Errorf("tree of %s/%s is too large to list in one request", owner, repo)
+	}
+
+	contents := treeContents(entries)
+	for _, item := range contents {
+		if item.IsSubmodule() && c.FollowSubmodules {
+			return fmt.Errorf("tree lists submodule %s without its URL", item.Path)
+		}
+	}
+
+	c.index = newRepoIndex(apiURL, rawURL, contents)
+	return nil
+}
+
+// treeContents converts tree entries to the contents API's shape. Directories
+// are left out; the index derives them from the paths beneath them.
+func treeContents(entries []ghclient.TreeEntry) []GitHubContent {
+	var contents []GitHubContent
+	for _, e := range entries {
+		item := GitHubContent{Name: path.Base(e.Path), Path: e.Path, SHA: e.SHA}
+		switch e.Type {
+		case "blob":
+			item.Type = "file"
+		case "commit":
+			item.Type = "submodule"
+		default:
+			continue
+		}
+		contents = append(contents, item)
+	}
+	return contents
+}
@@ -4,6 +4,7 @@ package ghclient
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -24,16 +25,33 @@ type Client struct {
 // New creates a new GitHub client
 // Token resolution order: GITHUB_TOKEN, GH_TOKEN, gh CLI config, unauthenticated
 func New() *Client {
-	token := getToken()
+	return NewWithTransport("", nil)
+}
+
+// NewWithTransport creates a GitHub client authenticated with token, e.g.
+// from a --token flag, that sends requests through transport, e.g. one
+// trusting a custom CA. An empty token falls back to New's resolution order;
+// a nil transport uses http.DefaultTransport.
+func NewWithTransport(token string, transport http.RoundTripper) *Client {
+	if token == "" {
+		token = getToken()
+	}
 
 	var httpClient *http.Client
+	if transport != nil {
+		httpClient = &http.Client{Transport: transport}
+	}
 	authenticated := false
 
 	if token != "" {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: token},
 		)
-		httpClient = oauth2.NewClient(context.Background(), ts)
+		ctx := context.Background()
+		if httpClient != nil {
+			ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+		}
+		httpClient = oauth2.NewClient(ctx, ts)
 		authenticated = true
 	}
 
@@ -45,7 +63,13 @@ func New() *Client {
 
 // NewForHost creates a GitHub client for a specific host (GitHub Enterprise)
 func NewForHost(host string) *Client {
-	c := New()
+	return NewForHostWithTransport(host, "", nil)
+}
+
+// NewForHostWithTransport creates a GitHub client for a specific host that
+// sends requests through transport (see NewWithTransport)
+func NewForHostWithTransport(host, token string, transport http.RoundTripper) *Client {
+	c := NewWithTransport(token, transport)
 
 	// Configure for GHE if not github.com
 	if host != "" && host != "github.com" && host != "api.github.com" {
@@ -58,28 +82,43 @@ func NewForHost(host string) *Client {
 	return c
 }
 
+// NewForBaseURL creates a GitHub client that sends API requests to baseURL,
+// e.g. an API proxy or a test server
+func NewForBaseURL(baseURL string) (*Client, error) {
+	c := New()
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid API base URL: %w", err)
+	}
+	c.gh.BaseURL = u
+	return c, nil
+}
+
+// SetUserAgent sets the User-Agent header sent with API requests
+func (c *Client) SetUserAgent(ua string) {
+	c.gh.UserAgent = ua
+}
+
 // IsAuthenticated returns true if the client has a token
 func (c *Client) IsAuthenticated() bool {
 	return c.authenticated
 }
 
-// GetContents fetches a file's content from a repository
-func (c *Client) GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) ([]byte, error) {
-	fileContent, _, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, opts)
+// OpenContents opens a file in a repository for reading. GitHub serves it
+// raw rather than base64 in JSON, so the caller can stop reading a file
+// that's too large; it closes the reader when done.
+func (c *Client) OpenContents(ctx context.Context, owner, repo, path string) (io.ReadCloser, error) {
+	escapedPath := (&url.URL{Path: strings.TrimSuffix(path, "/")}).String()
+	req, err := c.gh.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, escapedPath), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get contents: %w", err)
+		return nil, err
 	}
-
-	if fileContent == nil {
-		return nil, fmt.Errorf("path is a directory, not a file")
-	}
-
-	content, err := fileContent.GetContent()
+	req.Header.Set("Accept", "application/vnd.github.raw")
+	resp, err := c.gh.BareDo(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode content: %w", err)
+		return nil, fmt.Errorf("failed to get contents: %w", err)
 	}
-
-	return []byte(content), nil
+	return resp.Body, nil
 }
 
 // ListContents lists directory contents in a repository
@@ -92,6 +131,74 @@ func (c *Client) ListContents(ctx context.Context, owner, repo, path string, opt
 	return dirContents, nil
 }
 
+// TreeEntry is one entry of a repository tree
+type TreeEntry struct {
+	Path string // Repo-relative path
+	Type string // "blob" (file), "tree" (directory) or "commit" (submodule)
+	SHA  string
+	Size int
+}
+
+// GetTree fetches the whole tree of a repository at ref (a branch, tag,
+// commit SHA or HEAD) in a single request. truncated is set when GitHub cut
+// the listing short because the tree is too large.
+func (c *Client) GetTree(ctx context.Context, owner, repo, ref string) (entries []TreeEntry, truncated bool, err error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	tree, _, err := c.gh.Git.GetTree(ctx, owner, repo, ref, true)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get tree: %w", err)
+	}
+
+	for _, e := range tree.Entries {
+		entries = append(entries, TreeEntry{
+			Path: e.GetPath(),
+			Type: e.GetType(),
+			SHA:  e.GetSHA(),
+			Size: e.GetSize(),
+		})
+	}
+	return entries, tree.GetTruncated(), nil
+}
+
+// ResolveCommit returns the SHA of the commit ref (a branch, tag or commit
+// SHA; "" for the default branch) points to
+func (c *Client) ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	sha, _, err := c.gh.Repositories.GetCommitSHA1(ctx, owner, repo, ref, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return sha, nil
+}
+
+// maxTagPages caps how many pages of tags ListTags reads
+const maxTagPages = 10
+
+// ListTags returns the names of a repository's tags, reading up to
+// maxTagPages pages of 100
+func (c *Client) ListTags(ctx context.Context, owner, repo string) ([]string, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	var names []string
+	for page := 0; page < maxTagPages; page++ {
+		tags, resp, err := c.gh.Repositories.ListTags(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+		for _, t := range tags {
+			names = append(names, t.GetName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return names, nil
+}
+
 // SearchCodeResult represents a code search result
 type SearchCodeResult struct {
 	Repository string
@@ -160,6 +267,78 @@ func (c *Client) SearchRepos(ctx context.Context, query string, limit int) ([]Se
 	return results, nil
 }
 
+// collectionFilenames are the files whose presence marks a repo as holding
+// artifacts, most specific first
+var collectionFilenames = []string{"tome.yaml", "SKILL.md"}
+
+// repoLookupBatch is how many repo: qualifiers go in one search query,
+// keeping it under GitHub's 256 character query limit
+const repoLookupBatch = 6
+
+// SearchCollections finds repositories with a tome.yaml or SKILL.md matching
+// query, returning at most limit of them with their description and stars.
+// Code search needs authentication; the error is returned when every
+// filename search fails.
+func (c *Client) SearchCollections(ctx context.Context, query string, limit int) ([]SearchRepoResult, error) {
+	seen := make(map[string]bool)
+	var names []string
+	var lastErr error
+	failed := 0
+
+	for _, filename := range collectionFilenames {
+		if len(names) >= limit {
+			break
+		}
+		results, err := c.SearchCode(ctx, fmt.Sprintf("%s filename:%s", query, filename), limit*2)
+		if err != nil {
+			lastErr = err
+			failed++
+			continue
+		}
+		for _, r := range results {
+			if !seen[r.Repository] && len(names) < limit {
+				seen[r.Repository] = true
+				names = append(names, r.Repository)
+			}
+		}
+	}
+	if failed == len(collectionFilenames) {
+		return nil, lastErr
+	}
+
+	return c.lookupRepos(ctx, names), nil
+}
+
+// lookupRepos fills in description and stars for repos by full name, in
+// batched repository searches. Repos the lookup misses keep just their name.
+func (c *Client) lookupRepos(ctx context.Context, names []string) []SearchRepoResult {
+	details := make(map[string]SearchRepoResult)
+	for start := 0; start < len(names); start += repoLookupBatch {
+		end := min(start+repoLookupBatch, len(names))
+		qualifiers := make([]string, 0, end-start)
+		for _, name := range names[start:end] {
+			qualifiers = append(qualifiers, "repo:"+name)
+		}
+		repos, err := c.SearchRepos(ctx, strings.Join(qualifiers, " "), end-start)
+		if err != nil {
+			continue
+		}
+		for _, r := range repos {
+			details[strings.ToLower(r.FullName)] = r
+		}
+	}
+
+	results := make([]SearchRepoResult, 0, len(names))
+	for _, name := range names {
+		if r, ok := details[strings.ToLower(name)]; ok {
+			results = append(results, r)
+		} else {
+			results = append(results, SearchRepoResult{FullName: name})
+		}
+	}
+	return results
+}
+
 // getToken attempts to get a GitHub token from various sources
 func getToken() string {
 	// 1. GITHUB_TOKEN env var
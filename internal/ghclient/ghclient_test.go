@@ -1,7 +1,13 @@
 package ghclient
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -347,3 +353,197 @@ func TestSearchRepoResult(t *testing.T) {
 		t.Errorf("Stars = %d, want 42", result.Stars)
 	}
 }
+
+func TestGetTree(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/git/trees/HEAD" || r.URL.Query().Get("recursive") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sha": "abc", "truncated": true, "tree": [
+			{"path": "skills", "type": "tree", "sha": "1"},
+			{"path": "skills/SKILL.md", "type": "blob", "sha": "2", "size": 42}
+		]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewForBaseURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, truncated, err := client.GetTree(context.Background(), "owner", "repo", "")
+	if err != nil {
+		t.Fatalf("GetTree() error = %v", err)
+	}
+	if !truncated {
+		t.Error("GetTree() truncated = false, want true")
+	}
+	want := []TreeEntry{
+		{Path: "skills", Type: "tree", SHA: "1"},
+		{Path: "skills/SKILL.md", Type: "blob", SHA: "2", Size: 42},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("GetTree() = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	if _, _, err := client.GetTree(context.Background(), "owner", "missing", "main"); err == nil {
+		t.Error("GetTree() for a missing repo succeeded, want error")
+	}
+}
+
+func TestListTags(t *testing.T) {
+	var srvURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"name": "v1.0.0"}]`))
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/tags?page=2>; rel="next"`, srvURL))
+		w.Write([]byte(`[{"name": "v1.2.0"}, {"name": "v1.1.0"}]`))
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	client, err := NewForBaseURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := client.ListTags(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if strings.Join(tags, ",") != "v1.2.0,v1.1.0,v1.0.0" {
+		t.Errorf("ListTags() = %v, want both pages", tags)
+	}
+
+	if _, err := client.ListTags(context.Background(), "owner", "missing"); err == nil {
+		t.Error("ListTags() for a missing repo succeeded, want error")
+	}
+}
+
+func TestSearchCollections(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query().Get("q")
+		switch {
+		case r.URL.Path == "/search/code" && q == "pdf filename:tome.yaml":
+			w.Write([]byte(`{"items": [{"repository": {"full_name": "a/tools"}}]}`))
+		case r.URL.Path == "/search/code" && q == "pdf filename:SKILL.md":
+			w.Write([]byte(`{"items": [
+				{"repository": {"full_name": "b/skills"}},
+				{"repository": {"full_name": "a/tools"}},
+				{"repository": {"full_name": "c/more"}}
+			]}`))
+		case r.URL.Path == "/search/repositories" && q == "repo:a/tools repo:b/skills":
+			w.Write([]byte(`{"items": [
+				{"full_name": "b/skills", "description": "PDF skills", "stargazers_count": 7},
+				{"full_name": "a/tools", "description": "Tools", "stargazers_count": 3}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewForBaseURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := client.SearchCollections(context.Background(), "pdf", 2)
+	if err != nil {
+		t.Fatalf("SearchCollections() error = %v", err)
+	}
+	want := []SearchRepoResult{
+		{FullName: "a/tools", Description: "Tools", Stars: 3},
+		{FullName: "b/skills", Description: "PDF skills", Stars: 7},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("SearchCollections() = %+v, want %+v", results, want)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+
+	if _, err := client.SearchCollections(context.Background(), "nothing", 2); err == nil {
+		t.Error("SearchCollections() with every search failing succeeded, want error")
+	}
+}
+
+func TestResolveCommit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/commits/v1.2.0" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("0123456789abcdef0123456789abcdef01234567"))
+	}))
+	defer srv.Close()
+
+	client, err := NewForBaseURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sha, err := client.ResolveCommit(context.Background(), "owner", "repo", "v1.2.0")
+	if err != nil {
+		t.Fatalf("ResolveCommit() error = %v", err)
+	}
+	if sha != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("ResolveCommit() = %q", sha)
+	}
+
+	if _, err := client.ResolveCommit(context.Background(), "owner", "repo", "missing"); err == nil {
+		t.Error("ResolveCommit() for a missing ref succeeded, want error")
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestNewWithTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("0123456789abcdef0123456789abcdef01234567"))
+	}))
+	defer srv.Close()
+
+	var sent int
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		sent++
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	client := NewWithTransport("test-token", transport)
+	if !client.IsAuthenticated() {
+		t.Error("NewWithTransport() with a token is unauthenticated")
+	}
+	client.gh.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	if _, err := client.ResolveCommit(context.Background(), "owner", "repo", "main"); err != nil {
+		t.Fatalf("ResolveCommit() error = %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("transport carried %d request(s), want 1", sent)
+	}
+}
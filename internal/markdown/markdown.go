@@ -0,0 +1,15 @@
+// Package markdown holds text handling shared by the packages that parse
+// artifacts' markdown and frontmatter
+package markdown
+
+import "strings"
+
+// BOM is the UTF-8 byte order mark some Windows editors start files with
+const BOM = "\ufeff"
+
+// Normalize strips a leading BOM and converts CRLF line endings to LF, so
+// frontmatter delimiters are found in files authored on Windows
+func Normalize(content []byte) string {
+	text := strings.TrimPrefix(string(content), BOM)
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
@@ -0,0 +1,21 @@
+package markdown
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"unchanged", "---\nname: a\n---\n", "---\nname: a\n---\n"},
+		{"crlf", "---\r\nname: a\r\n---\r\n", "---\nname: a\n---\n"},
+		{"bom", BOM + "---\nname: a\n---\n", "---\nname: a\n---\n"},
+		{"bom only leading", "a" + BOM, "a" + BOM},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize([]byte(tt.in)); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
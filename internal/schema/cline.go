@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ClineWorkflow represents a Cline workflow (.clinerules/workflows/*.md).
+// Workflows are plain markdown invoked as /<filename>, so the name comes
+// from the file and there is nowhere to keep a description.
+type ClineWorkflow struct {
+	Name string // From the filename, not stored in the content
+	Body string
+}
+
+// Ensure ClineWorkflow implements Skill interface
+var _ Skill = (*ClineWorkflow)(nil)
+
+// GetName returns the workflow name
+func (w *ClineWorkflow) GetName() string {
+	return w.Name
+}
+
+// GetDescription returns an empty string; Cline workflows have no description
+func (w *ClineWorkflow) GetDescription() string {
+	return ""
+}
+
+// GetBody returns the markdown body content
+func (w *ClineWorkflow) GetBody() string {
+	return w.Body
+}
+
+// GetFormat returns FormatCline
+func (w *ClineWorkflow) GetFormat() Format {
+	return FormatCline
+}
+
+// Serialize returns the workflow as plain markdown
+func (w *ClineWorkflow) Serialize() ([]byte, error) {
+	return []byte(w.Body), nil
+}
+
+// ParseClineWorkflow parses content as a Cline workflow. Any frontmatter is
+// treated as part of the body, as Cline does; set Name from the filename.
+func ParseClineWorkflow(content []byte) (*ClineWorkflow, error) {
+	return &ClineWorkflow{Body: string(content)}, nil
+}
+
+// ToMetadata extracts common metadata from the workflow
+func (w *ClineWorkflow) ToMetadata() SkillMetadata {
+	return SkillMetadata{
+		Name: w.Name,
+		Body: w.Body,
+	}
+}
+
+// FromMetadata populates the workflow from common metadata
+func (w *ClineWorkflow) FromMetadata(m SkillMetadata) {
+	w.Name = m.Name
+	w.Body = m.Body
+}
+
+// clineWorkflowName derives a workflow's name from its filename
+func clineWorkflowName(filename string) string {
+	return strings.TrimSuffix(filepath.Base(filename), ".md")
+}
+
+// IsClineFile checks if a filename matches Cline patterns
+func IsClineFile(filename string) bool {
+	return strings.Contains(filename, ".clinerules")
+}
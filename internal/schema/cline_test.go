@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertCommand_ClaudeToCline(t *testing.T) {
+	cmd := &ClaudeCommand{
+		Name:         "Deploy",
+		Description:  "Deploy the app",
+		Author:       "someone",
+		AllowedTools: []string{"Bash"},
+		Body:         "1. Build\n2. Ship",
+	}
+
+	result, err := ConvertCommandWithInfo(cmd, FormatCline)
+	if err != nil {
+		t.Fatalf("ConvertCommandWithInfo() error = %v", err)
+	}
+	if string(result.Content) != cmd.Body {
+		t.Errorf("Content = %q, want plain body", result.Content)
+	}
+
+	var sawTools, sawAuthor, sawDesc bool
+	for _, w := range result.Warnings {
+		sawTools = sawTools || strings.Contains(w, "allowed-tools")
+		sawAuthor = sawAuthor || strings.Contains(w, "author")
+		sawDesc = sawDesc || strings.HasPrefix(w, "description")
+	}
+	if !sawTools || !sawAuthor || !sawDesc {
+		t.Errorf("expected allowed-tools, author and description warnings, got %v", result.Warnings)
+	}
+
+	filename := CommandOutputDirectory(cmd, FormatCline) + "/" + CommandOutputFilename(cmd, FormatCline)
+	if filename != ".clinerules/workflows/deploy.md" {
+		t.Errorf("output path = %q, want .clinerules/workflows/deploy.md", filename)
+	}
+
+	// The name survives through the filename
+	workflow, err := ParseCommandAuto(result.Content, filename)
+	if err != nil {
+		t.Fatalf("ParseCommandAuto() error = %v", err)
+	}
+	if workflow.GetFormat() != FormatCline {
+		t.Fatalf("GetFormat() = %v, want %v", workflow.GetFormat(), FormatCline)
+	}
+	if workflow.GetName() != "deploy" || workflow.GetBody() != cmd.Body {
+		t.Errorf("got %q %q", workflow.GetName(), workflow.GetBody())
+	}
+
+	back, err := ConvertCommand(workflow, FormatClaude)
+	if err != nil {
+		t.Fatalf("ConvertCommand() error = %v", err)
+	}
+	claude, err := ParseClaudeCommand(back)
+	if err != nil {
+		t.Fatalf("ParseClaudeCommand() error = %v", err)
+	}
+	if claude.Name != "deploy" || strings.TrimSpace(claude.Body) != cmd.Body {
+		t.Errorf("got %q %q", claude.Name, claude.Body)
+	}
+}
+
+func TestConvertCommandWithInfo_ClineNoDescription(t *testing.T) {
+	cmd := &ClaudeCommand{Name: "lint", Body: "Run the linter."}
+	result, err := ConvertCommandWithInfo(cmd, FormatCline)
+	if err != nil {
+		t.Fatalf("ConvertCommandWithInfo() error = %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings %v", result.Warnings)
+	}
+}
@@ -17,7 +17,8 @@ type ClaudeCommand struct {
 	Author  string `yaml:"author,omitempty"`
 
 	// Command-specific fields
-	AllowedTools []string `yaml:"allowed-tools,omitempty"` // Pre-approved tools
+	AllowedTools []string           `yaml:"allowed-tools,omitempty"` // Pre-approved tools (Claude)
+	Permission   OpenCodePermission `yaml:"permission,omitempty"`    // Tool permissions (OpenCode)
 
 	// Content
 	Body string `yaml:"-"` // Markdown body (not in frontmatter)
@@ -57,25 +58,51 @@ func (c *ClaudeCommand) SetFormat(f Format) {
 	c.sourceFormat = f
 }
 
-// Serialize returns the command as markdown content
+// Serialize returns the command as markdown content. Pre-approved tools are
+// written the way the format expects them: allowed-tools for Claude, a
+// permission map for OpenCode.
 func (c *ClaudeCommand) Serialize() ([]byte, error) {
 	fm := &commandFrontmatter{
-		Name:         c.Name,
-		Description:  c.Description,
-		Version:      c.Version,
-		Author:       c.Author,
-		AllowedTools: c.AllowedTools,
+		Name:        c.Name,
+		Description: c.Description,
+		Version:     c.Version,
+		Author:      c.Author,
+	}
+	if c.GetFormat() == FormatOpenCode {
+		mapped, _ := permissionFromAllowedTools(c.AllowedTools)
+		fm.Permission = mergePermission(c.Permission, mapped)
+	} else {
+		tools, _ := allowedToolsFromPermission(c.Permission)
+		fm.AllowedTools = appendMissing(c.AllowedTools, tools)
 	}
 	return SerializeFrontmatter(fm, c.Body)
 }
 
 // commandFrontmatter controls YAML field ordering
 type commandFrontmatter struct {
-	Name         string   `yaml:"name"`
-	Description  string   `yaml:"description"`
-	Version      string   `yaml:"version,omitempty"`
-	Author       string   `yaml:"author,omitempty"`
-	AllowedTools []string `yaml:"allowed-tools,omitempty"`
+	Name         string             `yaml:"name"`
+	Description  string             `yaml:"description"`
+	Version      string             `yaml:"version,omitempty"`
+	Author       string             `yaml:"author,omitempty"`
+	AllowedTools []string           `yaml:"allowed-tools,omitempty"`
+	Permission   OpenCodePermission `yaml:"permission,omitempty"`
+}
+
+// appendMissing appends the items of extra that list doesn't already have
+func appendMissing(list, extra []string) []string {
+	for _, item := range extra {
+		found := false
+		for _, existing := range list {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
 }
 
 // ParseClaudeCommand parses content as a Claude command file
@@ -143,5 +170,10 @@ func IsCommandFile(filename string) bool {
 	if hasExtension(filename, ".prompt.md") {
 		return true
 	}
+	// Check for Continue prompts and Cline workflows
+	if containsPath(filename, ".continue/prompts") ||
+		containsPath(filename, ".clinerules/workflows") {
+		return true
+	}
 	return false
 }
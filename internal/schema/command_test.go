@@ -569,3 +569,35 @@ func TestRoundTrip_ClaudeCommandToCopilotPromptToClaudeCommand(t *testing.T) {
 		t.Errorf("Body = %q, want %q", result.Body, original.Body)
 	}
 }
+
+func TestConvertCommandWithInfo_EmptyBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantWarn bool
+	}{
+		{"empty", "", true},
+		{"whitespace only", "\n\n   \n", true},
+		{"with content", "Run the tests", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &ClaudeCommand{Name: "test", Description: "A test", Body: tt.body}
+			result, err := ConvertCommandWithInfo(cmd, FormatCopilot)
+			if err != nil {
+				t.Fatalf("ConvertCommandWithInfo() error = %v", err)
+			}
+
+			gotWarn := false
+			for _, w := range result.Warnings {
+				if strings.Contains(w, "body is empty") {
+					gotWarn = true
+				}
+			}
+			if gotWarn != tt.wantWarn {
+				t.Errorf("empty body warning = %v, want %v (warnings: %v)", gotWarn, tt.wantWarn, result.Warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"strings"
+)
+
+// ContinuePrompt represents a Continue prompt file (.continue/prompts/*.md).
+// Invokable prompts show up as slash commands named after the name field.
+type ContinuePrompt struct {
+	// Core fields
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Invokable   bool   `yaml:"invokable"` // Available as a slash command
+
+	// Content
+	Body string `yaml:"-"` // Prompt body (not in frontmatter)
+}
+
+// Ensure ContinuePrompt implements Skill interface
+var _ Skill = (*ContinuePrompt)(nil)
+
+// GetName returns the prompt name
+func (p *ContinuePrompt) GetName() string {
+	return p.Name
+}
+
+// GetDescription returns the prompt description
+func (p *ContinuePrompt) GetDescription() string {
+	return p.Description
+}
+
+// GetBody returns the prompt body
+func (p *ContinuePrompt) GetBody() string {
+	return p.Body
+}
+
+// GetFormat returns FormatContinue
+func (p *ContinuePrompt) GetFormat() Format {
+	return FormatContinue
+}
+
+// Serialize returns the prompt as .md content for Continue
+func (p *ContinuePrompt) Serialize() ([]byte, error) {
+	fm := &continuePromptFrontmatter{
+		Name:        p.Name,
+		Description: p.Description,
+		Invokable:   p.Invokable,
+	}
+	return SerializeFrontmatter(fm, p.Body)
+}
+
+// continuePromptFrontmatter controls YAML field ordering
+type continuePromptFrontmatter struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Invokable   bool   `yaml:"invokable"`
+}
+
+// ParseContinuePrompt parses content as a Continue prompt file
+func ParseContinuePrompt(content []byte) (*ContinuePrompt, error) {
+	prompt := &ContinuePrompt{}
+	body, err := ParseFrontmatterTyped(content, prompt)
+	if err != nil {
+		return nil, err
+	}
+	prompt.Body = body
+	return prompt, nil
+}
+
+// ToMetadata extracts common metadata from the prompt
+func (p *ContinuePrompt) ToMetadata() SkillMetadata {
+	return SkillMetadata{
+		Name:        p.Name,
+		Description: p.Description,
+		Body:        p.Body,
+	}
+}
+
+// FromMetadata populates the prompt from common metadata. Converted
+// commands are always invokable.
+func (p *ContinuePrompt) FromMetadata(m SkillMetadata) {
+	p.Name = m.Name
+	p.Description = m.Description
+	p.Invokable = true
+	p.Body = m.Body
+}
+
+// IsContinueFile checks if a filename matches Continue patterns
+func IsContinueFile(filename string) bool {
+	return strings.Contains(filename, ".continue")
+}
@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseContinuePrompt(t *testing.T) {
+	content := `---
+name: review
+description: Review the current diff
+invokable: true
+---
+Review this code for bugs.`
+
+	prompt, err := ParseContinuePrompt([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseContinuePrompt() error = %v", err)
+	}
+	if prompt.Name != "review" || prompt.Description != "Review the current diff" {
+		t.Errorf("got %q/%q", prompt.Name, prompt.Description)
+	}
+	if !prompt.Invokable {
+		t.Error("Invokable = false, want true")
+	}
+	if prompt.Body != "Review this code for bugs." {
+		t.Errorf("Body = %q", prompt.Body)
+	}
+	if prompt.GetFormat() != FormatContinue {
+		t.Errorf("GetFormat() = %v, want %v", prompt.GetFormat(), FormatContinue)
+	}
+}
+
+func TestConvertCommand_ClaudeToContinue(t *testing.T) {
+	cmd := &ClaudeCommand{
+		Name:         "ReviewCode",
+		Description:  "Review the current diff",
+		Version:      "1.0.0",
+		AllowedTools: []string{"Bash"},
+		Body:         "Review this code for bugs.",
+	}
+
+	result, err := ConvertCommandWithInfo(cmd, FormatContinue)
+	if err != nil {
+		t.Fatalf("ConvertCommandWithInfo() error = %v", err)
+	}
+
+	var sawTools, sawVersion bool
+	for _, w := range result.Warnings {
+		sawTools = sawTools || strings.Contains(w, "allowed-tools")
+		sawVersion = sawVersion || strings.Contains(w, "Continue prompts")
+	}
+	if !sawTools || !sawVersion || len(result.Warnings) != 2 {
+		t.Errorf("expected allowed-tools and version warnings, got %v", result.Warnings)
+	}
+
+	prompt, err := ParseCommandAuto(result.Content, ".continue/prompts/review-code.md")
+	if err != nil {
+		t.Fatalf("ParseCommandAuto() error = %v", err)
+	}
+	cp, ok := prompt.(*ContinuePrompt)
+	if !ok {
+		t.Fatalf("ParseCommandAuto() = %T, want *ContinuePrompt", prompt)
+	}
+	if cp.Name != cmd.Name || cp.Description != cmd.Description || !cp.Invokable {
+		t.Errorf("got %+v", cp)
+	}
+	if strings.TrimSpace(cp.Body) != cmd.Body {
+		t.Errorf("Body = %q, want %q", cp.Body, cmd.Body)
+	}
+
+	if got := CommandOutputFilename(cmd, FormatContinue); got != "review-code.md" {
+		t.Errorf("CommandOutputFilename() = %q, want review-code.md", got)
+	}
+	if got := CommandOutputDirectory(cmd, FormatContinue); got != ".continue/prompts" {
+		t.Errorf("CommandOutputDirectory() = %q, want .continue/prompts", got)
+	}
+	if got := DetectArtifactType(".continue/prompts/review-code.md"); got != ArtifactCommand {
+		t.Errorf("DetectArtifactType() = %v, want %v", got, ArtifactCommand)
+	}
+}
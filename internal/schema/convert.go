@@ -40,6 +40,14 @@ func Convert(skill Skill, targetFormat Format) ([]byte, error) {
 		cs := &CursorSkill{}
 		cs.FromMetadata(meta)
 		target = cs
+	case FormatWindsurf:
+		wr := &WindsurfRule{Trigger: WindsurfTriggerModelDecision}
+		wr.FromMetadata(meta)
+		target = wr
+	case FormatZed:
+		zr := &ZedRule{}
+		zr.FromMetadata(meta)
+		target = zr
 	default:
 		return nil, fmt.Errorf("unsupported target format: %s", targetFormat)
 	}
@@ -102,6 +110,33 @@ func ConvertToCursorSkill(skill Skill) *CursorSkill {
 	}
 }
 
+// ConvertToWindsurfRule converts any skill to WindsurfRule
+func ConvertToWindsurfRule(skill Skill) *WindsurfRule {
+	if wr, ok := skill.(*WindsurfRule); ok {
+		return wr
+	}
+
+	return &WindsurfRule{
+		Name:        skill.GetName(),
+		Trigger:     WindsurfTriggerModelDecision,
+		Description: skill.GetDescription(),
+		Body:        skill.GetBody(),
+	}
+}
+
+// ConvertToZedRule converts any skill to ZedRule
+func ConvertToZedRule(skill Skill) *ZedRule {
+	if zr, ok := skill.(*ZedRule); ok {
+		return zr
+	}
+
+	return &ZedRule{
+		Title:       skill.GetName(),
+		Description: skill.GetDescription(),
+		Body:        skill.GetBody(),
+	}
+}
+
 // Parse parses content based on the detected or specified format
 func Parse(content []byte, format Format) (Skill, error) {
 	switch format {
@@ -114,6 +149,10 @@ func Parse(content []byte, format Format) (Skill, error) {
 		return ParseCopilotAgent(content)
 	case FormatCursor:
 		return ParseCursorSkill(content)
+	case FormatWindsurf:
+		return ParseWindsurfRule(content)
+	case FormatZed:
+		return ParseZedRule(content)
 	default:
 		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
@@ -164,6 +203,33 @@ func ConvertWithInfo(skill Skill, targetFormat Format) (*ConversionResult, error
 			result.Warnings = append(result.Warnings,
 				"allowed-tools field is Claude-specific (will be omitted)")
 		}
+		if targetFormat == FormatWindsurf {
+			if len(cs.Globs) > 0 {
+				result.Warnings = append(result.Warnings,
+					"globs field not supported on model_decision rules in Windsurf format (will be omitted)")
+			}
+			if len(cs.Includes) > 0 {
+				result.Warnings = append(result.Warnings,
+					"includes field not supported in Windsurf format (will be omitted)")
+			}
+			if cs.Version != "" || cs.Author != "" || cs.License != "" {
+				result.Warnings = append(result.Warnings,
+					"version, author, and license fields not supported in Windsurf format (will be omitted)")
+			}
+		}
+	}
+
+	if targetFormat == FormatWindsurf {
+		result.Warnings = append(result.Warnings, windsurfSizeWarnings(skill)...)
+	}
+
+	if targetFormat == FormatZed {
+		result.Warnings = append(result.Warnings, zedSkillWarnings(skill)...)
+	}
+
+	if isEmptyBody(skill) {
+		result.Warnings = append(result.Warnings,
+			"skill body is empty (only frontmatter will be written)")
 	}
 
 	return result, nil
@@ -181,7 +247,7 @@ func OutputFilename(skill Skill, targetFormat Format) string {
 		return "SKILL.md"
 	case FormatCopilot:
 		return toKebabCase(name) + ".agent.md"
-	case FormatCursor:
+	case FormatCursor, FormatWindsurf, FormatZed:
 		return toKebabCase(name) + ".md"
 	default:
 		return name + ".md"
@@ -204,11 +270,50 @@ func OutputDirectory(skill Skill, targetFormat Format) string {
 		return "agents"
 	case FormatCursor:
 		return ".cursor/rules"
+	case FormatWindsurf:
+		return ".windsurf/rules"
+	case FormatZed:
+		return ".zed/rules"
 	default:
 		return ""
 	}
 }
 
+// windsurfSizeWarnings warns when a body exceeds what Windsurf reads from a rule file
+func windsurfSizeWarnings(s Skill) []string {
+	if n := len(s.GetBody()); n > WindsurfRuleCharLimit {
+		return []string{fmt.Sprintf("body is %d characters; Windsurf only reads the first %d", n, WindsurfRuleCharLimit)}
+	}
+	return nil
+}
+
+// zedSkillWarnings lists skill fields a Zed rule can't carry. Zed rules have
+// no file scoping or bundled files, and only title and description survive.
+func zedSkillWarnings(s Skill) []string {
+	var warnings []string
+	switch src := s.(type) {
+	case *ClaudeSkill:
+		if len(src.Globs) > 0 {
+			warnings = append(warnings, "globs field not supported in Zed format (will be omitted)")
+		}
+		if len(src.Includes) > 0 {
+			warnings = append(warnings, "includes field not supported in Zed format (will be omitted)")
+		}
+		if src.Version != "" || src.Author != "" || src.License != "" {
+			warnings = append(warnings, "version, author, and license fields not supported in Zed format (will be omitted)")
+		}
+	case *CopilotAgent:
+		if src.Version != "" {
+			warnings = append(warnings, "version field not supported in Zed format (will be omitted)")
+		}
+	case *WindsurfRule:
+		if src.Globs != "" {
+			warnings = append(warnings, "globs field not supported in Zed format (will be omitted)")
+		}
+	}
+	return warnings
+}
+
 // toKebabCase converts a string to kebab-case
 func toKebabCase(s string) string {
 	result := make([]byte, 0, len(s))
@@ -237,18 +342,21 @@ func ConvertCommand(cmd Skill, targetFormat Format) ([]byte, error) {
 		Body:        cmd.GetBody(),
 	}
 
-	// Get version/author if available
+	// Get version/author and tool permissions if available
+	var allowedTools []string
+	var permission OpenCodePermission
 	switch c := cmd.(type) {
 	case *ClaudeCommand:
 		meta.Version = c.Version
 		meta.Author = c.Author
+		allowedTools, permission = c.AllowedTools, c.Permission
 	}
 
 	// Create target format command
 	var target Skill
 	switch targetFormat {
 	case FormatClaude, FormatOpenCode:
-		cc := &ClaudeCommand{}
+		cc := &ClaudeCommand{AllowedTools: allowedTools, Permission: permission}
 		cc.FromMetadata(meta)
 		cc.SetFormat(targetFormat)
 		target = cc
@@ -261,6 +369,23 @@ func ConvertCommand(cmd Skill, targetFormat Format) ([]byte, error) {
 		cs := &CursorSkill{}
 		cs.FromMetadata(meta)
 		target = cs
+	case FormatWindsurf:
+		ww := &WindsurfWorkflow{}
+		ww.FromMetadata(meta)
+		target = ww
+	case FormatZed:
+		// Zed doesn't have a command concept, convert to rule
+		zr := &ZedRule{}
+		zr.FromMetadata(meta)
+		target = zr
+	case FormatContinue:
+		cp := &ContinuePrompt{}
+		cp.FromMetadata(meta)
+		target = cp
+	case FormatCline:
+		cw := &ClineWorkflow{}
+		cw.FromMetadata(meta)
+		target = cw
 	default:
 		return nil, fmt.Errorf("unsupported target format for command: %s", targetFormat)
 	}
@@ -310,6 +435,15 @@ func ParseCommand(content []byte, format Format) (Skill, error) {
 	case FormatCursor:
 		// Cursor doesn't have commands, parse as skill/rule
 		return ParseCursorSkill(content)
+	case FormatWindsurf:
+		return ParseWindsurfWorkflow(content)
+	case FormatZed:
+		// Zed doesn't have commands, parse as rule
+		return ParseZedRule(content)
+	case FormatContinue:
+		return ParseContinuePrompt(content)
+	case FormatCline:
+		return ParseClineWorkflow(content)
 	default:
 		return nil, fmt.Errorf("unsupported format for command: %s", format)
 	}
@@ -318,7 +452,15 @@ func ParseCommand(content []byte, format Format) (Skill, error) {
 // ParseCommandAuto attempts to detect the format and parse as command
 func ParseCommandAuto(content []byte, filename string) (Skill, error) {
 	format := DetectFormat(filename, content)
-	return ParseCommand(content, format)
+	cmd, err := ParseCommand(content, format)
+	if err != nil {
+		return nil, err
+	}
+	// Cline workflows are named by their file
+	if cw, ok := cmd.(*ClineWorkflow); ok {
+		cw.Name = clineWorkflowName(filename)
+	}
+	return cmd, nil
 }
 
 // CommandOutputFilename returns the appropriate filename for a command in the target format
@@ -333,7 +475,7 @@ func CommandOutputFilename(cmd Skill, targetFormat Format) string {
 		return toKebabCase(name) + ".md"
 	case FormatCopilot:
 		return toKebabCase(name) + ".prompt.md"
-	case FormatCursor:
+	case FormatCursor, FormatWindsurf, FormatZed, FormatContinue, FormatCline:
 		return toKebabCase(name) + ".md"
 	default:
 		return name + ".md"
@@ -351,6 +493,14 @@ func CommandOutputDirectory(cmd Skill, targetFormat Format) string {
 		return "prompts"
 	case FormatCursor:
 		return ".cursor/rules"
+	case FormatWindsurf:
+		return ".windsurf/workflows"
+	case FormatZed:
+		return ".zed/rules"
+	case FormatContinue:
+		return ".continue/prompts"
+	case FormatCline:
+		return ".clinerules/workflows"
 	default:
 		return ""
 	}
@@ -373,10 +523,22 @@ func ConvertCommandWithInfo(cmd Skill, targetFormat Format) (*ConversionResult,
 
 	// Check for potential data loss
 	if cc, ok := cmd.(*ClaudeCommand); ok {
-		if len(cc.AllowedTools) > 0 && targetFormat != FormatClaude && targetFormat != FormatOpenCode {
+		if (len(cc.AllowedTools) > 0 || len(cc.Permission) > 0) && targetFormat != FormatClaude && targetFormat != FormatOpenCode {
 			result.Warnings = append(result.Warnings,
 				"allowed-tools field is Claude/OpenCode-specific (will be omitted)")
 		}
+		if targetFormat == FormatOpenCode {
+			if _, unmapped := permissionFromAllowedTools(cc.AllowedTools); len(unmapped) > 0 {
+				result.Warnings = append(result.Warnings,
+					fmt.Sprintf("allowed-tools %s have no OpenCode permission (will be omitted)", strings.Join(unmapped, ", ")))
+			}
+		}
+		if targetFormat == FormatClaude {
+			if _, dropped := allowedToolsFromPermission(cc.Permission); len(dropped) > 0 {
+				result.Warnings = append(result.Warnings,
+					fmt.Sprintf("permission entries %s have no Claude allowed-tools equivalent (will be omitted)", strings.Join(dropped, ", ")))
+			}
+		}
 		if cc.Version != "" && targetFormat == FormatCopilot {
 			result.Warnings = append(result.Warnings,
 				"version field not supported in Copilot prompts (will be omitted)")
@@ -385,6 +547,31 @@ func ConvertCommandWithInfo(cmd Skill, targetFormat Format) (*ConversionResult,
 			result.Warnings = append(result.Warnings,
 				"author field not supported in Copilot prompts (will be omitted)")
 		}
+		if (cc.Version != "" || cc.Author != "") && targetFormat == FormatZed {
+			result.Warnings = append(result.Warnings,
+				"version and author fields not supported in Zed rules (will be omitted)")
+		}
+		if (cc.Version != "" || cc.Author != "") && targetFormat == FormatWindsurf {
+			result.Warnings = append(result.Warnings,
+				"version and author fields not supported in Windsurf workflows (will be omitted)")
+		}
+		if (cc.Version != "" || cc.Author != "") && targetFormat == FormatContinue {
+			result.Warnings = append(result.Warnings,
+				"version and author fields not supported in Continue prompts (will be omitted)")
+		}
+		if (cc.Version != "" || cc.Author != "") && targetFormat == FormatCline {
+			result.Warnings = append(result.Warnings,
+				"version and author fields not supported in Cline workflows (will be omitted)")
+		}
+	}
+
+	if targetFormat == FormatCline && cmd.GetDescription() != "" {
+		result.Warnings = append(result.Warnings,
+			"description not supported in Cline workflows (will be omitted)")
+	}
+
+	if targetFormat == FormatWindsurf {
+		result.Warnings = append(result.Warnings, windsurfSizeWarnings(cmd)...)
 	}
 
 	if targetFormat == FormatCursor {
@@ -392,6 +579,16 @@ func ConvertCommandWithInfo(cmd Skill, targetFormat Format) (*ConversionResult,
 			"Cursor doesn't have commands; converting to rule instead")
 	}
 
+	if targetFormat == FormatZed {
+		result.Warnings = append(result.Warnings,
+			"Zed doesn't have commands; converting to rule instead")
+	}
+
+	if isEmptyBody(cmd) {
+		result.Warnings = append(result.Warnings,
+			"command body is empty (only frontmatter will be written)")
+	}
+
 	return result, nil
 }
 
@@ -400,20 +597,22 @@ func ConvertCommandWithInfo(cmd Skill, targetFormat Format) (*ConversionResult,
 func ConvertInstructions(inst Skill, targetFormat Format) ([]byte, error) {
 	body := inst.GetBody()
 	desc := inst.GetDescription()
+	globs := skillGlobs(inst)
 
 	var target Skill
 	switch targetFormat {
 	case FormatClaude:
-		ci := &ClaudeInstructions{Body: body}
+		ci := &ClaudeInstructions{Body: scopedBody(inst)}
 		ci.SetFormat(FormatClaude)
 		target = ci
 	case FormatOpenCode:
-		ci := &ClaudeInstructions{Body: body}
+		ci := &ClaudeInstructions{Body: scopedBody(inst)}
 		ci.SetFormat(FormatOpenCode)
 		target = ci
 	case FormatCopilot:
 		ci := &CopilotInstructions{
 			Description: desc,
+			ApplyTo:     globs,
 			Body:        body,
 		}
 		// Try to preserve applyTo if source is Copilot
@@ -424,6 +623,7 @@ func ConvertInstructions(inst Skill, targetFormat Format) ([]byte, error) {
 	case FormatCursor:
 		cr := &CursorRules{
 			Description: desc,
+			Globs:       globs,
 			Body:        body,
 		}
 		// Try to preserve globs if source is Cursor MDC
@@ -432,6 +632,32 @@ func ConvertInstructions(inst Skill, targetFormat Format) ([]byte, error) {
 			cr.AlwaysApply = src.AlwaysApply
 		}
 		target = cr
+	case FormatWindsurf:
+		wr := &WindsurfRule{
+			Trigger:     WindsurfTriggerAlwaysOn,
+			Description: desc,
+			Body:        body,
+		}
+		if globs != "" {
+			wr.Trigger, wr.Globs = WindsurfTriggerGlob, globs
+		}
+		// Glob-scoped instructions map onto Windsurf's glob trigger
+		switch src := inst.(type) {
+		case *CursorRules:
+			if src.Globs != "" && !src.AlwaysApply {
+				wr.Trigger, wr.Globs = WindsurfTriggerGlob, src.Globs
+			}
+		case *CopilotInstructions:
+			if src.ApplyTo != "" {
+				wr.Trigger, wr.Globs = WindsurfTriggerGlob, src.ApplyTo
+			}
+		case *WindsurfRule:
+			wr.Trigger, wr.Globs = src.Trigger, src.Globs
+		}
+		target = wr
+	case FormatZed:
+		// The project .rules file is plain markdown, always included
+		target = &ZedRule{Body: scopedBody(inst)}
 	default:
 		return nil, fmt.Errorf("unsupported target format for instructions: %s", targetFormat)
 	}
@@ -439,6 +665,107 @@ func ConvertInstructions(inst Skill, targetFormat Format) ([]byte, error) {
 	return target.Serialize()
 }
 
+// instructionScope returns the comma-separated globs instruction files are
+// scoped to (Copilot's applyTo, Cursor's globs, a Windsurf glob trigger), or
+// "" when they always apply
+func instructionScope(inst Skill) string {
+	switch src := inst.(type) {
+	case *CopilotInstructions:
+		if src.ApplyTo == "**" || src.ApplyTo == "**/*" {
+			return ""
+		}
+		return src.ApplyTo
+	case *CursorRules:
+		if src.AlwaysApply {
+			return ""
+		}
+		return src.Globs
+	case *WindsurfRule:
+		if src.Trigger == WindsurfTriggerGlob {
+			return src.Globs
+		}
+	}
+	return ""
+}
+
+// scopedBody returns the body of instructions for a format that can't scope
+// them to files (CLAUDE.md, AGENTS.md, .rules), led by an "Applies to"
+// heading naming their globs so the scoping isn't lost when several files'
+// instructions share one
+func scopedBody(inst Skill) string {
+	body := inst.GetBody()
+	scope := instructionScope(inst)
+	if scope == "" {
+		return body
+	}
+	var globs []string
+	for _, g := range strings.Split(scope, ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			globs = append(globs, "`"+g+"`")
+		}
+	}
+	return "## Applies to " + strings.Join(globs, ", ") + "\n\n" + strings.TrimLeft(body, "\n")
+}
+
+// labelsScope reports whether converting instructions to a format keeps
+// their globs only as a scopedBody heading
+func labelsScope(f Format) bool {
+	return f == FormatClaude || f == FormatOpenCode || f == FormatZed
+}
+
+// ConvertSkillToInstructions converts a skill into project instructions for
+// targetFormat. The skill's globs become Cursor's globs, Copilot's applyTo
+// or a Windsurf glob trigger, so the instructions stay scoped to those files.
+func ConvertSkillToInstructions(skill Skill, targetFormat Format) (*ConversionResult, error) {
+	result, err := ConvertInstructionsWithInfo(skill, targetFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	if cs, ok := skill.(*ClaudeSkill); ok {
+		if len(cs.Globs) > 0 && !supportsInstructionGlobs(targetFormat) {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("globs field not supported in %s instructions (will be omitted)", targetFormat))
+		}
+		if len(cs.Includes) > 0 {
+			result.Warnings = append(result.Warnings,
+				"includes field not supported in instructions (will be omitted)")
+		}
+		if len(cs.AllowedTools) > 0 {
+			result.Warnings = append(result.Warnings,
+				"allowed-tools field not supported in instructions (will be omitted)")
+		}
+	}
+
+	return result, nil
+}
+
+// supportsInstructionGlobs reports whether a format's instructions can be
+// scoped to files by glob
+func supportsInstructionGlobs(f Format) bool {
+	return f == FormatCursor || f == FormatCopilot || f == FormatWindsurf
+}
+
+// skillGlobs returns a skill's glob patterns joined for single-string fields
+func skillGlobs(s Skill) string {
+	if cs, ok := s.(*ClaudeSkill); ok {
+		return joinGlobs(cs.Globs)
+	}
+	return ""
+}
+
+// joinGlobs joins glob patterns into the comma-separated string Cursor's
+// globs and Copilot's applyTo fields take, dropping blanks
+func joinGlobs(globs []string) string {
+	parts := make([]string, 0, len(globs))
+	for _, g := range globs {
+		if g = strings.TrimSpace(g); g != "" {
+			parts = append(parts, g)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
 // ConvertToClaudeInstructions converts any instructions to ClaudeInstructions
 func ConvertToClaudeInstructions(inst Skill) *ClaudeInstructions {
 	if ci, ok := inst.(*ClaudeInstructions); ok {
@@ -503,6 +830,10 @@ func ParseInstructions(content []byte, format Format) (Skill, error) {
 		return ParseCopilotInstructions(content)
 	case FormatCursor:
 		return ParseCursorRules(content)
+	case FormatWindsurf:
+		return ParseWindsurfRule(content)
+	case FormatZed:
+		return ParseZedRule(content)
 	default:
 		return nil, fmt.Errorf("unsupported format for instructions: %s", format)
 	}
@@ -516,6 +847,10 @@ func ParseInstructionsAuto(content []byte, filename string) (Skill, error) {
 
 // ConvertInstructionsWithInfo converts instructions and returns detailed information
 func ConvertInstructionsWithInfo(inst Skill, targetFormat Format) (*ConversionResult, error) {
+	if isEmptyBody(inst) {
+		return nil, fmt.Errorf("instructions body is empty")
+	}
+
 	content, err := ConvertInstructions(inst, targetFormat)
 	if err != nil {
 		return nil, err
@@ -531,14 +866,24 @@ func ConvertInstructionsWithInfo(inst Skill, targetFormat Format) (*ConversionRe
 
 	// Check for potential data loss
 	if ci, ok := inst.(*CopilotInstructions); ok {
-		if ci.ApplyTo != "" && targetFormat != FormatCopilot {
+		switch {
+		case ci.ApplyTo == "" || targetFormat == FormatCopilot || targetFormat == FormatWindsurf:
+		case labelsScope(targetFormat):
+			result.Warnings = append(result.Warnings,
+				"applyTo glob pattern is Copilot-specific (kept as an \"Applies to\" heading the agent reads but doesn't enforce)")
+		default:
 			result.Warnings = append(result.Warnings,
 				"applyTo glob pattern is Copilot-specific (will be omitted)")
 		}
 	}
 
 	if cr, ok := inst.(*CursorRules); ok {
-		if cr.Globs != "" && targetFormat != FormatCursor {
+		switch {
+		case cr.Globs == "" || targetFormat == FormatCursor || targetFormat == FormatWindsurf:
+		case labelsScope(targetFormat) && !cr.AlwaysApply:
+			result.Warnings = append(result.Warnings,
+				"globs field is Cursor-specific (kept as an \"Applies to\" heading the agent reads but doesn't enforce)")
+		default:
 			result.Warnings = append(result.Warnings,
 				"globs field is Cursor-specific (will be omitted)")
 		}
@@ -548,5 +893,21 @@ func ConvertInstructionsWithInfo(inst Skill, targetFormat Format) (*ConversionRe
 		}
 	}
 
+	if wr, ok := inst.(*WindsurfRule); ok && targetFormat != FormatWindsurf {
+		if wr.Trigger != "" && wr.Trigger != WindsurfTriggerAlwaysOn {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("trigger %q is Windsurf-specific (instructions will always apply)", wr.Trigger))
+		}
+	}
+
+	if targetFormat == FormatWindsurf {
+		result.Warnings = append(result.Warnings, windsurfSizeWarnings(inst)...)
+	}
+
 	return result, nil
 }
+
+// isEmptyBody reports whether an artifact has no content beyond whitespace
+func isEmptyBody(s Skill) bool {
+	return strings.TrimSpace(s.GetBody()) == ""
+}
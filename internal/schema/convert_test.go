@@ -370,6 +370,7 @@ func TestOutputFilename(t *testing.T) {
 		{FormatOpenCode, "SKILL.md"},
 		{FormatCopilot, "test-skill.agent.md"},
 		{FormatCursor, "test-skill.md"},
+		{FormatWindsurf, "test-skill.md"},
 	}
 
 	for _, tt := range tests {
@@ -403,6 +404,7 @@ func TestOutputDirectory(t *testing.T) {
 		{FormatOpenCode, ".opencode/skill/test-skill"},
 		{FormatCopilot, "agents"},
 		{FormatCursor, ".cursor/rules"},
+		{FormatWindsurf, ".windsurf/rules"},
 	}
 
 	for _, tt := range tests {
@@ -528,3 +530,35 @@ func TestRoundTrip_CopilotToClaudeToCopilot(t *testing.T) {
 		t.Errorf("Body = %q, want %q", result.Body, original.Body)
 	}
 }
+
+func TestConvertWithInfo_EmptyBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantWarn bool
+	}{
+		{"empty", "", true},
+		{"whitespace only", "  \n\t\n", true},
+		{"with content", "Do the thing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skill := &ClaudeSkill{Name: "test", Description: "A test", Body: tt.body}
+			result, err := ConvertWithInfo(skill, FormatCursor)
+			if err != nil {
+				t.Fatalf("ConvertWithInfo() error = %v", err)
+			}
+
+			gotWarn := false
+			for _, w := range result.Warnings {
+				if strings.Contains(w, "body is empty") {
+					gotWarn = true
+				}
+			}
+			if gotWarn != tt.wantWarn {
+				t.Errorf("empty body warning = %v, want %v (warnings: %v)", gotWarn, tt.wantWarn, result.Warnings)
+			}
+		})
+	}
+}
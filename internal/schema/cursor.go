@@ -3,6 +3,8 @@ package schema
 import (
 	"path/filepath"
 	"strings"
+
+	"github.com/kennyg/tome/internal/markdown"
 )
 
 // CursorSkill represents a Cursor rule/skill (.cursor/rules/*.md format).
@@ -64,7 +66,7 @@ type cursorFrontmatter struct {
 func ParseCursorSkill(content []byte) (*CursorSkill, error) {
 	skill := &CursorSkill{}
 
-	text := string(content)
+	text := markdown.Normalize(content)
 
 	// Check if there's frontmatter
 	if strings.HasPrefix(text, "---") {
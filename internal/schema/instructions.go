@@ -3,6 +3,8 @@ package schema
 import (
 	"path/filepath"
 	"strings"
+
+	"github.com/kennyg/tome/internal/markdown"
 )
 
 // Instructions represents project-level instruction files.
@@ -244,7 +246,7 @@ type cursorRulesFrontmatter struct {
 
 // ParseCursorRules parses content as Cursor rules
 func ParseCursorRules(content []byte) (*CursorRules, error) {
-	text := string(content)
+	text := markdown.Normalize(content)
 	rules := &CursorRules{}
 
 	// Check if it has frontmatter (MDC format)
@@ -304,6 +306,16 @@ func IsInstructionsFile(filename string) bool {
 		return true
 	}
 
+	// Windsurf legacy and global rules
+	if baseLower == ".windsurfrules" || baseLower == "global_rules.md" {
+		return true
+	}
+
+	// Zed project rules
+	if baseLower == ".rules" {
+		return true
+	}
+
 	return false
 }
 
@@ -321,7 +333,15 @@ func InstructionsOutputFilename(inst Skill, targetFormat Format) string {
 		}
 		return strings.ToLower(strings.ReplaceAll(name, " ", "-")) + ".instructions.md"
 	case FormatCursor:
+		if skillGlobs(inst) != "" {
+			// Scoped rules need MDC frontmatter
+			return toKebabCase(inst.GetName()) + ".mdc"
+		}
 		return ".cursorrules"
+	case FormatWindsurf:
+		return "project.md"
+	case FormatZed:
+		return ".rules"
 	default:
 		return "instructions.md"
 	}
@@ -335,7 +355,14 @@ func InstructionsOutputDirectory(inst Skill, targetFormat Format) string {
 	case FormatCopilot:
 		return "instructions"
 	case FormatCursor:
+		if skillGlobs(inst) != "" {
+			return ".cursor/rules"
+		}
 		return "" // Root directory for .cursorrules
+	case FormatWindsurf:
+		return ".windsurf/rules"
+	case FormatZed:
+		return "" // Root directory for .rules
 	default:
 		return ""
 	}
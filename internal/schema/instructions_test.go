@@ -349,6 +349,44 @@ func TestConvertInstructions(t *testing.T) {
 	}
 }
 
+func TestConvertInstructions_LabelsScope(t *testing.T) {
+	copilot := &CopilotInstructions{ApplyTo: "**/*.ts,**/*.tsx", Body: "Use strict mode.\n"}
+	got, err := ConvertInstructions(copilot, FormatClaude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "## Applies to `**/*.ts`, `**/*.tsx`\n\nUse strict mode."; !strings.Contains(string(got), want) {
+		t.Errorf("ConvertInstructions() = %q, want it to contain %q", got, want)
+	}
+
+	// Instructions that always apply, or formats that scope them, get no label
+	for _, tt := range []struct {
+		inst   Skill
+		target Format
+	}{
+		{&CopilotInstructions{ApplyTo: "**", Body: "Everywhere."}, FormatClaude},
+		{&CursorRules{Globs: "*.go", AlwaysApply: true, Body: "Always."}, FormatOpenCode},
+		{copilot, FormatCursor},
+	} {
+		got, err := ConvertInstructions(tt.inst, tt.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(got), "Applies to") {
+			t.Errorf("ConvertInstructions(%s) = %q, want no Applies to heading", tt.target, got)
+		}
+	}
+
+	cursor := &CursorRules{Globs: "*.go", Body: "Run gofmt."}
+	got, err = ConvertInstructions(cursor, FormatOpenCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(got), "## Applies to `*.go`\n\nRun gofmt.") {
+		t.Errorf("ConvertInstructions(cursor) = %q", got)
+	}
+}
+
 func TestConvertToClaudeInstructions(t *testing.T) {
 	copilot := &CopilotInstructions{
 		Description: "Test",
@@ -562,3 +600,87 @@ func TestRoundTrip_ClaudeInstructionsToCopilotToClaude(t *testing.T) {
 		t.Errorf("Body = %q, want %q", result.Body, original.Body)
 	}
 }
+
+func TestConvertInstructionsWithInfo_EmptyBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		inst    Skill
+		wantErr bool
+	}{
+		{"empty claude", &ClaudeInstructions{Body: ""}, true},
+		{"whitespace copilot", &CopilotInstructions{ApplyTo: "**/*.go", Body: " \n\t"}, true},
+		{"empty cursor", &CursorRules{Description: "Rules", Body: "\n"}, true},
+		{"with content", &ClaudeInstructions{Body: "Use tabs"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConvertInstructionsWithInfo(tt.inst, FormatCopilot)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertInstructionsWithInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConvertSkillToInstructions_Globs(t *testing.T) {
+	skill := &ClaudeSkill{
+		Name:        "go-style",
+		Description: "Go style rules",
+		Globs:       []string{"**/*.go", " ", "go.mod"},
+		Body:        "Use gofmt.",
+	}
+
+	t.Run("cursor", func(t *testing.T) {
+		result, err := ConvertSkillToInstructions(skill, FormatCursor)
+		if err != nil {
+			t.Fatalf("ConvertSkillToInstructions() error = %v", err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("unexpected warnings %v", result.Warnings)
+		}
+		rules, err := ParseCursorRules(result.Content)
+		if err != nil {
+			t.Fatalf("ParseCursorRules() error = %v", err)
+		}
+		if rules.Globs != "**/*.go,go.mod" {
+			t.Errorf("Globs = %q, want %q", rules.Globs, "**/*.go,go.mod")
+		}
+		if rules.Description != skill.Description {
+			t.Errorf("Description = %q", rules.Description)
+		}
+		if got := InstructionsOutputFilename(skill, FormatCursor); got != "go-style.mdc" {
+			t.Errorf("InstructionsOutputFilename() = %q, want go-style.mdc", got)
+		}
+		if got := InstructionsOutputDirectory(skill, FormatCursor); got != ".cursor/rules" {
+			t.Errorf("InstructionsOutputDirectory() = %q, want .cursor/rules", got)
+		}
+	})
+
+	t.Run("copilot", func(t *testing.T) {
+		result, err := ConvertSkillToInstructions(skill, FormatCopilot)
+		if err != nil {
+			t.Fatalf("ConvertSkillToInstructions() error = %v", err)
+		}
+		inst, err := ParseCopilotInstructions(result.Content)
+		if err != nil {
+			t.Fatalf("ParseCopilotInstructions() error = %v", err)
+		}
+		if inst.ApplyTo != "**/*.go,go.mod" {
+			t.Errorf("ApplyTo = %q, want %q", inst.ApplyTo, "**/*.go,go.mod")
+		}
+		if got := InstructionsOutputFilename(skill, FormatCopilot); got != "go-style.instructions.md" {
+			t.Errorf("InstructionsOutputFilename() = %q", got)
+		}
+	})
+
+	t.Run("claude drops globs", func(t *testing.T) {
+		result, err := ConvertSkillToInstructions(skill, FormatClaude)
+		if err != nil {
+			t.Fatalf("ConvertSkillToInstructions() error = %v", err)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "globs") {
+			t.Errorf("expected globs warning, got %v", result.Warnings)
+		}
+	})
+}
@@ -1,9 +1,27 @@
 package schema
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
+
+	"github.com/kennyg/tome/internal/detect"
+)
+
+// MCP transports, independent of the local/remote server Type
+const (
+	MCPTransportStdio = "stdio" // Local process over stdin/stdout
+	MCPTransportHTTP  = "http"  // Streamable HTTP
+	MCPTransportSSE   = "sse"   // Server-sent events
 )
 
 // MCPServer represents a single MCP server configuration
@@ -13,7 +31,9 @@ type MCPServer struct {
 	Command     string            `json:"command,omitempty"`     // Executable command
 	Args        []string          `json:"args,omitempty"`        // Command arguments
 	Env         map[string]string `json:"env,omitempty"`         // Environment variables
+	EnvFile     string            `json:"envFile,omitempty"`     // Dotenv file with more variables (Copilot)
 	Type        string            `json:"type,omitempty"`        // "local" or "remote" (OpenCode)
+	Transport   string            `json:"transport,omitempty"`   // "stdio", "http", or "sse"
 	URL         string            `json:"url,omitempty"`         // Remote server URL (OpenCode)
 	Headers     map[string]string `json:"headers,omitempty"`     // HTTP headers (OpenCode remote)
 	Enabled     *bool             `json:"enabled,omitempty"`     // Enabled state (OpenCode)
@@ -22,9 +42,56 @@ type MCPServer struct {
 	Description string            `json:"description,omitempty"` // Optional description
 }
 
+// EffectiveTransport returns the server's transport, inferring it from the
+// legacy Type value or the presence of a URL when Transport is unset
+func (s *MCPServer) EffectiveTransport() string {
+	if s.Transport != "" {
+		return s.Transport
+	}
+	switch s.Type {
+	case MCPTransportStdio, MCPTransportHTTP, MCPTransportSSE:
+		return s.Type
+	}
+	if s.URL != "" || s.Type == "remote" {
+		return MCPTransportHTTP
+	}
+	return MCPTransportStdio
+}
+
+// IsRemote returns true if the server is reached over the network
+func (s *MCPServer) IsRemote() bool {
+	return s.EffectiveTransport() != MCPTransportStdio
+}
+
+// serverTypeForTransport maps a transport to the local/remote server Type
+func serverTypeForTransport(transport string) string {
+	if transport == MCPTransportStdio {
+		return "local"
+	}
+	return "remote"
+}
+
+// transportFromType normalizes a Claude/Copilot "type" value to a transport
+func transportFromType(typ string) string {
+	if typ == "" {
+		return MCPTransportStdio
+	}
+	return typ
+}
+
+// inferRemoteTransport guesses the transport of a remote server whose format
+// doesn't record it (OpenCode). SSE endpoints conventionally end in /sse.
+func inferRemoteTransport(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), "/sse") {
+		return MCPTransportSSE
+	}
+	return MCPTransportHTTP
+}
+
 // MCPConfig represents a collection of MCP servers
 type MCPConfig struct {
 	Servers      map[string]*MCPServer
+	Inputs       []CopilotMCPInput // Secret prompts servers reference (Copilot)
 	sourceFormat Format
 }
 
@@ -38,7 +105,8 @@ func (c *MCPConfig) SetFormat(f Format) {
 	c.sourceFormat = f
 }
 
-// ServerNames returns sorted server names for deterministic output
+// ServerNames returns sorted server names for deterministic output. The
+// serializers and conversion warnings visit servers in this order.
 func (c *MCPConfig) ServerNames() []string {
 	names := make([]string, 0, len(c.Servers))
 	for name := range c.Servers {
@@ -59,7 +127,9 @@ type ClaudeMCPServer struct {
 	Command  string            `json:"command,omitempty"`
 	Args     []string          `json:"args,omitempty"`
 	Env      map[string]string `json:"env,omitempty"`
-	Type     string            `json:"type,omitempty"`     // "stdio" (default) or others
+	Type     string            `json:"type,omitempty"`     // "stdio" (default), "http" or "sse"
+	URL      string            `json:"url,omitempty"`      // For http/sse types (read, not written)
+	Headers  map[string]string `json:"headers,omitempty"`  // For http/sse types (read, not written)
 	Disabled bool              `json:"disabled,omitempty"` // Disabled state
 	Timeout  int               `json:"timeout,omitempty"`  // Timeout in seconds
 }
@@ -127,14 +197,18 @@ func ParseClaudeMCP(content []byte) (*MCPConfig, error) {
 	}
 
 	for name, server := range cfg.MCPServers {
+		transport := transportFromType(server.Type)
 		config.Servers[name] = &MCPServer{
-			Name:     name,
-			Command:  server.Command,
-			Args:     server.Args,
-			Env:      server.Env,
-			Type:     server.Type,
-			Disabled: server.Disabled,
-			Timeout:  server.Timeout,
+			Name:      name,
+			Command:   server.Command,
+			Args:      server.Args,
+			Env:       server.Env,
+			Type:      serverTypeForTransport(transport),
+			Transport: transport,
+			URL:       server.URL,
+			Headers:   server.Headers,
+			Disabled:  server.Disabled,
+			Timeout:   server.Timeout,
 		}
 	}
 
@@ -151,6 +225,16 @@ func ParseCursorMCP(content []byte) (*MCPConfig, error) {
 	return config, nil
 }
 
+// ParseWindsurfMCP parses Windsurf MCP configuration (mcp_config.json, Claude's format)
+func ParseWindsurfMCP(content []byte) (*MCPConfig, error) {
+	config, err := ParseClaudeMCP(content)
+	if err != nil {
+		return nil, err
+	}
+	config.sourceFormat = FormatWindsurf
+	return config, nil
+}
+
 // ParseOpenCodeMCP parses OpenCode MCP configuration
 func ParseOpenCodeMCP(content []byte) (*MCPConfig, error) {
 	var cfg OpenCodeMCPConfig
@@ -173,6 +257,13 @@ func ParseOpenCodeMCP(content []byte) (*MCPConfig, error) {
 			Enabled: server.Enabled,
 		}
 
+		// OpenCode only records local vs remote
+		if server.Type == "remote" || (server.Type == "" && server.URL != "") {
+			srv.Transport = inferRemoteTransport(server.URL)
+		} else {
+			srv.Transport = MCPTransportStdio
+		}
+
 		// Convert command array to command + args
 		if len(server.Command) > 0 {
 			srv.Command = server.Command[0]
@@ -196,18 +287,22 @@ func ParseCopilotMCP(content []byte) (*MCPConfig, error) {
 
 	config := &MCPConfig{
 		Servers:      make(map[string]*MCPServer),
+		Inputs:       cfg.Inputs,
 		sourceFormat: FormatCopilot,
 	}
 
 	for name, server := range cfg.Servers {
+		transport := transportFromType(server.Type)
 		config.Servers[name] = &MCPServer{
-			Name:    name,
-			Command: server.Command,
-			Args:    server.Args,
-			Env:     server.Env,
-			Type:    server.Type,
-			URL:     server.URL,
-			Headers: server.Headers,
+			Name:      name,
+			Command:   server.Command,
+			Args:      server.Args,
+			Env:       server.Env,
+			EnvFile:   server.EnvFile,
+			Type:      serverTypeForTransport(transport),
+			Transport: transport,
+			URL:       server.URL,
+			Headers:   server.Headers,
 		}
 	}
 
@@ -225,6 +320,8 @@ func ParseMCP(content []byte, format Format) (*MCPConfig, error) {
 		return ParseCursorMCP(content)
 	case FormatOpenCode:
 		return ParseOpenCodeMCP(content)
+	case FormatWindsurf:
+		return ParseWindsurfMCP(content)
 	default:
 		return nil, fmt.Errorf("unsupported MCP format: %s", format)
 	}
@@ -245,6 +342,8 @@ func DetectMCPFormat(filename string) Format {
 		return FormatCursor
 	case contains(filename, "opencode"):
 		return FormatOpenCode
+	case contains(filename, "windsurf"):
+		return FormatWindsurf
 	case contains(filename, ".claude") || hasBasename(filename, ".mcp.json"):
 		return FormatClaude
 	default:
@@ -258,7 +357,8 @@ func SerializeClaudeMCP(config *MCPConfig) ([]byte, error) {
 		MCPServers: make(map[string]*ClaudeMCPServer),
 	}
 
-	for name, server := range config.Servers {
+	for _, name := range config.ServerNames() {
+		server := config.Servers[name]
 		srv := &ClaudeMCPServer{
 			Command:  server.Command,
 			Args:     server.Args,
@@ -266,9 +366,9 @@ func SerializeClaudeMCP(config *MCPConfig) ([]byte, error) {
 			Disabled: server.Disabled,
 			Timeout:  server.Timeout,
 		}
-		// Set type if specified
-		if server.Type != "" && server.Type != "local" {
-			srv.Type = server.Type
+		// stdio is the default; only non-local transports need a type
+		if transport := server.EffectiveTransport(); transport != MCPTransportStdio {
+			srv.Type = transport
 		}
 		cfg.MCPServers[name] = srv
 	}
@@ -287,7 +387,8 @@ func SerializeOpenCodeMCP(config *MCPConfig) ([]byte, error) {
 		MCP: make(map[string]*OpenCodeMCPServer),
 	}
 
-	for name, server := range config.Servers {
+	for _, name := range config.ServerNames() {
+		server := config.Servers[name]
 		srv := &OpenCodeMCPServer{
 			Environment: server.Env,
 			URL:         server.URL,
@@ -295,13 +396,11 @@ func SerializeOpenCodeMCP(config *MCPConfig) ([]byte, error) {
 			Enabled:     server.Enabled,
 		}
 
-		// Set type (default to "local" if not specified)
-		if server.Type != "" {
+		// OpenCode only distinguishes local from remote; the transport is implied
+		if server.Type == "local" || server.Type == "remote" {
 			srv.Type = server.Type
-		} else if server.URL != "" {
-			srv.Type = "remote"
 		} else {
-			srv.Type = "local"
+			srv.Type = serverTypeForTransport(server.EffectiveTransport())
 		}
 
 		// Combine command and args into command array
@@ -319,23 +418,22 @@ func SerializeOpenCodeMCP(config *MCPConfig) ([]byte, error) {
 func SerializeCopilotMCP(config *MCPConfig) ([]byte, error) {
 	cfg := CopilotMCPConfig{
 		Servers: make(map[string]*CopilotMCPServer),
+		Inputs:  sortedInputs(config.Inputs),
 	}
 
-	for name, server := range config.Servers {
+	for _, name := range config.ServerNames() {
+		server := config.Servers[name]
 		srv := &CopilotMCPServer{
 			Command: server.Command,
 			Args:    server.Args,
 			Env:     server.Env,
+			EnvFile: server.EnvFile,
 			URL:     server.URL,
 			Headers: server.Headers,
 		}
-		// Set type for remote servers
-		if server.URL != "" {
-			if server.Type == "sse" {
-				srv.Type = "sse"
-			} else {
-				srv.Type = "http"
-			}
+		// Set type for remote servers (http or sse)
+		if transport := server.EffectiveTransport(); transport != MCPTransportStdio {
+			srv.Type = transport
 		}
 		cfg.Servers[name] = srv
 	}
@@ -343,6 +441,17 @@ func SerializeCopilotMCP(config *MCPConfig) ([]byte, error) {
 	return json.MarshalIndent(cfg, "", "  ")
 }
 
+// sortedInputs returns a copy of inputs ordered by ID, so a config's
+// inputs serialize the same way however they were collected
+func sortedInputs(inputs []CopilotMCPInput) []CopilotMCPInput {
+	if len(inputs) == 0 {
+		return nil
+	}
+	sorted := append([]CopilotMCPInput(nil), inputs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
 // SerializeMCP serializes to the specified format
 func SerializeMCP(config *MCPConfig, format Format) ([]byte, error) {
 	switch format {
@@ -354,6 +463,8 @@ func SerializeMCP(config *MCPConfig, format Format) ([]byte, error) {
 		return SerializeCopilotMCP(config)
 	case FormatOpenCode:
 		return SerializeOpenCodeMCP(config)
+	case FormatWindsurf:
+		return SerializeClaudeMCP(config)
 	default:
 		return nil, fmt.Errorf("unsupported MCP format: %s", format)
 	}
@@ -364,6 +475,26 @@ func ConvertMCP(config *MCPConfig, targetFormat Format) ([]byte, error) {
 	return SerializeMCP(config, targetFormat)
 }
 
+// MCPConversionOptions controls optional conversion behavior
+type MCPConversionOptions struct {
+	// InlineEnvFiles reads each server's envFile and merges its variables into
+	// env for targets that have no envFile support. Variables set in env win.
+	InlineEnvFiles bool
+
+	// BaseDir is the directory of the source config; relative envFile paths
+	// and ${workspaceFolder} are resolved against it
+	BaseDir string
+
+	// VerifyCommands warns about local servers whose command isn't on PATH
+	VerifyCommands bool
+
+	// SecretInputs moves env values whose names look like credentials
+	// (GITHUB_TOKEN, OPENAI_API_KEY) into Copilot inputs, so VS Code prompts
+	// for them instead of the config holding them in plaintext. Copilot
+	// targets only.
+	SecretInputs bool
+}
+
 // MCPConversionResult holds the result of an MCP conversion
 type MCPConversionResult struct {
 	SourceFormat Format
@@ -375,6 +506,22 @@ type MCPConversionResult struct {
 
 // ConvertMCPWithInfo converts MCP config and returns detailed information
 func ConvertMCPWithInfo(config *MCPConfig, targetFormat Format) (*MCPConversionResult, error) {
+	return ConvertMCPWithOptions(config, targetFormat, MCPConversionOptions{})
+}
+
+// ConvertMCPWithOptions converts MCP config using opts and returns detailed information
+func ConvertMCPWithOptions(config *MCPConfig, targetFormat Format, opts MCPConversionOptions) (*MCPConversionResult, error) {
+	var envWarnings []string
+	if opts.InlineEnvFiles && targetFormat != FormatCopilot {
+		config, envWarnings = inlineEnvFiles(config, opts.BaseDir)
+	}
+	if opts.SecretInputs && targetFormat == FormatCopilot {
+		config, envWarnings = liftSecretInputs(config)
+	}
+
+	if errs := ValidateMCPConfig(config); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid MCP config: %w", errors.Join(errs...))
+	}
 	content, err := ConvertMCP(config, targetFormat)
 	if err != nil {
 		return nil, err
@@ -385,10 +532,19 @@ func ConvertMCPWithInfo(config *MCPConfig, targetFormat Format) (*MCPConversionR
 		TargetFormat: targetFormat,
 		ServerCount:  len(config.Servers),
 		Content:      content,
+		Warnings:     envWarnings,
+	}
+	if opts.VerifyCommands {
+		result.Warnings = append(result.Warnings, verifyMCPCommands(config)...)
 	}
 
 	// Check for potential data loss
-	for name, server := range config.Servers {
+	if targetFormat != FormatCopilot && len(config.Inputs) > 0 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("inputs are Copilot-specific (%d will be omitted)", len(config.Inputs)))
+	}
+	for _, name := range config.ServerNames() {
+		server := config.Servers[name]
 		// OpenCode-specific fields
 		if targetFormat != FormatOpenCode && targetFormat != FormatCopilot {
 			if server.URL != "" {
@@ -400,6 +556,14 @@ func ConvertMCPWithInfo(config *MCPConfig, targetFormat Format) (*MCPConversionR
 					fmt.Sprintf("server %q: headers not supported in %s (will be omitted)", name, targetFormat))
 			}
 		}
+		if targetFormat != FormatCopilot && server.EnvFile != "" {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("server %q: envFile not supported in %s (variables from %s will be omitted)", name, targetFormat, server.EnvFile))
+		}
+		if targetFormat == FormatOpenCode && server.EffectiveTransport() == MCPTransportSSE {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("server %q: SSE transport not supported in %s (will be written as a generic remote server)", name, targetFormat))
+		}
 		if targetFormat != FormatOpenCode {
 			if server.Enabled != nil {
 				result.Warnings = append(result.Warnings,
@@ -423,6 +587,191 @@ func ConvertMCPWithInfo(config *MCPConfig, targetFormat Format) (*MCPConversionR
 	return result, nil
 }
 
+// ValidateMCPConfig checks that every server could be started: a local server
+// needs a command, a remote one a url, and the transport must be stdio, http
+// or sse. It returns one error per problem, in server name order.
+func ValidateMCPConfig(config *MCPConfig) []error {
+	var errs []error
+	for _, name := range config.ServerNames() {
+		server := config.Servers[name]
+		transport := server.EffectiveTransport()
+		switch {
+		case transport != MCPTransportStdio && transport != MCPTransportHTTP && transport != MCPTransportSSE:
+			errs = append(errs, fmt.Errorf("server %q: unknown transport type %q (use %s, %s or %s)",
+				name, transport, MCPTransportStdio, MCPTransportHTTP, MCPTransportSSE))
+		case server.Command == "" && server.URL == "":
+			errs = append(errs, fmt.Errorf("server %q: has neither a command nor a url", name))
+		case server.IsRemote() && server.URL == "":
+			errs = append(errs, fmt.Errorf("server %q: remote server has no url", name))
+		case !server.IsRemote() && server.Command == "":
+			errs = append(errs, fmt.Errorf("server %q: local server has no command", name))
+		}
+	}
+	return errs
+}
+
+// verifyMCPCommands returns a warning for each local server whose command
+// can't be found on PATH. Remote servers have no command to check.
+func verifyMCPCommands(config *MCPConfig) []string {
+	var warnings []string
+	for _, name := range config.ServerNames() {
+		server := config.Servers[name]
+		if server.IsRemote() || server.Command == "" {
+			continue
+		}
+		if _, err := exec.LookPath(server.Command); err != nil {
+			warnings = append(warnings,
+				fmt.Sprintf("server %q: command not found: %s (the server won't start)", name, server.Command))
+		}
+	}
+	return warnings
+}
+
+// inlineEnvFiles returns a copy of config with each server's envFile merged
+// into Env. Unreadable files are reported as warnings and left in place.
+func inlineEnvFiles(config *MCPConfig, baseDir string) (*MCPConfig, []string) {
+	inlined := &MCPConfig{
+		Servers:      make(map[string]*MCPServer, len(config.Servers)),
+		Inputs:       config.Inputs,
+		sourceFormat: config.sourceFormat,
+	}
+
+	var warnings []string
+	for _, name := range config.ServerNames() {
+		server := config.Servers[name]
+		if server.EnvFile == "" {
+			inlined.Servers[name] = server
+			continue
+		}
+
+		path := resolveEnvFilePath(server.EnvFile, baseDir)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			warnings = append(warnings,
+				fmt.Sprintf("server %q: cannot read envFile %s: %v", name, server.EnvFile, err))
+			inlined.Servers[name] = server
+			continue
+		}
+
+		srv := *server
+		srv.Env = parseDotEnv(content)
+		for k, v := range server.Env {
+			srv.Env[k] = v
+		}
+		srv.EnvFile = ""
+		inlined.Servers[name] = &srv
+	}
+
+	return inlined, warnings
+}
+
+// liftSecretInputs returns a copy of config whose credential-looking env
+// values are replaced by ${input:id} references to new password inputs.
+// Values that already reference a variable or input are left alone. Each
+// lifted value is reported, since the converted config no longer has it.
+func liftSecretInputs(config *MCPConfig) (*MCPConfig, []string) {
+	lifted := &MCPConfig{
+		Servers:      make(map[string]*MCPServer, len(config.Servers)),
+		Inputs:       append([]CopilotMCPInput(nil), config.Inputs...),
+		sourceFormat: config.sourceFormat,
+	}
+
+	isSecret := func(key, value string) bool {
+		return value != "" && !strings.Contains(value, "${") && detect.IsSecretEnv(key)
+	}
+
+	// Servers sharing a variable name each get their own input
+	uses := make(map[string]int)
+	for _, server := range config.Servers {
+		for key, value := range server.Env {
+			if isSecret(key, value) {
+				uses[key]++
+			}
+		}
+	}
+	taken := make(map[string]bool, len(lifted.Inputs))
+	for _, input := range lifted.Inputs {
+		taken[input.ID] = true
+	}
+
+	var notes []string
+	for _, name := range config.ServerNames() {
+		server := config.Servers[name]
+		srv := *server
+		srv.Env = make(map[string]string, len(server.Env))
+		keys := make([]string, 0, len(server.Env))
+		for key, value := range server.Env {
+			srv.Env[key] = value
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if !isSecret(key, server.Env[key]) {
+				continue
+			}
+			id := strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+			if uses[key] > 1 || taken[id] {
+				id = strings.ToLower(name) + "-" + id
+			}
+			taken[id] = true
+			lifted.Inputs = append(lifted.Inputs, CopilotMCPInput{
+				ID:          id,
+				Type:        "promptString",
+				Description: fmt.Sprintf("%s for the %s MCP server", key, name),
+				Password:    true,
+			})
+			srv.Env[key] = "${input:" + id + "}"
+			notes = append(notes,
+				fmt.Sprintf("server %q: %s moved to input %q (VS Code prompts for it)", name, key, id))
+		}
+		lifted.Servers[name] = &srv
+	}
+
+	return lifted, notes
+}
+
+// resolveEnvFilePath resolves an envFile value against the config directory.
+// ${workspaceFolder} is the project root, i.e. the parent of a .vscode directory.
+func resolveEnvFilePath(envFile, baseDir string) string {
+	workspace := baseDir
+	if filepath.Base(baseDir) == ".vscode" {
+		workspace = filepath.Dir(baseDir)
+	}
+	path := strings.ReplaceAll(envFile, "${workspaceFolder}", workspace)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return path
+}
+
+// parseDotEnv parses KEY=VALUE lines, skipping blanks and # comments
+func parseDotEnv(content []byte) map[string]string {
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		if key != "" {
+			env[key] = value
+		}
+	}
+	return env
+}
+
 // MCPOutputFilename returns the appropriate filename for MCP config
 func MCPOutputFilename(targetFormat Format) string {
 	switch targetFormat {
@@ -434,6 +783,8 @@ func MCPOutputFilename(targetFormat Format) string {
 		return "mcp.json"
 	case FormatOpenCode:
 		return "opencode.json"
+	case FormatWindsurf:
+		return "mcp_config.json"
 	default:
 		return "mcp.json"
 	}
@@ -450,6 +801,8 @@ func MCPOutputDirectory(targetFormat Format) string {
 		return ".vscode"
 	case FormatOpenCode:
 		return "" // opencode.json goes in project root
+	case FormatWindsurf:
+		return "" // mcp_config.json lives in ~/.codeium/windsurf
 	default:
 		return ""
 	}
@@ -466,6 +819,8 @@ func IsMCPFile(filename string) bool {
 		return true
 	case hasBasename(filename, "opencode.json"):
 		return true
+	case hasBasename(filename, "mcp_config.json") && contains(filename, "windsurf"):
+		return true
 	case hasBasename(filename, ".claude.json"):
 		return true
 	case contains(filename, "settings.local.json"):
@@ -476,7 +831,7 @@ func IsMCPFile(filename string) bool {
 }
 
 // MergeMCPConfigs merges multiple MCP configs into one
-// Later configs override earlier ones for the same server name
+// Later configs override earlier ones for the same server name or input ID
 func MergeMCPConfigs(configs ...*MCPConfig) *MCPConfig {
 	merged := &MCPConfig{
 		Servers: make(map[string]*MCPServer),
@@ -489,6 +844,10 @@ func MergeMCPConfigs(configs ...*MCPConfig) *MCPConfig {
 		for name, server := range cfg.Servers {
 			merged.Servers[name] = server
 		}
+		for _, input := range cfg.Inputs {
+			merged.Inputs = slices.DeleteFunc(merged.Inputs, func(in CopilotMCPInput) bool { return in.ID == input.ID })
+			merged.Inputs = append(merged.Inputs, input)
+		}
 		// Use the last config's format
 		merged.sourceFormat = cfg.sourceFormat
 	}
@@ -1,7 +1,11 @@
 package schema
 
 import (
+	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -350,6 +354,61 @@ func TestConvertMCPWithInfo_Warnings(t *testing.T) {
 	}
 }
 
+func TestValidateMCPConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		server MCPServer
+		want   string // Substring of the error; empty for a valid server
+	}{
+		{"local", MCPServer{Command: "npx"}, ""},
+		{"remote", MCPServer{Transport: MCPTransportHTTP, URL: "https://mcp.example.com"}, ""},
+		{"opencode remote", MCPServer{Type: "remote", URL: "https://mcp.example.com"}, ""},
+		{"empty", MCPServer{Env: map[string]string{"DEBUG": "1"}}, "neither a command nor a url"},
+		{"remote without url", MCPServer{Transport: MCPTransportSSE, Command: "npx"}, "remote server has no url"},
+		{"opencode remote without url", MCPServer{Type: "remote", Command: "npx"}, "remote server has no url"},
+		{"local without command", MCPServer{Type: "local", Transport: MCPTransportStdio, URL: "https://mcp.example.com"}, "local server has no command"},
+		{"unknown transport", MCPServer{Transport: "websocket", URL: "wss://mcp.example.com"}, `unknown transport type "websocket"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.server
+			errs := ValidateMCPConfig(&MCPConfig{Servers: map[string]*MCPServer{"srv": &server}})
+			if tt.want == "" {
+				if len(errs) != 0 {
+					t.Errorf("ValidateMCPConfig() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.want) || !strings.Contains(errs[0].Error(), `"srv"`) {
+				t.Errorf("ValidateMCPConfig() = %v, want one error naming srv containing %q", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertMCPWithOptions_RejectsInvalidServers(t *testing.T) {
+	input := `{
+  "mcpServers": {
+    "ok": {"command": "npx"},
+    "docs": {"type": "http", "url": "https://docs.example.com/mcp"},
+    "broken": {"args": ["--verbose"]},
+    "stream": {"type": "websocket"}
+  }
+}`
+	config, err := ParseClaudeMCP([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseClaudeMCP failed: %v", err)
+	}
+	if errs := ValidateMCPConfig(config); len(errs) != 2 {
+		t.Errorf("ValidateMCPConfig() = %v, want errors for broken and stream", errs)
+	}
+
+	_, err = ConvertMCPWithOptions(config, FormatOpenCode, MCPConversionOptions{})
+	if err == nil || !strings.Contains(err.Error(), `"broken"`) || !strings.Contains(err.Error(), `"stream"`) {
+		t.Errorf("ConvertMCPWithOptions() error = %v, want both invalid servers named", err)
+	}
+}
+
 func TestDetectMCPFormat(t *testing.T) {
 	tests := []struct {
 		filename string
@@ -603,9 +662,9 @@ func TestMergeMCPConfigs(t *testing.T) {
 func TestServerNames(t *testing.T) {
 	config := &MCPConfig{
 		Servers: map[string]*MCPServer{
-			"zebra":   {Name: "zebra"},
-			"alpha":   {Name: "alpha"},
-			"middle":  {Name: "middle"},
+			"zebra":  {Name: "zebra"},
+			"alpha":  {Name: "alpha"},
+			"middle": {Name: "middle"},
 		},
 	}
 
@@ -681,3 +740,389 @@ func TestRoundTrip_ClaudeToOpenCodeAndBack(t *testing.T) {
 		t.Errorf("env PATH_PREFIX = %q, want %q", server.Env["PATH_PREFIX"], "/home/user")
 	}
 }
+
+func TestRoundTrip_CopilotSSEThroughOpenCode(t *testing.T) {
+	input := `{
+  "servers": {
+    "events": {
+      "type": "sse",
+      "url": "https://mcp.example.com/sse",
+      "headers": {
+        "Authorization": "Bearer xxx"
+      }
+    },
+    "api": {
+      "type": "http",
+      "url": "https://mcp.example.com/mcp"
+    }
+  }
+}`
+
+	copilot, err := ParseCopilotMCP([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseCopilotMCP failed: %v", err)
+	}
+	if got := copilot.Servers["events"].Transport; got != MCPTransportSSE {
+		t.Errorf("parsed transport = %q, want %q", got, MCPTransportSSE)
+	}
+	if got := copilot.Servers["events"].Type; got != "remote" {
+		t.Errorf("parsed type = %q, want %q", got, "remote")
+	}
+
+	result, err := ConvertMCPWithInfo(copilot, FormatOpenCode)
+	if err != nil {
+		t.Fatalf("ConvertMCPWithInfo failed: %v", err)
+	}
+	var sseWarnings int
+	for _, w := range result.Warnings {
+		if strings.Contains(w, "SSE") {
+			sseWarnings++
+		}
+	}
+	if sseWarnings != 1 {
+		t.Errorf("expected 1 SSE downgrade warning, got %v", result.Warnings)
+	}
+
+	var opencodeOut OpenCodeMCPConfig
+	if err := json.Unmarshal(result.Content, &opencodeOut); err != nil {
+		t.Fatalf("failed to parse OpenCode output: %v", err)
+	}
+	if got := opencodeOut.MCP["events"].Type; got != "remote" {
+		t.Errorf("opencode type = %q, want %q", got, "remote")
+	}
+
+	opencode, err := ParseOpenCodeMCP(result.Content)
+	if err != nil {
+		t.Fatalf("ParseOpenCodeMCP failed: %v", err)
+	}
+	output, err := ConvertMCP(opencode, FormatCopilot)
+	if err != nil {
+		t.Fatalf("ConvertMCP failed: %v", err)
+	}
+
+	var parsed CopilotMCPConfig
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		t.Fatalf("failed to parse Copilot output: %v", err)
+	}
+	if got := parsed.Servers["events"].Type; got != MCPTransportSSE {
+		t.Errorf("events type = %q, want %q", got, MCPTransportSSE)
+	}
+	if got := parsed.Servers["events"].Headers["Authorization"]; got != "Bearer xxx" {
+		t.Errorf("events Authorization header = %q", got)
+	}
+	if got := parsed.Servers["api"].Type; got != MCPTransportHTTP {
+		t.Errorf("api type = %q, want %q", got, MCPTransportHTTP)
+	}
+}
+
+func TestMCPServer_EffectiveTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		server MCPServer
+		want   string
+	}{
+		{"explicit", MCPServer{Type: "remote", Transport: MCPTransportSSE}, MCPTransportSSE},
+		{"legacy type", MCPServer{Type: "sse", URL: "https://x"}, MCPTransportSSE},
+		{"remote type", MCPServer{Type: "remote"}, MCPTransportHTTP},
+		{"url only", MCPServer{URL: "https://x"}, MCPTransportHTTP},
+		{"command", MCPServer{Command: "npx"}, MCPTransportStdio},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.server.EffectiveTransport(); got != tt.want {
+				t.Errorf("EffectiveTransport() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertMCPWithOptions_InlineEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	vscodeDir := filepath.Join(dir, ".vscode")
+	if err := os.MkdirAll(vscodeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	envContent := "# secrets\nAPI_KEY=from-file\nexport REGION=\"us-east-1\"\nDEBUG=false\n"
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(envContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := `{
+  "servers": {
+    "api": {
+      "command": "api-server",
+      "envFile": "${workspaceFolder}/.env",
+      "env": {
+        "DEBUG": "true"
+      }
+    },
+    "missing": {
+      "command": "other-server",
+      "envFile": "missing.env"
+    }
+  }
+}`
+
+	config, err := ParseCopilotMCP([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseCopilotMCP failed: %v", err)
+	}
+	if config.Servers["api"].EnvFile != "${workspaceFolder}/.env" {
+		t.Errorf("envFile = %q, want it preserved", config.Servers["api"].EnvFile)
+	}
+
+	result, err := ConvertMCPWithOptions(config, FormatClaude, MCPConversionOptions{
+		InlineEnvFiles: true,
+		BaseDir:        vscodeDir,
+	})
+	if err != nil {
+		t.Fatalf("ConvertMCPWithOptions failed: %v", err)
+	}
+
+	var parsed ClaudeMCPConfig
+	if err := json.Unmarshal(result.Content, &parsed); err != nil {
+		t.Fatalf("failed to parse converted output: %v", err)
+	}
+
+	env := parsed.MCPServers["api"].Env
+	if env["API_KEY"] != "from-file" {
+		t.Errorf("API_KEY = %q, want %q", env["API_KEY"], "from-file")
+	}
+	if env["REGION"] != "us-east-1" {
+		t.Errorf("REGION = %q, want %q", env["REGION"], "us-east-1")
+	}
+	if env["DEBUG"] != "true" {
+		t.Errorf("DEBUG = %q, want explicit env to win over envFile", env["DEBUG"])
+	}
+
+	var readWarning bool
+	for _, w := range result.Warnings {
+		if strings.Contains(w, `"missing"`) && strings.Contains(w, "cannot read envFile") {
+			readWarning = true
+		}
+		if strings.Contains(w, `"api"`) {
+			t.Errorf("unexpected warning for inlined server: %s", w)
+		}
+	}
+	if !readWarning {
+		t.Errorf("expected unreadable envFile warning, got %v", result.Warnings)
+	}
+
+	// The source config is left untouched
+	if config.Servers["api"].Env["API_KEY"] != "" {
+		t.Error("inlining should not modify the source config")
+	}
+}
+
+func TestConvertMCPWithInfo_EnvFileNotInlined(t *testing.T) {
+	config := &MCPConfig{
+		Servers: map[string]*MCPServer{
+			"api": {Name: "api", Command: "api-server", EnvFile: ".env"},
+		},
+	}
+
+	result, err := ConvertMCPWithInfo(config, FormatOpenCode)
+	if err != nil {
+		t.Fatalf("ConvertMCPWithInfo failed: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "envFile not supported") {
+		t.Errorf("expected envFile warning, got %v", result.Warnings)
+	}
+
+	copilot, err := ConvertMCP(config, FormatCopilot)
+	if err != nil {
+		t.Fatalf("ConvertMCP failed: %v", err)
+	}
+	if !strings.Contains(string(copilot), `"envFile": ".env"`) {
+		t.Errorf("expected envFile preserved in Copilot output:\n%s", copilot)
+	}
+}
+
+func TestConvertMCPWithOptions_VerifyCommands(t *testing.T) {
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "present-server"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	config := &MCPConfig{
+		Servers: map[string]*MCPServer{
+			"present": {Name: "present", Command: "present-server"},
+			"absent":  {Name: "absent", Command: "absent-server"},
+			"remote":  {Name: "remote", URL: "https://mcp.example.com/mcp"},
+		},
+	}
+
+	result, err := ConvertMCPWithOptions(config, FormatOpenCode, MCPConversionOptions{VerifyCommands: true})
+	if err != nil {
+		t.Fatalf("ConvertMCPWithOptions failed: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], `"absent"`) || !strings.Contains(result.Warnings[0], "absent-server") {
+		t.Errorf("expected one warning for absent-server, got %v", result.Warnings)
+	}
+
+	result, err = ConvertMCPWithInfo(config, FormatOpenCode)
+	if err != nil {
+		t.Fatalf("ConvertMCPWithInfo failed: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("expected no warnings without verification, got %v", result.Warnings)
+	}
+}
+
+func TestSerializeMCP_Deterministic(t *testing.T) {
+	// Two configs holding the same servers and inputs, collected in different orders
+	enabled := true
+	build := func(names []string, inputIDs []string) *MCPConfig {
+		config := &MCPConfig{Servers: make(map[string]*MCPServer)}
+		for _, name := range names {
+			config.Servers[name] = &MCPServer{
+				Name:    name,
+				Command: "npx",
+				Args:    []string{"-y", name},
+				Env:     map[string]string{"B": "2", "A": "1", "TOKEN": "${input:" + name + "}"},
+				Enabled: &enabled,
+				Timeout: 30,
+			}
+		}
+		for _, id := range inputIDs {
+			config.Inputs = append(config.Inputs, CopilotMCPInput{ID: id, Type: "promptString", Password: true})
+		}
+		return config
+	}
+	a := build([]string{"zeta", "alpha", "mid"}, []string{"zeta", "alpha", "mid"})
+	b := build([]string{"mid", "zeta", "alpha"}, []string{"alpha", "mid", "zeta"})
+
+	for _, format := range []Format{FormatClaude, FormatCursor, FormatCopilot, FormatOpenCode, FormatWindsurf} {
+		first, err := ConvertMCPWithInfo(a, format)
+		if err != nil {
+			t.Fatalf("%s: ConvertMCPWithInfo() error = %v", format, err)
+		}
+		second, err := ConvertMCPWithInfo(b, format)
+		if err != nil {
+			t.Fatalf("%s: ConvertMCPWithInfo() error = %v", format, err)
+		}
+		if !bytes.Equal(first.Content, second.Content) {
+			t.Errorf("%s: serializations differ:\n%s\n---\n%s", format, first.Content, second.Content)
+		}
+		if strings.Join(first.Warnings, "\n") != strings.Join(second.Warnings, "\n") {
+			t.Errorf("%s: warnings differ:\n%v\n%v", format, first.Warnings, second.Warnings)
+		}
+	}
+
+	out, err := SerializeCopilotMCP(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i, j := strings.Index(string(out), `"id": "alpha"`), strings.Index(string(out), `"id": "zeta"`); i < 0 || j < i {
+		t.Errorf("inputs not sorted by ID:\n%s", out)
+	}
+	if a.Inputs[0].ID != "zeta" {
+		t.Error("SerializeCopilotMCP() reordered the config's own inputs")
+	}
+}
+
+func TestMergeMCPConfigs_Inputs(t *testing.T) {
+	base := &MCPConfig{Inputs: []CopilotMCPInput{{ID: "token", Description: "old"}, {ID: "key"}}}
+	override := &MCPConfig{Inputs: []CopilotMCPInput{{ID: "token", Description: "new"}}}
+
+	merged := MergeMCPConfigs(base, override)
+	if len(merged.Inputs) != 2 {
+		t.Fatalf("inputs = %v, want 2", merged.Inputs)
+	}
+	for _, in := range merged.Inputs {
+		if in.ID == "token" && in.Description != "new" {
+			t.Errorf("token input = %+v, want the later config's", in)
+		}
+	}
+}
+
+func TestConvertMCPWithOptions_SecretInputs(t *testing.T) {
+	input := `{
+  "mcpServers": {
+    "github": {
+      "command": "npx",
+      "args": ["-y", "@modelcontextprotocol/server-github"],
+      "env": {
+        "GITHUB_PERSONAL_ACCESS_TOKEN": "ghp_secret",
+        "GITHUB_TOKEN": "ghp_other",
+        "GITHUB_HOST": "github.com",
+        "OPENAI_API_KEY": "${OPENAI_API_KEY}"
+      }
+    },
+    "search": {
+      "command": "search-mcp",
+      "env": {"GITHUB_TOKEN": "ghp_search"}
+    }
+  }
+}`
+	config, err := ParseClaudeMCP([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ConvertMCPWithOptions(config, FormatCopilot, MCPConversionOptions{SecretInputs: true})
+	if err != nil {
+		t.Fatalf("ConvertMCPWithOptions() error = %v", err)
+	}
+	if strings.Contains(string(result.Content), "ghp_") {
+		t.Errorf("secret left in plaintext:\n%s", result.Content)
+	}
+
+	var cfg CopilotMCPConfig
+	if err := json.Unmarshal(result.Content, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	github := cfg.Servers["github"].Env
+	want := map[string]string{
+		"GITHUB_PERSONAL_ACCESS_TOKEN": "${input:github-personal-access-token}",
+		"GITHUB_TOKEN":                 "${input:github-github-token}", // search uses the same name
+		"GITHUB_HOST":                  "github.com",
+		"OPENAI_API_KEY":               "${OPENAI_API_KEY}",
+	}
+	for key, value := range want {
+		if github[key] != value {
+			t.Errorf("github env %s = %q, want %q", key, github[key], value)
+		}
+	}
+	if got := cfg.Servers["search"].Env["GITHUB_TOKEN"]; got != "${input:search-github-token}" {
+		t.Errorf("search env GITHUB_TOKEN = %q", got)
+	}
+
+	var ids []string
+	for _, in := range cfg.Inputs {
+		if !in.Password || in.Type != "promptString" {
+			t.Errorf("input %s = %+v, want a password prompt", in.ID, in)
+		}
+		ids = append(ids, in.ID)
+	}
+	if got := strings.Join(ids, ","); got != "github-github-token,github-personal-access-token,search-github-token" {
+		t.Errorf("inputs = %s", got)
+	}
+	if len(result.Warnings) != 3 {
+		t.Errorf("warnings = %v, want one per lifted value", result.Warnings)
+	}
+
+	// The source config keeps its values
+	if config.Servers["github"].Env["GITHUB_TOKEN"] != "ghp_other" {
+		t.Error("SecretInputs modified the source config")
+	}
+
+	// Without the option, or for other targets, values are written as they are
+	for _, tc := range []struct {
+		format Format
+		opts   MCPConversionOptions
+	}{
+		{FormatCopilot, MCPConversionOptions{}},
+		{FormatClaude, MCPConversionOptions{SecretInputs: true}},
+	} {
+		result, err := ConvertMCPWithOptions(config, tc.format, tc.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(result.Content), "ghp_secret") {
+			t.Errorf("%s %+v: secret not kept:\n%s", tc.format, tc.opts, result.Content)
+		}
+	}
+}
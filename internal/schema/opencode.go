@@ -0,0 +1,151 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+)
+
+// OpenCodePermission is the permission field of OpenCode frontmatter. Each
+// tool maps to "allow", "ask" or "deny"; bash may instead map command
+// patterns ("git add *") to one.
+type OpenCodePermission map[string]any
+
+// openCodeTools maps Claude Code tool names to OpenCode permission keys
+var openCodeTools = map[string]string{
+	"Read":         "read",
+	"Write":        "edit",
+	"Edit":         "edit",
+	"MultiEdit":    "edit",
+	"NotebookEdit": "edit",
+	"Bash":         "bash",
+	"Glob":         "glob",
+	"Grep":         "grep",
+	"LS":           "list",
+	"WebFetch":     "webfetch",
+	"WebSearch":    "websearch",
+	"Task":         "task",
+	"TodoWrite":    "todowrite",
+	"TodoRead":     "todoread",
+}
+
+// claudeTools maps OpenCode permission keys back to the Claude Code tools
+// they cover
+var claudeTools = map[string][]string{
+	"read":      {"Read"},
+	"edit":      {"Edit", "Write"},
+	"bash":      {"Bash"},
+	"glob":      {"Glob"},
+	"grep":      {"Grep"},
+	"list":      {"LS"},
+	"webfetch":  {"WebFetch"},
+	"websearch": {"WebSearch"},
+	"task":      {"Task"},
+	"todowrite": {"TodoWrite"},
+	"todoread":  {"TodoRead"},
+}
+
+// permissionFromAllowedTools maps Claude allowed-tools to OpenCode
+// permissions that allow them. Bash(git add:*) becomes the bash pattern
+// "git add *". Tools OpenCode has no permission for, and patterns on tools
+// other than Bash (which OpenCode can't narrow the same way), are returned
+// as unmapped rather than granted more broadly.
+func permissionFromAllowedTools(tools []string) (OpenCodePermission, []string) {
+	perm := OpenCodePermission{}
+	var unmapped []string
+	for _, tool := range tools {
+		tool = strings.TrimSpace(tool)
+		name, pattern, hasPattern := strings.Cut(tool, "(")
+		key, ok := openCodeTools[name]
+		if !ok || (hasPattern && key != "bash") {
+			unmapped = append(unmapped, tool)
+			continue
+		}
+		if !hasPattern {
+			perm[key] = "allow"
+			continue
+		}
+
+		pattern = strings.TrimSuffix(pattern, ")")
+		if prefix, ok := strings.CutSuffix(pattern, ":*"); ok {
+			pattern = prefix + " *"
+		}
+		// A plain Bash grant already allows every command
+		if perm[key] == "allow" {
+			continue
+		}
+		patterns, _ := perm[key].(map[string]any)
+		if patterns == nil {
+			patterns = map[string]any{}
+			perm[key] = patterns
+		}
+		patterns[pattern] = "allow"
+	}
+	if len(perm) == 0 {
+		return nil, unmapped
+	}
+	return perm, unmapped
+}
+
+// allowedToolsFromPermission returns the Claude allowed-tools for what an
+// OpenCode permission map allows, in a stable order. Bash patterns become
+// Bash(git add:*). Entries that ask or deny, or that Claude has no tool
+// for, are returned as dropped.
+func allowedToolsFromPermission(perm OpenCodePermission) (tools, dropped []string) {
+	keys := make([]string, 0, len(perm))
+	for key := range perm {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		names, known := claudeTools[key]
+		value := perm[key]
+		// yaml decodes nested bash patterns as the parent's map type
+		if p, ok := value.(OpenCodePermission); ok {
+			value = map[string]any(p)
+		}
+		switch v := value.(type) {
+		case string:
+			if !known || v != "allow" {
+				dropped = append(dropped, key)
+				continue
+			}
+			tools = append(tools, names...)
+		case map[string]any:
+			patterns := make([]string, 0, len(v))
+			for pattern := range v {
+				patterns = append(patterns, pattern)
+			}
+			sort.Strings(patterns)
+			for _, pattern := range patterns {
+				if key != "bash" || v[pattern] != "allow" {
+					dropped = append(dropped, key+" "+pattern)
+					continue
+				}
+				if prefix, ok := strings.CutSuffix(pattern, " *"); ok {
+					pattern = prefix + ":*"
+				}
+				tools = append(tools, "Bash("+pattern+")")
+			}
+		default:
+			dropped = append(dropped, key)
+		}
+	}
+	return tools, dropped
+}
+
+// mergePermission adds mapped permissions to explicit ones; an explicit entry
+// for a tool wins, so an ask or deny is never loosened
+func mergePermission(explicit, mapped OpenCodePermission) OpenCodePermission {
+	if len(explicit) == 0 {
+		return mapped
+	}
+	merged := OpenCodePermission{}
+	for key, v := range mapped {
+		merged[key] = v
+	}
+	for key, v := range explicit {
+		merged[key] = v
+	}
+	return merged
+}
@@ -0,0 +1,126 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPermissionFromAllowedTools(t *testing.T) {
+	perm, unmapped := permissionFromAllowedTools([]string{"Read", "Write", "Edit", "Bash(git add:*)", "Bash(npm test)", "Read(./src/**)", "mcp__github__create_issue"})
+
+	want := OpenCodePermission{
+		"read": "allow",
+		"edit": "allow",
+		"bash": map[string]any{"git add *": "allow", "npm test": "allow"},
+	}
+	if !reflect.DeepEqual(perm, want) {
+		t.Errorf("permission = %v, want %v", perm, want)
+	}
+	if strings.Join(unmapped, ",") != "Read(./src/**),mcp__github__create_issue" {
+		t.Errorf("unmapped = %v, want the narrowed Read and the MCP tool", unmapped)
+	}
+
+	// A plain Bash grant covers any pattern, in either order
+	for _, tools := range [][]string{{"Bash", "Bash(ls:*)"}, {"Bash(ls:*)", "Bash"}} {
+		perm, _ := permissionFromAllowedTools(tools)
+		if perm["bash"] != "allow" {
+			t.Errorf("%v: bash = %v, want allow", tools, perm["bash"])
+		}
+	}
+
+	if perm, _ := permissionFromAllowedTools(nil); perm != nil {
+		t.Errorf("no tools: permission = %v, want nil", perm)
+	}
+}
+
+func TestConvertCommandWithInfo_AllowedToolsToOpenCode(t *testing.T) {
+	cmd := &ClaudeCommand{
+		Name:         "commit",
+		Description:  "Commit staged changes",
+		AllowedTools: []string{"Read", "Bash(git add:*)", "Bash(git commit:*)", "mcp__github__create_pr"},
+		Body:         "Write a commit message and commit.",
+	}
+
+	result, err := ConvertCommandWithInfo(cmd, FormatOpenCode)
+	if err != nil {
+		t.Fatalf("ConvertCommandWithInfo() error = %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "mcp__github__create_pr") {
+		t.Errorf("warnings = %v, want one for the unmapped MCP tool", result.Warnings)
+	}
+
+	out := string(result.Content)
+	if strings.Contains(out, "allowed-tools") {
+		t.Errorf("OpenCode command kept allowed-tools:\n%s", out)
+	}
+	parsed, err := ParseOpenCodeCommand(result.Content)
+	if err != nil {
+		t.Fatalf("ParseOpenCodeCommand() error = %v", err)
+	}
+	want := OpenCodePermission{
+		"read": "allow",
+		"bash": map[string]any{"git add *": "allow", "git commit *": "allow"},
+	}
+	if !reflect.DeepEqual(normalizePermission(parsed.Permission), want) {
+		t.Errorf("permission = %v, want %v\n%s", parsed.Permission, want, out)
+	}
+}
+
+func TestConvertCommandWithInfo_OpenCodePermissionToClaude(t *testing.T) {
+	content := []byte("---\nname: commit\ndescription: Commit\npermission:\n  edit: allow\n  webfetch: deny\n  bash:\n    \"git add *\": allow\n    \"git push *\": ask\n---\nCommit.\n")
+	cmd, err := ParseOpenCodeCommand(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ConvertCommandWithInfo(cmd, FormatClaude)
+	if err != nil {
+		t.Fatalf("ConvertCommandWithInfo() error = %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "bash git push *, webfetch") {
+		t.Errorf("warnings = %v, want one listing the ask and deny entries", result.Warnings)
+	}
+
+	parsed, err := ParseClaudeCommand(result.Content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Bash(git add:*)", "Edit", "Write"}; !reflect.DeepEqual(parsed.AllowedTools, want) {
+		t.Errorf("allowed-tools = %v, want %v", parsed.AllowedTools, want)
+	}
+	if strings.Contains(string(result.Content), "permission") {
+		t.Errorf("Claude command kept permission:\n%s", result.Content)
+	}
+}
+
+func TestConvertCommand_OpenCodeKeepsExplicitPermission(t *testing.T) {
+	cmd := &ClaudeCommand{
+		Name:         "deploy",
+		AllowedTools: []string{"Bash"},
+		Permission:   OpenCodePermission{"bash": "ask"},
+	}
+	cmd.SetFormat(FormatOpenCode)
+
+	out, err := ConvertCommand(cmd, FormatOpenCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseOpenCodeCommand(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Permission["bash"] != "ask" {
+		t.Errorf("bash = %v, want the explicit ask kept\n%s", parsed.Permission["bash"], out)
+	}
+}
+
+// normalizePermission converts the nested maps yaml decodes into plain maps
+func normalizePermission(perm OpenCodePermission) OpenCodePermission {
+	for key, v := range perm {
+		if p, ok := v.(OpenCodePermission); ok {
+			perm[key] = map[string]any(p)
+		}
+	}
+	return perm
+}
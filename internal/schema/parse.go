@@ -5,12 +5,14 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/kennyg/tome/internal/markdown"
 )
 
 // ParseFrontmatter extracts YAML frontmatter from content.
 // Returns the parsed frontmatter map, the body content, and any error.
 func ParseFrontmatter(content []byte) (map[string]interface{}, string, error) {
-	text := string(content)
+	text := markdown.Normalize(content)
 	fm := make(map[string]interface{})
 
 	// Check for frontmatter delimiter
@@ -40,7 +42,7 @@ func ParseFrontmatter(content []byte) (map[string]interface{}, string, error) {
 // ParseFrontmatterTyped extracts YAML frontmatter into a typed struct.
 // Returns the body content and any error.
 func ParseFrontmatterTyped[T any](content []byte, target *T) (string, error) {
-	text := string(content)
+	text := markdown.Normalize(content)
 
 	// Check for frontmatter delimiter
 	if !strings.HasPrefix(text, "---") {
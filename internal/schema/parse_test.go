@@ -30,6 +30,23 @@ Some text here.`,
 			},
 			wantBody: "# Body content\n\nSome text here.",
 		},
+		{
+			name:    "CRLF line endings",
+			content: "---\r\nname: test-skill\r\ndescription: A test skill\r\n---\r\n# Body content\r\n\r\nSome text here.",
+			wantFM: map[string]interface{}{
+				"name":        "test-skill",
+				"description": "A test skill",
+			},
+			wantBody: "# Body content\n\nSome text here.",
+		},
+		{
+			name:    "leading BOM",
+			content: "\ufeff---\nname: test-skill\n---\n# Body content",
+			wantFM: map[string]interface{}{
+				"name": "test-skill",
+			},
+			wantBody: "# Body content",
+		},
 		{
 			name: "no frontmatter",
 			content: `# Just a markdown file
@@ -15,11 +15,15 @@ const (
 	FormatOpenCode Format = "opencode" // OpenCode (.opencode/skill/*/SKILL.md) - same as Claude
 	FormatCopilot  Format = "copilot"  // GitHub Copilot (agents/*.agent.md)
 	FormatCursor   Format = "cursor"   // Cursor (.cursor/rules/*.md)
+	FormatWindsurf Format = "windsurf" // Windsurf (.windsurf/rules/*.md, .windsurf/workflows/*.md)
+	FormatZed      Format = "zed"      // Zed (.zed/rules/*.md, .rules)
+	FormatContinue Format = "continue" // Continue (.continue/prompts/*.md) - commands only
+	FormatCline    Format = "cline"    // Cline (.clinerules/workflows/*.md) - commands only
 )
 
 // AllFormats returns all supported formats
 func AllFormats() []Format {
-	return []Format{FormatClaude, FormatOpenCode, FormatCopilot, FormatCursor}
+	return []Format{FormatClaude, FormatOpenCode, FormatCopilot, FormatCursor, FormatWindsurf, FormatZed, FormatContinue, FormatCline}
 }
 
 // String returns the string representation of the format
@@ -30,7 +34,7 @@ func (f Format) String() string {
 // IsValid returns true if the format is recognized
 func (f Format) IsValid() bool {
 	switch f {
-	case FormatClaude, FormatOpenCode, FormatCopilot, FormatCursor:
+	case FormatClaude, FormatOpenCode, FormatCopilot, FormatCursor, FormatWindsurf, FormatZed, FormatContinue, FormatCline:
 		return true
 	default:
 		return false
@@ -81,8 +85,20 @@ func DetectFormat(filename string, content []byte) Format {
 		return FormatCopilot
 	case hasExtension(filename, ".prompt.md"):
 		return FormatCopilot
+	case hasExtension(filename, ".instructions.md"):
+		return FormatCopilot
+	case hasBasename(filename, ".cursorrules"):
+		return FormatCursor
 	case containsPath(filename, ".cursor"):
 		return FormatCursor
+	case containsPath(filename, ".windsurf") || hasBasename(filename, ".windsurfrules"):
+		return FormatWindsurf
+	case containsPath(filename, ".zed") || filepath.Base(filename) == ".rules":
+		return FormatZed
+	case containsPath(filename, ".continue"):
+		return FormatContinue
+	case containsPath(filename, ".clinerules"):
+		return FormatCline
 	case containsPath(filename, ".opencode"):
 		return FormatOpenCode
 	case containsPath(filename, ".claude"):
@@ -110,6 +126,10 @@ func DetectArtifactType(filename string) ArtifactType {
 		return ArtifactInstructions
 	case hasExtension(filename, ".mdc") && containsPath(filename, ".cursor/rules"):
 		return ArtifactInstructions
+	case baseLower == ".windsurfrules" || baseLower == "global_rules.md":
+		return ArtifactInstructions
+	case baseLower == ".rules":
+		return ArtifactInstructions
 	}
 
 	switch {
@@ -119,6 +139,14 @@ func DetectArtifactType(filename string) ArtifactType {
 	case hasExtension(filename, ".prompt.md"):
 		return ArtifactCommand
 
+	// Windsurf and Cline workflows and Continue prompts are slash commands
+	case containsPath(filename, ".windsurf/workflows"):
+		return ArtifactCommand
+	case containsPath(filename, ".clinerules/workflows"):
+		return ArtifactCommand
+	case containsPath(filename, ".continue/prompts"):
+		return ArtifactCommand
+
 	// Claude/OpenCode patterns
 	case containsPath(filename, "commands"):
 		return ArtifactCommand
@@ -131,9 +159,13 @@ func DetectArtifactType(filename string) ArtifactType {
 	case hasBasename(filename, "SKILL.md"):
 		return ArtifactSkill
 
-	// Cursor rules (non-instructions) are skills
+	// Cursor, Windsurf and Zed rules (non-instructions) are skills
 	case containsPath(filename, ".cursor/rules"):
 		return ArtifactSkill
+	case containsPath(filename, ".windsurf/rules"):
+		return ArtifactSkill
+	case containsPath(filename, ".zed/rules"):
+		return ArtifactSkill
 	}
 
 	// Default to skill
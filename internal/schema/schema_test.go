@@ -13,6 +13,10 @@ func TestFormat_IsValid(t *testing.T) {
 		{FormatOpenCode, true},
 		{FormatCopilot, true},
 		{FormatCursor, true},
+		{FormatWindsurf, true},
+		{FormatZed, true},
+		{FormatContinue, true},
+		{FormatCline, true},
 		{Format("unknown"), false},
 		{Format(""), false},
 	}
@@ -35,6 +39,7 @@ func TestFormat_String(t *testing.T) {
 		{FormatOpenCode, "opencode"},
 		{FormatCopilot, "copilot"},
 		{FormatCursor, "cursor"},
+		{FormatWindsurf, "windsurf"},
 	}
 
 	for _, tt := range tests {
@@ -48,8 +53,8 @@ func TestFormat_String(t *testing.T) {
 
 func TestAllFormats(t *testing.T) {
 	formats := AllFormats()
-	if len(formats) != 4 {
-		t.Errorf("AllFormats() returned %d formats, want 4", len(formats))
+	if len(formats) != 8 {
+		t.Errorf("AllFormats() returned %d formats, want 8", len(formats))
 	}
 
 	// Verify all formats are valid
@@ -71,10 +76,25 @@ func TestDetectFormat(t *testing.T) {
 		{"copilot agent path", "agents/CSharpExpert.agent.md", FormatCopilot},
 		{"copilot prompt", "create-readme.prompt.md", FormatCopilot},
 		{"copilot prompt path", "prompts/create-readme.prompt.md", FormatCopilot},
+		{"copilot instructions", ".github/instructions/go.instructions.md", FormatCopilot},
 
 		// Cursor patterns
 		{"cursor rules", ".cursor/rules/coding.md", FormatCursor},
 		{"cursor path", "project/.cursor/settings.md", FormatCursor},
+		{"cursor legacy rules", ".cursorrules", FormatCursor},
+
+		// Windsurf patterns
+		{"windsurf rule", ".windsurf/rules/coding.md", FormatWindsurf},
+		{"windsurf workflow", "project/.windsurf/workflows/deploy.md", FormatWindsurf},
+		{"windsurf legacy rules", ".windsurfrules", FormatWindsurf},
+
+		// Zed patterns
+		{"zed rule", ".zed/rules/coding.md", FormatZed},
+		{"zed project rules", "project/.rules", FormatZed},
+
+		// Continue and Cline patterns
+		{"continue prompt", ".continue/prompts/review.md", FormatContinue},
+		{"cline workflow", "project/.clinerules/workflows/deploy.md", FormatCline},
 
 		// OpenCode patterns
 		{"opencode skill", ".opencode/skill/test/SKILL.md", FormatOpenCode},
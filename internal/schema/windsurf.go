@@ -0,0 +1,195 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/kennyg/tome/internal/markdown"
+)
+
+// Windsurf rule activation modes (the "trigger" frontmatter field)
+const (
+	WindsurfTriggerAlwaysOn      = "always_on"      // Always included in context
+	WindsurfTriggerManual        = "manual"         // Only when @mentioned
+	WindsurfTriggerModelDecision = "model_decision" // Included when the description matches
+	WindsurfTriggerGlob          = "glob"           // Included for files matching globs
+)
+
+// WindsurfRuleCharLimit is the maximum size Windsurf reads from a rule file
+const WindsurfRuleCharLimit = 12000
+
+// WindsurfRule represents a Windsurf rule (.windsurf/rules/*.md format).
+// Skills become model_decision rules; project instructions become always_on
+// rules. Legacy .windsurfrules files have no frontmatter.
+type WindsurfRule struct {
+	// Core fields (Windsurf itself names rules by filename)
+	Name        string `yaml:"name,omitempty"`
+	Trigger     string `yaml:"trigger,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Globs       string `yaml:"globs,omitempty"` // Comma-separated, for glob triggers
+
+	// Content
+	Body string `yaml:"-"` // Markdown body (not in frontmatter)
+}
+
+// Ensure WindsurfRule implements Skill interface
+var _ Skill = (*WindsurfRule)(nil)
+
+// GetName returns the rule name
+func (r *WindsurfRule) GetName() string {
+	return r.Name
+}
+
+// GetDescription returns the rule description
+func (r *WindsurfRule) GetDescription() string {
+	return r.Description
+}
+
+// GetBody returns the markdown body content
+func (r *WindsurfRule) GetBody() string {
+	return r.Body
+}
+
+// GetFormat returns FormatWindsurf
+func (r *WindsurfRule) GetFormat() Format {
+	return FormatWindsurf
+}
+
+// Serialize returns the rule as .md content for Windsurf
+func (r *WindsurfRule) Serialize() ([]byte, error) {
+	if r.Name == "" && r.Trigger == "" && r.Description == "" && r.Globs == "" {
+		// Legacy .windsurfrules style: plain markdown
+		return []byte(r.Body), nil
+	}
+
+	fm := &windsurfRuleFrontmatter{
+		Name:        r.Name,
+		Trigger:     r.Trigger,
+		Description: r.Description,
+		Globs:       r.Globs,
+	}
+	return SerializeFrontmatter(fm, r.Body)
+}
+
+// windsurfRuleFrontmatter controls YAML field ordering
+type windsurfRuleFrontmatter struct {
+	Name        string `yaml:"name,omitempty"`
+	Trigger     string `yaml:"trigger,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Globs       string `yaml:"globs,omitempty"`
+}
+
+// ParseWindsurfRule parses content as a Windsurf rule file
+func ParseWindsurfRule(content []byte) (*WindsurfRule, error) {
+	rule := &WindsurfRule{}
+
+	text := markdown.Normalize(content)
+	if strings.HasPrefix(text, "---") {
+		body, err := ParseFrontmatterTyped(content, rule)
+		if err != nil {
+			return nil, err
+		}
+		rule.Body = body
+	} else {
+		// Legacy .windsurfrules: entire content is the body
+		rule.Body = text
+	}
+
+	return rule, nil
+}
+
+// ToMetadata extracts common metadata from the rule
+func (r *WindsurfRule) ToMetadata() SkillMetadata {
+	return SkillMetadata{
+		Name:        r.Name,
+		Description: r.Description,
+		Body:        r.Body,
+	}
+}
+
+// FromMetadata populates the rule from common metadata
+func (r *WindsurfRule) FromMetadata(m SkillMetadata) {
+	r.Name = m.Name
+	r.Description = m.Description
+	r.Body = m.Body
+}
+
+// WindsurfWorkflow represents a Windsurf workflow (.windsurf/workflows/*.md),
+// invoked as a slash command named after the file.
+type WindsurfWorkflow struct {
+	// Core fields
+	Name        string `yaml:"name,omitempty"`
+	Description string `yaml:"description"`
+
+	// Content
+	Body string `yaml:"-"`
+}
+
+// Ensure WindsurfWorkflow implements Skill interface
+var _ Skill = (*WindsurfWorkflow)(nil)
+
+// GetName returns the workflow name
+func (w *WindsurfWorkflow) GetName() string {
+	return w.Name
+}
+
+// GetDescription returns the workflow description
+func (w *WindsurfWorkflow) GetDescription() string {
+	return w.Description
+}
+
+// GetBody returns the markdown body content
+func (w *WindsurfWorkflow) GetBody() string {
+	return w.Body
+}
+
+// GetFormat returns FormatWindsurf
+func (w *WindsurfWorkflow) GetFormat() Format {
+	return FormatWindsurf
+}
+
+// Serialize returns the workflow as .md content
+func (w *WindsurfWorkflow) Serialize() ([]byte, error) {
+	fm := &windsurfWorkflowFrontmatter{
+		Name:        w.Name,
+		Description: w.Description,
+	}
+	return SerializeFrontmatter(fm, w.Body)
+}
+
+type windsurfWorkflowFrontmatter struct {
+	Name        string `yaml:"name,omitempty"`
+	Description string `yaml:"description"`
+}
+
+// ParseWindsurfWorkflow parses content as a Windsurf workflow file
+func ParseWindsurfWorkflow(content []byte) (*WindsurfWorkflow, error) {
+	workflow := &WindsurfWorkflow{}
+	body, err := ParseFrontmatterTyped(content, workflow)
+	if err != nil {
+		return nil, err
+	}
+	workflow.Body = body
+	return workflow, nil
+}
+
+// ToMetadata extracts common metadata from the workflow
+func (w *WindsurfWorkflow) ToMetadata() SkillMetadata {
+	return SkillMetadata{
+		Name:        w.Name,
+		Description: w.Description,
+		Body:        w.Body,
+	}
+}
+
+// FromMetadata populates the workflow from common metadata
+func (w *WindsurfWorkflow) FromMetadata(m SkillMetadata) {
+	w.Name = m.Name
+	w.Description = m.Description
+	w.Body = m.Body
+}
+
+// IsWindsurfFile checks if a filename matches Windsurf patterns
+// (.windsurf/ directories and legacy .windsurfrules)
+func IsWindsurfFile(filename string) bool {
+	return strings.Contains(filename, ".windsurf")
+}
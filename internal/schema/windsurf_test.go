@@ -0,0 +1,250 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseWindsurfRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantName    string
+		wantTrigger string
+		wantGlobs   string
+		wantBody    string
+	}{
+		{
+			name: "model decision rule",
+			content: `---
+name: go-style
+trigger: model_decision
+description: Go style rules
+---
+# Go Style
+
+Use gofmt.`,
+			wantName:    "go-style",
+			wantTrigger: WindsurfTriggerModelDecision,
+			wantBody:    "# Go Style\n\nUse gofmt.",
+		},
+		{
+			name: "glob rule",
+			content: `---
+trigger: glob
+globs: "*.ts,*.tsx"
+---
+Prefer type imports.`,
+			wantTrigger: WindsurfTriggerGlob,
+			wantGlobs:   "*.ts,*.tsx",
+			wantBody:    "Prefer type imports.",
+		},
+		{
+			name:     "legacy windsurfrules",
+			content:  "# Project rules\n\nBe concise.",
+			wantBody: "# Project rules\n\nBe concise.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseWindsurfRule([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseWindsurfRule() error = %v", err)
+			}
+			if rule.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", rule.Name, tt.wantName)
+			}
+			if rule.Trigger != tt.wantTrigger {
+				t.Errorf("Trigger = %q, want %q", rule.Trigger, tt.wantTrigger)
+			}
+			if rule.Globs != tt.wantGlobs {
+				t.Errorf("Globs = %q, want %q", rule.Globs, tt.wantGlobs)
+			}
+			if rule.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", rule.Body, tt.wantBody)
+			}
+			if rule.GetFormat() != FormatWindsurf {
+				t.Errorf("GetFormat() = %v, want %v", rule.GetFormat(), FormatWindsurf)
+			}
+		})
+	}
+}
+
+func TestWindsurfRule_SerializeLegacy(t *testing.T) {
+	rule := &WindsurfRule{Body: "Just rules."}
+	got, err := rule.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+	if string(got) != "Just rules." {
+		t.Errorf("Serialize() = %q, want plain body", got)
+	}
+}
+
+func TestRoundTrip_ClaudeToWindsurfToClaude(t *testing.T) {
+	original := &ClaudeSkill{
+		Name:        "test-skill",
+		Description: "A test skill",
+		Version:     "1.0.0",
+		Includes:    []string{"helper.py"},
+		Body:        "# Content\n\nBody text here.",
+	}
+
+	result, err := ConvertWithInfo(original, FormatWindsurf)
+	if err != nil {
+		t.Fatalf("ConvertWithInfo to Windsurf: %v", err)
+	}
+	if !strings.Contains(string(result.Content), "trigger: model_decision") {
+		t.Errorf("expected model_decision trigger, got:\n%s", result.Content)
+	}
+
+	var sawIncludes, sawVersion bool
+	for _, w := range result.Warnings {
+		sawIncludes = sawIncludes || strings.Contains(w, "includes")
+		sawVersion = sawVersion || strings.Contains(w, "version")
+	}
+	if !sawIncludes || !sawVersion {
+		t.Errorf("expected includes and version warnings, got %v", result.Warnings)
+	}
+
+	windsurf, err := ParseAuto(result.Content, ".windsurf/rules/test-skill.md")
+	if err != nil {
+		t.Fatalf("Parse Windsurf: %v", err)
+	}
+	if windsurf.GetFormat() != FormatWindsurf {
+		t.Fatalf("ParseAuto format = %v, want %v", windsurf.GetFormat(), FormatWindsurf)
+	}
+
+	claudeBytes, err := Convert(windsurf, FormatClaude)
+	if err != nil {
+		t.Fatalf("Convert to Claude: %v", err)
+	}
+	back, err := ParseClaudeSkill(claudeBytes)
+	if err != nil {
+		t.Fatalf("Parse Claude: %v", err)
+	}
+
+	if back.Name != original.Name {
+		t.Errorf("Name = %q, want %q", back.Name, original.Name)
+	}
+	if back.Description != original.Description {
+		t.Errorf("Description = %q, want %q", back.Description, original.Description)
+	}
+	if strings.TrimSpace(back.Body) != strings.TrimSpace(original.Body) {
+		t.Errorf("Body = %q, want %q", back.Body, original.Body)
+	}
+}
+
+func TestRoundTrip_ClaudeCommandToWindsurfWorkflow(t *testing.T) {
+	original := &ClaudeCommand{
+		Name:        "deploy",
+		Description: "Deploy the app",
+		Author:      "someone",
+		Body:        "1. Build\n2. Ship",
+	}
+
+	result, err := ConvertCommandWithInfo(original, FormatWindsurf)
+	if err != nil {
+		t.Fatalf("ConvertCommandWithInfo: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "Windsurf workflows") {
+		t.Errorf("expected author warning, got %v", result.Warnings)
+	}
+
+	workflow, err := ParseCommand(result.Content, FormatWindsurf)
+	if err != nil {
+		t.Fatalf("ParseCommand: %v", err)
+	}
+	claudeBytes, err := ConvertCommand(workflow, FormatClaude)
+	if err != nil {
+		t.Fatalf("ConvertCommand to Claude: %v", err)
+	}
+	back, err := ParseClaudeCommand(claudeBytes)
+	if err != nil {
+		t.Fatalf("ParseClaudeCommand: %v", err)
+	}
+	if back.Name != original.Name || back.Description != original.Description {
+		t.Errorf("got %q/%q, want %q/%q", back.Name, back.Description, original.Name, original.Description)
+	}
+	if strings.TrimSpace(back.Body) != original.Body {
+		t.Errorf("Body = %q, want %q", back.Body, original.Body)
+	}
+
+	if got := CommandOutputDirectory(original, FormatWindsurf); got != ".windsurf/workflows" {
+		t.Errorf("CommandOutputDirectory() = %q", got)
+	}
+}
+
+func TestConvertInstructions_ToWindsurf(t *testing.T) {
+	claude := &ClaudeInstructions{Body: "# Project\n\nUse tabs."}
+	out, err := ConvertInstructions(claude, FormatWindsurf)
+	if err != nil {
+		t.Fatalf("ConvertInstructions: %v", err)
+	}
+	rule, err := ParseWindsurfRule(out)
+	if err != nil {
+		t.Fatalf("ParseWindsurfRule: %v", err)
+	}
+	if rule.Trigger != WindsurfTriggerAlwaysOn {
+		t.Errorf("Trigger = %q, want %q", rule.Trigger, WindsurfTriggerAlwaysOn)
+	}
+
+	copilot := &CopilotInstructions{Description: "C# rules", ApplyTo: "**/*.cs", Body: "Use records."}
+	result, err := ConvertInstructionsWithInfo(copilot, FormatWindsurf)
+	if err != nil {
+		t.Fatalf("ConvertInstructionsWithInfo: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("applyTo maps to a glob trigger; unexpected warnings %v", result.Warnings)
+	}
+	rule, err = ParseWindsurfRule(result.Content)
+	if err != nil {
+		t.Fatalf("ParseWindsurfRule: %v", err)
+	}
+	if rule.Trigger != WindsurfTriggerGlob || rule.Globs != "**/*.cs" {
+		t.Errorf("got trigger %q globs %q, want glob **/*.cs", rule.Trigger, rule.Globs)
+	}
+
+	// Back to Claude, the trigger can't be expressed
+	back, err := ConvertInstructionsWithInfo(rule, FormatClaude)
+	if err != nil {
+		t.Fatalf("ConvertInstructionsWithInfo: %v", err)
+	}
+	if len(back.Warnings) != 1 || !strings.Contains(back.Warnings[0], "trigger") {
+		t.Errorf("expected trigger warning, got %v", back.Warnings)
+	}
+}
+
+func TestConvertWithInfo_WindsurfSizeLimit(t *testing.T) {
+	skill := &ClaudeSkill{
+		Name:        "big",
+		Description: "Too big",
+		Body:        strings.Repeat("x", WindsurfRuleCharLimit+1),
+	}
+
+	result, err := ConvertWithInfo(skill, FormatWindsurf)
+	if err != nil {
+		t.Fatalf("ConvertWithInfo: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "Windsurf only reads") {
+		t.Errorf("expected size warning, got %v", result.Warnings)
+	}
+}
+
+func TestDetectArtifactType_Windsurf(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     ArtifactType
+	}{
+		{".windsurf/rules/go-style.md", ArtifactSkill},
+		{".windsurf/workflows/deploy.md", ArtifactCommand},
+		{".windsurfrules", ArtifactInstructions},
+	}
+
+	for _, tt := range tests {
+		if got := DetectArtifactType(tt.filename); got != tt.want {
+			t.Errorf("DetectArtifactType(%q) = %v, want %v", tt.filename, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/kennyg/tome/internal/markdown"
+)
+
+// ZedRule represents a Zed rule (.zed/rules/*.md format). Zed's rules
+// library names rules by title; a project's .rules file at the repo root is
+// plain markdown with no frontmatter and is always included.
+type ZedRule struct {
+	// Core fields
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Default     bool   `yaml:"default,omitempty"` // Included in every thread
+
+	// Content
+	Body string `yaml:"-"` // Markdown body (not in frontmatter)
+}
+
+// Ensure ZedRule implements Skill interface
+var _ Skill = (*ZedRule)(nil)
+
+// GetName returns the rule title
+func (r *ZedRule) GetName() string {
+	return r.Title
+}
+
+// GetDescription returns the rule description
+func (r *ZedRule) GetDescription() string {
+	return r.Description
+}
+
+// GetBody returns the markdown body content
+func (r *ZedRule) GetBody() string {
+	return r.Body
+}
+
+// GetFormat returns FormatZed
+func (r *ZedRule) GetFormat() Format {
+	return FormatZed
+}
+
+// Serialize returns the rule as .md content for Zed
+func (r *ZedRule) Serialize() ([]byte, error) {
+	if r.Title == "" && r.Description == "" && !r.Default {
+		// .rules style: plain markdown
+		return []byte(r.Body), nil
+	}
+
+	fm := &zedRuleFrontmatter{
+		Title:       r.Title,
+		Description: r.Description,
+		Default:     r.Default,
+	}
+	return SerializeFrontmatter(fm, r.Body)
+}
+
+// zedRuleFrontmatter controls YAML field ordering
+type zedRuleFrontmatter struct {
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Default     bool   `yaml:"default,omitempty"`
+}
+
+// ParseZedRule parses content as a Zed rule file
+func ParseZedRule(content []byte) (*ZedRule, error) {
+	rule := &ZedRule{}
+
+	text := markdown.Normalize(content)
+	if strings.HasPrefix(text, "---") {
+		body, err := ParseFrontmatterTyped(content, rule)
+		if err != nil {
+			return nil, err
+		}
+		rule.Body = body
+	} else {
+		// .rules file: entire content is the body
+		rule.Body = text
+	}
+
+	return rule, nil
+}
+
+// ToMetadata extracts common metadata from the rule
+func (r *ZedRule) ToMetadata() SkillMetadata {
+	return SkillMetadata{
+		Name:        r.Title,
+		Description: r.Description,
+		Body:        r.Body,
+	}
+}
+
+// FromMetadata populates the rule from common metadata
+func (r *ZedRule) FromMetadata(m SkillMetadata) {
+	r.Title = m.Name
+	r.Description = m.Description
+	r.Body = m.Body
+}
+
+// IsZedFile checks if a filename matches Zed patterns
+// (.zed/ directories and the project .rules file)
+func IsZedFile(filename string) bool {
+	return containsPath(filename, ".zed") || filepath.Base(filename) == ".rules"
+}
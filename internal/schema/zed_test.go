@@ -0,0 +1,188 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseZedRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantTitle string
+		wantDesc  string
+		wantBody  string
+	}{
+		{
+			name: "library rule",
+			content: `---
+title: go-style
+description: Go style rules
+---
+# Go Style
+
+Use gofmt.`,
+			wantTitle: "go-style",
+			wantDesc:  "Go style rules",
+			wantBody:  "# Go Style\n\nUse gofmt.",
+		},
+		{
+			name:     "project rules file",
+			content:  "# Project rules\n\nBe concise.",
+			wantBody: "# Project rules\n\nBe concise.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseZedRule([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseZedRule() error = %v", err)
+			}
+			if rule.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", rule.Title, tt.wantTitle)
+			}
+			if rule.Description != tt.wantDesc {
+				t.Errorf("Description = %q, want %q", rule.Description, tt.wantDesc)
+			}
+			if rule.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", rule.Body, tt.wantBody)
+			}
+			if rule.GetFormat() != FormatZed {
+				t.Errorf("GetFormat() = %v, want %v", rule.GetFormat(), FormatZed)
+			}
+		})
+	}
+}
+
+func TestRoundTrip_ClaudeToZedToClaude(t *testing.T) {
+	original := &ClaudeSkill{
+		Name:        "test-skill",
+		Description: "A test skill",
+		Author:      "someone",
+		Globs:       []string{"*.go"},
+		Body:        "# Content\n\nBody text here.",
+	}
+
+	result, err := ConvertWithInfo(original, FormatZed)
+	if err != nil {
+		t.Fatalf("ConvertWithInfo to Zed: %v", err)
+	}
+	if !strings.Contains(string(result.Content), "title: test-skill") {
+		t.Errorf("expected title frontmatter, got:\n%s", result.Content)
+	}
+
+	var sawGlobs, sawAuthor bool
+	for _, w := range result.Warnings {
+		sawGlobs = sawGlobs || strings.Contains(w, "globs")
+		sawAuthor = sawAuthor || strings.Contains(w, "author")
+	}
+	if !sawGlobs || !sawAuthor {
+		t.Errorf("expected globs and author warnings, got %v", result.Warnings)
+	}
+
+	if got := OutputDirectory(original, FormatZed); got != ".zed/rules" {
+		t.Errorf("OutputDirectory() = %q, want .zed/rules", got)
+	}
+	if got := OutputFilename(original, FormatZed); got != "test-skill.md" {
+		t.Errorf("OutputFilename() = %q, want test-skill.md", got)
+	}
+
+	zed, err := ParseAuto(result.Content, ".zed/rules/test-skill.md")
+	if err != nil {
+		t.Fatalf("Parse Zed: %v", err)
+	}
+	if zed.GetFormat() != FormatZed {
+		t.Fatalf("ParseAuto format = %v, want %v", zed.GetFormat(), FormatZed)
+	}
+
+	claudeBytes, err := Convert(zed, FormatClaude)
+	if err != nil {
+		t.Fatalf("Convert to Claude: %v", err)
+	}
+	back, err := ParseClaudeSkill(claudeBytes)
+	if err != nil {
+		t.Fatalf("Parse Claude: %v", err)
+	}
+
+	if back.Name != original.Name {
+		t.Errorf("Name = %q, want %q", back.Name, original.Name)
+	}
+	if back.Description != original.Description {
+		t.Errorf("Description = %q, want %q", back.Description, original.Description)
+	}
+	if strings.TrimSpace(back.Body) != strings.TrimSpace(original.Body) {
+		t.Errorf("Body = %q, want %q", back.Body, original.Body)
+	}
+}
+
+func TestRoundTrip_ZedToClaudeToZed(t *testing.T) {
+	original := &ZedRule{
+		Title:       "review",
+		Description: "Code review checklist",
+		Body:        "- Check tests\n- Check docs",
+	}
+
+	result, err := ConvertWithInfo(original, FormatClaude)
+	if err != nil {
+		t.Fatalf("ConvertWithInfo to Claude: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings %v", result.Warnings)
+	}
+
+	claude, err := ParseClaudeSkill(result.Content)
+	if err != nil {
+		t.Fatalf("Parse Claude: %v", err)
+	}
+	zedBytes, err := Convert(claude, FormatZed)
+	if err != nil {
+		t.Fatalf("Convert to Zed: %v", err)
+	}
+	back, err := ParseZedRule(zedBytes)
+	if err != nil {
+		t.Fatalf("ParseZedRule: %v", err)
+	}
+
+	if back.Title != original.Title || back.Description != original.Description {
+		t.Errorf("got %q/%q, want %q/%q", back.Title, back.Description, original.Title, original.Description)
+	}
+	if strings.TrimSpace(back.Body) != original.Body {
+		t.Errorf("Body = %q, want %q", back.Body, original.Body)
+	}
+}
+
+func TestConvertInstructions_ToZed(t *testing.T) {
+	claude := &ClaudeInstructions{Body: "# Project\n\nUse tabs."}
+	out, err := ConvertInstructions(claude, FormatZed)
+	if err != nil {
+		t.Fatalf("ConvertInstructions: %v", err)
+	}
+	if string(out) != claude.Body {
+		t.Errorf("ConvertInstructions() = %q, want plain body", out)
+	}
+	if got := InstructionsOutputFilename(claude, FormatZed); got != ".rules" {
+		t.Errorf("InstructionsOutputFilename() = %q, want .rules", got)
+	}
+	if got := DetectArtifactType(".rules"); got != ArtifactInstructions {
+		t.Errorf("DetectArtifactType(.rules) = %v, want %v", got, ArtifactInstructions)
+	}
+}
+
+func TestConvertCommandWithInfo_ToZed(t *testing.T) {
+	cmd := &ClaudeCommand{Name: "deploy", Description: "Deploy the app", Body: "Ship it."}
+	result, err := ConvertCommandWithInfo(cmd, FormatZed)
+	if err != nil {
+		t.Fatalf("ConvertCommandWithInfo: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "converting to rule") {
+		t.Errorf("expected rule conversion warning, got %v", result.Warnings)
+	}
+	rule, err := ParseZedRule(result.Content)
+	if err != nil {
+		t.Fatalf("ParseZedRule: %v", err)
+	}
+	if rule.Title != "deploy" || strings.TrimSpace(rule.Body) != "Ship it." {
+		t.Errorf("got %q %q", rule.Title, rule.Body)
+	}
+}
@@ -0,0 +1,294 @@
+package source
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// version is a parsed semantic version. Missing minor or patch numbers are
+// recorded as -1 in ranges (1.x) and as 0 in tags (v1.2 is 1.2.0).
+type version struct {
+	major, minor, patch int
+	pre                 string
+}
+
+// comparator is a single constraint like >=1.2.0
+type comparator struct {
+	op string // ">=", ">", "<=", "<" or "="
+	v  version
+}
+
+// refRange is a semver range: any of its alternatives (separated by ||)
+// matches when every comparator in it does
+type refRange [][]comparator
+
+// rangeOps are the operators a range token may start with, longest first
+var rangeOps = []string{">=", "<=", ">", "<", "=", "^", "~"}
+
+// IsRefRange reports whether ref is a semver range (^1.2, ~1.4.0, >=1 <2,
+// 1.x) rather than a literal branch, tag or commit. A plain version like
+// v1.2.3 is a literal tag.
+func IsRefRange(ref string) bool {
+	_, ok := parseRefRange(ref)
+	return ok
+}
+
+// ResolveRefRange returns the tag with the highest version matching the
+// range. Tags may carry a "v" prefix. Prerelease tags are only considered
+// when the range mentions a prerelease.
+func ResolveRefRange(ref string, tags []string) (string, error) {
+	r, ok := parseRefRange(ref)
+	if !ok {
+		return "", fmt.Errorf("not a version range: %s", ref)
+	}
+	allowPre := strings.Contains(ref, "-")
+
+	best, bestVersion := "", version{}
+	for _, tag := range tags {
+		v, ok := parseTagVersion(tag)
+		if !ok || (v.pre != "" && !allowPre) || !r.matches(v) {
+			continue
+		}
+		if best == "" || compareVersions(v, bestVersion) > 0 {
+			best, bestVersion = tag, v
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no tag matches %s", ref)
+	}
+	return best, nil
+}
+
+// ResolveRange replaces a semver range ref with the highest matching tag,
+// remembering the range so String still shows it. It does nothing when the
+// ref isn't a range.
+func (s *Source) ResolveRange(tags []string) error {
+	if !IsRefRange(s.Ref) {
+		return nil
+	}
+	tag, err := ResolveRefRange(s.Ref, tags)
+	if err != nil {
+		return err
+	}
+	s.Range, s.Ref = s.Ref, tag
+	return nil
+}
+
+// parseRefRange parses ref as a range. It fails for anything that isn't
+// one, including a single exact version.
+func parseRefRange(ref string) (refRange, bool) {
+	var r refRange
+	isRange := false
+	for _, alt := range strings.Split(ref, "||") {
+		tokens := strings.FieldsFunc(alt, func(c rune) bool { return c == ' ' || c == ',' })
+		if len(tokens) == 0 {
+			return nil, false
+		}
+		if len(tokens) > 1 || strings.Contains(ref, "||") {
+			isRange = true
+		}
+
+		var set []comparator
+		for _, token := range tokens {
+			op := ""
+			for _, candidate := range rangeOps {
+				if strings.HasPrefix(token, candidate) {
+					op = candidate
+					break
+				}
+			}
+			v, wildcard, ok := parsePartialVersion(strings.TrimPrefix(token, op))
+			if !ok {
+				return nil, false
+			}
+			if op != "" || wildcard {
+				isRange = true
+			}
+			set = append(set, expandComparator(op, v)...)
+		}
+		r = append(r, set)
+	}
+	return r, isRange
+}
+
+// parsePartialVersion parses a version in a range, where trailing parts may
+// be missing or wildcards (1, 1.2, 1.x, 1.2.*). Missing parts are -1;
+// wildcard reports whether any part was an x or *.
+func parsePartialVersion(s string) (v version, wildcard bool, ok bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	s, v.pre, _ = strings.Cut(s, "-")
+	parts := strings.Split(s, ".")
+	if s == "" || len(parts) > 3 {
+		return version{}, false, false
+	}
+
+	nums := []int{-1, -1, -1}
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			wildcard = true
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || wildcard {
+			// Numbers can't follow a wildcard (1.x.3)
+			return version{}, false, false
+		}
+		nums[i] = n
+	}
+	if nums[0] < 0 && !wildcard {
+		return version{}, false, false
+	}
+	v.major, v.minor, v.patch = nums[0], nums[1], nums[2]
+	return v, wildcard, true
+}
+
+// expandComparator turns a range token into plain comparators, following
+// npm's rules for ^, ~ and partial versions
+func expandComparator(op string, v version) []comparator {
+	// A bare * or x matches everything
+	if v.major < 0 {
+		return nil
+	}
+
+	partial := v.minor < 0 || v.patch < 0
+	low := version{major: v.major, minor: max(v.minor, 0), patch: max(v.patch, 0), pre: v.pre}
+
+	// next returns the first version past v's last specified part
+	next := func() version {
+		switch {
+		case v.minor < 0:
+			return version{major: v.major + 1}
+		case v.patch < 0:
+			return version{major: v.major, minor: v.minor + 1}
+		default:
+			return version{major: v.major, minor: v.minor, patch: v.patch + 1}
+		}
+	}
+
+	switch op {
+	case "^":
+		var high version
+		switch {
+		case v.major > 0 || v.minor < 0:
+			high = version{major: v.major + 1}
+		case v.minor > 0 || v.patch < 0:
+			high = version{minor: v.minor + 1}
+		default:
+			high = version{patch: v.patch + 1}
+		}
+		return []comparator{{">=", low}, {"<", high}}
+	case "~":
+		high := version{major: v.major, minor: v.minor + 1}
+		if v.minor < 0 {
+			high = version{major: v.major + 1}
+		}
+		return []comparator{{">=", low}, {"<", high}}
+	case ">":
+		if partial {
+			return []comparator{{">=", next()}}
+		}
+		return []comparator{{">", low}}
+	case "<=":
+		if partial {
+			return []comparator{{"<", next()}}
+		}
+		return []comparator{{"<=", low}}
+	case ">=", "<":
+		return []comparator{{op, low}}
+	default:
+		if partial {
+			return []comparator{{">=", low}, {"<", next()}}
+		}
+		return []comparator{{"=", low}}
+	}
+}
+
+// parseTagVersion parses a tag like v1.2.3, 1.2 or v2.0.0-rc.1+build
+func parseTagVersion(tag string) (version, bool) {
+	tag, _, _ = strings.Cut(tag, "+")
+	v, wildcard, ok := parsePartialVersion(tag)
+	if !ok || wildcard {
+		return version{}, false
+	}
+	v.minor, v.patch = max(v.minor, 0), max(v.patch, 0)
+	return v, true
+}
+
+// matches reports whether v satisfies any alternative of the range
+func (r refRange) matches(v version) bool {
+	for _, set := range r {
+		ok := true
+		for _, c := range set {
+			if !c.matches(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether v satisfies the comparator
+func (c comparator) matches(v version) bool {
+	cmp := compareVersions(v, c.v)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// compareVersions orders versions by precedence, a prerelease coming before
+// its release
+func compareVersions(a, b version) int {
+	for _, d := range []int{a.major - b.major, a.minor - b.minor, a.patch - b.patch} {
+		if d != 0 {
+			return d
+		}
+	}
+	switch {
+	case a.pre == b.pre:
+		return 0
+	case a.pre == "":
+		return 1
+	case b.pre == "":
+		return -1
+	}
+	return comparePrerelease(a.pre, b.pre)
+}
+
+// comparePrerelease compares dot-separated prerelease identifiers, numbers
+// numerically and everything else lexically
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return an - bn
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return len(as) - len(bs)
+}
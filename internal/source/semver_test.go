@@ -0,0 +1,95 @@
+package source
+
+import "testing"
+
+func TestIsRefRange(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"^1.2", true},
+		{"~1.4.0", true},
+		{">=1.0.0 <2.0.0", true},
+		{">=1, <2", true},
+		{"1.x", true},
+		{"1.2.*", true},
+		{"^1 || ^2", true},
+		{"=v1.2.3", true},
+		{"v1.2.3", false},
+		{"1.2", false},
+		{"main", false},
+		{"feature/semver", false},
+		{"abc123", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsRefRange(tt.ref); got != tt.want {
+			t.Errorf("IsRefRange(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestResolveRefRange(t *testing.T) {
+	tags := []string{
+		"v0.9.0", "v1.0.0", "v1.2.0", "v1.2.5", "v1.3.0", "v1.10.1",
+		"v2.0.0-rc.1", "v2.0.0-rc.2", "v2.0.0", "v2.1.0",
+		"0.1.0", "0.1.3", "0.2.0", "latest", "release-3",
+	}
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "^1.2", want: "v1.10.1"},
+		{ref: "~1.2", want: "v1.2.5"},
+		{ref: "~1.2.1", want: "v1.2.5"},
+		{ref: "1.x", want: "v1.10.1"},
+		{ref: "1.3.*", want: "v1.3.0"},
+		{ref: ">=1.0.0 <1.3", want: "v1.2.5"},
+		{ref: ">1.3", want: "v2.1.0"},
+		{ref: "<=1.2", want: "v1.2.5"},
+		{ref: "^0.1", want: "0.1.3"},
+		{ref: "^0.0.9 || ~0.2", want: "0.2.0"},
+		{ref: "*", want: "v2.1.0"},
+		{ref: "=2.0.0", want: "v2.0.0"},
+		{ref: ">=2.0.0-rc.1 <2.0.0", want: "v2.0.0-rc.2"},
+		{ref: "^3", wantErr: true},
+		{ref: "main", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ResolveRefRange(tt.ref, tags)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ResolveRefRange(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveRefRange(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestSource_ResolveRange(t *testing.T) {
+	src, err := Parse("owner/repo:skills@^1.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.ResolveRange([]string{"v1.1.0", "v1.4.2", "v2.0.0"}); err != nil {
+		t.Fatalf("ResolveRange() error = %v", err)
+	}
+	if src.Ref != "v1.4.2" || src.Range != "^1.2" {
+		t.Errorf("Ref = %q, Range = %q; want v1.4.2 from ^1.2", src.Ref, src.Range)
+	}
+	if got := src.String(); got != "owner/repo:skills@^1.2" {
+		t.Errorf("String() = %q, want the range kept", got)
+	}
+	if want := "https://raw.githubusercontent.com/owner/repo/v1.4.2/skills/SKILL.md"; src.GitHubRawURL("SKILL.md") != want {
+		t.Errorf("GitHubRawURL() = %q, want %q", src.GitHubRawURL("SKILL.md"), want)
+	}
+
+	// Literal refs are left alone
+	literal, _ := Parse("owner/repo@v1.2.3")
+	if err := literal.ResolveRange(nil); err != nil || literal.Ref != "v1.2.3" || literal.Range != "" {
+		t.Errorf("literal ref changed: Ref = %q, Range = %q, err = %v", literal.Ref, literal.Range, err)
+	}
+}
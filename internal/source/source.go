@@ -13,21 +13,37 @@ import (
 type Type string
 
 const (
-	TypeGitHub Type = "github"
-	TypeURL    Type = "url"
-	TypeLocal  Type = "local"
+	TypeRepo  Type = "repo" // Hosted git repository (see Provider)
+	TypeURL   Type = "url"
+	TypeLocal Type = "local"
+
+	// TypeGitHub is the original name for TypeRepo, from before other providers
+	TypeGitHub = TypeRepo
+)
+
+// Provider identifies the git hosting service for a TypeRepo source
+type Provider string
+
+const (
+	ProviderGitHub    Provider = "github"
+	ProviderGitLab    Provider = "gitlab"
+	ProviderBitbucket Provider = "bitbucket"
 )
 
 // Source represents a parsed artifact source
 type Source struct {
 	Type     Type
-	Host     string // GitHub host (github.com or GHE hostname)
-	Owner    string // GitHub owner
-	Repo     string // GitHub repo
-	Path     string // Subpath within repo or local path
-	URL      string // Full URL for URL type
-	Ref      string // Git ref (branch, tag, commit)
-	Original string // Original input string
+	Provider Provider // Hosting provider for TypeRepo (empty means GitHub)
+	Host     string   // Provider host (github.com, gitlab.com, GHE hostname, etc.)
+	Owner    string   // Repo owner (may contain slashes for GitLab subgroups)
+	Repo     string   // Repo name
+	Path     string   // Subpath within repo or local path
+	URL      string   // Full URL for URL type
+	Ref      string   // Git ref (branch, tag, commit)
+	Commit   string   // Commit Ref resolved to; fetched from instead of Ref when set
+	Range    string   // Semver range Ref was resolved from (owner/repo@^1.2)
+	Name     string   // Single artifact to select by name (owner/repo#name)
+	Original string   // Original input string
 }
 
 var (
@@ -36,8 +52,21 @@ var (
 
 	// Matches owner/repo@ref or owner/repo:path@ref
 	githubWithRef = regexp.MustCompile(`^([a-zA-Z0-9_-]+)/([a-zA-Z0-9_.-]+)(?::([^@]+))?@(.+)$`)
+
+	// Matches the artifact name in a #name selector
+	artifactName = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
 )
 
+// providerHosts maps shorthand prefixes (gitlab:owner/repo) to provider and default host
+var providerHosts = map[string]struct {
+	provider Provider
+	host     string
+}{
+	"github":    {ProviderGitHub, "github.com"},
+	"gitlab":    {ProviderGitLab, "gitlab.com"},
+	"bitbucket": {ProviderBitbucket, "bitbucket.org"},
+}
+
 // Parse parses a source string into a Source struct
 func Parse(input string) (*Source, error) {
 	input = strings.TrimSpace(input)
@@ -47,7 +76,11 @@ func Parse(input string) (*Source, error) {
 
 	// Check for local path first
 	if isLocalPath(input) {
-		absPath, err := filepath.Abs(input)
+		path, err := expandHome(input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid local path: %w", err)
+		}
+		absPath, err := filepath.Abs(path)
 		if err != nil {
 			return nil, fmt.Errorf("invalid local path: %w", err)
 		}
@@ -63,10 +96,42 @@ func Parse(input string) (*Source, error) {
 		return parseURL(input)
 	}
 
+	// Artifact selector (owner/repo#name, owner/repo:path@ref#name)
+	if rest, name, ok := strings.Cut(input, "#"); ok {
+		if !artifactName.MatchString(name) {
+			return nil, fmt.Errorf("invalid artifact name %q in source: %s", name, input)
+		}
+		src, err := Parse(rest)
+		if err != nil {
+			return nil, err
+		}
+		if src.Type != TypeRepo {
+			return nil, fmt.Errorf("#name selects an artifact from a repo, not a %s source: %s", src.Type, input)
+		}
+		src.Name = name
+		src.Original = input
+		return src, nil
+	}
+
+	// Provider-prefixed shorthand (gitlab:owner/repo, bitbucket:owner/repo)
+	if prefix, rest, ok := strings.Cut(input, ":"); ok {
+		if ph, known := providerHosts[prefix]; known {
+			src, err := Parse(rest)
+			if err != nil || src.Type != TypeRepo {
+				return nil, fmt.Errorf("unable to parse source: %s", input)
+			}
+			src.Provider = ph.provider
+			src.Host = ph.host
+			src.Original = input
+			return src, nil
+		}
+	}
+
 	// Try GitHub shorthand with ref (owner/repo:path@ref)
 	if matches := githubWithRef.FindStringSubmatch(input); matches != nil {
 		return &Source{
-			Type:     TypeGitHub,
+			Type:     TypeRepo,
+			Provider: ProviderGitHub,
 			Host:     "github.com",
 			Owner:    matches[1],
 			Repo:     matches[2],
@@ -79,7 +144,8 @@ func Parse(input string) (*Source, error) {
 	// Try GitHub shorthand (owner/repo or owner/repo:path)
 	if matches := githubShorthand.FindStringSubmatch(input); matches != nil {
 		return &Source{
-			Type:     TypeGitHub,
+			Type:     TypeRepo,
+			Provider: ProviderGitHub,
 			Host:     "github.com",
 			Owner:    matches[1],
 			Repo:     matches[2],
@@ -99,6 +165,14 @@ func parseURL(input string) (*Source, error) {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
+	// GitLab and Bitbucket are checked first; their hosts never look like GitHub
+	if isGitLabHost(u.Host) {
+		return parseGitLabURL(u, input)
+	}
+	if isBitbucketHost(u.Host) {
+		return parseBitbucketURL(u, input)
+	}
+
 	// Check if it's a GitHub URL (public or enterprise)
 	if isGitHubHost(u.Host) {
 		return parseGitHubURL(u, input)
@@ -140,6 +214,85 @@ func isGitHubHost(host string) bool {
 	return false
 }
 
+// isGitLabHost checks if a host is GitLab (gitlab.com or self-hosted gitlab.*)
+func isGitLabHost(host string) bool {
+	lowerHost := strings.ToLower(host)
+	return lowerHost == "gitlab.com" || strings.HasPrefix(lowerHost, "gitlab.")
+}
+
+// isBitbucketHost checks if a host is Bitbucket Cloud
+func isBitbucketHost(host string) bool {
+	return strings.ToLower(host) == "bitbucket.org"
+}
+
+// parseGitLabURL parses GitLab URLs into a Source.
+// Format: gitlab.com/group[/subgroup]/repo/-/(blob|tree|raw)/ref/path
+func parseGitLabURL(u *url.URL, original string) (*Source, error) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	// Everything before the "/-/" separator is the project path
+	sep := len(parts)
+	for i, p := range parts {
+		if p == "-" {
+			sep = i
+			break
+		}
+	}
+	if sep < 2 {
+		return nil, fmt.Errorf("invalid GitLab URL: %s", original)
+	}
+
+	src := &Source{
+		Type:     TypeRepo,
+		Provider: ProviderGitLab,
+		Host:     u.Host,
+		Owner:    strings.Join(parts[:sep-1], "/"),
+		Repo:     parts[sep-1],
+		Ref:      "main",
+		URL:      original,
+		Original: original,
+	}
+
+	rest := parts[sep:]
+	if len(rest) >= 3 && (rest[1] == "blob" || rest[1] == "tree" || rest[1] == "raw") {
+		src.Ref = rest[2]
+		if len(rest) > 3 {
+			src.Path = strings.Join(rest[3:], "/")
+		}
+	}
+
+	return src, nil
+}
+
+// parseBitbucketURL parses Bitbucket URLs into a Source.
+// Format: bitbucket.org/owner/repo/(src|raw)/ref/path
+func parseBitbucketURL(u *url.URL, original string) (*Source, error) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid Bitbucket URL: %s", original)
+	}
+
+	src := &Source{
+		Type:     TypeRepo,
+		Provider: ProviderBitbucket,
+		Host:     u.Host,
+		Owner:    parts[0],
+		Repo:     parts[1],
+		Ref:      "main",
+		URL:      original,
+		Original: original,
+	}
+
+	if len(parts) >= 4 && (parts[2] == "src" || parts[2] == "raw") {
+		src.Ref = parts[3]
+		if len(parts) > 4 {
+			src.Path = strings.Join(parts[4:], "/")
+		}
+	}
+
+	return src, nil
+}
+
 // parseGitHubURL parses GitHub URLs (public or enterprise) into a Source
 func parseGitHubURL(u *url.URL, original string) (*Source, error) {
 	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
@@ -158,7 +311,8 @@ func parseGitHubURL(u *url.URL, original string) (*Source, error) {
 	}
 
 	src := &Source{
-		Type:     TypeGitHub,
+		Type:     TypeRepo,
+		Provider: ProviderGitHub,
 		Host:     host,
 		Owner:    parts[0],
 		Repo:     parts[1],
@@ -221,32 +375,156 @@ func isLocalPath(input string) bool {
 	return err == nil
 }
 
-// GitHubRawURL returns the raw content URL for a GitHub source
-func (s *Source) GitHubRawURL(path string) string {
-	if s.Type != TypeGitHub {
-		return ""
+// expandHome replaces a leading ~ in a local path with the user's home
+// directory. Other paths, ~user/... included, are returned as they are.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
 	}
-	fullPath := path
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// IsGitHub returns true if this is a repo source hosted on GitHub (public or enterprise)
+func (s *Source) IsGitHub() bool {
+	return s.Type == TypeRepo && (s.Provider == "" || s.Provider == ProviderGitHub)
+}
+
+// fullPath joins the source's subpath with path
+func (s *Source) fullPath(path string) string {
 	if s.Path != "" && path == "" {
-		fullPath = s.Path
+		return s.Path
 	} else if s.Path != "" {
-		fullPath = s.Path + "/" + path
+		return s.Path + "/" + path
+	}
+	return path
+}
+
+// RawURL returns the raw content URL for a file in a repo source, for any provider
+func (s *Source) RawURL(path string) string {
+	switch {
+	case s.Type != TypeRepo:
+		return ""
+	case s.Provider == ProviderGitLab:
+		return s.GitLabRawURL(path)
+	case s.Provider == ProviderBitbucket:
+		return s.BitbucketRawURL(path)
+	default:
+		return s.GitHubRawURL(path)
+	}
+}
+
+// RepoRawURL returns the raw content URL at the source's ref for a path
+// relative to the repo root (ignoring the source's subpath)
+func (s *Source) RepoRawURL(repoPath string) string {
+	root := *s
+	root.Path = ""
+	return root.RawURL(repoPath)
+}
+
+// APIURL returns the provider API URL for listing the source's contents
+func (s *Source) APIURL() string {
+	switch {
+	case s.Type != TypeRepo:
+		return ""
+	case s.Provider == ProviderGitLab:
+		return s.GitLabAPIURL()
+	case s.Provider == ProviderBitbucket:
+		return s.BitbucketAPIURL()
+	default:
+		return s.GitHubAPIURL()
 	}
+}
+
+// GitLabRawURL returns the raw content URL for a GitLab source
+func (s *Source) GitLabRawURL(path string) string {
+	if s.Type != TypeRepo || s.Provider != ProviderGitLab {
+		return ""
+	}
+	return fmt.Sprintf("https://%s/%s/%s/-/raw/%s/%s",
+		s.host(), s.Owner, s.Repo, s.FetchRef(), s.fullPath(path))
+}
+
+// GitLabAPIURL returns the GitLab API URL for listing the repository tree
+func (s *Source) GitLabAPIURL() string {
+	if s.Type != TypeRepo || s.Provider != ProviderGitLab {
+		return ""
+	}
+
+	project := url.PathEscape(s.Owner + "/" + s.Repo)
+	base := fmt.Sprintf("https://%s/api/v4/projects/%s/repository/tree", s.host(), project)
+
+	query := url.Values{}
+	if s.Path != "" {
+		query.Set("path", s.Path)
+	}
+	if s.FetchRef() != "" {
+		query.Set("ref", s.FetchRef())
+	}
+	if len(query) > 0 {
+		base += "?" + query.Encode()
+	}
+	return base
+}
+
+// BitbucketRawURL returns the raw content URL for a Bitbucket source
+func (s *Source) BitbucketRawURL(path string) string {
+	if s.Type != TypeRepo || s.Provider != ProviderBitbucket {
+		return ""
+	}
+	return fmt.Sprintf("https://%s/%s/%s/raw/%s/%s",
+		s.host(), s.Owner, s.Repo, s.FetchRef(), s.fullPath(path))
+}
+
+// BitbucketAPIURL returns the Bitbucket API URL for listing the source directory
+func (s *Source) BitbucketAPIURL() string {
+	if s.Type != TypeRepo || s.Provider != ProviderBitbucket {
+		return ""
+	}
+	base := fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s/%s/src/%s/", s.Owner, s.Repo, s.FetchRef())
+	if s.Path != "" {
+		base += s.Path + "/"
+	}
+	return base
+}
+
+// host returns the source host, defaulting per provider
+func (s *Source) host() string {
+	if s.Host != "" {
+		return s.Host
+	}
+	for _, ph := range providerHosts {
+		if ph.provider == s.Provider {
+			return ph.host
+		}
+	}
+	return "github.com"
+}
+
+// GitHubRawURL returns the raw content URL for a GitHub source
+func (s *Source) GitHubRawURL(path string) string {
+	if !s.IsGitHub() {
+		return ""
+	}
+	fullPath := s.fullPath(path)
 
 	// Public GitHub
 	if s.Host == "github.com" || s.Host == "" {
 		return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s",
-			s.Owner, s.Repo, s.Ref, fullPath)
+			s.Owner, s.Repo, s.FetchRef(), fullPath)
 	}
 
 	// GitHub Enterprise - use /raw/ path
 	return fmt.Sprintf("https://%s/%s/%s/raw/%s/%s",
-		s.Host, s.Owner, s.Repo, s.Ref, fullPath)
+		s.Host, s.Owner, s.Repo, s.FetchRef(), fullPath)
 }
 
 // GitHubAPIURL returns the GitHub API URL for listing contents
 func (s *Source) GitHubAPIURL() string {
-	if s.Type != TypeGitHub {
+	if !s.IsGitHub() {
 		return ""
 	}
 
@@ -261,28 +539,74 @@ func (s *Source) GitHubAPIURL() string {
 	if s.Path != "" {
 		base += "/" + s.Path
 	}
-	if s.Ref != "" {
-		base += "?ref=" + s.Ref
+	if s.FetchRef() != "" {
+		base += "?ref=" + s.FetchRef()
 	}
 	return base
 }
 
+// GitHubArchiveURL returns the URL of a gzipped tarball of the whole repo
+// at the source's ref (the default branch when no ref is set)
+func (s *Source) GitHubArchiveURL() string {
+	if !s.IsGitHub() {
+		return ""
+	}
+	host := s.Host
+	if host == "" {
+		host = "github.com"
+	}
+	ref := s.FetchRef()
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return fmt.Sprintf("https://%s/%s/%s/archive/%s.tar.gz", host, s.Owner, s.Repo, ref)
+}
+
 // IsEnterprise returns true if this is a GitHub Enterprise source
 func (s *Source) IsEnterprise() bool {
-	return s.Host != "" && s.Host != "github.com"
+	isGitHub := s.Provider == "" || s.Provider == ProviderGitHub
+	return isGitHub && s.Host != "" && s.Host != "github.com"
+}
+
+// SameRepo reports whether s and other are repo sources for the same
+// repository, whatever path or ref each points at
+func (s *Source) SameRepo(other *Source) bool {
+	if s.Type != TypeRepo || other.Type != TypeRepo {
+		return false
+	}
+	return strings.EqualFold(s.host(), other.host()) &&
+		strings.EqualFold(s.Owner, other.Owner) &&
+		strings.EqualFold(s.Repo, other.Repo)
+}
+
+// FetchRef returns the ref content is fetched at: the commit Ref resolved
+// to when it's known, so a branch moving mid-install can't mix versions
+func (s *Source) FetchRef() string {
+	if s.Commit != "" {
+		return s.Commit
+	}
+	return s.Ref
 }
 
 // String returns a human-readable representation
 func (s *Source) String() string {
 	switch s.Type {
-	case TypeGitHub:
+	case TypeRepo:
 		result := fmt.Sprintf("%s/%s", s.Owner, s.Repo)
+		if !s.IsGitHub() {
+			result = string(s.Provider) + ":" + result
+		}
 		if s.Path != "" {
 			result += ":" + s.Path
 		}
-		if s.Ref != "" && s.Ref != "main" {
+		if s.Range != "" {
+			result += "@" + s.Range
+		} else if s.Ref != "" && s.Ref != "main" {
 			result += "@" + s.Ref
 		}
+		if s.Name != "" {
+			result += "#" + s.Name
+		}
 		return result
 	case TypeLocal:
 		return s.Path
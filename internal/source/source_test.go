@@ -1,6 +1,8 @@
 package source
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -207,6 +209,53 @@ func TestParseLocalPath(t *testing.T) {
 	}
 }
 
+func TestParseLocalPath_ExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	want := filepath.Join(home, "skills", "test")
+	if err := os.MkdirAll(want, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Parse("~/skills/test")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got.Type != TypeLocal || got.Path != want {
+		t.Fatalf("Parse() = %s %s, want local %s", got.Type, got.Path, want)
+	}
+	if _, err := os.Stat(got.Path); err != nil {
+		t.Errorf("Stat(%s) error = %v", got.Path, err)
+	}
+	if got.Original != "~/skills/test" {
+		t.Errorf("Original = %q, want the path as given", got.Original)
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", home},
+		{"~/skills", filepath.Join(home, "skills")},
+		{"~other/skills", "~other/skills"},
+		{"./skills/~", "./skills/~"},
+		{"/abs/path", "/abs/path"},
+	}
+	for _, tt := range tests {
+		got, err := expandHome(tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("expandHome(%q) = %q, %v, want %q", tt.path, got, err, tt.want)
+		}
+	}
+}
+
 func TestSource_GitHubRawURL(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -484,3 +533,322 @@ func TestIsGitHubHost(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_Providers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		provider Provider
+		host     string
+		owner    string
+		repo     string
+		path     string
+		ref      string
+	}{
+		{
+			name:     "gitlab shorthand",
+			input:    "gitlab:myorg/myskills",
+			provider: ProviderGitLab, host: "gitlab.com",
+			owner: "myorg", repo: "myskills", ref: "main",
+		},
+		{
+			name:     "gitlab shorthand with path and ref",
+			input:    "gitlab:myorg/myskills:skills/lint@v2",
+			provider: ProviderGitLab, host: "gitlab.com",
+			owner: "myorg", repo: "myskills", path: "skills/lint", ref: "v2",
+		},
+		{
+			name:     "gitlab blob URL",
+			input:    "https://gitlab.com/org/repo/-/blob/main/SKILL.md",
+			provider: ProviderGitLab, host: "gitlab.com",
+			owner: "org", repo: "repo", path: "SKILL.md", ref: "main",
+		},
+		{
+			name:     "gitlab raw URL",
+			input:    "https://gitlab.com/org/repo/-/raw/develop/skills/x/SKILL.md",
+			provider: ProviderGitLab, host: "gitlab.com",
+			owner: "org", repo: "repo", path: "skills/x/SKILL.md", ref: "develop",
+		},
+		{
+			name:     "gitlab subgroup tree URL",
+			input:    "https://gitlab.com/org/team/repo/-/tree/main/skills",
+			provider: ProviderGitLab, host: "gitlab.com",
+			owner: "org/team", repo: "repo", path: "skills", ref: "main",
+		},
+		{
+			name:     "self-hosted gitlab repo URL",
+			input:    "https://gitlab.example.com/org/repo",
+			provider: ProviderGitLab, host: "gitlab.example.com",
+			owner: "org", repo: "repo", ref: "main",
+		},
+		{
+			name:     "bitbucket shorthand",
+			input:    "bitbucket:team/skills@release",
+			provider: ProviderBitbucket, host: "bitbucket.org",
+			owner: "team", repo: "skills", ref: "release",
+		},
+		{
+			name:     "bitbucket src URL",
+			input:    "https://bitbucket.org/team/skills/src/main/commands/review.md",
+			provider: ProviderBitbucket, host: "bitbucket.org",
+			owner: "team", repo: "skills", path: "commands/review.md", ref: "main",
+		},
+		{
+			name:     "github unchanged",
+			input:    "kennyg/tome",
+			provider: ProviderGitHub, host: "github.com",
+			owner: "kennyg", repo: "tome", ref: "main",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.Type != TypeRepo {
+				t.Errorf("Type = %v, want %v", got.Type, TypeRepo)
+			}
+			if got.Provider != tt.provider {
+				t.Errorf("Provider = %v, want %v", got.Provider, tt.provider)
+			}
+			if got.Host != tt.host {
+				t.Errorf("Host = %v, want %v", got.Host, tt.host)
+			}
+			if got.Owner != tt.owner || got.Repo != tt.repo {
+				t.Errorf("Owner/Repo = %v/%v, want %v/%v", got.Owner, got.Repo, tt.owner, tt.repo)
+			}
+			if got.Path != tt.path {
+				t.Errorf("Path = %v, want %v", got.Path, tt.path)
+			}
+			if got.Ref != tt.ref {
+				t.Errorf("Ref = %v, want %v", got.Ref, tt.ref)
+			}
+		})
+	}
+}
+
+func TestParse_ProviderErrors(t *testing.T) {
+	for _, input := range []string{"gitlab:", "gitlab:not a repo", "https://gitlab.com/org"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected error", input)
+		}
+	}
+}
+
+func TestSource_ProviderURLs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		path    string
+		wantRaw string
+		wantAPI string
+	}{
+		{
+			name:    "gitlab uses /-/raw/",
+			input:   "gitlab:org/repo",
+			path:    "SKILL.md",
+			wantRaw: "https://gitlab.com/org/repo/-/raw/main/SKILL.md",
+			wantAPI: "https://gitlab.com/api/v4/projects/org%2Frepo/repository/tree?ref=main",
+		},
+		{
+			name:    "gitlab subgroup with path",
+			input:   "https://gitlab.com/org/team/repo/-/tree/v1/skills",
+			path:    "SKILL.md",
+			wantRaw: "https://gitlab.com/org/team/repo/-/raw/v1/skills/SKILL.md",
+			wantAPI: "https://gitlab.com/api/v4/projects/org%2Fteam%2Frepo/repository/tree?path=skills&ref=v1",
+		},
+		{
+			name:    "bitbucket",
+			input:   "bitbucket:team/skills:commands",
+			path:    "review.md",
+			wantRaw: "https://bitbucket.org/team/skills/raw/main/commands/review.md",
+			wantAPI: "https://api.bitbucket.org/2.0/repositories/team/skills/src/main/commands/",
+		},
+		{
+			name:    "github dispatches to GitHub builders",
+			input:   "kennyg/tome",
+			path:    "SKILL.md",
+			wantRaw: "https://raw.githubusercontent.com/kennyg/tome/main/SKILL.md",
+			wantAPI: "https://api.github.com/repos/kennyg/tome/contents?ref=main",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := src.RawURL(tt.path); got != tt.wantRaw {
+				t.Errorf("RawURL() = %q, want %q", got, tt.wantRaw)
+			}
+			if got := src.APIURL(); got != tt.wantAPI {
+				t.Errorf("APIURL() = %q, want %q", got, tt.wantAPI)
+			}
+			if !src.IsGitHub() {
+				if src.GitHubRawURL(tt.path) != "" || src.GitHubAPIURL() != "" {
+					t.Error("GitHub URL builders should be empty for other providers")
+				}
+				if src.IsEnterprise() {
+					t.Error("IsEnterprise() should be false for other providers")
+				}
+			}
+		})
+	}
+}
+
+func TestSource_RepoRawURL(t *testing.T) {
+	src, err := Parse("owner/repo:skills/my-skill@v1.2.0")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// Paths from API listings are already repo-relative; the subpath must not be re-applied
+	want := "https://raw.githubusercontent.com/owner/repo/v1.2.0/skills/my-skill/ref.md"
+	if got := src.RepoRawURL("skills/my-skill/ref.md"); got != want {
+		t.Errorf("RepoRawURL() = %q, want %q", got, want)
+	}
+	if src.Path != "skills/my-skill" {
+		t.Errorf("RepoRawURL() modified the source path: %q", src.Path)
+	}
+}
+
+func TestSource_String_Providers(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"gitlab:org/repo", "gitlab:org/repo"},
+		{"gitlab:org/repo:skills/x@v2", "gitlab:org/repo:skills/x@v2"},
+		{"bitbucket:team/skills", "bitbucket:team/skills"},
+		{"github:kennyg/tome", "kennyg/tome"},
+	}
+
+	for _, tt := range tests {
+		src, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.input, err)
+		}
+		if got := src.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+		// String output must round-trip through Parse
+		again, err := Parse(src.String())
+		if err != nil || again.Provider != src.Provider {
+			t.Errorf("Parse(%q) did not round-trip provider: %v, %v", src.String(), again, err)
+		}
+	}
+}
+
+func TestSource_GitHubArchiveURL(t *testing.T) {
+	tests := []struct {
+		src  Source
+		want string
+	}{
+		{Source{Type: TypeRepo, Owner: "owner", Repo: "repo", Ref: "v1.2.0", Path: "skills"},
+			"https://github.com/owner/repo/archive/v1.2.0.tar.gz"},
+		{Source{Type: TypeRepo, Owner: "owner", Repo: "repo"},
+			"https://github.com/owner/repo/archive/HEAD.tar.gz"},
+		{Source{Type: TypeRepo, Host: "github.example.com", Owner: "org", Repo: "skills", Ref: "main"},
+			"https://github.example.com/org/skills/archive/main.tar.gz"},
+		{Source{Type: TypeRepo, Provider: ProviderGitLab, Owner: "org", Repo: "skills"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.src.GitHubArchiveURL(); got != tt.want {
+			t.Errorf("GitHubArchiveURL() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSource_FetchesAtCommit(t *testing.T) {
+	src := Source{Type: TypeRepo, Owner: "owner", Repo: "repo", Path: "skills", Ref: "main", Commit: "abc123"}
+	if got, want := src.GitHubAPIURL(), "https://api.github.com/repos/owner/repo/contents/skills?ref=abc123"; got != want {
+		t.Errorf("GitHubAPIURL() = %q, want %q", got, want)
+	}
+	if got, want := src.GitHubRawURL("SKILL.md"), "https://raw.githubusercontent.com/owner/repo/abc123/skills/SKILL.md"; got != want {
+		t.Errorf("GitHubRawURL() = %q, want %q", got, want)
+	}
+	if got, want := src.GitHubArchiveURL(), "https://github.com/owner/repo/archive/abc123.tar.gz"; got != want {
+		t.Errorf("GitHubArchiveURL() = %q, want %q", got, want)
+	}
+	// The ref named is what's recorded
+	if got, want := src.String(), "owner/repo:skills"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestSource_SameRepo(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"owner/repo", "owner/repo:commands/review.md", true},
+		{"owner/repo", "Owner/Repo@v2", true},
+		{"owner/repo", "other/repo", false},
+		{"owner/repo", "gitlab:owner/repo", false},
+		{"owner/repo", "https://example.com/review.md", false},
+	}
+	for _, tt := range tests {
+		a, err := Parse(tt.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := Parse(tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a.SameRepo(b); got != tt.want {
+			t.Errorf("%s SameRepo %s = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParse_ArtifactName(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantRepo string
+		wantPath string
+		wantRef  string
+		wantName string
+		wantStr  string
+	}{
+		{"owner/repo#pdf", "repo", "", "main", "pdf", "owner/repo#pdf"},
+		{"owner/repo@v1#code-review", "repo", "", "v1", "code-review", "owner/repo@v1#code-review"},
+		{"owner/repo:skills@dev#pdf", "repo", "skills", "dev", "pdf", "owner/repo:skills@dev#pdf"},
+		{"gitlab:group/repo#pdf", "repo", "", "main", "pdf", "gitlab:group/repo#pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			src, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if src.Repo != tt.wantRepo || src.Path != tt.wantPath || src.Ref != tt.wantRef || src.Name != tt.wantName {
+				t.Errorf("Parse() = repo %q path %q ref %q name %q", src.Repo, src.Path, src.Ref, src.Name)
+			}
+			if src.Original != tt.input {
+				t.Errorf("Original = %q, want %q", src.Original, tt.input)
+			}
+			if got := src.String(); got != tt.wantStr {
+				t.Errorf("String() = %q, want %q", got, tt.wantStr)
+			}
+		})
+	}
+
+	for _, input := range []string{"owner/repo#", "owner/repo#a/b", "owner/repo#pdf@v1", "https://example.com/x.md#top"} {
+		src, err := Parse(input)
+		if input == "https://example.com/x.md#top" {
+			// URLs keep their fragment
+			if err != nil || src.Type != TypeURL || src.Name != "" {
+				t.Errorf("Parse(%q) = %+v, %v; want a URL source", input, src, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("Parse(%q) expected error", input)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// progressInterval is how often a live Progress redraws its spinner
+const progressInterval = 100 * time.Millisecond
+
+// Progress reports how far through a batch of work (like fetching files) a
+// command is. On a terminal it's one line with a spinner, an X/Y count and
+// the current item, redrawn in place with carriage returns. Otherwise each
+// step is written as a plain line, so logs stay readable. Methods are safe
+// for concurrent use and on a nil *Progress, which does nothing.
+type Progress struct {
+	w     io.Writer
+	label string
+	total int
+	live  bool
+
+	mu      sync.Mutex
+	done    int
+	current string
+	frame   int
+	stop    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// NewProgress starts a progress indicator for total items, live when IsTTY
+func NewProgress(w io.Writer, label string, total int) *Progress {
+	p := &Progress{w: w, label: label, total: total, live: IsTTY}
+	if p.live {
+		p.stop = make(chan struct{})
+		p.stopped.Add(1)
+		go p.animate()
+	}
+	return p
+}
+
+// Step records that item finished
+func (p *Progress) Step(item string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	p.current = item
+	if p.live {
+		p.redraw()
+		return
+	}
+	fmt.Fprintf(p.w, "  %s %d/%d %s\n", p.label, p.done, p.total, item)
+}
+
+// Done stops the indicator and, when live, erases its line so whatever is
+// printed next starts clean
+func (p *Progress) Done() {
+	if p == nil || !p.live {
+		return
+	}
+	close(p.stop)
+	p.stopped.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprint(p.w, "\r\033[K")
+}
+
+// animate advances the spinner until Done
+func (p *Progress) animate() {
+	defer p.stopped.Done()
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.frame++
+			p.redraw()
+			p.mu.Unlock()
+		}
+	}
+}
+
+// redraw rewrites the progress line in place; p.mu must be held
+func (p *Progress) redraw() {
+	count := Muted.Render(fmt.Sprintf("%d/%d", p.done, p.total))
+	fmt.Fprintf(p.w, "\r\033[K  %s %s %s %s", Spinner(p.frame), p.label, count, Truncate(p.current, 50))
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgress_PlainOutput(t *testing.T) {
+	saved := IsTTY
+	IsTTY = false
+	defer func() { IsTTY = saved }()
+
+	var buf bytes.Buffer
+	p := NewProgress(&buf, "Fetching", 2)
+	p.Step("skills/pdf/SKILL.md")
+	p.Step("commands/review.md")
+	p.Done()
+
+	want := "  Fetching 1/2 skills/pdf/SKILL.md\n  Fetching 2/2 commands/review.md\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+	if strings.ContainsAny(buf.String(), "\r\033") {
+		t.Error("plain output contains carriage returns or escape codes")
+	}
+}
+
+func TestProgress_Live(t *testing.T) {
+	saved := IsTTY
+	IsTTY = true
+	defer func() { IsTTY = saved }()
+
+	var buf bytes.Buffer
+	p := NewProgress(&buf, "Fetching", 1)
+	p.Step("a.md")
+	p.Done()
+
+	out := buf.String()
+	if !strings.Contains(out, "\r") || !strings.Contains(out, "a.md") {
+		t.Errorf("live output = %q, want an in-place line naming a.md", out)
+	}
+	if !strings.HasSuffix(out, "\r\033[K") {
+		t.Errorf("live output = %q, want the line cleared on Done", out)
+	}
+}
+
+func TestProgress_Nil(t *testing.T) {
+	var p *Progress
+	p.Step("a.md")
+	p.Done()
+}